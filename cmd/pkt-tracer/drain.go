@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/wildberries-tech/pkt-tracer/internal/app/pkt-tracer" //nolint:revive
+	"github.com/wildberries-tech/pkt-tracer/internal/nftrace"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/H-BF/corlib/pkg/patterns/observer"
+)
+
+// drainStats - traces delivered and abandoned since drain has started
+type drainStats struct {
+	startedAt time.Time
+	delivered atomic.Int64
+	abandoned atomic.Int64
+	// messages - netlink messages of traces not merged yet
+	messages atomic.Int64
+	obs      observer.Observer
+}
+
+// drain - stop collecting traces, let mergers flush them and sender deliver them and close
+// the stream within drain timeout. It returns when the sender has finished or time is out
+func (m *mainJob) drain(ctx context.Context) {
+	if m.drainTimeout < time.Second {
+		return
+	}
+	st := &drainStats{startedAt: time.Now()}
+	st.obs = observer.NewObserver(st.observe, false, nftrace.CountTraceEvent{}, nftrace.AbandonEvent{})
+	AgentSubject().ObserversAttach(st.obs)
+	m.drained = st
+
+	log := logger.FromContext(ctx)
+	log.Infof("drain traces within %s", m.drainTimeout)
+	ctx1, cancel := context.WithTimeout(ctx, m.drainTimeout)
+	defer cancel()
+	if err := m.nsTracer.Drain(ctx1); err != nil {
+		log.Warnf("traces have not been drained: %v", err)
+		return
+	}
+	// sender finishes when it has delivered the last trace, the rest of components are stopped then
+	<-ctx1.Done()
+}
+
+func (st *drainStats) observe(ev observer.EventType) {
+	switch o := ev.(type) {
+	case nftrace.CountTraceEvent:
+		st.delivered.Add(int64(o.Cnt))
+	case nftrace.AbandonEvent:
+		if o.Stage == nftrace.AbandonCollector {
+			st.messages.Add(int64(o.Cnt))
+		} else {
+			st.abandoned.Add(int64(o.Cnt))
+		}
+	}
+}
+
+// report - it is called when components have been closed, so traces left in them are counted
+func (st *drainStats) report(ctx context.Context) {
+	AgentSubject().ObserversDetach(st.obs)
+	delivered, abandoned, messages := st.delivered.Load(), st.abandoned.Load(), st.messages.Load()
+	msg := "shutdown in %s: %d traces delivered, %d traces abandoned, %d netlink messages of traces abandoned"
+	args := []any{time.Since(st.startedAt).Round(time.Millisecond), delivered, abandoned, messages}
+	if abandoned+messages > 0 {
+		logger.Warnf(ctx, msg, args...)
+	} else {
+		logger.Infof(ctx, msg, args...)
+	}
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/wildberries-tech/pkt-tracer/internal/app/pkt-tracer" //nolint:revive
+	"github.com/wildberries-tech/pkt-tracer/internal/health"
+	"github.com/wildberries-tech/pkt-tracer/internal/nftrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nstrace"
+	sgnw "github.com/wildberries-tech/pkt-tracer/internal/providers/sg-network"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/H-BF/corlib/pkg/backoff"
+	"github.com/pkg/errors"
+)
+
+// sgroupsLink - connection to sgroups and collector of security groups over it
+type sgroupsLink struct {
+	client    *SGClient
+	collector sgnw.SGCollector
+}
+
+// sgroupsSync - settings of sync with sgroups
+func sgroupsSync(ctx context.Context) (interval time.Duration, push bool, err error) {
+	if interval, err = SGroupsSyncStatusInterval.Value(ctx); err != nil {
+		return 0, false, err
+	}
+	if interval < time.Second {
+		return 0, false, errors.Errorf("'%s' is (%v) less than 1s", SGroupsSyncStatusInterval, interval)
+	}
+	push, err = SGroupsSyncStatusPush.Value(ctx)
+	return interval, push, err
+}
+
+func connectSgroups(ctx context.Context) (*sgroupsLink, error) {
+	interval, push, err := sgroupsSync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l := new(sgroupsLink)
+	if l.client, err = NewSGClient(ctx); err != nil {
+		return nil, err
+	}
+	if l.collector, err = sgnw.NewSgCollector(ctx, *l.client, interval, push,
+		sgnw.SgWithAgentSubject(AgentSubject())); err != nil {
+		_ = l.client.CloseConn()
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *sgroupsLink) close() {
+	_ = l.collector.Close()
+	_ = l.client.CloseConn()
+}
+
+// sgroupsProvider - collector of security groups over the current sgroups link, the link is
+// replaced by the new one when settings of sgroups are reloaded. The link is made by Run and
+// remade until sgroups is reachable, networks and rules are missed meanwhile so the next
+// providers of the chain are asked
+type sgroupsProvider struct {
+	link      atomic.Pointer[sgroupsLink]
+	reload    chan struct{}
+	onceRun   sync.Once
+	onceClose sync.Once
+	stop      chan struct{}
+	stopped   chan struct{}
+}
+
+// connectBackoff - backoff between attempts to connect to sgroups
+var connectBackoff = func() backoff.Backoff {
+	return backoff.ExponentialBackoffBuilder().
+		WithInitialInterval(time.Second).
+		WithMaxInterval(30 * time.Second).
+		WithMaxElapsedThreshold(0).
+		Build()
+}
+
+func newSgroupsProvider(ctx context.Context) (*sgroupsProvider, error) {
+	if _, _, err := sgroupsSync(ctx); err != nil {
+		return nil, err
+	}
+	return &sgroupsProvider{
+		reload: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}, nil
+}
+
+// reconnect - make the new link on the next turn of Run
+func (p *sgroupsProvider) reconnect() {
+	select {
+	case p.reload <- struct{}{}:
+	default:
+	}
+}
+
+// Run - run collector of the current link. Traces keep being enriched by the current
+// collector until the new one has fetched security groups
+func (p *sgroupsProvider) Run(ctx context.Context) error {
+	var doRun bool
+	p.onceRun.Do(func() {
+		doRun = true
+		p.stopped = make(chan struct{})
+	})
+	if !doRun {
+		return errors.New("sgroups provider has been run or closed yet")
+	}
+	defer close(p.stopped)
+	log := logger.FromContext(ctx)
+	for {
+		cur := p.link.Load()
+		if cur == nil {
+			if err := p.connect(ctx); err != nil || p.link.Load() == nil {
+				return err
+			}
+			continue
+		}
+		errc := make(chan error, 1)
+		go func() {
+			errc <- cur.collector.Run(ctx)
+		}()
+		for replaced := false; !replaced; {
+			select {
+			case err := <-errc:
+				return err
+			case <-p.stop:
+				_ = cur.collector.Close()
+				return <-errc
+			case <-p.reload:
+				next, err := connectSgroups(ctx)
+				if err != nil {
+					log.Errorf("unable to apply new settings of sgroups, the current connection is kept: %v", err)
+					continue
+				}
+				p.link.Store(next)
+				_ = cur.collector.Close()
+				<-errc
+				_ = cur.client.CloseConn()
+				log.Info("reconnected to sgroups")
+				replaced = true
+			}
+		}
+	}
+}
+
+// connect - make the link, it is retried until sgroups is reachable; it returns nil
+// without the link when the provider is closed
+func (p *sgroupsProvider) connect(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	bkf := connectBackoff()
+	for {
+		l, err := connectSgroups(ctx)
+		if err == nil {
+			p.link.Store(l)
+			log.Info("connected to sgroups")
+			return nil
+		}
+		health.Report(AgentSubject(), health.SgCollector, "", err)
+		d := bkf.NextBackOff()
+		log.Errorf("unable to connect to sgroups, next attempt in %v: %v", d, err)
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-p.stop:
+			t.Stop()
+			return nil
+		case <-p.reload:
+			t.Stop()
+		case <-t.C:
+		}
+	}
+}
+
+// GetSGByIP -
+func (p *sgroupsProvider) GetSGByIP(ip net.IP) (sgnw.SgNet, error) {
+	if l := p.link.Load(); l != nil {
+		return l.collector.GetSGByIP(ip)
+	}
+	return sgnw.SgNet{}, sgnw.ErrSgMiss
+}
+
+// GetSgRule -
+func (p *sgroupsProvider) GetSgRule(pkt sgnw.Packet, action string) (sgnw.SgRule, error) {
+	if l := p.link.Load(); l != nil {
+		return l.collector.GetSgRule(pkt, action)
+	}
+	return sgnw.SgRule{}, sgnw.ErrSgRuleMiss
+}
+
+// Close -
+func (p *sgroupsProvider) Close() error {
+	p.onceClose.Do(func() {
+		close(p.stop)
+		p.onceRun.Do(func() {})
+		if p.stopped != nil {
+			<-p.stopped
+		}
+		if l := p.link.Load(); l != nil {
+			l.close()
+		}
+	})
+	return nil
+}
+
+// connectSgNet - make priority chain of providers of networks and security groups,
+// sgroups is connected only when it is in the chain and not before the chain is run
+func (m *mainJob) connectSgNet(ctx context.Context) (err error) {
+	var chain []sgnw.SGCollector
+	defer func() {
+		if err != nil {
+			for _, p := range chain {
+				_ = p.Close()
+			}
+		}
+	}()
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(SgNetProviders.MustValue(ctx), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if seen[name] {
+			return errors.Errorf("provider '%s' is repeated in '%s'", name, SgNetProviders)
+		}
+		seen[name] = true
+		var p sgnw.SGCollector
+		switch name {
+		case SgNetProviderSgroups:
+			if m.sgroups, err = newSgroupsProvider(ctx); err != nil {
+				return err
+			}
+			p = m.sgroups
+		case SgNetProviderStatic:
+			interval := SgNetStaticReloadInterval.MustValue(ctx)
+			if interval < time.Second {
+				return errors.Errorf("'%s' is (%v) less than 1s", SgNetStaticReloadInterval, interval)
+			}
+			if p, err = sgnw.NewStaticCollector(SgNetStaticFile.MustValue(ctx), interval); err != nil {
+				return err
+			}
+		case SgNetProviderNone:
+			p = sgnw.NewSgNone()
+		default:
+			return errors.Errorf("unknown provider '%s' in '%s'", name, SgNetProviders)
+		}
+		chain = append(chain, p)
+	}
+	if len(chain) == 0 {
+		return errors.Errorf("no providers are set in '%s'", SgNetProviders)
+	}
+	m.sgNet = sgnw.NewSgChain(chain...)
+	return nil
+}
+
+func newTraceSender(ctx context.Context, c *THClient, tracer nstrace.Tracer) (nftrace.TraceSender, error) {
+	heartbeat, err := TrHeartbeatInterval.Value(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nftrace.NewTraceSend(*c, tracer, AgentSubject(),
+		nftrace.SendWithAgentInfo(AgentInfo()),
+		nftrace.SendWithHeartbeat(heartbeat),
+	), nil
+}
+
+// runTraceHub - run sender of traces, it is replaced by the new one over the new connection
+// when settings of trace-hub are reloaded. Traces wait in the queue of the tracer meanwhile,
+// so merge buffers are kept and nothing is sent twice
+func (m *mainJob) runTraceHub(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	for {
+		sender := m.trSender
+		errc := make(chan error, 1)
+		go func() {
+			errc <- sender.Run(ctx)
+		}()
+		for replaced := false; !replaced; {
+			select {
+			case err := <-errc:
+				return err
+			case <-m.thReload:
+				if err := m.reconnectTraceHub(ctx, func() {
+					_ = sender.Close()
+					<-errc
+				}); err != nil {
+					log.Errorf("unable to apply new settings of trace-hub, the current connection is kept: %v", err)
+					continue
+				}
+				log.Info("reconnected to trace-hub")
+				replaced = true
+			}
+		}
+	}
+}
+
+// reconnectTraceHub - move sync of tables and sending of traces to the new connection,
+// 'stopSender' stops the current sender
+func (m *mainJob) reconnectTraceHub(ctx context.Context, stopSender func()) error {
+	syncInterval, err := TableSyncInterval.Value(ctx)
+	if err != nil {
+		return err
+	}
+	next, err := NewTHClient(ctx)
+	if err != nil {
+		return err
+	}
+	sender, err := newTraceSender(ctx, next, m.nsTracer)
+	if err != nil {
+		_ = next.CloseConn()
+		return err
+	}
+	prev := m.thClient.Swap(next)
+	if err = m.nsTracer.RestartTableSync(ctx, syncInterval); err != nil {
+		// streams of tables which have moved already go back to the current connection
+		m.thClient.Store(prev)
+		if e := m.nsTracer.RestartTableSync(ctx, syncInterval); e != nil {
+			logger.FromContext(ctx).Errorf("unable to resume sync of tables over the current connection: %v", e)
+		}
+		_ = next.CloseConn()
+		return err
+	}
+	stopSender()
+	m.trSender = sender
+	_ = prev.CloseConn()
+	return nil
+}
@@ -2,16 +2,24 @@ package main
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/wildberries-tech/pkt-tracer/internal/app"
 	. "github.com/wildberries-tech/pkt-tracer/internal/app/pkt-tracer" //nolint:revive
+	"github.com/wildberries-tech/pkt-tracer/internal/bqueue"
 	"github.com/wildberries-tech/pkt-tracer/internal/config"
+	"github.com/wildberries-tech/pkt-tracer/internal/conntrack"
+	"github.com/wildberries-tech/pkt-tracer/internal/health"
 	iftrace "github.com/wildberries-tech/pkt-tracer/internal/iface"
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
+	"github.com/wildberries-tech/pkt-tracer/internal/nflog"
 	"github.com/wildberries-tech/pkt-tracer/internal/nfrule"
 	"github.com/wildberries-tech/pkt-tracer/internal/nftmonitor"
 	"github.com/wildberries-tech/pkt-tracer/internal/nftrace"
-	"github.com/wildberries-tech/pkt-tracer/internal/nl"
+	"github.com/wildberries-tech/pkt-tracer/internal/nstrace"
+	cmeta "github.com/wildberries-tech/pkt-tracer/internal/providers/container-meta"
+	procowner "github.com/wildberries-tech/pkt-tracer/internal/providers/proc-owner"
 	sgnw "github.com/wildberries-tech/pkt-tracer/internal/providers/sg-network"
 
 	"github.com/H-BF/corlib/logger"
@@ -23,7 +31,6 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
-	"golang.org/x/sys/unix"
 )
 
 func main() {
@@ -43,9 +50,35 @@ func main() {
 		config.WithDefValue{Key: TrAddress, Val: "tcp://127.0.0.1:9000"},
 		config.WithDefValue{Key: UseCompression, Val: false},
 		config.WithDefValue{Key: TableSyncInterval, Val: "3s"},
+		config.WithDefValue{Key: TrHeartbeatInterval, Val: "10s"},
 		config.WithDefValue{Key: SGroupsAddress, Val: "tcp://127.0.0.1:9001"},
 		config.WithDefValue{Key: SGroupsSyncStatusInterval, Val: "10s"},
 		config.WithDefValue{Key: SGroupsSyncStatusPush, Val: false},
+		config.WithDefValue{Key: SgNetProviders, Val: SgNetProviderSgroups},
+		config.WithDefValue{Key: SgNetStaticFile, Val: ""},
+		config.WithDefValue{Key: SgNetStaticReloadInterval, Val: "10s"},
+		config.WithDefValue{Key: SgNetSgroupsRuleScope, Val: "main*"},
+		config.WithDefValue{Key: NetnsEnable, Val: false},
+		config.WithDefValue{Key: NetnsRescanInterval, Val: "5s"},
+		config.WithDefValue{Key: NetnsDir, Val: netns.DefNetnsDir},
+		config.WithDefValue{Key: NetnsProcDir, Val: netns.DefProcDir},
+		config.WithDefValue{Key: ContainersEnable, Val: false},
+		config.WithDefValue{Key: ContainersRuntimeDir, Val: cmeta.DefRuntimeDir},
+		config.WithDefValue{Key: ContainersMappingFile, Val: ""},
+		config.WithDefValue{Key: ContainersReloadInterval, Val: "10s"},
+		config.WithDefValue{Key: ProcsEnable, Val: false},
+		config.WithDefValue{Key: ProcsCacheSize, Val: procowner.DefCacheSize},
+		config.WithDefValue{Key: ConntrackEnable, Val: false},
+		config.WithDefValue{Key: TraceSource, Val: TraceSourceNftrace},
+		config.WithDefValue{Key: TraceRawHeaders, Val: false},
+		config.WithDefValue{Key: NflogGroups, Val: "0"},
+		config.WithDefValue{Key: NflogCopyRange, Val: nflog.DefCopyRange},
+		config.WithDefValue{Key: NftraceFilterEnable, Val: false},
+		config.WithDefValue{Key: NftraceFilterFamily, Val: ""},
+		config.WithDefValue{Key: NftraceFilterTables, Val: ""},
+		config.WithDefValue{Key: NftraceFilterTypes, Val: ""},
+		config.WithDefValue{Key: QueueCapacity, Val: 65536},
+		config.WithDefValue{Key: QueueOverflow, Val: "drop-oldest"},
 
 		//telemetry group
 		config.WithDefValue{Key: TelemetryEndpoint, Val: "127.0.0.1:5000"},
@@ -87,9 +120,28 @@ func main() {
 			iftrace.CountIfaceNlErrMemEvent{},
 			nfrule.CountRulerNlErrMemEvent{},
 			nftrace.CountCollectNlErrMemEvent{},
+			conntrack.CountConntrackNlErrMemEvent{},
+			nflog.CountNflogNlErrMemEvent{},
+			nflog.CountNflogDecodeErrEvent{},
+			nftmonitor.CountTableWatcherNlErrMemEvent{},
+			nftrace.LatencyEvent{},
+			nftrace.MergeBufSizeEvent{},
+			nftrace.RuleLookupEvent{},
+			nftrace.RuleTimeoutEvent{},
+			iftrace.IfaceCacheMissEvent{},
+			sgnw.SgSyncEvent{},
 		),
+		observer.NewObserver(agentHealthObserver, false, health.StateEvent{}),
 	)
 
+	cfgWatcher := config.NewReloadWatcher(ConfigFile)
+	go func() {
+		if e := cfgWatcher.Run(ctx); e != nil && !errors.Is(e, context.Canceled) {
+			logger.Errorf(ctx, "config will not be reloaded: %v", e)
+		}
+	}()
+	defer cfgWatcher.Close() //nolint:errcheck
+
 	gracefulDuration := AppGracefulShutdown.MustValue(ctx)
 	errc := make(chan error, 1)
 
@@ -103,7 +155,8 @@ func main() {
 	case <-ctx.Done():
 		if gracefulDuration >= time.Second {
 			logger.Infof(ctx, "%s in shutdowning...", gracefulDuration)
-			_ = gs.ForDuration(gracefulDuration).Run(
+			// traces are drained within graceful duration, components are closed after that
+			_ = gs.ForDuration(gracefulDuration + closeMargin).Run(
 				gs.Chan(errc).Consume(
 					func(_ context.Context, err error) {
 						jobErr = err
@@ -129,61 +182,88 @@ func agentMetricsObserver(ev observer.EventType) {
 			metrics.ObserveTracesCounter(o.Cnt)
 		case iftrace.CountIfaceNlErrMemEvent:
 			metrics.ObserveErrNlMemCounter(ESrcIface)
+			metrics.ObserveNlLostCounter(ESrcIface, o.Lost)
 		case nfrule.CountRulerNlErrMemEvent:
 			metrics.ObserveErrNlMemCounter(ESrcRuler)
+			metrics.ObserveNlLostCounter(ESrcRuler, o.Lost)
 		case nftrace.CountCollectNlErrMemEvent:
 			metrics.ObserveErrNlMemCounter(ESrcCollector)
+			metrics.ObserveNlLostCounter(ESrcCollector, o.Lost)
+		case conntrack.CountConntrackNlErrMemEvent:
+			metrics.ObserveErrNlMemCounter(ESrcConntrack)
+			metrics.ObserveNlLostCounter(ESrcConntrack, o.Lost)
+		case nflog.CountNflogNlErrMemEvent:
+			metrics.ObserveErrNlMemCounter(ESrcNflog)
+			metrics.ObserveNlLostCounter(ESrcNflog, o.Lost)
+		case nflog.CountNflogDecodeErrEvent:
+			metrics.ObserveDecodeErrCounter(ESrcNflog)
+		case nftmonitor.CountTableWatcherNlErrMemEvent:
+			metrics.ObserveErrNlMemCounter(ESrcTables)
+			metrics.ObserveNlLostCounter(ESrcTables, o.Lost)
+		case nftrace.LatencyEvent:
+			metrics.ObserveLatency(o.Stage, o.Latency)
+		case nftrace.MergeBufSizeEvent:
+			metrics.ObserveMergeBufSize(o.Delta)
+		case nftrace.RuleLookupEvent:
+			metrics.ObserveRuleLookup(o.Hit)
+		case nftrace.RuleTimeoutEvent:
+			metrics.ObserveRuleTimeout()
+		case iftrace.IfaceCacheMissEvent:
+			metrics.ObserveIfaceCacheMiss()
+		case sgnw.SgSyncEvent:
+			metrics.ObserveSgSync(o.At)
 		}
 	}
 }
 
+func agentHealthObserver(ev observer.EventType) {
+	if o, ok := ev.(health.StateEvent); ok {
+		if o.Gone {
+			app.RemoveComponentHealth(o.Component, o.Instance)
+		} else {
+			app.SetComponentHealth(o.Component, o.Instance, o.Err)
+		}
+	}
+}
+
+// closeMargin - time to close components after traces have been drained on shutdown
+const closeMargin = time.Second
+
 type mainJob struct {
-	thClient    *THClient
-	sgClient    *SGClient
-	sgCollector sgnw.SGCollector
-	ifTracer    iftrace.Iface
-	nlWatcher   nl.NetlinkWatcher
-	nfruler     nfrule.RuleTracer
-	tblWatcher  nftmonitor.TableWatcher
-	trCollect   nftrace.TraceCollector
-	trSender    nftrace.TraceSender
-	trMerge     nftrace.TraceMerger
+	// thClient is replaced on reconnect when settings of trace-hub are reloaded
+	thClient atomic.Pointer[THClient]
+	thReload chan struct{}
+	// sgNet is chain of providers of networks and security groups, sgroups is nil
+	// unless it is in the chain
+	sgNet      sgnw.SGCollector
+	sgroups    *sgroupsProvider
+	containers cmeta.ContainerCollector
+	nsTracer   nstrace.Tracer
+	trSender   nftrace.TraceSender
+	// drainTimeout - time to deliver traces collected yet on shutdown
+	drainTimeout time.Duration
+	drained      *drainStats
 }
 
 func (m *mainJob) cleanup() {
-	if m.thClient != nil {
-		_ = m.thClient.CloseConn()
+	if c := m.thClient.Load(); c != nil {
+		_ = c.CloseConn()
 	}
 
-	if m.sgClient != nil {
-		_ = m.sgClient.CloseConn()
+	if m.sgNet != nil {
+		_ = m.sgNet.Close()
 	}
 
-	if m.sgCollector != nil {
-		_ = m.sgCollector.Close()
+	if m.containers != nil {
+		_ = m.containers.Close()
 	}
 
-	if m.ifTracer != nil {
-		_ = m.ifTracer.Close()
-	}
-	if m.nlWatcher != nil {
-		_ = m.nlWatcher.Close()
-	}
-	if m.nfruler != nil {
-		_ = m.nfruler.Close()
-	}
-	if m.tblWatcher != nil {
-		_ = m.tblWatcher.Close()
-	}
-	if m.trCollect != nil {
-		_ = m.trCollect.Close()
+	if m.nsTracer != nil {
+		_ = m.nsTracer.Close()
 	}
 	if m.trSender != nil {
 		_ = m.trSender.Close()
 	}
-	if m.trMerge != nil {
-		_ = m.trMerge.Close()
-	}
 }
 
 func (m *mainJob) init(ctx context.Context) (err error) {
@@ -194,91 +274,194 @@ func (m *mainJob) init(ctx context.Context) (err error) {
 	}()
 
 	as := AgentSubject()
+	m.drainTimeout = AppGracefulShutdown.MustValue(ctx)
+	m.thReload = make(chan struct{}, 1)
 
-	if m.thClient, err = NewTHClient(ctx); err != nil {
+	thClient, err := NewTHClient(ctx)
+	if err != nil {
 		return err
 	}
+	m.thClient.Store(thClient)
 
-	if m.sgClient, err = NewSGClient(ctx); err != nil {
+	if err = m.connectSgNet(ctx); err != nil {
 		return err
 	}
 
-	if m.sgCollector, err = sgnw.NewSgCollector(
-		ctx,
-		*m.sgClient,
-		SGroupsSyncStatusInterval.MustValue(ctx),
-		SGroupsSyncStatusPush.MustValue(ctx)); err != nil {
-		return err
+	deps := nstrace.Deps{
+		AgentSubject:   as,
+		SgNetProvider:  m.sgNet,
+		SgRuleProvider: m.sgNet,
 	}
-
-	m.ifTracer = iftrace.NewIface(as)
-
-	if m.nlWatcher, err = nl.NewNetlinkWatcher(2, unix.NETLINK_NETFILTER,
-		nl.SkWithBufLen(nl.SockBufLen16MB),
-		nl.SkWithNlMs(unix.NFNLGRP_NFTABLES),
-	); err != nil {
-		return err
+	if ContainersEnable.MustValue(ctx) {
+		var opts []cmeta.CollectorOpt
+		if f := ContainersMappingFile.MustValue(ctx); f != "" {
+			opts = append(opts, cmeta.WithStaticFile(f))
+		}
+		if d := ContainersRuntimeDir.MustValue(ctx); d != "" {
+			opts = append(opts, cmeta.WithRuntimeDir(d, NetnsProcDir.MustValue(ctx)))
+		}
+		if m.containers, err = cmeta.NewContainerCollector(ContainersReloadInterval.MustValue(ctx), opts...); err != nil {
+			return err
+		}
+		deps.ContainerProvider = m.containers
 	}
 
-	nlWatchers := map[string]nl.NlReader{
-		"rule-watcher":  m.nlWatcher.Reader(0),
-		"table-watcher": m.nlWatcher.Reader(1),
+	var tracerOpts []nstrace.TracerOpt
+	ruleScope, err := sgnw.ParseRuleScope(SgNetSgroupsRuleScope.MustValue(ctx))
+	if err != nil {
+		return errors.WithMessagef(err, "bad '%s'", SgNetSgroupsRuleScope)
 	}
-
-	m.nfruler = nfrule.NewRuleTrace(nfrule.Deps{
-		AgentSubject: as,
-		NlWatcher:    nlWatchers["rule-watcher"],
-	})
-	tblCli, err := m.thClient.SyncNftTables(ctx)
+	tracerOpts = append(tracerOpts, nstrace.WithSgRuleScope(ruleScope))
+	if NetnsEnable.MustValue(ctx) {
+		tracerOpts = append(tracerOpts, nstrace.WithNetNSDiscovery(
+			netns.Scanner{
+				NetnsDir: NetnsDir.MustValue(ctx),
+				ProcDir:  NetnsProcDir.MustValue(ctx),
+			},
+			NetnsRescanInterval.MustValue(ctx),
+		))
+	}
+	if ProcsEnable.MustValue(ctx) {
+		tracerOpts = append(tracerOpts, nstrace.WithProcessAttribution(
+			ProcsCacheSize.MustValue(ctx),
+			NetnsProcDir.MustValue(ctx),
+		))
+	}
+	if ConntrackEnable.MustValue(ctx) {
+		tracerOpts = append(tracerOpts, nstrace.WithConntrack())
+	}
+	if TraceRawHeaders.MustValue(ctx) {
+		tracerOpts = append(tracerOpts, nstrace.WithRawHeaders())
+	}
+	switch src := TraceSource.MustValue(ctx); src {
+	case TraceSourceNftrace:
+	case TraceSourceNflog, TraceSourceBoth:
+		groups, err := nflog.ParseGroups(NflogGroups.MustValue(ctx))
+		if err != nil {
+			return err
+		}
+		if len(groups) == 0 {
+			return errors.Errorf("no log groups are set in '%s'", NflogGroups)
+		}
+		tracerOpts = append(tracerOpts, nstrace.WithNflog(groups, NflogCopyRange.MustValue(ctx)))
+		if src == TraceSourceNflog {
+			tracerOpts = append(tracerOpts, nstrace.WithoutNftrace())
+		}
+	default:
+		return errors.Errorf("unknown trace source '%s' in '%s'", src, TraceSource)
+	}
+	if NftraceFilterEnable.MustValue(ctx) {
+		spec, err := nftrace.ParseFilterSpec(
+			NftraceFilterFamily.MustValue(ctx),
+			NftraceFilterTables.MustValue(ctx),
+			NftraceFilterTypes.MustValue(ctx),
+		)
+		if err != nil {
+			return errors.WithMessage(err, "bad nftrace filter")
+		}
+		tracerOpts = append(tracerOpts, nstrace.WithNftraceFilter(spec))
+	}
+	queCapacity := QueueCapacity.MustValue(ctx)
+	if queCapacity < 0 {
+		return errors.Errorf("'%s' must not be negative", QueueCapacity)
+	}
+	quePolicy, err := bqueue.ParsePolicy(QueueOverflow.MustValue(ctx))
 	if err != nil {
-		return err
+		return errors.WithMessagef(err, "bad '%s'", QueueOverflow)
 	}
-	m.tblWatcher = nftmonitor.NewTableWatcher(nftmonitor.Deps{
-		Client:       tblCli,
-		AgentSubject: as,
-		NlWatcher:    nlWatchers["table-watcher"],
-	},
-		TableSyncInterval.MustValue(ctx),
-	)
-
-	if m.trCollect, err = nftrace.NewCollector(as); err != nil {
-		return err
+	var queMetrics func(stage string) bqueue.Metrics
+	if am := GetAgentMetrics(); am != nil {
+		queMetrics = am.QueueMetrics
+	}
+	tracerOpts = append(tracerOpts, nstrace.WithQueues(queCapacity, quePolicy, queMetrics))
+	deps.TablesStream = func(ctx context.Context) (nftmonitor.StreamCli, error) {
+		return m.thClient.Load().SyncNftTables(ctx)
 	}
+	m.nsTracer = nstrace.NewTracer(deps, TableSyncInterval.MustValue(ctx), tracerOpts...)
 
-	m.trMerge = nftrace.NewTraceMerge(m.trCollect, m.ifTracer, m.nfruler, m.sgCollector)
+	m.trSender, err = newTraceSender(ctx, thClient, m.nsTracer)
 
-	m.trSender = nftrace.NewTraceSend(*m.thClient, m.trMerge, AgentSubject())
+	return err
+}
 
-	return nil
+// subscribe - apply changes of the config live, changes of the rest of settings require restart
+func (m *mainJob) subscribe() (unsubscribe func()) {
+	thReconnect := func() {
+		select {
+		case m.thReload <- struct{}{}:
+		default:
+		}
+	}
+	// settings of sgroups are not in use when it is not in the chain of providers
+	sgReconnect := func() {}
+	if m.sgroups != nil {
+		sgReconnect = m.sgroups.reconnect
+	}
+	unsub := []func(){
+		onChange(ServicesDefDialDuration, thReconnect),
+		onChange(ServicesDefDialDuration, sgReconnect),
+		onChange(TrAddress, thReconnect),
+		onChange(TrDialDuration, thReconnect),
+		onChange(UseCompression, thReconnect),
+		onChange(TrHeartbeatInterval, thReconnect),
+		onChange(SGroupsAddress, sgReconnect),
+		onChange(SGroupsDialDuration, sgReconnect),
+		onChange(SGroupsSyncStatusInterval, sgReconnect),
+		onChange(SGroupsSyncStatusPush, sgReconnect),
+		TableSyncInterval.OnChange(func(ctx context.Context, ch config.Change[time.Duration]) error {
+			return m.nsTracer.RestartTableSync(ctx, ch.New)
+		}),
+	}
+	return func() {
+		for _, f := range unsub {
+			f()
+		}
+	}
+}
+
+// onChange - f is called on change of the value
+func onChange[T any](v config.ValueT[T], f func()) (unsubscribe func()) {
+	return v.OnChange(func(context.Context, config.Change[T]) error {
+		f()
+		return nil
+	})
 }
 
 func (m *mainJob) run(ctx context.Context) error {
+	defer func() {
+		if m.drained != nil {
+			m.drained.report(ctx)
+		}
+	}()
 	defer m.cleanup()
-	ctx1, cancel := context.WithCancel(ctx)
+	// components are stopped by drain on shutdown rather than by cancel
+	ctx1, cancel := context.WithCancel(context.WithoutCancel(ctx))
 	defer cancel()
-	ff := [...]func() error{
-		func() error {
-			return m.ifTracer.Run(ctx1)
-		},
+	defer m.subscribe()()
+	ff := []func() error{
 		func() error {
-			return m.nfruler.Run(ctx1)
-		},
-		func() error {
-			return m.tblWatcher.Run(ctx1)
-		},
-		func() error {
-			return m.trCollect.Run(ctx1)
+			select {
+			case <-ctx.Done():
+				m.drain(ctx1)
+			case <-ctx1.Done():
+			}
+			return nil
 		},
 		func() error {
-			return m.sgCollector.Run(ctx1)
+			return m.sgNet.Run(ctx1)
 		},
 		func() error {
-			return m.trMerge.Run(ctx1)
+			return m.nsTracer.Run(ctx1)
 		},
 		func() error {
-			return m.trSender.Run(ctx1)
+			return m.runTraceHub(ctx1)
 		},
 	}
+	if m.containers != nil {
+		ff = append(ff, func() error {
+			return m.containers.Run(ctx1)
+		})
+	}
 	errs := make([]error, len(ff))
 	_ = parallel.ExecAbstract(len(ff), int32(len(ff))-1, func(i int) error {
 		defer cancel()
@@ -294,15 +477,11 @@ func (m *mainJob) run(ctx context.Context) error {
 }
 
 func runJobs(ctx context.Context) (err error) {
-	defer func() {
-		app.SetHealthState(false)
-	}()
-
+	// health of the agent is made of health of its components
 	var jb mainJob
 	if err = jb.init(ctx); err != nil {
 		return err
 	}
-	app.SetHealthState(true)
 	if err = jb.run(ctx); err != nil {
 		return err
 	}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/wildberries-tech/pkt-tracer/internal/api/tracehub"
 	"github.com/wildberries-tech/pkt-tracer/internal/app"
 	. "github.com/wildberries-tech/pkt-tracer/internal/app/trace-hub" //nolint:revive
@@ -28,9 +30,18 @@ func main() {
 		config.WithSourceFile{FileName: ConfigFile},
 		config.WithDefValue{Key: AppLoggerLevel, Val: "DEBUG"},
 		config.WithDefValue{Key: MetricsEnable, Val: true},
+		config.WithDefValue{Key: TrafficMetricsEnable, Val: false},
+		config.WithDefValue{Key: TrafficMetricsLabels, Val: "agent,table,chain,verdict,proto,sg_src,sg_dst"},
+		config.WithDefValue{Key: TrafficMetricsMaxSeries, Val: 10000},
 		config.WithDefValue{Key: HealthcheckEnable, Val: true},
 		config.WithDefValue{Key: ServerGracefulShutdown, Val: "10s"},
 		config.WithDefValue{Key: ServerEndpoint, Val: "tcp://127.0.0.1:9000"},
+		config.WithDefValue{Key: AgentsLivenessTimeout, Val: "30s"},
+		config.WithDefValue{Key: AgentsRetention, Val: "24h"},
+		config.WithDefValue{Key: AlertsRepeatInterval, Val: "5m"},
+		config.WithDefValue{Key: AlertsWebhookTimeout, Val: "5s"},
+		config.WithDefValue{Key: AlertsWebhookAllowedSchemes, Val: "https,http"},
+		config.WithDefValue{Key: AlertsWebhookAllowedHosts, Val: ""},
 		config.WithDefValue{Key: StorageType, Val: "clickhouse"},
 		config.WithDefValue{Key: ClickHouseDSN, Val: "tcp://localhost:19000/swarm?max_execution_time=60&dial_timeout=10s&client_info_product=trace-hub/0.0.1&compress=lz4&block_buffer_size=10&max_compression_buffer=10240&skip_verify=true"},
 		config.WithDefValue{Key: ClickMaxRowsInBatch, Val: 10000},
@@ -49,9 +60,17 @@ func main() {
 
 	ServerSubject().ObserversAttach(
 		observer.NewObserver(serverMetricsObserver, false,
-			tracehub.CountTraceEvent{}, registry.CountDBWriteEvent{}),
+			tracehub.CountTraceEvent{}, tracehub.TracesEvent{}, registry.CountDBWriteEvent{}),
 	)
 
+	cfgWatcher := config.NewReloadWatcher(ConfigFile)
+	go func() {
+		if e := cfgWatcher.Run(ctx); e != nil && !errors.Is(e, context.Canceled) {
+			logger.Errorf(ctx, "config will not be reloaded: %v", e)
+		}
+	}()
+	defer cfgWatcher.Close() //nolint:errcheck
+
 	var ep *pkgNet.Endpoint
 	_, err = ServerEndpoint.Value(ctx, ServerEndpoint.OptSink(func(v string) error {
 		var e error
@@ -66,6 +85,15 @@ func main() {
 	if err = SetupRegistry(); err != nil {
 		logger.Fatal(ctx, errors.WithMessage(err, "on opening db storade"))
 	}
+	if err = SetupAlerts(ctx); err != nil {
+		logger.Fatal(ctx, errors.WithMessage(err, "setup alerts"))
+	}
+	go func() {
+		if e := AppAlerts().Run(ctx); e != nil && !errors.Is(e, context.Canceled) {
+			logger.Errorf(ctx, "alerts will not be evaluated: %v", e)
+		}
+	}()
+	defer AppAlerts().Close() //nolint:errcheck
 	var srv *server.APIServer
 	if srv, err = SetupTraceHubServer(ctx); err != nil {
 		logger.Fatalf(ctx, "setup server: %v", err)
@@ -85,6 +113,8 @@ func serverMetricsObserver(ev observer.EventType) {
 		switch o := ev.(type) {
 		case tracehub.CountTraceEvent:
 			metrics.ObserveTracesCounter()
+		case tracehub.TracesEvent:
+			metrics.ObserveTraffic(o.Traces)
 		case registry.CountDBWriteEvent:
 			metrics.ObserveDBWriteCounter(o.Cnt)
 		}
@@ -0,0 +1,142 @@
+package agents
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/agent"
+)
+
+// rateWindow - time constant of the exponentially decayed trace rate
+const rateWindow = time.Minute
+
+type (
+	// Registry - in-memory inventory of the agents which stream traces to the server
+	Registry struct {
+		mu              sync.Mutex
+		agents          map[string]*agentState
+		livenessTimeout time.Duration
+		retention       time.Duration
+		now             func() time.Time
+	}
+
+	// Session - connection of the agent to the server
+	Session struct {
+		reg  *Registry
+		id   string
+		once sync.Once
+	}
+
+	agentState struct {
+		model.AgentModel
+		streams int
+		rateAt  time.Time
+	}
+)
+
+// NewRegistry creates agents registry.
+// Agent is considered offline when it has no connected streams or it has not been seen
+// during 'livenessTimeout'. Offline agents are forgotten after 'retention'.
+func NewRegistry(livenessTimeout, retention time.Duration) *Registry {
+	return &Registry{
+		agents:          make(map[string]*agentState),
+		livenessTimeout: livenessTimeout,
+		retention:       retention,
+		now:             time.Now,
+	}
+}
+
+// Connect registers new connection of the agent
+func (r *Registry) Connect(id, remoteAddr string, info model.AgentInfo) *Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	st := r.agents[id]
+	if st == nil {
+		st = &agentState{rateAt: now}
+		st.Id = id
+		r.agents[id] = st
+	}
+	st.AgentInfo = info
+	st.RemoteAddr = remoteAddr
+	st.ConnectedAt = now
+	st.LastSeenAt = now
+	st.streams++
+	return &Session{reg: r, id: id}
+}
+
+// Heartbeat marks the agent as alive
+func (s *Session) Heartbeat() {
+	s.Traces(0)
+}
+
+// Traces accounts received traces and marks the agent as alive
+func (s *Session) Traces(cnt int) {
+	r := s.reg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := r.agents[s.id]
+	if st == nil {
+		return
+	}
+	now := r.now()
+	st.LastSeenAt = now
+	st.decayRate(now)
+	if cnt > 0 {
+		st.LastTraceAt = now
+		st.TracesTotal += uint64(cnt)
+		st.TraceRate += float64(cnt) / rateWindow.Seconds()
+	}
+}
+
+// Disconnect unregisters connection of the agent
+func (s *Session) Disconnect() {
+	s.once.Do(func() {
+		r := s.reg
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if st := r.agents[s.id]; st != nil && st.streams > 0 {
+			st.streams--
+		}
+	})
+}
+
+// List returns agents matched to the scope sorted by id
+func (r *Registry) List(scope model.AgentScopeModel) []model.AgentModel {
+	ids := make(map[string]struct{}, len(scope.AgentsIds))
+	for _, id := range scope.AgentsIds {
+		ids[id] = struct{}{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	ret := make([]model.AgentModel, 0, len(r.agents))
+	for id, st := range r.agents {
+		st.decayRate(now)
+		st.Online = st.streams > 0 && now.Sub(st.LastSeenAt) <= r.livenessTimeout
+		if !st.Online && r.retention > 0 && now.Sub(st.LastSeenAt) > r.retention {
+			delete(r.agents, id)
+			continue
+		}
+		if _, ok := ids[id]; len(ids) > 0 && !ok {
+			continue
+		}
+		if scope.OnlineOnly && !st.Online {
+			continue
+		}
+		ret = append(ret, st.AgentModel)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Id < ret[j].Id
+	})
+	return ret
+}
+
+func (st *agentState) decayRate(now time.Time) {
+	if dt := now.Sub(st.rateAt); dt > 0 {
+		st.TraceRate *= math.Exp(-dt.Seconds() / rateWindow.Seconds())
+		st.rateAt = now
+	}
+}
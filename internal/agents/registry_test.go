@@ -0,0 +1,75 @@
+package agents
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/agent"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type agentsRegistryTestSuite struct {
+	suite.Suite
+	reg *Registry
+	now time.Time
+}
+
+func Test_AgentsRegistry(t *testing.T) {
+	suite.Run(t, new(agentsRegistryTestSuite))
+}
+
+func (sui *agentsRegistryTestSuite) SetupTest() {
+	sui.now = time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	sui.reg = NewRegistry(30*time.Second, time.Hour)
+	sui.reg.now = func() time.Time { return sui.now }
+}
+
+func (sui *agentsRegistryTestSuite) Test_Liveness() {
+	info := model.AgentInfo{Version: "1.0.0", Hostname: "host1", Kernel: "6.1.0"}
+	s := sui.reg.Connect("tracer1", "10.0.0.1:5000", info)
+	sui.reg.Connect("tracer2", "10.0.0.2:5000", model.AgentInfo{}).Disconnect()
+
+	ags := sui.reg.List(model.AgentScopeModel{})
+	sui.Require().Len(ags, 2)
+	sui.Require().Equal("tracer1", ags[0].Id)
+	sui.Require().Equal(info, ags[0].AgentInfo)
+	sui.Require().Equal("10.0.0.1:5000", ags[0].RemoteAddr)
+	sui.Require().True(ags[0].Online)
+	sui.Require().False(ags[1].Online)
+
+	ags = sui.reg.List(model.AgentScopeModel{OnlineOnly: true})
+	sui.Require().Len(ags, 1)
+	sui.Require().Equal("tracer1", ags[0].Id)
+
+	sui.now = sui.now.Add(time.Minute)
+	sui.Require().Empty(sui.reg.List(model.AgentScopeModel{OnlineOnly: true}))
+	s.Heartbeat()
+	sui.Require().Len(sui.reg.List(model.AgentScopeModel{OnlineOnly: true}), 1)
+
+	s.Disconnect()
+	s.Disconnect()
+	ags = sui.reg.List(model.AgentScopeModel{AgentsIds: []string{"tracer1"}})
+	sui.Require().Len(ags, 1)
+	sui.Require().False(ags[0].Online)
+
+	sui.now = sui.now.Add(2 * time.Hour)
+	sui.Require().Empty(sui.reg.List(model.AgentScopeModel{}))
+}
+
+func (sui *agentsRegistryTestSuite) Test_TraceRate() {
+	s := sui.reg.Connect("tracer1", "", model.AgentInfo{})
+	for i := 0; i < 60; i++ {
+		sui.now = sui.now.Add(time.Second)
+		s.Traces(100)
+	}
+	ags := sui.reg.List(model.AgentScopeModel{})
+	sui.Require().Len(ags, 1)
+	sui.Require().Equal(uint64(6000), ags[0].TracesTotal)
+	sui.Require().Equal(sui.now, ags[0].LastTraceAt)
+	sui.Require().InDelta(60, ags[0].TraceRate, 10)
+
+	sui.now = sui.now.Add(10 * time.Minute)
+	ags = sui.reg.List(model.AgentScopeModel{})
+	sui.Require().Less(ags[0].TraceRate, 0.1)
+}
@@ -0,0 +1,431 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/alert"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	"github.com/wildberries-tech/pkt-tracer/pkg/tracequery"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxSamples - number of the last matched traces sent with the notification
+	maxSamples = 10
+	// evalInterval - interval the thresholds of the rules are checked with
+	evalInterval = time.Second
+	// notifyQueueSize - max number of notifications waiting for delivery
+	notifyQueueSize = 256
+)
+
+// statuses of the notification
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+type (
+	// Notification - payload of the webhook call
+	Notification struct {
+		// Rule - name of the alert rule
+		Rule string `json:"rule"`
+		// Status - firing/resolved
+		Status string `json:"status"`
+		// Fingerprint - identity of the firing, it is the same for repeats and resolving of the alert
+		Fingerprint string `json:"fingerprint"`
+		Query       string `json:"query"`
+		Threshold   uint64 `json:"threshold"`
+		Window      string `json:"window"`
+		// Count - number of the traces matched within the window
+		Count       uint64    `json:"count"`
+		FiringSince time.Time `json:"firing_since"`
+		At          time.Time `json:"at"`
+		// Samples - the last traces matched by the rule
+		Samples []trace.TraceModel `json:"samples,omitempty"`
+	}
+
+	// Notifier - delivers notifications to the webhook
+	Notifier interface {
+		Notify(ctx context.Context, url string, n Notification) error
+	}
+
+	// Engine - evaluates alert rules on the traces coming to the server
+	Engine struct {
+		mu         sync.RWMutex
+		rules      map[string]*ruleState
+		defWebhook string
+		webhooks   WebhookAllowlist
+		defRepeat  time.Duration
+		notifier   Notifier
+		queue      chan delivery
+		now        func() time.Time
+		onceRun    sync.Once
+		onceClose  sync.Once
+		stop       chan struct{}
+		stopped    chan struct{}
+	}
+
+	// EngineOpt - option of the engine
+	EngineOpt func(*Engine)
+
+	ruleState struct {
+		mu         sync.Mutex
+		rule       model.AlertRuleModel
+		source     string
+		match      tracequery.Matcher[*trace.TraceModel]
+		win        window
+		samples    []trace.TraceModel // ring of the last matched traces
+		nSamples   int
+		firing     bool
+		since      time.Time
+		notifiedAt time.Time
+	}
+
+	delivery struct {
+		url string
+		n   Notification
+	}
+)
+
+// WithDefaultWebhook - webhook of the rules which have no own one
+func WithDefaultWebhook(url string) EngineOpt {
+	return func(e *Engine) {
+		e.defWebhook = url
+	}
+}
+
+// WithWebhookAllowlist - webhooks alert rules of the API may have besides the default one,
+// rules of the API may have no own webhook unless it is set
+func WithWebhookAllowlist(a WebhookAllowlist) EngineOpt {
+	return func(e *Engine) {
+		e.webhooks = a
+	}
+}
+
+// WithRepeatInterval - min interval of repeated notifications of the rules which have no own one
+func WithRepeatInterval(d time.Duration) EngineOpt {
+	return func(e *Engine) {
+		e.defRepeat = d
+	}
+}
+
+// WithNotifier - deliverer of the notifications
+func WithNotifier(n Notifier) EngineOpt {
+	return func(e *Engine) {
+		e.notifier = n
+	}
+}
+
+// NewEngine creates engine of the alert rules, notifications are delivered by webhooks
+func NewEngine(opts ...EngineOpt) *Engine {
+	e := &Engine{
+		rules:     make(map[string]*ruleState),
+		defRepeat: 5 * time.Minute,
+		notifier:  NewWebhookNotifier(5 * time.Second),
+		queue:     make(chan delivery, notifyQueueSize),
+		now:       time.Now,
+		stop:      make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(e)
+	}
+	return e
+}
+
+// SetConfigRules replaces rules defined in config, rules defined via API win over them
+func (e *Engine) SetConfigRules(rules []model.AlertRuleModel) error {
+	states, err := e.compile(rules, model.SourceConfig)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for name, st := range e.rules {
+		if _, ok := states[name]; st.source == model.SourceConfig && !ok {
+			delete(e.rules, name)
+		}
+	}
+	for name, st := range states {
+		if cur := e.rules[name]; cur == nil || cur.source == model.SourceConfig {
+			e.put(st)
+		}
+	}
+	return nil
+}
+
+// Upsert adds or replaces rules defined via API, webhooks of the rules are checked by the allowlist
+func (e *Engine) Upsert(rules []model.AlertRuleModel) error {
+	for _, r := range rules {
+		if r.Webhook == "" || r.Webhook == e.defWebhook {
+			continue
+		}
+		if err := e.webhooks.Check(r.Webhook); err != nil {
+			return ErrAlerts{Err: errors.WithMessagef(err, "rule '%s'", r.Name)}
+		}
+	}
+	states, err := e.compile(rules, model.SourceApi)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, st := range states {
+		e.put(st)
+	}
+	return nil
+}
+
+// Delete deletes rules by names
+func (e *Engine) Delete(names []string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, name := range names {
+		delete(e.rules, name)
+	}
+}
+
+// Observe accounts traces arrived to the server
+func (e *Engine) Observe(traces []*trace.TraceModel) {
+	if len(traces) == 0 {
+		return
+	}
+	now := e.now()
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, st := range e.rules {
+		st.observe(now, traces)
+	}
+}
+
+// List returns state of the alerts sorted by names of the rules
+func (e *Engine) List(scope model.AlertScopeModel) []model.AlertModel {
+	now := e.now()
+	e.mu.RLock()
+	ret := make([]model.AlertModel, 0, len(e.rules))
+	for _, st := range e.rules {
+		st.mu.Lock()
+		if !scope.FiringOnly || st.firing {
+			ret = append(ret, model.AlertModel{
+				Rule:        st.rule,
+				Source:      st.source,
+				Firing:      st.firing,
+				Count:       st.win.count(now),
+				FiringSince: st.since,
+				NotifiedAt:  st.notifiedAt,
+			})
+		}
+		st.mu.Unlock()
+	}
+	e.mu.RUnlock()
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Rule.Name < ret[j].Rule.Name
+	})
+	return ret
+}
+
+// Run checks thresholds of the rules and delivers notifications
+func (e *Engine) Run(ctx context.Context) error {
+	var doRun bool
+	e.onceRun.Do(func() {
+		doRun = true
+		e.stopped = make(chan struct{})
+	})
+	if !doRun {
+		return ErrAlerts{Err: errors.New("it has been run or closed yet")}
+	}
+	log := logger.FromContext(ctx).Named("alerts")
+	log.Info("start")
+	defer func() {
+		log.Info("stop")
+		close(e.stopped)
+	}()
+
+	var wg sync.WaitGroup
+	sendCtx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-sendCtx.Done():
+				return
+			case d := <-e.queue:
+				if err := e.notifier.Notify(sendCtx, d.url, d.n); err != nil {
+					log.Warnf("failed to notify '%s' alert of rule '%s': %v", d.n.Status, d.n.Rule, err)
+				}
+			}
+		}
+	}()
+
+	tc := time.NewTicker(evalInterval)
+	defer tc.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("will exit cause ctx canceled")
+			return ctx.Err()
+		case <-e.stop:
+			log.Info("will exit cause it has closed")
+			return nil
+		case <-tc.C:
+			e.eval(log)
+		}
+	}
+}
+
+// Close -
+func (e *Engine) Close() error {
+	e.onceClose.Do(func() {
+		close(e.stop)
+		e.onceRun.Do(func() {})
+		if e.stopped != nil {
+			<-e.stopped
+		}
+	})
+	return nil
+}
+
+// eval checks thresholds of the rules and enqueues notifications of the changed alerts
+func (e *Engine) eval(log logger.TypeOfLogger) {
+	now := e.now()
+	var out []delivery
+	e.mu.RLock()
+	for _, st := range e.rules {
+		if d, ok := st.eval(now, e.defWebhook, e.defRepeat); ok {
+			out = append(out, d)
+		}
+	}
+	e.mu.RUnlock()
+	for _, d := range out {
+		if d.url == "" {
+			log.Debugf("alert of rule '%s' is %s, no webhook to notify", d.n.Rule, d.n.Status)
+			continue
+		}
+		select {
+		case e.queue <- d:
+		default:
+			log.Warnf("notification of '%s' alert of rule '%s' is dropped cause the queue is full", d.n.Status, d.n.Rule)
+		}
+	}
+}
+
+func (e *Engine) compile(rules []model.AlertRuleModel, source string) (map[string]*ruleState, error) {
+	ret := make(map[string]*ruleState, len(rules))
+	for i, r := range rules {
+		if r.Name == "" {
+			return nil, ErrAlerts{Err: errors.Errorf("rule #%d has no name", i+1)}
+		}
+		if _, dup := ret[r.Name]; dup {
+			return nil, ErrAlerts{Err: errors.Errorf("rule '%s' is duplicated", r.Name)}
+		}
+		if r.Window < time.Second {
+			return nil, ErrAlerts{Err: errors.Errorf("rule '%s': window (%v) is less than 1s", r.Name, r.Window)}
+		}
+		m, err := trace.NewQueryMatcher(r.Query)
+		if err != nil {
+			return nil, ErrAlerts{Err: errors.WithMessagef(err, "rule '%s': bad query", r.Name)}
+		}
+		ret[r.Name] = &ruleState{
+			rule:    r,
+			source:  source,
+			match:   m,
+			win:     newWindow(r.Window),
+			samples: make([]trace.TraceModel, maxSamples),
+		}
+	}
+	return ret, nil
+}
+
+// put adds the rule, state of the alert is kept when the rule has not changed
+func (e *Engine) put(st *ruleState) {
+	if cur := e.rules[st.rule.Name]; cur != nil && cur.rule == st.rule {
+		cur.source = st.source
+		return
+	}
+	e.rules[st.rule.Name] = st
+}
+
+func (st *ruleState) observe(now time.Time, traces []*trace.TraceModel) {
+	var cnt uint64
+	var matched []*trace.TraceModel
+	for _, tr := range traces {
+		if st.match(tr) {
+			cnt++
+			if matched = append(matched, tr); len(matched) > maxSamples {
+				matched = matched[1:]
+			}
+		}
+	}
+	if cnt == 0 {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.win.add(now, cnt)
+	for _, tr := range matched {
+		st.samples[st.nSamples%maxSamples] = *tr
+		st.nSamples++
+	}
+}
+
+func (st *ruleState) eval(now time.Time, defWebhook string, defRepeat time.Duration) (d delivery, ok bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	cnt := st.win.count(now)
+	repeat := st.rule.RepeatInterval
+	if repeat == 0 {
+		repeat = defRepeat
+	}
+	var status string
+	switch {
+	case cnt > st.rule.Threshold && !st.firing:
+		st.firing, st.since, status = true, now, StatusFiring
+	case cnt > st.rule.Threshold && now.Sub(st.notifiedAt) >= repeat:
+		status = StatusFiring
+	case cnt <= st.rule.Threshold && st.firing:
+		st.firing, status = false, StatusResolved
+	default:
+		return d, false
+	}
+	st.notifiedAt = now
+	d.url = st.rule.Webhook
+	if d.url == "" {
+		d.url = defWebhook
+	}
+	d.n = Notification{
+		Rule:        st.rule.Name,
+		Status:      status,
+		Fingerprint: fmt.Sprintf("%s@%d", st.rule.Name, st.since.Unix()),
+		Query:       st.rule.Query,
+		Threshold:   st.rule.Threshold,
+		Window:      st.rule.Window.String(),
+		Count:       cnt,
+		FiringSince: st.since,
+		At:          now,
+		Samples:     st.lastSamples(),
+	}
+	if status == StatusResolved {
+		st.since = time.Time{}
+	}
+	return d, true
+}
+
+// lastSamples returns the last matched traces in order they have arrived
+func (st *ruleState) lastSamples() []trace.TraceModel {
+	n := min(st.nSamples, maxSamples)
+	ret := make([]trace.TraceModel, 0, n)
+	for i := st.nSamples - n; i < st.nSamples; i++ {
+		ret = append(ret, st.samples[i%maxSamples])
+	}
+	return ret
+}
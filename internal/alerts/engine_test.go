@@ -0,0 +1,225 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/alert"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/stretchr/testify/suite"
+)
+
+type alertsTestSuite struct {
+	suite.Suite
+}
+
+func Test_Alerts(t *testing.T) {
+	suite.Run(t, new(alertsTestSuite))
+}
+
+func drops(n int, from, to string) []*trace.TraceModel {
+	ret := make([]*trace.TraceModel, n)
+	for i := range ret {
+		ret[i] = &trace.TraceModel{TrId: uint32(i), SSgName: from, DSgName: to, Verdict: "rule::drop"}
+	}
+	return ret
+}
+
+func (sui *alertsTestSuite) Test_Window() {
+	t0 := time.Unix(1700000000, 0)
+	w := newWindow(time.Minute)
+	w.add(t0, 5)
+	w.add(t0.Add(30*time.Second), 3)
+	sui.Require().Equal(uint64(8), w.count(t0.Add(30*time.Second)))
+	sui.Require().Equal(uint64(3), w.count(t0.Add(60*time.Second)))
+	sui.Require().Equal(uint64(0), w.count(t0.Add(90*time.Second)))
+	w.add(t0.Add(120*time.Second), 1)
+	sui.Require().Equal(uint64(1), w.count(t0.Add(120*time.Second)))
+}
+
+func (sui *alertsTestSuite) Test_FiringAndResolving() {
+	now := time.Unix(1700000000, 0)
+	e := NewEngine(WithDefaultWebhook("http://hook"), WithRepeatInterval(time.Minute))
+	e.now = func() time.Time { return now }
+	err := e.SetConfigRules([]model.AlertRuleModel{{
+		Name:      "a-b-drops",
+		Query:     `sg-src == "sg-a" and sg-dst == "sg-b" and verdict in ("rule::drop", "policy::drop")`,
+		Threshold: 100,
+		Window:    time.Minute,
+	}})
+	sui.Require().NoError(err)
+	log := logger.FromContext(context.Background())
+	next := func() *delivery {
+		select {
+		case d := <-e.queue:
+			return &d
+		default:
+			return nil
+		}
+	}
+
+	e.Observe(drops(60, "sg-a", "sg-b"))
+	e.Observe(drops(60, "sg-a", "sg-c"))
+	e.eval(log)
+	sui.Require().Nil(next())
+
+	now = now.Add(10 * time.Second)
+	e.Observe(drops(50, "sg-a", "sg-b"))
+	e.eval(log)
+	d := next()
+	sui.Require().NotNil(d)
+	sui.Require().Equal("http://hook", d.url)
+	sui.Require().Equal(StatusFiring, d.n.Status)
+	sui.Require().Equal(uint64(110), d.n.Count)
+	sui.Require().Len(d.n.Samples, maxSamples)
+	sui.Require().Equal(uint32(49), d.n.Samples[maxSamples-1].TrId)
+	fingerprint := d.n.Fingerprint
+
+	// firing alert is not repeated before the repeat interval
+	now = now.Add(5 * time.Second)
+	e.eval(log)
+	sui.Require().Nil(next())
+	alerts := e.List(model.AlertScopeModel{FiringOnly: true})
+	sui.Require().Len(alerts, 1)
+	sui.Require().Equal(model.SourceConfig, alerts[0].Source)
+	sui.Require().Equal(uint64(110), alerts[0].Count)
+
+	// the first batch leaves the window
+	now = now.Add(50 * time.Second)
+	e.eval(log)
+	d = next()
+	sui.Require().NotNil(d)
+	sui.Require().Equal(StatusResolved, d.n.Status)
+	sui.Require().Equal(fingerprint, d.n.Fingerprint)
+	sui.Require().Empty(e.List(model.AlertScopeModel{FiringOnly: true}))
+}
+
+func (sui *alertsTestSuite) Test_Repeat() {
+	now := time.Unix(1700000000, 0)
+	webhooks, err := ParseWebhookAllowlist("", "own")
+	sui.Require().NoError(err)
+	e := NewEngine(WithDefaultWebhook("http://hook"), WithWebhookAllowlist(webhooks))
+	e.now = func() time.Time { return now }
+	sui.Require().NoError(e.Upsert([]model.AlertRuleModel{{
+		Name: "any", Threshold: 0, Window: time.Hour, RepeatInterval: time.Minute, Webhook: "http://own",
+	}}))
+	log := logger.FromContext(context.Background())
+	e.Observe(drops(1, "", ""))
+	e.eval(log)
+	d := <-e.queue
+	sui.Require().Equal("http://own", d.url)
+	now = now.Add(30 * time.Second)
+	e.eval(log)
+	sui.Require().Len(e.queue, 0)
+	now = now.Add(30 * time.Second)
+	e.eval(log)
+	d = <-e.queue
+	sui.Require().Equal(StatusFiring, d.n.Status)
+}
+
+func (sui *alertsTestSuite) Test_Rules() {
+	e := NewEngine()
+	sui.Require().Error(e.Upsert([]model.AlertRuleModel{{Name: "", Window: time.Minute}}))
+	sui.Require().Error(e.Upsert([]model.AlertRuleModel{{Name: "a", Window: time.Millisecond}}))
+	sui.Require().Error(e.Upsert([]model.AlertRuleModel{{Name: "a", Window: time.Minute, Query: "bad =="}}))
+	sui.Require().Error(e.Upsert([]model.AlertRuleModel{
+		{Name: "a", Window: time.Minute}, {Name: "a", Window: time.Minute},
+	}))
+
+	sui.Require().NoError(e.SetConfigRules([]model.AlertRuleModel{
+		{Name: "a", Window: time.Minute, Threshold: 1},
+		{Name: "b", Window: time.Minute, Threshold: 1},
+	}))
+	sui.Require().NoError(e.Upsert([]model.AlertRuleModel{{Name: "b", Window: time.Minute, Threshold: 2}}))
+	// config rules are replaced but API ones win
+	sui.Require().NoError(e.SetConfigRules([]model.AlertRuleModel{
+		{Name: "b", Window: time.Minute, Threshold: 3},
+		{Name: "c", Window: time.Minute, Threshold: 1},
+	}))
+	alerts := e.List(model.AlertScopeModel{})
+	sui.Require().Len(alerts, 2)
+	sui.Require().Equal("b", alerts[0].Rule.Name)
+	sui.Require().Equal(model.SourceApi, alerts[0].Source)
+	sui.Require().Equal(uint64(2), alerts[0].Rule.Threshold)
+	sui.Require().Equal("c", alerts[1].Rule.Name)
+
+	e.Delete([]string{"b", "unknown"})
+	alerts = e.List(model.AlertScopeModel{})
+	sui.Require().Len(alerts, 1)
+	sui.Require().Equal("c", alerts[0].Rule.Name)
+}
+
+func (sui *alertsTestSuite) Test_Webhook() {
+	got := make(chan Notification, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var n Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got <- n
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(time.Second)
+	err := n.Notify(context.Background(), srv.URL, Notification{Rule: "r", Status: StatusFiring})
+	sui.Require().NoError(err)
+	sui.Require().Equal("r", (<-got).Rule)
+	err = n.Notify(context.Background(), srv.URL+"/fail", Notification{})
+	sui.Require().Error(err)
+}
+
+func (sui *alertsTestSuite) Test_WebhookAllowlist() {
+	webhooks, err := ParseWebhookAllowlist("https", "alertmanager, *.hooks.example.com")
+	sui.Require().NoError(err)
+	e := NewEngine(WithDefaultWebhook("http://default/hook"), WithWebhookAllowlist(webhooks))
+	rule := func(hook string) []model.AlertRuleModel {
+		return []model.AlertRuleModel{{Name: "r", Window: time.Minute, Webhook: hook}}
+	}
+	sui.Require().NoError(e.Upsert(rule("")))
+	sui.Require().NoError(e.Upsert(rule("http://default/hook")))
+	sui.Require().NoError(e.Upsert(rule("https://alertmanager:9093/hook")))
+	sui.Require().NoError(e.Upsert(rule("https://team.hooks.example.com/a")))
+	for _, hook := range []string{
+		"http://alertmanager/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://localhost:9000/v1/alerts/rules/upsert",
+		"https://hooks.example.com.evil.io/a",
+		"file:///etc/passwd",
+		"https://",
+	} {
+		sui.Require().Error(e.Upsert(rule(hook)), hook)
+	}
+	// config rules are trusted
+	sui.Require().NoError(e.SetConfigRules(rule("http://localhost/hook")))
+
+	// nothing but the default webhook is allowed unless hosts are set
+	e = NewEngine(WithDefaultWebhook("http://default/hook"))
+	sui.Require().Error(e.Upsert(rule("https://alertmanager/hook")))
+	_, err = ParseWebhookAllowlist("", "bad[")
+	sui.Require().Error(err)
+}
+
+func (sui *alertsTestSuite) Test_WebhookNoRedirect() {
+	redirected := make(chan struct{}, 1)
+	target := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		redirected <- struct{}{}
+	}))
+	defer target.Close()
+	srv := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer srv.Close()
+
+	err := NewWebhookNotifier(time.Second).Notify(context.Background(), srv.URL, Notification{})
+	sui.Require().Error(err)
+	sui.Require().Len(redirected, 0)
+}
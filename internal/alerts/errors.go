@@ -0,0 +1,20 @@
+package alerts
+
+import (
+	"fmt"
+)
+
+// ErrAlerts -
+type ErrAlerts struct {
+	Err error
+}
+
+// Error -
+func (e ErrAlerts) Error() string {
+	return fmt.Sprintf("Alerts: %v", e.Err)
+}
+
+// Cause -
+func (e ErrAlerts) Cause() error {
+	return e.Err
+}
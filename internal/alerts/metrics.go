@@ -0,0 +1,53 @@
+package alerts
+
+import (
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/alert"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	nsAlerts  = "alerts"
+	labelRule = "rule"
+)
+
+type collector struct {
+	e           *Engine
+	firingDesc  *prometheus.Desc
+	matchedDesc *prometheus.Desc
+}
+
+// NewCollector makes collector of the gauges of the alerts state
+func NewCollector(e *Engine, labels prometheus.Labels) prometheus.Collector {
+	return &collector{
+		e: e,
+		firingDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(nsAlerts, "", "firing"),
+			"alert of the rule is firing (1) or not (0)",
+			[]string{labelRule}, labels,
+		),
+		matchedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(nsAlerts, "", "window_traces"),
+			"number of the traces matched by the rule within its window",
+			[]string{labelRule}, labels,
+		),
+	}
+}
+
+// Describe -
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.firingDesc
+	ch <- c.matchedDesc
+}
+
+// Collect -
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	for _, a := range c.e.List(model.AlertScopeModel{}) {
+		var firing float64
+		if a.Firing {
+			firing = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.firingDesc, prometheus.GaugeValue, firing, a.Rule.Name)
+		ch <- prometheus.MustNewConstMetric(c.matchedDesc, prometheus.GaugeValue, float64(a.Count), a.Rule.Name)
+	}
+}
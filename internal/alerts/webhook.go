@@ -0,0 +1,106 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	webhookNotifier struct {
+		client *http.Client
+	}
+
+	// WebhookAllowlist - schemes and hosts of the webhooks alert rules of the API may have,
+	// hosts are matched without the port and may have shell wildcards like '*.example.com'
+	WebhookAllowlist struct {
+		Schemes []string
+		Hosts   []string
+	}
+)
+
+// NewWebhookNotifier makes notifier posting notifications as JSON to the webhook URL,
+// redirects are not followed so the notification never leaves the allowed hosts
+func NewWebhookNotifier(timeout time.Duration) Notifier {
+	return &webhookNotifier{client: &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// ParseWebhookAllowlist parses comma separated schemes and hosts, empty schemes are http and https
+func ParseWebhookAllowlist(schemes, hosts string) (ret WebhookAllowlist, err error) {
+	ret.Schemes = splitList(strings.ToLower(schemes))
+	if len(ret.Schemes) == 0 {
+		ret.Schemes = []string{"http", "https"}
+	}
+	ret.Hosts = splitList(strings.ToLower(hosts))
+	for _, h := range ret.Hosts {
+		if _, err = path.Match(h, ""); err != nil {
+			return ret, errors.WithMessagef(err, "bad webhook host '%s'", h)
+		}
+	}
+	return ret, nil
+}
+
+// Check checks the webhook is allowed
+func (a WebhookAllowlist) Check(webhook string) error {
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(a.Schemes, strings.ToLower(u.Scheme)) {
+		return errors.Errorf("scheme of the webhook '%s' is not allowed", webhook)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range a.Hosts {
+		if ok, _ := path.Match(h, host); ok && host != "" {
+			return nil
+		}
+	}
+	return errors.Errorf("host of the webhook '%s' is not allowed", webhook)
+}
+
+func splitList(s string) []string {
+	var ret []string
+	for _, x := range strings.Split(s, ",") {
+		if x = strings.TrimSpace(x); x != "" {
+			ret = append(ret, x)
+		}
+	}
+	return ret
+}
+
+// Notify -
+func (w *webhookNotifier) Notify(ctx context.Context, url string, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("webhook '%s' responded with status %s", url, resp.Status)
+	}
+	return nil
+}
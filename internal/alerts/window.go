@@ -0,0 +1,51 @@
+package alerts
+
+import (
+	"time"
+)
+
+// windowBuckets - max number of buckets the sliding window is split into
+const windowBuckets = 60
+
+// window - counts of the matched traces in the buckets of the sliding window, count of the window
+// is accurate to the bucket
+type window struct {
+	step    int64 // duration of the bucket in nanoseconds
+	buckets []uint64
+	slots   []int64 // time slot every bucket is counted for
+}
+
+func newWindow(d time.Duration) window {
+	n := int64(windowBuckets)
+	if s := int64(d / time.Second); s < n {
+		n = max(s, 1)
+	}
+	return window{
+		step:    max(int64(d)/n, 1),
+		buckets: make([]uint64, n),
+		slots:   make([]int64, n),
+	}
+}
+
+// add accounts 'cnt' traces matched at 't'
+func (w *window) add(t time.Time, cnt uint64) {
+	slot := t.UnixNano() / w.step
+	i := slot % int64(len(w.buckets))
+	if w.slots[i] != slot {
+		w.slots[i], w.buckets[i] = slot, 0
+	}
+	w.buckets[i] += cnt
+}
+
+// count returns number of the traces matched within the window ending at 't'
+func (w *window) count(t time.Time) uint64 {
+	slot := t.UnixNano() / w.step
+	n := int64(len(w.buckets))
+	var ret uint64
+	for i, s := range w.slots {
+		if d := slot - s; d >= 0 && d < n {
+			ret += w.buckets[i]
+		}
+	}
+	return ret
+}
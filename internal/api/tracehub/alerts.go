@@ -0,0 +1,43 @@
+package tracehub
+
+import (
+	"context"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/dto"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/alert"
+	pb "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (srv *thService) ListAlerts(_ context.Context, req *pb.ListAlertsReq) (*pb.AlertList, error) {
+	var scopeDto dto.AlertScopeDTO
+	scopeDto.InitFromProto(req)
+	resp := new(pb.AlertList)
+	for _, a := range srv.alerts.List(*scopeDto.ToModel()) {
+		var alertDto dto.AlertDTO
+		alertDto.InitFromModel(&a)
+		resp.Alerts = append(resp.Alerts, alertDto.ToProto())
+	}
+	return resp, nil
+}
+
+func (srv *thService) UpsertAlertRules(_ context.Context, req *pb.AlertRuleList) (*emptypb.Empty, error) {
+	rules := make([]model.AlertRuleModel, 0, len(req.GetRules()))
+	for _, r := range req.GetRules() {
+		var ruleDto dto.AlertRuleDTO
+		ruleDto.InitFromProto(r)
+		rules = append(rules, *ruleDto.ToModel())
+	}
+	if err := srv.alerts.Upsert(rules); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return new(emptypb.Empty), nil
+}
+
+func (srv *thService) DeleteAlertRules(_ context.Context, req *pb.DeleteAlertRulesReq) (*emptypb.Empty, error) {
+	srv.alerts.Delete(req.GetNames())
+	return new(emptypb.Empty), nil
+}
@@ -0,0 +1,43 @@
+package tracehub
+
+import (
+	"github.com/wildberries-tech/pkt-tracer/internal/dto"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	th "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defExportBatchSize = 1000
+	maxExportBatchSize = 10000
+)
+
+func (srv *thService) ExportTraces(req *th.ExportTracesReq, stream th.TraceHubService_ExportTracesServer) error {
+	var dtoTraceScope dto.TraceScopeDTO
+	dtoTraceScope.InitFromProto(req.GetScope())
+	flt := dtoTraceScope.ToModel()
+	if flt.FollowMode {
+		return status.Error(codes.InvalidArgument, "follow mode is not supported by the export")
+	}
+	batchSize := int(req.GetBatchSize())
+	if batchSize == 0 {
+		batchSize = defExportBatchSize
+	}
+	batchSize = min(batchSize, maxExportBatchSize)
+
+	rd, err := srv.reg.Reader(srv.appCtx)
+	if err != nil {
+		return err
+	}
+	return rd.StreamTraces(stream.Context(), flt, batchSize, func(traces []model.FetchTraceModel) error {
+		obj := th.TraceList{Traces: make([]*th.FetchTrace, 0, len(traces))}
+		for i := range traces {
+			var dtoTrace dto.FetchTraceDTO
+			dtoTrace.InitFromModel(&traces[i])
+			obj.Traces = append(obj.Traces, dtoTrace.ToProto())
+		}
+		return stream.Send(&obj)
+	})
+}
@@ -0,0 +1,86 @@
+package tracehub
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+
+	th "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"github.com/H-BF/corlib/server"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// MIMEEventStream content type of the server-sent events
+const MIMEEventStream = "text/event-stream"
+
+var (
+	jsonMarshalOptions   = protojson.MarshalOptions{EmitUnpopulated: true}
+	jsonUnmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+//go:embed tracehub.swagger.json
+var swaggerJSON []byte
+
+// RegisterProxyGW impl server.APIGatewayProxy
+func (srv *thService) RegisterProxyGW(ctx context.Context, mux *runtime.ServeMux, c *grpc.ClientConn) error {
+	return th.RegisterTraceHubServiceHandler(ctx, mux, c)
+}
+
+// GatewayOptions options of the HTTP/JSON gateway: streamed responses are
+// newline delimited JSON by default or server-sent events when client accepts 'text/event-stream',
+// both are marshaled by the same JSON options
+func GatewayOptions() []runtime.ServeMuxOption {
+	return []runtime.ServeMuxOption{
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
+			Marshaler: &runtime.JSONPb{
+				MarshalOptions:   jsonMarshalOptions,
+				UnmarshalOptions: jsonUnmarshalOptions,
+			},
+		}),
+		runtime.WithMarshalerOption(MIMEEventStream, &EventStreamMarshaler{
+			JSONPb: runtime.JSONPb{
+				MarshalOptions:   jsonMarshalOptions,
+				UnmarshalOptions: jsonUnmarshalOptions,
+			},
+		}),
+	}
+}
+
+// SwaggerDocs OpenAPI document of the HTTP/JSON gateway
+func SwaggerDocs() (*server.SwaggerSpec, error) {
+	ret := new(server.SwaggerSpec)
+	if err := json.Unmarshal(swaggerJSON, ret); err != nil {
+		return nil, errors.WithMessage(err, "failed to load swagger docs")
+	}
+	return ret, nil
+}
+
+// EventStreamMarshaler marshals every response message as the 'data' field of the server-sent event
+type EventStreamMarshaler struct {
+	runtime.JSONPb
+}
+
+var _ runtime.Delimited = (*EventStreamMarshaler)(nil)
+
+// ContentType impl runtime.Marshaler
+func (*EventStreamMarshaler) ContentType(_ any) string {
+	return MIMEEventStream
+}
+
+// Marshal impl runtime.Marshaler
+func (m *EventStreamMarshaler) Marshal(v any) ([]byte, error) {
+	data, err := m.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("data: "), data...), nil
+}
+
+// Delimiter impl runtime.Delimited
+func (*EventStreamMarshaler) Delimiter() []byte {
+	return []byte("\n\n")
+}
@@ -0,0 +1,59 @@
+package tracehub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	th "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/suite"
+)
+
+type gatewayTestSuite struct {
+	suite.Suite
+}
+
+func Test_Gateway(t *testing.T) {
+	suite.Run(t, new(gatewayTestSuite))
+}
+
+func (sui *gatewayTestSuite) marshalerOf(accept string) runtime.Marshaler {
+	mux := runtime.NewServeMux(GatewayOptions()...)
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces/fetch", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	_, m := runtime.MarshalerForRequest(mux, req)
+	return m
+}
+
+func (sui *gatewayTestSuite) Test_Marshalers() {
+	msg := map[string]any{"result": &th.TraceList{
+		Traces: []*th.FetchTrace{{AgentId: "agent1", Trace: &th.Trace{TrId: 5}}},
+	}}
+	ndjson := sui.marshalerOf("")
+	sse := sui.marshalerOf(MIMEEventStream)
+	sui.Require().Equal("application/json", ndjson.ContentType(nil))
+	sui.Require().Equal(MIMEEventStream, sse.ContentType(nil))
+
+	data, err := ndjson.Marshal(msg)
+	sui.Require().NoError(err)
+	event, err := sse.Marshal(msg)
+	sui.Require().NoError(err)
+	sui.Require().Equal("data: ", string(event[:len("data: ")]))
+	// both outputs are made with the same options: zero fields are emitted too
+	sui.Require().JSONEq(string(data), string(event[len("data: "):]))
+	sui.Require().Contains(string(data), `"verdict":""`)
+	sui.Require().Equal([]byte("\n\n"), sse.(runtime.Delimited).Delimiter())
+}
+
+func (sui *gatewayTestSuite) Test_SwaggerDocs() {
+	docs, err := SwaggerDocs()
+	sui.Require().NoError(err)
+	sui.Require().Equal("TraceHubService", docs.Info.Title)
+	for _, p := range []string{"/v1/traces/fetch", "/v1/traces/export", "/v1/nft-tables/fetch", "/v1/agents/list"} {
+		sui.Require().Contains(docs.Paths.Paths, p)
+	}
+}
@@ -0,0 +1,20 @@
+package tracehub
+
+import (
+	"context"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/dto"
+	pb "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+)
+
+func (srv *thService) ListAgents(_ context.Context, req *pb.ListAgentsReq) (*pb.AgentList, error) {
+	var scopeDto dto.AgentScopeDTO
+	scopeDto.InitFromProto(req)
+	resp := new(pb.AgentList)
+	for _, a := range srv.agents.List(*scopeDto.ToModel()) {
+		var agentDto dto.AgentDTO
+		agentDto.InitFromModel(&a)
+		resp.Agents = append(resp.Agents, agentDto.ToProto())
+	}
+	return resp, nil
+}
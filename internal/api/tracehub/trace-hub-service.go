@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/wildberries-tech/pkt-tracer/internal/agents"
+	"github.com/wildberries-tech/pkt-tracer/internal/alerts"
 	registry "github.com/wildberries-tech/pkt-tracer/internal/registry"
 	th "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
 
@@ -15,6 +17,8 @@ import (
 type thService struct {
 	appCtx            context.Context
 	reg               registry.Registry
+	agents            *agents.Registry
+	alerts            *alerts.Engine
 	serverSubject     observer.Subject
 	flushTimeInterval time.Duration
 	checkDBInterval   time.Duration
@@ -24,18 +28,23 @@ type thService struct {
 var (
 	_ th.TraceHubServiceServer = (*thService)(nil)
 	_ server.APIService        = (*thService)(nil)
+	_ server.APIGatewayProxy   = (*thService)(nil)
 )
 
 // NewTraceHubeService creates service
 func NewTraceHubeService(
 	ctx context.Context,
 	r registry.Registry,
+	ag *agents.Registry,
+	al *alerts.Engine,
 	subj observer.Subject,
 	flushTime time.Duration,
 	dbTime time.Duration) server.APIService {
 	return &thService{
 		appCtx:            ctx,
 		reg:               r,
+		agents:            ag,
+		alerts:            al,
 		serverSubject:     subj,
 		flushTimeInterval: flushTime,
 		checkDBInterval:   dbTime,
@@ -21,6 +21,12 @@ type CountTraceEvent struct {
 	observer.EventType
 }
 
+// TracesEvent - traces received from the agent
+type TracesEvent struct {
+	Traces []*model.TraceModel
+	observer.EventType
+}
+
 type TraceWriter interface {
 	PutTrace(*model.TraceModel) error
 	Flush() error
@@ -46,6 +52,11 @@ func (srv *thService) TraceStream(stream th.TraceHubService_TraceStreamServer) (
 	}
 	defer wr.Close()
 
+	var agentInfo dto.AgentInfoDTO
+	agentInfo.InitFromContext(ctxInc)
+	agentSession := srv.agents.Connect(agentInfo.AgentId(), agentInfo.RemoteAddr, *agentInfo.ToModel())
+	defer agentSession.Disconnect()
+
 	incoming := make(chan any, 1)
 	go func() {
 		defer close(incoming)
@@ -75,7 +86,12 @@ loop:
 				err = t
 			case *th.Traces:
 				traces := t.GetTraces()
+				agentSession.Traces(len(traces)) //empty batch means heartbeat
+				if len(traces) == 0 {
+					break
+				}
 				srv.serverSubject.Notify(CountTraceEvent{Cnt: len(traces)})
+				models := make([]*model.TraceModel, 0, len(traces))
 				for _, m := range traces {
 					var dtoTrace dto.TraceDTO
 					dtoTrace.InitFromProto(ctxInc, m)
@@ -84,7 +100,10 @@ loop:
 					if err != nil {
 						break
 					}
+					models = append(models, traceMd)
 				}
+				srv.serverSubject.Notify(TracesEvent{Traces: models})
+				srv.alerts.Observe(models)
 			}
 		case <-srv.appCtx.Done():
 			err = srv.appCtx.Err()
@@ -5,20 +5,45 @@ import (
 	"encoding/json"
 	"net/http"
 	"runtime"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	app_identity "github.com/H-BF/corlib/app/identity"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type (
-	// HcHandler -
+	// HcHandler - serves health of the app on '/' and health of its component on '/<component>'
 	HcHandler struct{}
+
+	// ComponentHealth - health of the component, it is healthy when all its instances are healthy
+	ComponentHealth struct {
+		Healthy   bool                      `json:"healthy"`
+		Instances map[string]InstanceHealth `json:"instances"`
+	}
+
+	// InstanceHealth - health of the component instance
+	InstanceHealth struct {
+		Healthy bool      `json:"healthy"`
+		Reason  string    `json:"reason,omitempty"`
+		Since   time.Time `json:"since"`
+	}
+
+	componentsHealth struct {
+		mu         sync.RWMutex
+		components map[string]map[string]InstanceHealth
+	}
 )
 
 var (
 	flagHealthy int32
 
+	components = componentsHealth{
+		components: make(map[string]map[string]InstanceHealth),
+	}
+
 	bldInfo = func() map[string]string {
 		ret := make(map[string]string)
 		bldItem := []struct {
@@ -51,6 +76,73 @@ func SetHealthState(state bool) {
 	atomic.StoreInt32(&flagHealthy, st)
 }
 
+// SetComponentHealth - instance of the component is healthy when err is nil,
+// app is healthy when all instances of its components are healthy
+func SetComponentHealth(component, instance string, err error) {
+	st := InstanceHealth{Healthy: err == nil}
+	if err != nil {
+		st.Reason = err.Error()
+	}
+	components.mu.Lock()
+	defer components.mu.Unlock()
+	inst := components.components[component]
+	if inst == nil {
+		inst = make(map[string]InstanceHealth)
+		components.components[component] = inst
+	}
+	if prev, ok := inst[instance]; ok && prev.Healthy == st.Healthy {
+		st.Since = prev.Since
+	} else {
+		st.Since = time.Now()
+	}
+	inst[instance] = st
+}
+
+// RemoveComponentHealth - instance of the component is not taken into account anymore
+func RemoveComponentHealth(component, instance string) {
+	components.mu.Lock()
+	defer components.mu.Unlock()
+	if inst := components.components[component]; inst != nil {
+		delete(inst, instance)
+		if len(inst) == 0 {
+			delete(components.components, component)
+		}
+	}
+}
+
+// GetComponentsHealth - health of the components of the app
+func GetComponentsHealth() map[string]ComponentHealth {
+	components.mu.RLock()
+	defer components.mu.RUnlock()
+	ret := make(map[string]ComponentHealth, len(components.components))
+	for name, inst := range components.components {
+		c := ComponentHealth{Healthy: true, Instances: make(map[string]InstanceHealth, len(inst))}
+		for id, st := range inst {
+			c.Instances[id] = st
+			c.Healthy = c.Healthy && st.Healthy
+		}
+		ret[name] = c
+	}
+	return ret
+}
+
+// IsHealthy - app is running and all its components are healthy,
+// app is running when it has set its state so or its components have reported their health
+func IsHealthy() bool {
+	running := atomic.AddInt32(&flagHealthy, 0) != 0
+	components.mu.RLock()
+	defer components.mu.RUnlock()
+	for _, inst := range components.components {
+		for _, st := range inst {
+			if !st.Healthy {
+				return false
+			}
+			running = true
+		}
+	}
+	return running
+}
+
 // NewHealthcheckMetric -
 func NewHealthcheckMetric(withAdditionalLabels prometheus.Labels) prometheus.Collector {
 	labs := make(map[string]string)
@@ -68,21 +160,46 @@ func NewHealthcheckMetric(withAdditionalLabels prometheus.Labels) prometheus.Col
 		ConstLabels: labs,
 	}
 	return prometheus.NewGaugeFunc(opts, func() float64 {
-		return float64(atomic.AddInt32(&flagHealthy, 0))
+		if IsHealthy() {
+			return 1
+		}
+		return 0
 	})
 }
 
 // ServeHTTP -
 func (HcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	nfo := struct {
-		App     any  `json:"app,omitempty"`
-		Healthy bool `json:"healthy"`
-	}{bldInfo, atomic.AddInt32(&flagHealthy, 0) != 0}
+	var (
+		nfo  any
+		code = http.StatusOK
+	)
+	if name := strings.Trim(r.URL.Path, "/"); name != "" {
+		c, ok := GetComponentsHealth()[name]
+		switch {
+		case !ok:
+			code = http.StatusNotFound
+			nfo = struct {
+				Error string `json:"error"`
+			}{"unknown component '" + name + "'"}
+		case !c.Healthy:
+			code = http.StatusServiceUnavailable
+			fallthrough
+		default:
+			nfo = c
+		}
+	} else {
+		nfo = struct {
+			App        any                        `json:"app,omitempty"`
+			Healthy    bool                       `json:"healthy"`
+			Components map[string]ComponentHealth `json:"components,omitempty"`
+		}{bldInfo, IsHealthy(), GetComponentsHealth()}
+	}
 	w.Header().Add("Content-Type", "application/json")
 	bt := bytes.NewBuffer(nil)
 	if e := json.NewEncoder(bt).Encode(nfo); e != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 	} else {
+		w.WriteHeader(code)
 		_, _ = w.Write(bt.Bytes())
 	}
 }
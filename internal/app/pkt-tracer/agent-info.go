@@ -0,0 +1,23 @@
+package pkttracer
+
+import (
+	"os"
+
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/agent"
+
+	app_identity "github.com/H-BF/corlib/app/identity"
+	"golang.org/x/sys/unix"
+)
+
+// AgentInfo describes the agent and its host for the trace-hub agents inventory
+func AgentInfo() model.AgentInfo {
+	info := model.AgentInfo{
+		Version: app_identity.Version,
+	}
+	info.Hostname, _ = os.Hostname()
+	var uts unix.Utsname
+	if unix.Uname(&uts) == nil {
+		info.Kernel = unix.ByteSliceToString(uts.Release[:])
+	}
+	return info
+}
@@ -21,6 +21,7 @@ extapi:
       address: tcp://127.0.0.1:9006
 	  use-compression: false
 	  sync-interval: 1s
+	  heartbeat-interval: 10s
 	sgroups:
       dial-duration: 3s #override default-connect-tmo
       address: tcp://127.0.0.1:9006
@@ -28,6 +29,53 @@ extapi:
         interval: 20s #mandatory
         push: true
 
+sgnet:
+  providers: "static,sgroups" #priority chain of providers of networks and security groups: sgroups/static/none
+  static:
+    file: /etc/pkt-tracer/networks.yaml #YAML or CSV list of CIDR, network name and security group
+    reload-interval: 10s
+  sgroups:
+    rule-scope: "main*" #tables or table/chain patterns made by sgroups, verdicts made elsewhere have no sgroups rule
+
+netns:
+  enable: true #trace all network namespaces of the host
+  rescan-interval: 5s
+  dir: /var/run/netns
+  proc-dir: /proc
+
+containers:
+  enable: true #attribute traces to containers
+  runtime-dir: /var/lib/docker/containers #state of the container runtime, empty to disable
+  mapping-file: /etc/pkt-tracer/containers.json #static mapping of interfaces to containers, empty to disable
+  reload-interval: 10s
+
+procs:
+  enable: true #attribute locally sent or received packets to processes owning sockets
+  cache-size: 4096 #connections resolved kept in cache per network namespace
+
+conntrack:
+  enable: true #attach conntrack state and NAT translation of the connection to traces
+
+trace:
+  source: nftrace #source of traces: nftrace/nflog/both
+  raw-headers: false #keep raw bytes of the packet headers in traces to export them as pcapng
+
+nflog:
+  groups: "0,1" #log groups to collect
+  copy-range: 128 #bytes of the packet copied to decode its headers
+
+nftrace:
+  filter:
+    enable: true #drop uninteresting nftrace messages in kernel by the socket filter
+    family: inet #family of the traced tables: ip/ip6/inet/arp/bridge/netdev, empty for any
+    tables: "filter,sg" #names of the traced tables, empty for any
+    types: "rule,policy" #trace types: rule/return/policy, empty for any; rule and policy are required to complete traces
+    #traces which lose messages by the filter (e.g. of other tables) are abandoned by the merger after 10s
+
+queue:
+  capacity: 65536 #max number of traces (netlink messages after collectors) in every queue between stages of the pipeline, 0 for unbounded
+  overflow: drop-oldest #what to drop when queue is full: drop-oldest/drop-newest/prefer-drops
+
 telemetry:
   useragent: "string"
   endpoint: 127.0.0.1:5000
@@ -39,7 +87,7 @@ telemetry:
 */
 
 const (
-	// AppGracefulShutdown [optional]
+	// AppGracefulShutdown time to deliver traces collected yet on shutdown [optional]
 	AppGracefulShutdown config.ValueT[time.Duration] = "graceful-schutdown"
 
 	// LoggerLevel log level
@@ -60,6 +108,9 @@ const (
 	// TableSyncInterval time interval to update new state of nftables on server
 	TableSyncInterval config.ValueT[time.Duration] = "extapi/svc/tracehub/sync-interval"
 
+	// TrHeartbeatInterval time interval to send heartbeat to trace-hub when there are no traces
+	TrHeartbeatInterval config.ValueT[time.Duration] = "extapi/svc/tracehub/heartbeat-interval"
+
 	// TelemetryEndpoint server endpoint
 	TelemetryEndpoint config.ValueT[string] = "telemetry/endpoint"
 
@@ -80,4 +131,81 @@ const (
 	SGroupsSyncStatusInterval config.ValueT[time.Duration] = "extapi/svc/sgroups/sync-status/interval"
 	//SGroupsSyncStatusPush use push model of 'sync-status'
 	SGroupsSyncStatusPush config.ValueT[bool] = "extapi/svc/sgroups/sync-status/push"
+
+	// SgNetProviders comma separated priority chain of providers of networks and security groups:
+	// sgroups, static or none
+	SgNetProviders config.ValueT[string] = "sgnet/providers"
+	// SgNetStaticFile YAML or CSV file of networks and security groups of the static provider
+	SgNetStaticFile config.ValueT[string] = "sgnet/static/file"
+	// SgNetStaticReloadInterval interval to check the file of the static provider for changes
+	SgNetStaticReloadInterval config.ValueT[time.Duration] = "sgnet/static/reload-interval"
+	// SgNetSgroupsRuleScope comma separated patterns of tables or table/chain made by sgroups,
+	// empty for any [optional]
+	SgNetSgroupsRuleScope config.ValueT[string] = "sgnet/sgroups/rule-scope"
+
+	// NetnsEnable trace all network namespaces of the host, not only own agent namespace
+	NetnsEnable config.ValueT[bool] = "netns/enable"
+	// NetnsRescanInterval interval to discover network namespaces created or destroyed at runtime
+	NetnsRescanInterval config.ValueT[time.Duration] = "netns/rescan-interval"
+	// NetnsDir directory with named network namespaces
+	NetnsDir config.ValueT[string] = "netns/dir"
+	// NetnsProcDir procfs mount point to discover network namespaces of processes
+	NetnsProcDir config.ValueT[string] = "netns/proc-dir"
+
+	// ContainersEnable attribute traces to containers owning interfaces or network namespaces
+	ContainersEnable config.ValueT[bool] = "containers/enable"
+	// ContainersRuntimeDir state directory of the container runtime [optional]
+	ContainersRuntimeDir config.ValueT[string] = "containers/runtime-dir"
+	// ContainersMappingFile static JSON mapping of interfaces and network namespaces to containers [optional]
+	ContainersMappingFile config.ValueT[string] = "containers/mapping-file"
+	// ContainersReloadInterval interval to reload containers from sources
+	ContainersReloadInterval config.ValueT[time.Duration] = "containers/reload-interval"
+
+	// ProcsEnable attribute locally sent or received packets to processes owning sockets
+	ProcsEnable config.ValueT[bool] = "procs/enable"
+	// ProcsCacheSize number of connections resolved kept in cache per network namespace
+	ProcsCacheSize config.ValueT[int] = "procs/cache-size"
+
+	// ConntrackEnable attach conntrack state and NAT translation of the connection to traces
+	ConntrackEnable config.ValueT[bool] = "conntrack/enable"
+
+	// TraceSource source of traces: nftrace, nflog or both of them
+	TraceSource config.ValueT[string] = "trace/source"
+	// TraceRawHeaders keep raw bytes of the link, network and transport headers in traces
+	TraceRawHeaders config.ValueT[bool] = "trace/raw-headers"
+	// NflogGroups comma separated list of the log groups collected by nflog source
+	NflogGroups config.ValueT[string] = "nflog/groups"
+	// NflogCopyRange number of bytes of the packet copied to decode its headers
+	NflogCopyRange config.ValueT[uint32] = "nflog/copy-range"
+
+	// NftraceFilterEnable drop uninteresting nftrace messages in kernel by the socket filter
+	NftraceFilterEnable config.ValueT[bool] = "nftrace/filter/enable"
+	// NftraceFilterFamily family of the traced tables, empty for any [optional]
+	NftraceFilterFamily config.ValueT[string] = "nftrace/filter/family"
+	// NftraceFilterTables comma separated names of the traced tables, empty for any [optional]
+	NftraceFilterTables config.ValueT[string] = "nftrace/filter/tables"
+	// NftraceFilterTypes comma separated trace types: rule, return, policy, empty for any [optional];
+	// rule and policy are required, traces are completed by their decisions
+	NftraceFilterTypes config.ValueT[string] = "nftrace/filter/types"
+
+	// QueueCapacity max number of traces in every queue between stages of the pipeline, 0 means unbounded;
+	// queues after collectors count netlink messages of the traces
+	QueueCapacity config.ValueT[int] = "queue/capacity"
+	// QueueOverflow overflow policy of the queues: drop-oldest, drop-newest or prefer-drops
+	// (traces of dropped packets are kept over accepted ones)
+	QueueOverflow config.ValueT[string] = "queue/overflow"
+)
+
+// values of the TraceSource
+const (
+	TraceSourceNftrace = "nftrace"
+	TraceSourceNflog   = "nflog"
+	TraceSourceBoth    = "both"
+)
+
+// providers of the SgNetProviders
+const (
+	SgNetProviderSgroups = "sgroups"
+	SgNetProviderStatic  = "static"
+	SgNetProviderNone    = "none"
 )
@@ -3,8 +3,11 @@ package pkttracer
 import (
 	"context"
 	"os"
+	stdatomic "sync/atomic"
+	"time"
 
 	"github.com/wildberries-tech/pkt-tracer/internal/app"
+	"github.com/wildberries-tech/pkt-tracer/internal/bqueue"
 	grpc_client "github.com/wildberries-tech/pkt-tracer/internal/grpc-client"
 
 	"github.com/H-BF/corlib/pkg/atomic"
@@ -12,10 +15,33 @@ import (
 )
 
 type AgentMetrics struct {
-	traceCount    prometheus.Counter
-	errNlMemCount *prometheus.CounterVec
+	traceCount     prometheus.Counter
+	errNlMemCount  *prometheus.CounterVec
+	nlLostCount    *prometheus.CounterVec
+	decodeErrCount *prometheus.CounterVec
+	queueDepth     *prometheus.GaugeVec
+	queueWait      *prometheus.HistogramVec
+	queueDrops     *prometheus.CounterVec
+	latency        *prometheus.HistogramVec
+	mergeBufSize   prometheus.Gauge
+	ruleHitRatio   prometheus.GaugeFunc
+	ifaceMissCount prometheus.Counter
+	ruleTimeouts   prometheus.Counter
+	sgCacheAge     prometheus.GaugeFunc
+	ruleHits       stdatomic.Uint64
+	ruleMisses     stdatomic.Uint64
+	sgSyncedAt     stdatomic.Int64 // unix nano
 }
 
+// queueMetrics - statistics of the queues of the pipeline stage
+type queueMetrics struct {
+	depth prometheus.Gauge
+	wait  prometheus.Observer
+	drops prometheus.Counter
+}
+
+var _ bqueue.Metrics = (*queueMetrics)(nil)
+
 var agentMetricsHolder atomic.Value[*AgentMetrics]
 
 const (
@@ -23,8 +49,12 @@ const (
 	labelHostName  = "host_name"
 	nsAgent        = "agent"
 	labelSource    = "source"
+	labelStage     = "stage"
 )
 
+// buckets of the pipeline latencies from 100us up to ~26s
+var latencyBuckets = prometheus.ExponentialBuckets(0.0001, 4, 10)
+
 const ( // error sources
 	// ESrcIface -
 	ESrcIface = "iface"
@@ -34,6 +64,15 @@ const ( // error sources
 
 	// ESrcRuler -
 	ESrcRuler = "ruler"
+
+	// ESrcConntrack -
+	ESrcConntrack = "conntrack"
+
+	// ESrcNflog -
+	ESrcNflog = "nflog"
+
+	// ESrcTables -
+	ESrcTables = "tables"
 )
 
 // SetupMetrics -
@@ -58,6 +97,17 @@ func SetupMetrics(ctx context.Context) error {
 			grpc_client.GRPCClientMetrics(),
 			am.traceCount,
 			am.errNlMemCount,
+			am.nlLostCount,
+			am.decodeErrCount,
+			am.queueDepth,
+			am.queueWait,
+			am.queueDrops,
+			am.latency,
+			am.mergeBufSize,
+			am.ruleHitRatio,
+			am.ifaceMissCount,
+			am.ruleTimeouts,
+			am.sgCacheAge,
 		},
 	}
 	err = app.SetupMetrics(metricsOpt)
@@ -86,6 +136,85 @@ func (am *AgentMetrics) init(labels prometheus.Labels) {
 		Help:        "count of netlink receive buffer overload",
 		ConstLabels: labels,
 	}, []string{labelSource})
+	am.nlLostCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   nsAgent,
+		Name:        "nl_lost_messages_counter",
+		Help:        "estimated count of netlink messages lost on receive buffer overload",
+		ConstLabels: labels,
+	}, []string{labelSource})
+	am.decodeErrCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   nsAgent,
+		Name:        "decode_err_counter",
+		Help:        "count of netlink messages skipped as they can not be decoded",
+		ConstLabels: labels,
+	}, []string{labelSource})
+	am.queueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   nsAgent,
+		Name:        "queue_depth",
+		Help:        "number of items waiting in the queues after the pipeline stage",
+		ConstLabels: labels,
+	}, []string{labelStage})
+	am.queueWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   nsAgent,
+		Name:        "queue_wait_seconds",
+		Help:        "time items spend in the queues after the pipeline stage",
+		ConstLabels: labels,
+		Buckets:     latencyBuckets,
+	}, []string{labelStage})
+	am.queueDrops = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   nsAgent,
+		Name:        "queue_drops_counter",
+		Help:        "count of items dropped on overflow of the queues after the pipeline stage",
+		ConstLabels: labels,
+	}, []string{labelStage})
+	am.latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   nsAgent,
+		Name:        "latency_seconds",
+		Help:        "latency of traces: kernel-to-merge, merge-to-send and send",
+		ConstLabels: labels,
+		Buckets:     latencyBuckets,
+	}, []string{labelStage})
+	am.mergeBufSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   nsAgent,
+		Name:        "merge_buf_size",
+		Help:        "number of traces waiting in merge buffers for the rest of their messages",
+		ConstLabels: labels,
+	})
+	am.ruleHitRatio = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   nsAgent,
+		Name:        "rule_cache_hit_ratio",
+		Help:        "ratio of rules of traces found in the rule cache at the first lookup",
+		ConstLabels: labels,
+	}, func() float64 {
+		hits, misses := am.ruleHits.Load(), am.ruleMisses.Load()
+		if hits+misses == 0 {
+			return 1
+		}
+		return float64(hits) / float64(hits+misses)
+	})
+	am.ifaceMissCount = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   nsAgent,
+		Name:        "iface_cache_miss_counter",
+		Help:        "count of interfaces of traces not found in the interface cache",
+		ConstLabels: labels,
+	})
+	am.ruleTimeouts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   nsAgent,
+		Name:        "rule_timeout_counter",
+		Help:        "count of traces sent with unknown rule as their rule events have not come in time",
+		ConstLabels: labels,
+	})
+	am.sgCacheAge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   nsAgent,
+		Name:        "sgroups_cache_age_seconds",
+		Help:        "time since the cache of security groups has been synced with sgroups",
+		ConstLabels: labels,
+	}, func() float64 {
+		if at := am.sgSyncedAt.Load(); at != 0 {
+			return time.Since(time.Unix(0, at)).Seconds()
+		}
+		return 0
+	})
 }
 
 // ObserveTracesCounter -
@@ -97,3 +226,71 @@ func (am *AgentMetrics) ObserveTracesCounter(cnt int) {
 func (am *AgentMetrics) ObserveErrNlMemCounter(errSource string) {
 	am.errNlMemCount.WithLabelValues(errSource).Inc()
 }
+
+// ObserveDecodeErrCounter -
+func (am *AgentMetrics) ObserveDecodeErrCounter(errSource string) {
+	am.decodeErrCount.WithLabelValues(errSource).Inc()
+}
+
+// ObserveNlLostCounter -
+func (am *AgentMetrics) ObserveNlLostCounter(errSource string, lost uint32) {
+	am.nlLostCount.WithLabelValues(errSource).Add(float64(lost))
+}
+
+// QueueMetrics - sink of the statistics of the queues after the pipeline stage
+func (am *AgentMetrics) QueueMetrics(stage string) bqueue.Metrics {
+	return &queueMetrics{
+		depth: am.queueDepth.WithLabelValues(stage),
+		wait:  am.queueWait.WithLabelValues(stage),
+		drops: am.queueDrops.WithLabelValues(stage),
+	}
+}
+
+// AddDepth -
+func (m *queueMetrics) AddDepth(delta int) {
+	m.depth.Add(float64(delta))
+}
+
+// ObserveWait -
+func (m *queueMetrics) ObserveWait(d time.Duration) {
+	m.wait.Observe(d.Seconds())
+}
+
+// AddDrops -
+func (m *queueMetrics) AddDrops(n int) {
+	m.drops.Add(float64(n))
+}
+
+// ObserveLatency -
+func (am *AgentMetrics) ObserveLatency(stage string, d time.Duration) {
+	am.latency.WithLabelValues(stage).Observe(d.Seconds())
+}
+
+// ObserveMergeBufSize -
+func (am *AgentMetrics) ObserveMergeBufSize(delta int) {
+	am.mergeBufSize.Add(float64(delta))
+}
+
+// ObserveRuleLookup -
+func (am *AgentMetrics) ObserveRuleLookup(hit bool) {
+	if hit {
+		am.ruleHits.Add(1)
+	} else {
+		am.ruleMisses.Add(1)
+	}
+}
+
+// ObserveIfaceCacheMiss -
+func (am *AgentMetrics) ObserveIfaceCacheMiss() {
+	am.ifaceMissCount.Inc()
+}
+
+// ObserveRuleTimeout -
+func (am *AgentMetrics) ObserveRuleTimeout() {
+	am.ruleTimeouts.Inc()
+}
+
+// ObserveSgSync -
+func (am *AgentMetrics) ObserveSgSync(at time.Time) {
+	am.sgSyncedAt.Store(at.UnixNano())
+}
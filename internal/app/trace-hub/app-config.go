@@ -12,6 +12,10 @@ logger:
 
 metrics:
   enable: true
+  traffic:
+    enable: false
+    labels: agent,table,chain,verdict,proto,sg_src,sg_dst
+    max-series: 10000
 
 healthcheck:
   enable: true
@@ -19,6 +23,25 @@ healthcheck:
 server:
   endpoint: tcp://127.0.0.1:9006
   graceful-shutdown: 30s
+  docs:
+    enable: true
+
+agents:
+  liveness-timeout: 30s
+  retention: 24h
+
+alerts:
+  repeat-interval: 5m
+  webhook:
+    url: http://alertmanager:8080/hook
+    timeout: 5s
+    allowed-schemes: https,http #schemes of the webhooks of the rules set via API
+    allowed-hosts: alertmanager,*.hooks.example.com #hosts of the webhooks of the rules set via API
+  rules:
+    - name: sg-a-sg-b-drops
+      query: sg-src == "sg-a" and sg-dst == "sg-b" and verdict in ("rule::drop", "policy::drop")
+      threshold: 100
+      window: 1m
 
 storage:
    type: clickhouse
@@ -39,12 +62,52 @@ const (
 	// ServerGracefulShutdown graceful shutdown period
 	ServerGracefulShutdown config.ValueT[time.Duration] = "server/graceful-shutdown"
 
+	// ServerDocsEnable enables|disables swagger docs of the HTTP/JSON gateway on '/docs'
+	ServerDocsEnable config.ValueT[bool] = "server/docs/enable"
+
 	// MetricsEnable enable api metrics
 	MetricsEnable config.ValueT[bool] = "metrics/enable"
 
+	// TrafficMetricsEnable enable packet and byte counters derived from the traces
+	TrafficMetricsEnable config.ValueT[bool] = "metrics/traffic/enable"
+
+	// TrafficMetricsLabels comma separated labels of the traffic counters:
+	// agent, table, chain, verdict, proto, sg_src, sg_dst;
+	// verdict is the final action of the trace (accept or drop), not the whole verdict chain
+	TrafficMetricsLabels config.ValueT[string] = "metrics/traffic/labels"
+
+	// TrafficMetricsMaxSeries max number of the series of the traffic counters,
+	// traces of the new series over the limit are counted with all labels set to '_other_'
+	TrafficMetricsMaxSeries config.ValueT[int] = "metrics/traffic/max-series"
+
 	// HealthcheckEnable enables|disables health check handler
 	HealthcheckEnable config.ValueT[bool] = "healthcheck/enable"
 
+	// AgentsLivenessTimeout agent is considered offline if it has not sent heartbeat or traces during this period
+	AgentsLivenessTimeout config.ValueT[time.Duration] = "agents/liveness-timeout"
+
+	// AgentsRetention period of keeping offline agents in the inventory
+	AgentsRetention config.ValueT[time.Duration] = "agents/retention"
+
+	// AlertsRules alert rules: name, query, threshold, window, webhook and repeat-interval of every rule
+	AlertsRules config.ValueT[any] = "alerts/rules"
+
+	// AlertsRepeatInterval min interval of repeated notifications of the firing alert
+	AlertsRepeatInterval config.ValueT[time.Duration] = "alerts/repeat-interval"
+
+	// AlertsWebhookUrl default webhook of the alert rules
+	AlertsWebhookUrl config.ValueT[string] = "alerts/webhook/url"
+
+	// AlertsWebhookTimeout timeout of the webhook call
+	AlertsWebhookTimeout config.ValueT[time.Duration] = "alerts/webhook/timeout"
+
+	// AlertsWebhookAllowedSchemes comma separated schemes of the webhooks of the rules set via API
+	AlertsWebhookAllowedSchemes config.ValueT[string] = "alerts/webhook/allowed-schemes"
+
+	// AlertsWebhookAllowedHosts comma separated hosts of the webhooks of the rules set via API,
+	// wildcards like '*.example.com' are accepted, empty allows the default webhook only
+	AlertsWebhookAllowedHosts config.ValueT[string] = "alerts/webhook/allowed-hosts"
+
 	// StorageType selects storage DB backend
 	StorageType config.ValueT[string] = "storage/type"
 
@@ -0,0 +1,91 @@
+package tracehub
+
+import (
+	"context"
+	"os"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/alerts"
+	"github.com/wildberries-tech/pkt-tracer/internal/app"
+	"github.com/wildberries-tech/pkt-tracer/internal/config"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/alert"
+
+	"github.com/H-BF/corlib/pkg/atomic"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v3"
+)
+
+var storedAppAlerts atomic.Value[*alerts.Engine]
+
+// AppAlerts returns engine of the alert rules
+func AppAlerts() *alerts.Engine {
+	var ret *alerts.Engine
+	if !storedAppAlerts.Fetch(func(v *alerts.Engine) { ret = v }) {
+		panic(errors.New("need setup alerts"))
+	}
+	return ret
+}
+
+// SetupAlerts creates engine of the alert rules, rules of the config follow reloads of the config
+func SetupAlerts(ctx context.Context) error {
+	repeat, err := AlertsRepeatInterval.Value(ctx)
+	if err != nil {
+		return err
+	}
+	timeout, err := AlertsWebhookTimeout.Value(ctx)
+	if err != nil {
+		return err
+	}
+	hook, err := AlertsWebhookUrl.Value(ctx)
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return err
+	}
+	webhooks, err := alerts.ParseWebhookAllowlist(
+		AlertsWebhookAllowedSchemes.MustValue(ctx),
+		AlertsWebhookAllowedHosts.MustValue(ctx),
+	)
+	if err != nil {
+		return errors.WithMessagef(err, "bad '%s'", AlertsWebhookAllowedHosts)
+	}
+	e := alerts.NewEngine(
+		alerts.WithDefaultWebhook(hook),
+		alerts.WithWebhookAllowlist(webhooks),
+		alerts.WithRepeatInterval(repeat),
+		alerts.WithNotifier(alerts.NewWebhookNotifier(timeout)),
+	)
+	raw, err := AlertsRules.Value(ctx)
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return err
+	}
+	if err = setAlertRules(e, raw); err != nil {
+		return err
+	}
+	AlertsRules.OnChange(func(_ context.Context, ch config.Change[any]) error {
+		return setAlertRules(e, ch.New)
+	})
+	app.WhenHaveMetricsRegistry(func(reg *prometheus.Registry) {
+		hostname, _ := os.Hostname()
+		err = reg.Register(alerts.NewCollector(e, prometheus.Labels{labelHostName: hostname}))
+	})
+	if err == nil {
+		storedAppAlerts.Store(e, func(old *alerts.Engine) {
+			_ = old.Close()
+		})
+	}
+	return err
+}
+
+// setAlertRules decodes rules of the config and replaces the ones of the engine
+func setAlertRules(e *alerts.Engine, raw any) error {
+	var rules []model.AlertRuleModel
+	if raw != nil {
+		data, err := yaml.Marshal(raw)
+		if err != nil {
+			return err
+		}
+		if err = yaml.Unmarshal(data, &rules); err != nil {
+			return errors.WithMessagef(err, "decode '%s'", AlertsRules)
+		}
+	}
+	return e.SetConfigRules(rules)
+}
@@ -1,22 +1,32 @@
 package tracehub
 
 import (
+	"context"
+
 	"github.com/wildberries-tech/pkt-tracer/internal/app"
+	"github.com/wildberries-tech/pkt-tracer/internal/config"
 
 	"github.com/H-BF/corlib/logger"
 	"github.com/pkg/errors"
 )
 
-// SetupLogger setup app logger
+// SetupLogger setup app logger, level of the logger follows reloads of the config
 func SetupLogger() error {
 	ctx := app.Context()
-	_, err := AppLoggerLevel.Value(ctx, AppLoggerLevel.OptSink(func(v string) error {
-		var l logger.LogLevel
-		if e := l.UnmarshalText([]byte(v)); e != nil {
-			return errors.Wrapf(e, "recognize '%s' logger level from config", v)
-		}
-		logger.SetLevel(l)
-		return nil
-	}))
+	_, err := AppLoggerLevel.Value(ctx, AppLoggerLevel.OptSink(setLoggerLevel))
+	if err == nil {
+		AppLoggerLevel.OnChange(func(_ context.Context, ch config.Change[string]) error {
+			return setLoggerLevel(ch.New)
+		})
+	}
 	return err
 }
+
+func setLoggerLevel(v string) error {
+	var l logger.LogLevel
+	if e := l.UnmarshalText([]byte(v)); e != nil {
+		return errors.Wrapf(e, "recognize '%s' logger level from config", v)
+	}
+	logger.SetLevel(l)
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/wildberries-tech/pkt-tracer/internal/app"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	trafficmetrics "github.com/wildberries-tech/pkt-tracer/internal/traffic-metrics"
 
 	"github.com/H-BF/corlib/pkg/atomic"
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,6 +15,7 @@ import (
 type ServerMetrics struct {
 	traceCount   prometheus.Counter
 	dbWriteCount prometheus.Counter
+	traffic      *trafficmetrics.Collector
 }
 
 var serverMetricsHolder atomic.Value[*ServerMetrics]
@@ -45,6 +48,17 @@ func SetupMetrics(ctx context.Context) error {
 			am.dbWriteCount,
 		},
 	}
+	if TrafficMetricsEnable.MustValue(ctx) {
+		am.traffic, err = trafficmetrics.NewCollector(
+			trafficmetrics.ParseLabels(TrafficMetricsLabels.MustValue(ctx)),
+			TrafficMetricsMaxSeries.MustValue(ctx),
+			labels,
+		)
+		if err != nil {
+			return err
+		}
+		metricsOpt.Metrics = append(metricsOpt.Metrics, am.traffic)
+	}
 	err = app.SetupMetrics(metricsOpt)
 	if err == nil {
 		serverMetricsHolder.Store(am, nil)
@@ -78,6 +92,13 @@ func (am *ServerMetrics) ObserveTracesCounter() {
 	am.traceCount.Inc()
 }
 
+// ObserveTraffic accounts the traces in the traffic counters when they are enabled
+func (am *ServerMetrics) ObserveTraffic(traces []*model.TraceModel) {
+	if am.traffic != nil {
+		am.traffic.Observe(traces)
+	}
+}
+
 // ObserveTracesCounter -
 func (am *ServerMetrics) ObserveDBWriteCounter(cnt int) {
 	am.dbWriteCount.Add(float64(cnt))
@@ -3,6 +3,7 @@ package tracehub
 import (
 	"context"
 
+	"github.com/wildberries-tech/pkt-tracer/internal/agents"
 	"github.com/wildberries-tech/pkt-tracer/internal/api/tracehub"
 	"github.com/wildberries-tech/pkt-tracer/internal/app"
 
@@ -33,10 +34,27 @@ func SetupTraceHubServer(ctx context.Context) (*server.APIServer, error) {
 	if err != nil {
 		return nil, err
 	}
-	srv := tracehub.NewTraceHubeService(ctx, getAppRegistry(), ServerSubject(), flushTimeInterval, checkTimeInterval)
+	livenessTimeout, err := AgentsLivenessTimeout.Value(ctx)
+	if err != nil {
+		return nil, err
+	}
+	retention, err := AgentsRetention.Value(ctx)
+	if err != nil {
+		return nil, err
+	}
+	agentsReg := agents.NewRegistry(livenessTimeout, retention)
+	srv := tracehub.NewTraceHubeService(ctx, getAppRegistry(), agentsReg, AppAlerts(), ServerSubject(), flushTimeInterval, checkTimeInterval)
 
 	opts := []server.APIServerOption{
 		server.WithServices(srv),
+		server.WithGatewayOptions(tracehub.GatewayOptions()...),
+	}
+	if docs, _ := ServerDocsEnable.Value(ctx); docs { // add swagger docs of the HTTP/JSON gateway
+		spec, err := tracehub.SwaggerDocs()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, server.WithDocs(spec, ""))
 	}
 
 	//если есть регистр Прометеуса то - подклчим метрики
@@ -19,7 +19,11 @@ extapi:
     tracehub:
       dial-duration: 3s #override default-connect-tmo
       address: tcp://127.0.0.1:9006
+      from-file: traces.parquet #offline mode, address is not used
 	  use-compression: false
+    sgroups:
+      dial-duration: 3s #override default-connect-tmo
+      address: tcp://127.0.0.1:9000
 
 */
 
@@ -36,9 +40,18 @@ const (
 	// TrAddress service address [mandatory]
 	TrAddress config.ValueT[string] = "extapi/svc/tracehub/address"
 
+	// TrFromFile file of the traces exported by visor-cli, it is read instead of trace-hub service [optional]
+	TrFromFile config.ValueT[string] = "extapi/svc/tracehub/from-file"
+
 	// UseCompression enable compression for grpc messages
 	UseCompression config.ValueT[bool] = "extapi/svc/tracehub/use-compression"
 
+	// SGroupsAddress sgroups service address, it is used by the conformance check [optional]
+	SGroupsAddress config.ValueT[string] = "extapi/svc/sgroups/address"
+
+	// SGroupsDialDuration sgroups service dial duration [optional]
+	SGroupsDialDuration config.ValueT[time.Duration] = "extapi/svc/sgroups/dial-duration"
+
 	// UserAgent
 	UserAgent config.ValueT[string] = "useragent"
 )
@@ -6,8 +6,8 @@ import (
 	"time"
 
 	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
-	ch "github.com/wildberries-tech/pkt-tracer/internal/registry/clickhouse"
 	"github.com/wildberries-tech/pkt-tracer/pkg/meta"
+	"github.com/wildberries-tech/pkt-tracer/pkg/tracequery"
 
 	"github.com/go-faster/errors"
 	"github.com/spf13/cobra"
@@ -22,7 +22,7 @@ const (
 	groupFlagTag    = "gr"
 )
 
-var validateFilterFlags = regexp.MustCompile(`^[a-zA-Z0-9!@#$%^&*_.+\-_:<>~/?-]+$`)
+var validateFilterFlags = regexp.MustCompile(`^[a-zA-Z0-9!@#$%^&*_.+\-_:<>~/?=-]+$`)
 
 type (
 	SliceT interface {
@@ -54,6 +54,8 @@ type (
 		JsonFormat bool `name:"json" key:"j" usage:"enable extended output in the json format"`
 		// trace hub server url
 		ServerUrl string `name:"host" key:"H" usage:"trace-hub service address (format: <IP>:<port>)" eg:"tcp://127.0.0.1:9000"`
+		// file of the traces used instead of trace hub server
+		FromFile string `name:"from-file" usage:"read traces from the file exported by 'visor-cli export' (jsonl, parquet or pcapng) instead of trace-hub service" eg:"traces.parquet"`
 		// level of log
 		LogLevel string `name:"log-level" usage:"log level: INFO|DEBUG|WARN|ERROR|PANIC|FATAL"`
 		// verbose output mode
@@ -104,6 +106,46 @@ type (
 		SSgNet []string `name:"net-src" gr:"trace" usage:"set filter by source network name. Supported multiple values (see --table Flag)" eg:"192.168.0.0/32,192.168.50.0/32"`
 		// names of the network for dst ip
 		DSgNet []string `name:"net-dst" gr:"trace" usage:"set filter by destination network name. Supported multiple values (see --table Flag)" eg:"192.168.0.0/32,192.168.50.0/32"`
+		// network namespaces
+		NetNS []string `name:"netns" gr:"trace" usage:"set filter by network namespace of the agent host (empty value means host namespace). Supported multiple values (see --table Flag)" eg:"ns1,ns2"`
+		// ids of the containers
+		ContainerId []string `name:"container-id" gr:"trace" usage:"set filter by container id. Supported multiple values (see --table Flag)" eg:"3a9f0c2b1d4e,7c1e9a0b2f3d"`
+		// names of the containers
+		ContainerName []string `name:"container" gr:"trace" usage:"set filter by container name. Supported multiple values (see --table Flag)" eg:"web,db"`
+		// names of the pods
+		Pod []string `name:"pod" gr:"trace" usage:"set filter by pod name. Supported multiple values (see --table Flag)" eg:"web-0,db-0"`
+		// namespaces of the pods
+		PodNamespace []string `name:"pod-ns" gr:"trace" usage:"set filter by pod namespace. Supported multiple values (see --table Flag)" eg:"prod,stage"`
+		// labels of the containers
+		ContainerLabels []string `name:"label" gr:"trace" usage:"set filter by container label in the form key=value, traces having any of the labels are matched. Supported multiple values (see --table Flag)" eg:"app=web,tier=front"`
+		// ids of the processes owning sockets
+		Pid []uint `name:"pid" gr:"trace" usage:"set filter by id of the local process owning socket. Supported multiple values (see --trid Flag)" eg:"1234,5678"`
+		// command names of the processes
+		Comm []string `name:"comm" gr:"trace" usage:"set filter by command name of the local process owning socket. Supported multiple values (see --table Flag)" eg:"nginx,curl"`
+		// cgroup paths of the processes
+		Cgroup []string `name:"cgroup" gr:"trace" usage:"set filter by cgroup path of the local process owning socket. Supported multiple values (see --table Flag)" eg:"/system.slice/nginx.service"`
+		// conntrack states of the connections
+		CtState []string `name:"ct-state" gr:"trace" usage:"set filter by conntrack state of the connection (new/established/related). Supported multiple values (see --table Flag)" eg:"new,established"`
+		// directions of the packets in the connections
+		CtDirection []string `name:"ct-dir" gr:"trace" usage:"set filter by direction of the packet in the conntrack connection (original/reply). Supported multiple values (see --table Flag)" eg:"original,reply"`
+		// address translations applied to the connections
+		CtNat []string `name:"ct-nat" gr:"trace" usage:"set filter by address translation applied to the connection (snat/dnat). Supported multiple values (see --table Flag)" eg:"snat,dnat"`
+		// conntrack marks of the connections
+		CtMark []uint `name:"ct-mark" gr:"trace" usage:"set filter by conntrack mark of the connection. Supported multiple values (see --trid Flag)" eg:"16,32"`
+		// conntrack zones of the connections
+		CtZone []uint `name:"ct-zone" gr:"trace" usage:"set filter by conntrack zone of the connection. Supported multiple values (see --trid Flag)" eg:"1,2"`
+		// subsystems the traces are collected from
+		Source []string `name:"source" gr:"trace" usage:"set filter by subsystem the trace is collected from (nftrace/nflog). Supported multiple values (see --table Flag)" eg:"nftrace,nflog"`
+		// prefixes of the log rules
+		LogPrefix []string `name:"log-prefix" gr:"trace" usage:"set filter by prefix of the log rule (nflog source). Supported multiple values (see --table Flag)" eg:"drop-in,drop-out"`
+		// log groups of the packets
+		LogGroup []uint `name:"log-group" gr:"trace" usage:"set filter by log group of the packet (nflog source). Supported multiple values (see --trid Flag)" eg:"0,1"`
+		// netfilter hooks the packets are logged at
+		Hook []string `name:"hook" gr:"trace" usage:"set filter by netfilter hook the packet is logged at (nflog source). Supported multiple values (see --table Flag)" eg:"input,forward"`
+		// sgroups rules the verdicts are resolved to
+		SgRule []string `name:"sg-rule" gr:"trace" usage:"set filter by sgroups rule the verdict is resolved to. Supported multiple values (see --table Flag)" eg:"sg-sg:web->db:tcp"`
+		// actions of the sgroups rules
+		SgRuleAction []string `name:"sg-rule-action" gr:"trace" usage:"set filter by action of the sgroups rule (accept/drop). Supported multiple values (see --table Flag)" eg:"drop"`
 		// lengths of packets
 		Length []uint `name:"len" gr:"trace" usage:"set filter by network packet length. Supported multiple values (see --trid Flag)" eg:"20,80"`
 		// ip protocols (tcp/udp/icmp/...)
@@ -134,34 +176,7 @@ type (
 )
 
 func (q QueryFlag) ToSql() (ret string, err error) {
-	if q == "" {
-		return
-	}
-	obj := &ch.TraceDB{}
-	f := Flags{}
-	return NewQueryParser(string(q), map[string]string{
-		f.NameFromTag(&f.TrId):       obj.FieldTag(&obj.TrId),
-		f.NameFromTag(&f.Table):      obj.FieldTag(&obj.Table),
-		f.NameFromTag(&f.Chain):      obj.FieldTag(&obj.Chain),
-		f.NameFromTag(&f.JumpTarget): obj.FieldTag(&obj.JumpTarget),
-		f.NameFromTag(&f.RuleHandle): obj.FieldTag(&obj.RuleHandle),
-		f.NameFromTag(&f.Family):     obj.FieldTag(&obj.Family),
-		f.NameFromTag(&f.Iifname):    obj.FieldTag(&obj.Iifname),
-		f.NameFromTag(&f.Oifname):    obj.FieldTag(&obj.Oifname),
-		f.NameFromTag(&f.SMacAddr):   obj.FieldTag(&obj.SMacAddr),
-		f.NameFromTag(&f.DMacAddr):   obj.FieldTag(&obj.DMacAddr),
-		f.NameFromTag(&f.SAddr):      obj.FieldTag(&obj.SAddr),
-		f.NameFromTag(&f.DAddr):      obj.FieldTag(&obj.DAddr),
-		f.NameFromTag(&f.SPort):      obj.FieldTag(&obj.SPort),
-		f.NameFromTag(&f.DPort):      obj.FieldTag(&obj.DPort),
-		f.NameFromTag(&f.SSgName):    obj.FieldTag(&obj.SSgName),
-		f.NameFromTag(&f.DSgName):    obj.FieldTag(&obj.DSgName),
-		f.NameFromTag(&f.SSgNet):     obj.FieldTag(&obj.SSgNet),
-		f.NameFromTag(&f.DSgNet):     obj.FieldTag(&obj.DSgNet),
-		f.NameFromTag(&f.Length):     obj.FieldTag(&obj.Length),
-		f.NameFromTag(&f.IpProto):    obj.FieldTag(&obj.IpProto),
-		f.NameFromTag(&f.Verdict):    obj.FieldTag(&obj.Verdict),
-	}).ToSql()
+	return tracequery.ToSql(string(q))
 }
 
 func (f Flags) Clone(fn func(f *Flags)) {
@@ -223,31 +238,52 @@ func (f *Flags) ToTraceScopeModel() (md model.TraceScopeModel, err error) {
 	}
 
 	md = model.TraceScopeModel{
-		TrId:       castSlice[uint, uint32](f.TrId),
-		Table:      f.Table,
-		Chain:      f.Chain,
-		JumpTarget: f.JumpTarget,
-		RuleHandle: castSlice[uint, uint64](f.RuleHandle),
-		Family:     f.Family,
-		Iifname:    f.Iifname,
-		Oifname:    f.Oifname,
-		SMacAddr:   f.SMacAddr,
-		DMacAddr:   f.DMacAddr,
-		SAddr:      f.SAddr,
-		DAddr:      f.DAddr,
-		SPort:      castSlice[uint, uint32](f.SPort),
-		DPort:      castSlice[uint, uint32](f.DPort),
-		SSgName:    f.SSgName,
-		DSgName:    f.DSgName,
-		SSgNet:     f.SSgNet,
-		DSgNet:     f.DSgNet,
-		Length:     castSlice[uint, uint32](f.Length),
-		IpProto:    f.IpProto,
-		Verdict:    f.Verdict,
-		Time:       timeRange,
-		AgentsIds:  f.AgentsIds,
-		FollowMode: f.FollowMode,
-		Query:      sqlQuery,
+		TrId:            castSlice[uint, uint32](f.TrId),
+		Table:           f.Table,
+		Chain:           f.Chain,
+		JumpTarget:      f.JumpTarget,
+		RuleHandle:      castSlice[uint, uint64](f.RuleHandle),
+		Family:          f.Family,
+		Iifname:         f.Iifname,
+		Oifname:         f.Oifname,
+		SMacAddr:        f.SMacAddr,
+		DMacAddr:        f.DMacAddr,
+		SAddr:           f.SAddr,
+		DAddr:           f.DAddr,
+		SPort:           castSlice[uint, uint32](f.SPort),
+		DPort:           castSlice[uint, uint32](f.DPort),
+		SSgName:         f.SSgName,
+		DSgName:         f.DSgName,
+		SSgNet:          f.SSgNet,
+		DSgNet:          f.DSgNet,
+		NetNS:           f.NetNS,
+		ContainerId:     f.ContainerId,
+		ContainerName:   f.ContainerName,
+		Pod:             f.Pod,
+		PodNamespace:    f.PodNamespace,
+		ContainerLabels: f.ContainerLabels,
+		Pid:             castSlice[uint, uint32](f.Pid),
+		Comm:            f.Comm,
+		Cgroup:          f.Cgroup,
+		CtState:         f.CtState,
+		CtDirection:     f.CtDirection,
+		CtNat:           f.CtNat,
+		CtMark:          castSlice[uint, uint32](f.CtMark),
+		CtZone:          castSlice[uint, uint32](f.CtZone),
+		Source:          f.Source,
+		LogPrefix:       f.LogPrefix,
+		LogGroup:        castSlice[uint, uint32](f.LogGroup),
+		Hook:            f.Hook,
+		SgRule:          f.SgRule,
+		SgRuleAction:    f.SgRuleAction,
+		Length:          castSlice[uint, uint32](f.Length),
+		IpProto:         f.IpProto,
+		Verdict:         f.Verdict,
+		Time:            timeRange,
+		AgentsIds:       f.AgentsIds,
+		FollowMode:      f.FollowMode,
+		Query:           sqlQuery,
+		QueryDsl:        f.Query,
 	}
 
 	return md, err
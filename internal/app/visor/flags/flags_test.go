@@ -51,6 +51,26 @@ func (sui *flagsTestSuite) Test_GetFlagParamsByGroup() {
 				{Name: "sg-dst", Group: "trace", Usage: "set filter by destination security group name. Supported multiple values (see --table Flag)", Example: "sg1,sg2"},
 				{Name: "net-src", Group: "trace", Usage: "set filter by source network name. Supported multiple values (see --table Flag)", Example: "192.168.0.0/32,192.168.50.0/32"},
 				{Name: "net-dst", Group: "trace", Usage: "set filter by destination network name. Supported multiple values (see --table Flag)", Example: "192.168.0.0/32,192.168.50.0/32"},
+				{Name: "netns", Group: "trace", Usage: "set filter by network namespace of the agent host (empty value means host namespace). Supported multiple values (see --table Flag)", Example: "ns1,ns2"},
+				{Name: "container-id", Group: "trace", Usage: "set filter by container id. Supported multiple values (see --table Flag)", Example: "3a9f0c2b1d4e,7c1e9a0b2f3d"},
+				{Name: "container", Group: "trace", Usage: "set filter by container name. Supported multiple values (see --table Flag)", Example: "web,db"},
+				{Name: "pod", Group: "trace", Usage: "set filter by pod name. Supported multiple values (see --table Flag)", Example: "web-0,db-0"},
+				{Name: "pod-ns", Group: "trace", Usage: "set filter by pod namespace. Supported multiple values (see --table Flag)", Example: "prod,stage"},
+				{Name: "label", Group: "trace", Usage: "set filter by container label in the form key=value, traces having any of the labels are matched. Supported multiple values (see --table Flag)", Example: "app=web,tier=front"},
+				{Name: "pid", Group: "trace", Usage: "set filter by id of the local process owning socket. Supported multiple values (see --trid Flag)", Example: "1234,5678"},
+				{Name: "comm", Group: "trace", Usage: "set filter by command name of the local process owning socket. Supported multiple values (see --table Flag)", Example: "nginx,curl"},
+				{Name: "cgroup", Group: "trace", Usage: "set filter by cgroup path of the local process owning socket. Supported multiple values (see --table Flag)", Example: "/system.slice/nginx.service"},
+				{Name: "ct-state", Group: "trace", Usage: "set filter by conntrack state of the connection (new/established/related). Supported multiple values (see --table Flag)", Example: "new,established"},
+				{Name: "ct-dir", Group: "trace", Usage: "set filter by direction of the packet in the conntrack connection (original/reply). Supported multiple values (see --table Flag)", Example: "original,reply"},
+				{Name: "ct-nat", Group: "trace", Usage: "set filter by address translation applied to the connection (snat/dnat). Supported multiple values (see --table Flag)", Example: "snat,dnat"},
+				{Name: "ct-mark", Group: "trace", Usage: "set filter by conntrack mark of the connection. Supported multiple values (see --trid Flag)", Example: "16,32"},
+				{Name: "ct-zone", Group: "trace", Usage: "set filter by conntrack zone of the connection. Supported multiple values (see --trid Flag)", Example: "1,2"},
+				{Name: "source", Group: "trace", Usage: "set filter by subsystem the trace is collected from (nftrace/nflog). Supported multiple values (see --table Flag)", Example: "nftrace,nflog"},
+				{Name: "log-prefix", Group: "trace", Usage: "set filter by prefix of the log rule (nflog source). Supported multiple values (see --table Flag)", Example: "drop-in,drop-out"},
+				{Name: "log-group", Group: "trace", Usage: "set filter by log group of the packet (nflog source). Supported multiple values (see --trid Flag)", Example: "0,1"},
+				{Name: "hook", Group: "trace", Usage: "set filter by netfilter hook the packet is logged at (nflog source). Supported multiple values (see --table Flag)", Example: "input,forward"},
+				{Name: "sg-rule", Group: "trace", Usage: "set filter by sgroups rule the verdict is resolved to. Supported multiple values (see --table Flag)", Example: "sg-sg:web->db:tcp"},
+				{Name: "sg-rule-action", Group: "trace", Usage: "set filter by action of the sgroups rule (accept/drop). Supported multiple values (see --table Flag)", Example: "drop"},
 				{Name: "len", Group: "trace", Usage: "set filter by network packet length. Supported multiple values (see --trid Flag)", Example: "20,80"},
 				{Name: "proto", Group: "trace", Usage: "set filter by ip protocol (tcp/udp/icmp/...). Supported multiple values (see --table Flag)", Example: "tcp,udp,icmp"},
 				{Name: "verdict", Group: "trace", Usage: "set filter by rule verdict (accept/drop/continue). Supported multiple values (see --table Flag)", Example: "accept,drop,continue"},
@@ -435,6 +455,7 @@ func (sui *flagsTestSuite) Test_ToTraceScopeModel() {
 			md: model.TraceScopeModel{
 				FollowMode: true,
 				Query:      "sport >= 80 AND sport <= 443 AND ip_d = '93.184.215.14' AND dport IN (80,443)",
+				QueryDsl:   "(sport>=80 and sport<=443) and ip-dst=='93.184.215.14' and dport in (80,443)",
 			},
 		},
 	}
@@ -444,6 +465,7 @@ func (sui *flagsTestSuite) Test_ToTraceScopeModel() {
 			sui.Require().NoError(err)
 			sui.Require().Equal(tc.md.FollowMode, md.FollowMode)
 			sui.Require().Equal(tc.md.Query, md.Query)
+			sui.Require().Equal(tc.md.QueryDsl, md.QueryDsl)
 		})
 	}
 
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	ch "github.com/wildberries-tech/pkt-tracer/internal/registry/clickhouse"
+	"github.com/wildberries-tech/pkt-tracer/pkg/meta"
+	"github.com/wildberries-tech/pkt-tracer/pkg/tracequery"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -253,7 +255,7 @@ func (sui *queryTestSuite) Test_ValidQuery() {
 
 	for _, test := range testData {
 		sui.Run(test.name, func() {
-			parserQuery := NewQueryParser(test.data, sui.flagToSqlArg)
+			parserQuery := tracequery.NewQueryParser(test.data, sui.flagToSqlArg)
 			sqlQuery, err := parserQuery.ToSql()
 			sui.Require().NoError(err)
 			sui.Require().Equal(test.expected, sqlQuery)
@@ -270,7 +272,7 @@ func (sui *queryTestSuite) Test_ValidQuery() {
 			expected += " AND "
 		}
 	}
-	parserQuery := NewQueryParser(expr, sui.flagToSqlArg)
+	parserQuery := tracequery.NewQueryParser(expr, sui.flagToSqlArg)
 	sqlQuery, err := parserQuery.ToSql()
 	sui.Require().NoError(err)
 	sui.Require().Equal(expected, sqlQuery)
@@ -333,9 +335,31 @@ func (sui *queryTestSuite) Test_InvalidQuery() {
 	}
 	for _, test := range testData {
 		sui.Run(test.name, func() {
-			parserQuery := NewQueryParser(test.expr, sui.flagToSqlArg)
+			parserQuery := tracequery.NewQueryParser(test.expr, sui.flagToSqlArg)
 			_, err := parserQuery.ToSql()
 			sui.Require().Error(err)
 		})
 	}
 }
+
+func (sui *queryTestSuite) Test_Params() {
+	// every query parameter is the trace filter flag and its column is the column of the traces
+	flagNames := make(map[string]bool)
+	meta.IterFields(Flags{}, flagNameTag, func(_ any, tag string, _ uintptr) {
+		flagNames[tag] = true
+	})
+	dbCols := make(map[string]bool)
+	meta.IterFields(ch.TraceDB{}, "ch", func(_ any, tag string, _ uintptr) {
+		dbCols[tag] = true
+	})
+	for arg, col := range sui.flagToSqlArg {
+		c, ok := tracequery.Column(arg)
+		sui.Require().True(ok, arg)
+		sui.Require().Equal(col, c, arg)
+	}
+	for _, arg := range tracequery.Params() {
+		col, _ := tracequery.Column(arg)
+		sui.Require().True(flagNames[arg], arg)
+		sui.Require().True(dbCols[col], col)
+	}
+}
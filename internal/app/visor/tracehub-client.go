@@ -15,10 +15,14 @@ import (
 // THClosableClient is an alias to 'thAPI.ClosableClient'
 type THClosableClient = thAPI.ClosableClient
 
-// NewTHClient makes 'trace-hub' API client
+// NewTHClient makes 'trace-hub' API client, traces of the file are served in process when it is configured
 func NewTHClient(ctx context.Context) (*THClosableClient, error) {
 	const api = "NewTHClient"
 
+	if fileName, _ := TrFromFile.Value(ctx); fileName != "" {
+		return NewOfflineTHClient(fileName)
+	}
+
 	addr, err := TrAddress.Value(ctx)
 	if err != nil {
 		return nil, errors.WithMessage(err, api)
@@ -0,0 +1,69 @@
+package visor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/dto"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/agent"
+
+	"github.com/pkg/errors"
+)
+
+// FetchAgents fetches agents inventory from trace-hub server
+func FetchAgents(ctx context.Context, cli THClient, scope model.AgentScopeModel) ([]model.AgentModel, error) {
+	var scopeDto dto.AgentScopeDTO
+	scopeDto.InitFromModel(&scope)
+	resp, err := cli.ListAgents(ctx, scopeDto.ToProto())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to obtain agents from server")
+	}
+	ret := make([]model.AgentModel, 0, len(resp.GetAgents()))
+	for _, a := range resp.GetAgents() {
+		var agentDto dto.AgentDTO
+		agentDto.InitFromProto(a)
+		ret = append(ret, *agentDto.ToModel())
+	}
+	return ret, nil
+}
+
+// PrintAgents prints agents as a table or as json lines
+func PrintAgents(w io.Writer, agents []model.AgentModel, jsonFormat bool) error {
+	if jsonFormat {
+		enc := json.NewEncoder(w)
+		for _, a := range agents {
+			if err := enc.Encode(a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tVERSION\tHOSTNAME\tKERNEL\tREMOTE\tCONNECTED\tLAST-TRACE\tRATE/s\tTOTAL")
+	for _, a := range agents {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%.2f\t%d\n",
+			a.Id, AgentStatus(a), a.Version, a.Hostname, a.Kernel, a.RemoteAddr,
+			formatAgentTime(a.ConnectedAt), formatAgentTime(a.LastTraceAt),
+			a.TraceRate, a.TracesTotal)
+	}
+	return tw.Flush()
+}
+
+// AgentStatus returns liveness status of the agent
+func AgentStatus(a model.AgentModel) string {
+	if a.Online {
+		return "online"
+	}
+	return "offline"
+}
+
+func formatAgentTime(t time.Time) string {
+	if t.IsZero() || t.Unix() == 0 {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/app"
+	. "github.com/wildberries-tech/pkt-tracer/internal/app/visor" //nolint:revive
+	vf "github.com/wildberries-tech/pkt-tracer/internal/app/visor/flags"
+	vc "github.com/wildberries-tech/pkt-tracer/internal/app/visor/visor-cli"
+	"github.com/wildberries-tech/pkt-tracer/internal/config"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/agent"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const flagOnlineOnly = "online"
+
+func newAgentsCommand() *cobra.Command {
+	fl := vf.Flags{}
+	c := &cobra.Command{
+		Use:     "agents",
+		Short:   "List tracer agents registered on trace-hub",
+		Example: "visor-cli agents -H tcp://10.10.0.150:9650 --online --agent-id tracer1,tracer2",
+		RunE:    runAgents,
+	}
+	excludeFlags := []string{
+		fl.NameFromTag(&fl.TimeFrom),
+		fl.NameFromTag(&fl.TimeTo),
+		fl.NameFromTag(&fl.TimeDuration),
+		fl.NameFromTag(&fl.FollowMode),
+		fl.NameFromTag(&fl.Query),
+	}
+	for _, p := range fl.GetFlagParamsByGroup("trace") {
+		excludeFlags = append(excludeFlags, p.Name)
+	}
+	err := fl.Attach(c,
+		vf.WithExcludeFlags{ExcludeFlags: excludeFlags},
+		vf.WithDefValues{Defvalues: map[string]any{fl.NameFromTag(&fl.LogLevel): "INFO"}},
+		vf.WithPersistentFlags{Pflags: map[string]*pflag.FlagSet{
+			fl.NameFromTag(&fl.LogLevel):    c.PersistentFlags(),
+			fl.NameFromTag(&fl.VerboseMode): c.PersistentFlags(),
+		}},
+	)
+	if err != nil {
+		panic(errors.WithMessage(err, "failed to attach flag"))
+	}
+	c.Flags().Bool(flagOnlineOnly, false, "show online agents only")
+	c.MarkFlagsOneRequired(fl.NameFromTag(&fl.ConfigPath), fl.NameFromTag(&fl.ServerUrl), fl.NameFromTag(&fl.FromFile))
+	c.MarkFlagsMutuallyExclusive(fl.NameFromTag(&fl.ServerUrl), fl.NameFromTag(&fl.FromFile))
+	SetupContext()
+	return c
+}
+
+func runAgents(cmd *cobra.Command, args []string) (err error) {
+	fl := vf.Flags{}
+	if err = fl.Action(cmd); err != nil {
+		return err
+	}
+	onlineOnly, err := cmd.Flags().GetBool(flagOnlineOnly)
+	if err != nil {
+		return err
+	}
+	ctx := app.Context()
+	err = config.InitGlobalConfig(
+		config.WithAcceptEnvironment{EnvPrefix: "VC"},
+		config.WithSourceFile{FileName: fl.ConfigPath},
+
+		config.WithCmdFlag{Key: AppLoggerLevel, Flag: cmd.Flag(fl.NameFromTag(&fl.LogLevel))},
+		config.WithDefValue{Key: AppLoggerLevel, Val: "INFO"},
+
+		config.WithCmdFlag{Key: TrAddress, Flag: cmd.Flag(fl.NameFromTag(&fl.ServerUrl))},
+		config.WithCmdFlag{Key: TrFromFile, Flag: cmd.Flag(fl.NameFromTag(&fl.FromFile))},
+		config.WithDefValue{Key: TrAddress, Val: "tcp://127.0.0.1:9000"},
+
+		config.WithDefValue{Key: ServicesDefDialDuration, Val: 30 * time.Second},
+
+		config.WithDefValue{Key: UseCompression, Val: false},
+		config.WithDefValue{Key: UserAgent, Val: "visor-cli0"},
+	)
+	if err != nil {
+		return err
+	}
+	if err = SetupLogger(fl.JsonFormat); err != nil {
+		return err
+	}
+	scope := agent.AgentScopeModel{
+		AgentsIds:  fl.AgentsIds,
+		OnlineOnly: onlineOnly,
+	}
+	if err = vc.RunListAgents(ctx, scope, fl.JsonFormat); err != nil {
+		select {
+		case <-ctx.Done():
+		default:
+			return err
+		}
+	}
+	return nil
+}
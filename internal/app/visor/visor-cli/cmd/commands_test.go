@@ -326,7 +326,7 @@ func (sui *cmdTestSuite) Test_MultipleValidFlags() {
 		},
 		{
 			name: "sub07",
-			args: "--host 10.10.0.150:9650 --iif eth0,eth1,eth2 --oif eth3,eth4,eth5 --sport 80,81,82 --dport 443,444,445 --ip-src 192.168.0.50,192.168.0.51 --ip-dst 93.184.215.14,93.184.215.15 --proto tcp,udp --family ip,ip6 --sg-src sg1,sg2,sg3 --sg-dst sg4,sg5,sg6 --net-src net1,net2 --net-dst net3,net4",
+			args: "--host 10.10.0.150:9650 --iif eth0,eth1,eth2 --oif eth3,eth4,eth5 --sport 80,81,82 --dport 443,444,445 --ip-src 192.168.0.50,192.168.0.51 --ip-dst 93.184.215.14,93.184.215.15 --proto tcp,udp --family ip,ip6 --sg-src sg1,sg2,sg3 --sg-dst sg4,sg5,sg6 --net-src net1,net2 --net-dst net3,net4 --netns ns1,ns2",
 			expFilterFlags: model.TraceScopeModel{
 				Iifname: []string{"eth0", "eth1", "eth2"},
 				Oifname: []string{"eth3", "eth4", "eth5"},
@@ -340,6 +340,56 @@ func (sui *cmdTestSuite) Test_MultipleValidFlags() {
 				DSgName: []string{"sg4", "sg5", "sg6"},
 				SSgNet:  []string{"net1", "net2"},
 				DSgNet:  []string{"net3", "net4"},
+				NetNS:   []string{"ns1", "ns2"},
+			},
+		},
+		{
+			name: "sub08",
+			args: "--host 10.10.0.150:9650 --container-id c1,c2 --container web,db --pod web-0 --pod-ns prod,stage --label app=web,tier=front",
+			expFilterFlags: model.TraceScopeModel{
+				ContainerId:     []string{"c1", "c2"},
+				ContainerName:   []string{"web", "db"},
+				Pod:             []string{"web-0"},
+				PodNamespace:    []string{"prod", "stage"},
+				ContainerLabels: []string{"app=web", "tier=front"},
+			},
+		},
+		{
+			name: "sub09",
+			args: "--host 10.10.0.150:9650 --pid 1234,5678 --comm nginx,curl --cgroup /system.slice/nginx.service",
+			expFilterFlags: model.TraceScopeModel{
+				Pid:    []uint32{1234, 5678},
+				Comm:   []string{"nginx", "curl"},
+				Cgroup: []string{"/system.slice/nginx.service"},
+			},
+		},
+		{
+			name: "sub10",
+			args: "--host 10.10.0.150:9650 --ct-state new,established --ct-dir reply --ct-nat dnat --ct-mark 16,32 --ct-zone 1",
+			expFilterFlags: model.TraceScopeModel{
+				CtState:     []string{"new", "established"},
+				CtDirection: []string{"reply"},
+				CtNat:       []string{"dnat"},
+				CtMark:      []uint32{16, 32},
+				CtZone:      []uint32{1},
+			},
+		},
+		{
+			name: "sub11",
+			args: "--host 10.10.0.150:9650 --source nflog --log-prefix drop-in --log-group 0,1 --hook input,forward",
+			expFilterFlags: model.TraceScopeModel{
+				Source:    []string{"nflog"},
+				LogPrefix: []string{"drop-in"},
+				LogGroup:  []uint32{0, 1},
+				Hook:      []string{"input", "forward"},
+			},
+		},
+		{
+			name: "sub12",
+			args: "--host 10.10.0.150:9650 --sg-rule sg-sg:web->db:tcp --sg-rule-action drop",
+			expFilterFlags: model.TraceScopeModel{
+				SgRule:       []string{"sg-sg:web->db:tcp"},
+				SgRuleAction: []string{"drop"},
 			},
 		},
 	}
@@ -608,3 +658,21 @@ func (sui *cmdTestSuite) Test_Verbose() {
 	sui.Require().NoError(err)
 	sui.Require().Equal("DEBUG", level)
 }
+
+func (sui *cmdTestSuite) Test_AgentsFlags() {
+	fl := vf.Flags{}
+	cmd := newAgentsCommand()
+	sui.Require().NotNil(cmd.Flags().Lookup(flagOnlineOnly))
+	sui.Require().NotNil(cmd.Flags().Lookup(fl.NameFromTag(&fl.AgentsIds)))
+	sui.Require().NotNil(cmd.Flags().Lookup(fl.NameFromTag(&fl.ServerUrl)))
+	sui.Require().Nil(cmd.Flags().Lookup(fl.NameFromTag(&fl.Iifname)))
+	sui.Require().Nil(cmd.Flags().Lookup(fl.NameFromTag(&fl.FollowMode)))
+
+	err := cmd.ParseFlags([]string{"--host", "10.10.0.150:9650", "--online", "--agent-id", "tracer1,tracer2"})
+	sui.Require().NoError(err)
+	sui.Require().NoError(fl.InitFromCmd(cmd))
+	sui.Require().Equal([]string{"tracer1", "tracer2"}, fl.AgentsIds)
+	online, err := cmd.Flags().GetBool(flagOnlineOnly)
+	sui.Require().NoError(err)
+	sui.Require().True(online)
+}
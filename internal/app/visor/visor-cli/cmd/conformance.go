@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/app"
+	. "github.com/wildberries-tech/pkt-tracer/internal/app/visor" //nolint:revive
+	vf "github.com/wildberries-tech/pkt-tracer/internal/app/visor/flags"
+	vc "github.com/wildberries-tech/pkt-tracer/internal/app/visor/visor-cli"
+	"github.com/wildberries-tech/pkt-tracer/internal/config"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagSgroupsAddr    = "sgroups"
+	flagReportInterval = "report-interval"
+)
+
+func newConformanceCommand() *cobra.Command {
+	fl := vf.Flags{}
+	c := &cobra.Command{
+		Use:     "conformance",
+		Short:   "Report packets whose verdict contradicts sgroups policy",
+		Example: "visor-cli conformance -H tcp://10.10.0.150:9650 --sgroups tcp://10.10.0.151:9000 -t 1h -f --report-interval 1m --sg-src sg1,sg2",
+		RunE:    runConformance,
+	}
+	err := fl.Attach(c,
+		vf.WithDefValues{Defvalues: map[string]any{fl.NameFromTag(&fl.LogLevel): "INFO"}},
+		vf.WithPersistentFlags{Pflags: map[string]*pflag.FlagSet{
+			fl.NameFromTag(&fl.LogLevel):    c.PersistentFlags(),
+			fl.NameFromTag(&fl.VerboseMode): c.PersistentFlags(),
+		}},
+	)
+	if err != nil {
+		panic(errors.WithMessage(err, "failed to attach flag"))
+	}
+	c.Flags().String(flagSgroupsAddr, "", "sgroups server address (e.g. tcp://127.0.0.1:9000)")
+	c.Flags().Duration(flagReportInterval, time.Minute, "print the drift report every interval [follow mode]")
+
+	qName := fl.NameFromTag(&fl.Query)
+	for _, p := range fl.GetFlagParamsByGroup("trace") {
+		c.MarkFlagsMutuallyExclusive(qName, p.Name)
+	}
+	c.MarkFlagsRequiredTogether(fl.NameFromTag(&fl.TimeFrom), fl.NameFromTag(&fl.TimeTo))
+	c.MarkFlagsMutuallyExclusive(fl.NameFromTag(&fl.TimeFrom), fl.NameFromTag(&fl.TimeDuration))
+	c.MarkFlagsRequiredTogether(fl.NameFromTag(&fl.TimeDuration), fl.NameFromTag(&fl.FollowMode))
+	c.MarkFlagsOneRequired(fl.NameFromTag(&fl.ConfigPath), fl.NameFromTag(&fl.ServerUrl), fl.NameFromTag(&fl.FromFile))
+	c.MarkFlagsMutuallyExclusive(fl.NameFromTag(&fl.ServerUrl), fl.NameFromTag(&fl.FromFile))
+	SetupContext()
+	return c
+}
+
+func runConformance(cmd *cobra.Command, args []string) (err error) {
+	fl := vf.Flags{}
+	if err = fl.Action(cmd); err != nil {
+		return err
+	}
+	reportInterval, err := cmd.Flags().GetDuration(flagReportInterval)
+	if err != nil {
+		return err
+	}
+	if reportInterval < time.Second {
+		return errors.Errorf("'--%s' is (%v) less than 1s", flagReportInterval, reportInterval)
+	}
+	ctx := app.Context()
+	err = config.InitGlobalConfig(
+		config.WithAcceptEnvironment{EnvPrefix: "VC"},
+		config.WithSourceFile{FileName: fl.ConfigPath},
+
+		config.WithCmdFlag{Key: AppLoggerLevel, Flag: cmd.Flag(fl.NameFromTag(&fl.LogLevel))},
+		config.WithDefValue{Key: AppLoggerLevel, Val: "INFO"},
+
+		config.WithCmdFlag{Key: TrAddress, Flag: cmd.Flag(fl.NameFromTag(&fl.ServerUrl))},
+		config.WithCmdFlag{Key: TrFromFile, Flag: cmd.Flag(fl.NameFromTag(&fl.FromFile))},
+		config.WithDefValue{Key: TrAddress, Val: "tcp://127.0.0.1:9000"},
+
+		config.WithCmdFlag{Key: SGroupsAddress, Flag: cmd.Flag(flagSgroupsAddr)},
+		config.WithDefValue{Key: SGroupsAddress, Val: "tcp://127.0.0.1:9000"},
+
+		config.WithDefValue{Key: ServicesDefDialDuration, Val: 30 * time.Second},
+
+		config.WithDefValue{Key: UseCompression, Val: false},
+		config.WithDefValue{Key: UserAgent, Val: "visor-cli0"},
+	)
+	if err != nil {
+		return err
+	}
+	if err = SetupLogger(fl.JsonFormat); err != nil {
+		return err
+	}
+	md, err := fl.ToTraceScopeModel()
+	if err != nil {
+		return err
+	}
+	if err = vc.RunConformance(ctx, md, fl.JsonFormat, reportInterval); err != nil {
+		select {
+		case <-ctx.Done():
+		default:
+			return err
+		}
+	}
+	return nil
+}
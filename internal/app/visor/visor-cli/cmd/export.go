@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/app"
+	. "github.com/wildberries-tech/pkt-tracer/internal/app/visor" //nolint:revive
+	vf "github.com/wildberries-tech/pkt-tracer/internal/app/visor/flags"
+	vc "github.com/wildberries-tech/pkt-tracer/internal/app/visor/visor-cli"
+	"github.com/wildberries-tech/pkt-tracer/internal/config"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagFormat    = "format"
+	flagOutput    = "output"
+	flagCompress  = "compress"
+	flagChunk     = "chunk"
+	flagBatchSize = "batch-size"
+)
+
+const exportExample = `visor-cli export -H tcp://10.10.0.150:9650 --format pcapng -o drops.pcapng -t 1h --verdict rule::drop
+visor-cli export -H tcp://10.10.0.150:9650 --format parquet --chunk 1h -o ./traces --time-from 2024-12-01T00:00:00Z --time-to 2024-12-08T00:00:00Z`
+
+func newExportCommand() *cobra.Command {
+	fl := vf.Flags{}
+	c := &cobra.Command{
+		Use:     "export",
+		Short:   "Export traces into file",
+		Example: exportExample,
+		RunE:    runExport,
+	}
+	err := fl.Attach(c,
+		vf.WithDefValues{Defvalues: map[string]any{fl.NameFromTag(&fl.LogLevel): "INFO"}},
+		vf.WithPersistentFlags{Pflags: map[string]*pflag.FlagSet{
+			fl.NameFromTag(&fl.LogLevel):    c.PersistentFlags(),
+			fl.NameFromTag(&fl.VerboseMode): c.PersistentFlags(),
+		}},
+	)
+	if err != nil {
+		panic(errors.WithMessage(err, "failed to attach flag"))
+	}
+	c.Flags().String(flagFormat, ExportPcapng,
+		fmt.Sprintf("format of the export: %s", strings.Join(ExportFormats, ", ")))
+	c.Flags().StringP(flagOutput, "o", "-",
+		"file the traces are exported into, '-' is stdout; directory of the chunk files in the chunked export")
+	c.Flags().String(flagCompress, "",
+		fmt.Sprintf("compression of the export: %s; default is none, snappy for parquet",
+			strings.Join(ExportCompressions, ", ")))
+	c.Flags().Duration(flagChunk, 0,
+		"export time range by chunks of the duration into separate files, already exported chunks are skipped")
+	c.Flags().Uint32(flagBatchSize, DefExportBatchSize, "count of the traces the server sends in one message")
+
+	qName := fl.NameFromTag(&fl.Query)
+	for _, p := range fl.GetFlagParamsByGroup("trace") {
+		c.MarkFlagsMutuallyExclusive(qName, p.Name)
+	}
+	c.MarkFlagsRequiredTogether(fl.NameFromTag(&fl.TimeFrom), fl.NameFromTag(&fl.TimeTo))
+	c.MarkFlagsMutuallyExclusive(fl.NameFromTag(&fl.TimeFrom), fl.NameFromTag(&fl.TimeDuration))
+	c.MarkFlagsOneRequired(fl.NameFromTag(&fl.ConfigPath), fl.NameFromTag(&fl.ServerUrl), fl.NameFromTag(&fl.FromFile))
+	c.MarkFlagsMutuallyExclusive(fl.NameFromTag(&fl.ServerUrl), fl.NameFromTag(&fl.FromFile))
+	SetupContext()
+	return c
+}
+
+func runExport(cmd *cobra.Command, args []string) (err error) {
+	fl := vf.Flags{}
+	if err = fl.Action(cmd); err != nil {
+		return err
+	}
+	if fl.FollowMode {
+		return errors.New("follow mode is not supported by the export")
+	}
+	format, err := cmd.Flags().GetString(flagFormat)
+	if err != nil {
+		return err
+	}
+	opts := vc.ExportOptions{Format: format}
+	if opts.Output, err = cmd.Flags().GetString(flagOutput); err != nil {
+		return err
+	}
+	if opts.Compression, err = cmd.Flags().GetString(flagCompress); err != nil {
+		return err
+	}
+	if opts.Chunk, err = cmd.Flags().GetDuration(flagChunk); err != nil {
+		return err
+	}
+	if opts.BatchSize, err = cmd.Flags().GetUint32(flagBatchSize); err != nil {
+		return err
+	}
+	ctx := app.Context()
+	err = config.InitGlobalConfig(
+		config.WithAcceptEnvironment{EnvPrefix: "VC"},
+		config.WithSourceFile{FileName: fl.ConfigPath},
+
+		config.WithCmdFlag{Key: AppLoggerLevel, Flag: cmd.Flag(fl.NameFromTag(&fl.LogLevel))},
+		config.WithDefValue{Key: AppLoggerLevel, Val: "INFO"},
+
+		config.WithCmdFlag{Key: TrAddress, Flag: cmd.Flag(fl.NameFromTag(&fl.ServerUrl))},
+		config.WithCmdFlag{Key: TrFromFile, Flag: cmd.Flag(fl.NameFromTag(&fl.FromFile))},
+		config.WithDefValue{Key: TrAddress, Val: "tcp://127.0.0.1:9000"},
+
+		config.WithDefValue{Key: ServicesDefDialDuration, Val: 30 * time.Second},
+
+		config.WithDefValue{Key: UseCompression, Val: false},
+		config.WithDefValue{Key: UserAgent, Val: "visor-cli0"},
+	)
+	if err != nil {
+		return err
+	}
+	if err = SetupLogger(fl.JsonFormat); err != nil {
+		return err
+	}
+	md, err := fl.ToTraceScopeModel()
+	if err != nil {
+		return err
+	}
+	if err = vc.RunExport(ctx, md, opts); err != nil {
+		select {
+		case <-ctx.Done():
+		default:
+			return err
+		}
+	}
+	return nil
+}
@@ -20,7 +20,7 @@ func newRootCmd() *cobra.Command {
 		Short:   shortAppDesc,
 		Long:    longAppDesc,
 	}
-	rootCmd.AddCommand(newWatcherCommand())
+	rootCmd.AddCommand(newWatcherCommand(), newAgentsCommand(), newConformanceCommand(), newExportCommand())
 	return rootCmd
 }
 
@@ -40,7 +40,8 @@ func newWatcherCommand() *cobra.Command {
 	c.MarkFlagsRequiredTogether(fl.NameFromTag(&fl.TimeFrom), fl.NameFromTag(&fl.TimeTo))
 	c.MarkFlagsMutuallyExclusive(fl.NameFromTag(&fl.TimeFrom), fl.NameFromTag(&fl.TimeDuration))
 	c.MarkFlagsRequiredTogether(fl.NameFromTag(&fl.TimeDuration), fl.NameFromTag(&fl.FollowMode))
-	c.MarkFlagsOneRequired(fl.NameFromTag(&fl.ConfigPath), fl.NameFromTag(&fl.ServerUrl))
+	c.MarkFlagsOneRequired(fl.NameFromTag(&fl.ConfigPath), fl.NameFromTag(&fl.ServerUrl), fl.NameFromTag(&fl.FromFile))
+	c.MarkFlagsMutuallyExclusive(fl.NameFromTag(&fl.ServerUrl), fl.NameFromTag(&fl.FromFile))
 	SetupContext()
 	return c
 }
@@ -60,6 +61,7 @@ func run(cmd *cobra.Command, args []string) (err error) {
 		config.WithDefValue{Key: AppLoggerLevel, Val: "INFO"},
 
 		config.WithCmdFlag{Key: TrAddress, Flag: cmd.Flag(fl.NameFromTag(&fl.ServerUrl))},
+		config.WithCmdFlag{Key: TrFromFile, Flag: cmd.Flag(fl.NameFromTag(&fl.FromFile))},
 		config.WithDefValue{Key: TrAddress, Val: "tcp://127.0.0.1:9000"},
 
 		config.WithDefValue{Key: ServicesDefDialDuration, Val: 30 * time.Second},
@@ -2,12 +2,17 @@ package visor_cli
 
 import (
 	"context"
+	"os"
+	"time"
 
 	. "github.com/wildberries-tech/pkt-tracer/internal/app/visor" //nolint:revive
+	"github.com/wildberries-tech/pkt-tracer/internal/models/agent"
 	"github.com/wildberries-tech/pkt-tracer/internal/models/trace"
 	"github.com/wildberries-tech/pkt-tracer/internal/nftrace/printer"
+	sgnw "github.com/wildberries-tech/pkt-tracer/internal/providers/sg-network"
 
 	"github.com/H-BF/corlib/logger"
+	"github.com/pkg/errors"
 )
 
 type mainJob struct {
@@ -57,3 +62,102 @@ func RunJobs(ctx context.Context, traceScope trace.TraceScopeModel, jsonFlag boo
 	}
 	return jb.visor.Run(ctx, traceScope)
 }
+
+func RunListAgents(ctx context.Context, scope agent.AgentScopeModel, jsonFlag bool) (err error) {
+	thClient, err := NewTHClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer thClient.CloseConn() //nolint:errcheck
+
+	agents, err := FetchAgents(ctx, THClient{TraceHubServiceClient: thClient.TraceHubServiceClient}, scope)
+	if err != nil {
+		return err
+	}
+	return PrintAgents(os.Stdout, agents, jsonFlag)
+}
+
+// RunConformance checks traces against the sgroups policy and prints drifts, while traces are
+// followed the report is printed every 'reportInterval'
+func RunConformance(ctx context.Context, traceScope trace.TraceScopeModel, jsonFlag bool, reportInterval time.Duration) (err error) {
+	thClient, err := NewTHClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer thClient.CloseConn() //nolint:errcheck
+
+	sgClient, err := NewSGClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer sgClient.CloseConn() //nolint:errcheck
+
+	if !traceScope.FollowMode {
+		reportInterval = 0
+	}
+	return CheckConformance(ctx, ConformanceDeps{
+		Client: THClient{TraceHubServiceClient: thClient.TraceHubServiceClient},
+		FetchPolicy: func(ctx context.Context) (PolicyProvider, error) {
+			return sgnw.FetchPolicy(ctx, sgClient.SecGroupServiceClient)
+		},
+		Out:            os.Stdout,
+		JsonFormat:     jsonFlag,
+		ReportInterval: reportInterval,
+	}, traceScope)
+}
+
+// ExportOptions - options of the export
+type ExportOptions struct {
+	Format      string
+	Compression string
+	// Output - file of the export, '-' is stdout; directory of the chunk files when Chunk is set
+	Output string
+	// Chunk - time range of the traces exported into one file
+	Chunk     time.Duration
+	BatchSize uint32
+}
+
+// RunExport exports traces of the scope according to the options
+func RunExport(ctx context.Context, traceScope trace.TraceScopeModel, opts ExportOptions) (err error) {
+	if opts.Chunk > 0 && opts.Output == "-" {
+		return errors.New("chunked export requires directory of the chunk files as the output")
+	}
+	thClient, err := NewTHClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer thClient.CloseConn() //nolint:errcheck
+	client := THClient{TraceHubServiceClient: thClient.TraceHubServiceClient}
+
+	if opts.Chunk > 0 {
+		return ExportChunks(ctx, ExportChunksDeps{
+			Client:      client,
+			Format:      opts.Format,
+			Compression: opts.Compression,
+			Dir:         opts.Output,
+			Chunk:       opts.Chunk,
+			BatchSize:   opts.BatchSize,
+		}, traceScope)
+	}
+
+	out := os.Stdout
+	if opts.Output != "-" {
+		if out, err = os.Create(opts.Output); err != nil {
+			return err
+		}
+		defer func() {
+			if e := out.Close(); err == nil {
+				err = e
+			}
+		}()
+	}
+	exporter, err := NewTraceExporter(opts.Format, opts.Compression, out)
+	if err != nil {
+		return err
+	}
+	return ExportTraces(ctx, ExportDeps{
+		Client:    client,
+		Exporter:  exporter,
+		BatchSize: opts.BatchSize,
+	}, traceScope)
+}
@@ -0,0 +1,367 @@
+package visor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/conntrack"
+	"github.com/wildberries-tech/pkt-tracer/internal/dto"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	sgnw "github.com/wildberries-tech/pkt-tracer/internal/providers/sg-network"
+	proto "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// conntrack states of the connections opened yet
+const (
+	ctStateEstablished = "established"
+	ctStateRelated     = "related"
+)
+
+// drifts of the observed verdict from the sgroups policy
+const (
+	// DriftDroppedAllowed - packet is dropped although the sgroups rule allows the flow
+	DriftDroppedAllowed = "dropped-allowed"
+	// DriftAcceptedNotAllowed - packet is accepted but no sgroups rule allows the flow
+	DriftAcceptedNotAllowed = "accepted-not-allowed"
+)
+
+type (
+	// PolicyProvider - sgroups policy the traces are checked against
+	PolicyProvider interface {
+		GetSGByIP(ip net.IP) (sgnw.SgNet, error)
+		GetSgRule(p sgnw.Packet, action string) (sgnw.SgRule, error)
+	}
+
+	// Drift - packets of the flow whose observed verdict contradicts the policy
+	Drift struct {
+		// SgFrom - security group of the source
+		SgFrom string `json:"sg-src"`
+		// SgTo - security group of the destination
+		SgTo string `json:"sg-dst"`
+		// Proto - ip protocol
+		Proto string `json:"proto"`
+		// Port - destination port, type for ICMP
+		Port uint32 `json:"port"`
+		// Kind - dropped-allowed or accepted-not-allowed
+		Kind string `json:"drift"`
+		// Rule - sgroups rule the flow is intended to be decided by, empty when there is none
+		Rule string `json:"intended-rule,omitempty"`
+		// Count - number of the drifted packets
+		Count uint64 `json:"count"`
+		// FirstSeen, LastSeen - time stamps of the first and the last drifted packets
+		FirstSeen time.Time `json:"first-seen"`
+		LastSeen  time.Time `json:"last-seen"`
+		// Sample - the last drifted packet
+		Sample string `json:"sample"`
+	}
+
+	// ConformanceReport - drifts of the traces from the policy
+	ConformanceReport struct {
+		// At - time the report is made
+		At time.Time `json:"at"`
+		// Checked - number of the packets accepted or dropped between security groups
+		Checked uint64 `json:"checked"`
+		// Drifted - number of the packets whose verdict contradicts the policy
+		Drifted uint64 `json:"drifted"`
+		// Drifts - drifts grouped by security groups, protocol and port, the most frequent go first
+		Drifts []Drift `json:"drifts"`
+	}
+
+	// ConformanceChecker - checks verdicts of the traces against the sgroups policy
+	ConformanceChecker struct {
+		policy  PolicyProvider
+		checked uint64
+		drifted uint64
+		drifts  map[driftKey]*Drift
+	}
+
+	driftKey struct {
+		sgFrom, sgTo, proto, kind string
+		port                      uint32
+	}
+)
+
+// NewConformanceChecker -
+func NewConformanceChecker(p PolicyProvider) *ConformanceChecker {
+	return &ConformanceChecker{
+		policy: p,
+		drifts: make(map[driftKey]*Drift),
+	}
+}
+
+// SetPolicy replaces the policy, drifts found so far are kept
+func (c *ConformanceChecker) SetPolicy(p PolicyProvider) {
+	c.policy = p
+}
+
+// Check checks the trace against the policy and returns kind of the drift, it is empty when
+// the trace conforms to the policy or it is out of the policy: the packet has not been accepted
+// or dropped yet, it follows the connection opened yet or neither of its addresses belongs
+// to a security group
+func (c *ConformanceChecker) Check(tr *model.FetchTraceModel) string {
+	action := traceAction(tr.Verdict)
+	if action == "" || ctFollowUp(tr) {
+		return ""
+	}
+	p := sgnw.Packet{
+		SAddr: net.ParseIP(tr.SAddr),
+		DAddr: net.ParseIP(tr.DAddr),
+		SPort: uint16(tr.SPort), //nolint:gosec
+		DPort: uint16(tr.DPort), //nolint:gosec
+	}
+	if p.SAddr == nil || p.DAddr == nil {
+		return ""
+	}
+	if nw, err := c.policy.GetSGByIP(p.SAddr); err == nil {
+		p.SSg = nw.SgName
+	}
+	if nw, err := c.policy.GetSGByIP(p.DAddr); err == nil {
+		p.DSg = nw.SgName
+	}
+	if p.SSg == "" && p.DSg == "" {
+		return ""
+	}
+	port := tr.DPort
+	switch tr.IpProto {
+	case "tcp":
+		p.Proto = unix.IPPROTO_TCP
+	case "udp":
+		p.Proto = unix.IPPROTO_UDP
+	case "icmp", "icmpv6":
+		p.Proto = unix.IPPROTO_ICMP
+		if tr.IpProto == "icmpv6" {
+			p.Proto = unix.IPPROTO_ICMPV6
+		}
+		// type and code of ICMP are in place of the source port
+		p.IcmpType = uint8(tr.SPort >> 8) //nolint:gosec
+		port = uint32(p.IcmpType)
+	default:
+		return "" //sgroups has no rules for the protocol
+	}
+	c.checked++
+
+	var kind string
+	r, err := c.policy.GetSgRule(p, "")
+	intended := r.Action
+	if err != nil {
+		intended = ""
+	}
+	switch {
+	case action == sgnw.RuleActionDrop && intended == sgnw.RuleActionAccept:
+		kind = DriftDroppedAllowed
+	case action == sgnw.RuleActionAccept && intended != sgnw.RuleActionAccept:
+		kind = DriftAcceptedNotAllowed
+	default:
+		return ""
+	}
+	c.drifted++
+	k := driftKey{sgFrom: p.SSg, sgTo: p.DSg, proto: tr.IpProto, kind: kind, port: port}
+	d := c.drifts[k]
+	if d == nil {
+		d = &Drift{
+			SgFrom:    p.SSg,
+			SgTo:      p.DSg,
+			Proto:     tr.IpProto,
+			Port:      port,
+			Kind:      kind,
+			FirstSeen: tr.Timestamp,
+		}
+		c.drifts[k] = d
+	}
+	d.Rule = r.Id
+	d.Count++
+	if tr.Timestamp.Before(d.FirstSeen) {
+		d.FirstSeen = tr.Timestamp
+	}
+	if !tr.Timestamp.Before(d.LastSeen) {
+		d.LastSeen = tr.Timestamp
+		d.Sample = fmt.Sprintf("%s:%d -> %s:%d agent=%s trace=%d",
+			tr.SAddr, tr.SPort, tr.DAddr, tr.DPort, tr.UserAgent, tr.TrId)
+	}
+	return kind
+}
+
+// Report returns drifts found so far
+func (c *ConformanceChecker) Report() ConformanceReport {
+	ret := ConformanceReport{
+		At:      time.Now(),
+		Checked: c.checked,
+		Drifted: c.drifted,
+		Drifts:  make([]Drift, 0, len(c.drifts)),
+	}
+	for _, d := range c.drifts {
+		ret.Drifts = append(ret.Drifts, *d)
+	}
+	sort.Slice(ret.Drifts, func(i, j int) bool {
+		a, b := ret.Drifts[i], ret.Drifts[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.SgFrom != b.SgFrom {
+			return a.SgFrom < b.SgFrom
+		}
+		if a.SgTo != b.SgTo {
+			return a.SgTo < b.SgTo
+		}
+		if a.Proto != b.Proto {
+			return a.Proto < b.Proto
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.Kind < b.Kind
+	})
+	return ret
+}
+
+// PrintConformanceReport prints the report as a table or as a json line
+func PrintConformanceReport(w io.Writer, r ConformanceReport, jsonFormat bool) error {
+	if jsonFormat {
+		return json.NewEncoder(w).Encode(r)
+	}
+	fmt.Fprintf(w, "drift report at %s: %d packets checked, %d drifted\n",
+		r.At.Local().Format(time.DateTime), r.Checked, r.Drifted)
+	if len(r.Drifts) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SG-SRC\tSG-DST\tPROTO\tPORT\tDRIFT\tINTENDED-RULE\tCOUNT\tFIRST-SEEN\tLAST-SEEN\tSAMPLE")
+	for _, d := range r.Drifts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			orDash(d.SgFrom), orDash(d.SgTo), d.Proto, d.Port, d.Kind, orDash(d.Rule), d.Count,
+			formatAgentTime(d.FirstSeen), formatAgentTime(d.LastSeen), d.Sample)
+	}
+	return tw.Flush()
+}
+
+// ConformanceDeps - dependency of the conformance check
+type ConformanceDeps struct {
+	Client THClient
+	// FetchPolicy - fetches the actual sgroups policy
+	FetchPolicy func(context.Context) (PolicyProvider, error)
+	Out         io.Writer
+	JsonFormat  bool
+	// ReportInterval - the report is printed and the policy is refetched every interval while
+	// traces are followed, zero means the report is printed once when the traces are over
+	ReportInterval time.Duration
+}
+
+// CheckConformance checks traces of the scope against the sgroups policy and prints the drift report
+func CheckConformance(ctx context.Context, d ConformanceDeps, flt model.TraceScopeModel) (err error) {
+	log := logger.FromContext(ctx).Named("conformance")
+	log.Debug("start")
+	defer log.Debug("stop")
+
+	policy, err := d.FetchPolicy(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to fetch sgroups policy")
+	}
+	checker := NewConformanceChecker(policy)
+
+	var dtoTraceScope dto.TraceScopeDTO
+	dtoTraceScope.InitFromModel(&flt)
+	stream, err := d.Client.FetchTraces(ctx, dtoTraceScope.ToProto())
+	if err != nil {
+		return errors.WithMessage(err, "failed to obtain trace dump stream from server")
+	}
+	log.Debug("connected to trace-hub server")
+
+	incoming := make(chan any)
+	go func() {
+		defer close(incoming)
+		for {
+			var val any
+			val, e := stream.Recv()
+			if e != nil {
+				val = e
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case incoming <- val:
+			}
+			if e != nil {
+				return
+			}
+		}
+	}()
+
+	var tick <-chan time.Time
+	if d.ReportInterval > 0 {
+		tc := time.NewTicker(d.ReportInterval)
+		defer tc.Stop()
+		tick = tc.C
+	}
+	for err == nil {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-tick:
+			if p, e := d.FetchPolicy(ctx); e != nil {
+				log.Warnf("failed to refetch sgroups policy, the previous one is kept: %v", e)
+			} else {
+				checker.SetPolicy(p)
+			}
+			err = PrintConformanceReport(d.Out, checker.Report(), d.JsonFormat)
+		case val := <-incoming:
+			switch t := val.(type) {
+			case error:
+				err = t
+			case *proto.TraceList:
+				for _, tr := range t.GetTraces() {
+					var dtoTrace dto.FetchTraceDTO
+					dtoTrace.InitFromProto(tr)
+					checker.Check(dtoTrace.ToModel())
+				}
+			}
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		log.Debug("receive completed")
+		err = PrintConformanceReport(d.Out, checker.Report(), d.JsonFormat)
+	}
+	return err
+}
+
+// traceAction - final action of the verdict, empty when the packet has not been accepted
+// or dropped yet
+func traceAction(verdict string) string {
+	switch {
+	case strings.HasSuffix(verdict, "::"+sgnw.RuleActionAccept):
+		return sgnw.RuleActionAccept
+	case strings.HasSuffix(verdict, "::"+sgnw.RuleActionDrop):
+		return sgnw.RuleActionDrop
+	}
+	return ""
+}
+
+// ctFollowUp - packet is the reply or it belongs to the established or related connection,
+// sgroups rules decide packets opening connections and the rest are decided by conntrack state.
+// Traces without conntrack state are checked as opening ones
+func ctFollowUp(tr *model.FetchTraceModel) bool {
+	switch {
+	case tr.CtDirection == conntrack.DirReply:
+		return true
+	case tr.CtState == ctStateEstablished, tr.CtState == ctStateRelated:
+		return true
+	}
+	return false
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
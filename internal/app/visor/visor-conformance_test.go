@@ -0,0 +1,134 @@
+package visor
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	sgnw "github.com/wildberries-tech/pkt-tracer/internal/providers/sg-network"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/sys/unix"
+)
+
+type conformanceTestSuite struct {
+	suite.Suite
+}
+
+func Test_Conformance(t *testing.T) {
+	suite.Run(t, new(conformanceTestSuite))
+}
+
+// policyMock - 10.0.1.0/24 is 'web', 10.0.2.0/24 is 'db'; web->db tcp/5432 is accepted,
+// web->db tcp/22 is dropped
+type policyMock struct{}
+
+func (policyMock) GetSGByIP(ip net.IP) (sgnw.SgNet, error) {
+	for _, x := range []struct{ cidr, sg string }{{"10.0.1.0/24", "web"}, {"10.0.2.0/24", "db"}} {
+		_, n, _ := net.ParseCIDR(x.cidr)
+		if n.Contains(ip) {
+			return sgnw.SgNet{Network: sgnw.Network{Net: *n, Name: x.cidr}, SgName: x.sg}, nil
+		}
+	}
+	return sgnw.SgNet{}, sgnw.ErrSgMiss
+}
+
+func (policyMock) GetSgRule(p sgnw.Packet, action string) (sgnw.SgRule, error) {
+	if p.SSg != "web" || p.DSg != "db" || p.Proto != unix.IPPROTO_TCP {
+		return sgnw.SgRule{}, sgnw.ErrSgRuleMiss
+	}
+	r := sgnw.SgRule{Kind: sgnw.RuleSgSg}
+	switch p.DPort {
+	case 5432:
+		r.Id, r.Action = "sg-sg:web->db:tcp", sgnw.RuleActionAccept
+	case 22:
+		r.Id, r.Action = "sg-sg:web->db:tcp#ssh", sgnw.RuleActionDrop
+	default:
+		return sgnw.SgRule{}, sgnw.ErrSgRuleMiss
+	}
+	if action != "" && action != r.Action {
+		return sgnw.SgRule{}, sgnw.ErrSgRuleMiss
+	}
+	return r, nil
+}
+
+func (sui *conformanceTestSuite) Test_Check() {
+	ts := time.Date(2024, 12, 2, 12, 0, 0, 0, time.UTC)
+	tr := func(saddr, daddr, proto string, dport uint32, verdict string) *model.FetchTraceModel {
+		return &model.FetchTraceModel{
+			SAddr: saddr, DAddr: daddr, IpProto: proto,
+			SPort: 40000, DPort: dport, Verdict: verdict, Timestamp: ts,
+		}
+	}
+	withCt := func(t *model.FetchTraceModel, state, dir string) *model.FetchTraceModel {
+		t.CtState, t.CtDirection = state, dir
+		return t
+	}
+	testCases := []struct {
+		name string
+		tr   *model.FetchTraceModel
+		exp  string
+	}{
+		{"allowed and accepted", tr("10.0.1.1", "10.0.2.1", "tcp", 5432, "rule::accept"), ""},
+		{"allowed but dropped", tr("10.0.1.1", "10.0.2.1", "tcp", 5432, "policy::drop"), DriftDroppedAllowed},
+		{"denied and dropped", tr("10.0.1.1", "10.0.2.1", "tcp", 22, "rule::drop"), ""},
+		{"denied but accepted", tr("10.0.1.1", "10.0.2.1", "tcp", 22, "rule::accept"), DriftAcceptedNotAllowed},
+		{"no rule but accepted", tr("10.0.1.1", "10.0.2.1", "udp", 53, "rule::accept"), DriftAcceptedNotAllowed},
+		{"no rule and dropped", tr("10.0.1.1", "10.0.2.1", "udp", 53, "rule::drop"), ""},
+		{"not decided yet", tr("10.0.1.1", "10.0.2.1", "tcp", 5432, "rule::jump->"), ""},
+		{"out of security groups", tr("192.168.0.1", "192.168.0.2", "tcp", 80, "rule::accept"), ""},
+		{"no address", tr("", "10.0.2.1", "tcp", 80, "rule::accept"), ""},
+		{"reply is accepted by conntrack", withCt(tr("10.0.2.1", "10.0.1.1", "tcp", 40000, "rule::accept"), "established", "reply"), ""},
+		{"established is accepted by conntrack", withCt(tr("10.0.1.1", "10.0.2.1", "tcp", 22, "rule::accept"), "established", "original"), ""},
+		{"related is accepted by conntrack", withCt(tr("10.0.2.1", "10.0.1.1", "icmp", 0, "rule::accept"), "related", "original"), ""},
+		{"new connection is checked", withCt(tr("10.0.1.1", "10.0.2.1", "tcp", 22, "rule::accept"), "new", "original"), DriftAcceptedNotAllowed},
+	}
+	for _, tc := range testCases {
+		sui.Run(tc.name, func() {
+			c := NewConformanceChecker(policyMock{})
+			sui.Require().Equal(tc.exp, c.Check(tc.tr))
+		})
+	}
+}
+
+func (sui *conformanceTestSuite) Test_Report() {
+	c := NewConformanceChecker(policyMock{})
+	t0 := time.Date(2024, 12, 2, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		c.Check(&model.FetchTraceModel{
+			TrId: uint32(i), SAddr: "10.0.1.1", DAddr: "10.0.2.1", IpProto: "tcp", SPort: 40000, DPort: 5432,
+			Verdict: "policy::drop", Timestamp: t0.Add(time.Duration(i) * time.Second),
+		})
+	}
+	c.Check(&model.FetchTraceModel{
+		SAddr: "10.0.2.7", DAddr: "10.0.1.1", IpProto: "udp", SPort: 53, DPort: 40000,
+		Verdict: "rule::accept", Timestamp: t0,
+	})
+	c.Check(&model.FetchTraceModel{
+		SAddr: "10.0.1.1", DAddr: "10.0.2.1", IpProto: "tcp", SPort: 40000, DPort: 5432,
+		Verdict: "rule::accept", Timestamp: t0,
+	})
+
+	r := c.Report()
+	sui.Require().Equal(uint64(5), r.Checked)
+	sui.Require().Equal(uint64(4), r.Drifted)
+	sui.Require().Len(r.Drifts, 2)
+	sui.Require().Equal(Drift{
+		SgFrom: "web", SgTo: "db", Proto: "tcp", Port: 5432, Kind: DriftDroppedAllowed,
+		Rule: "sg-sg:web->db:tcp", Count: 3, FirstSeen: t0, LastSeen: t0.Add(2 * time.Second),
+		Sample: "10.0.1.1:40000 -> 10.0.2.1:5432 agent= trace=2",
+	}, r.Drifts[0])
+	sui.Require().Equal("db", r.Drifts[1].SgFrom)
+	sui.Require().Equal(DriftAcceptedNotAllowed, r.Drifts[1].Kind)
+	sui.Require().Empty(r.Drifts[1].Rule)
+
+	var buf bytes.Buffer
+	sui.Require().NoError(PrintConformanceReport(&buf, r, false))
+	sui.Require().Contains(buf.String(), "5 packets checked, 4 drifted")
+	sui.Require().Contains(buf.String(), "sg-sg:web->db:tcp")
+	buf.Reset()
+	sui.Require().NoError(PrintConformanceReport(&buf, r, true))
+	sui.Require().Contains(buf.String(), `"drift":"dropped-allowed"`)
+}
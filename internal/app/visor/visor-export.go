@@ -0,0 +1,644 @@
+package visor
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/dto"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nl/nlheaders"
+	"github.com/wildberries-tech/pkt-tracer/internal/pcapng"
+	proto "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/klauspost/compress/zstd"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	gzipcodec "github.com/parquet-go/parquet-go/compress/gzip"
+	"github.com/parquet-go/parquet-go/compress/snappy"
+	"github.com/parquet-go/parquet-go/compress/uncompressed"
+	zstdcodec "github.com/parquet-go/parquet-go/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// formats of the export
+const (
+	ExportPcapng  = "pcapng"
+	ExportCsv     = "csv"
+	ExportJsonl   = "jsonl"
+	ExportParquet = "parquet"
+)
+
+// compressions of the export
+const (
+	CompressNone   = "none"
+	CompressGzip   = "gzip"
+	CompressZstd   = "zstd"
+	CompressSnappy = "snappy"
+)
+
+// ExportFormats - formats the traces can be exported in
+var ExportFormats = []string{ExportPcapng, ExportCsv, ExportJsonl, ExportParquet}
+
+// ExportCompressions - compressions of the export, empty one is the default of the format:
+// none for the stream formats and snappy for parquet which compresses its columns itself
+var ExportCompressions = []string{CompressNone, CompressGzip, CompressZstd, CompressSnappy}
+
+const (
+	// DefExportBatchSize - default count of the traces the server sends in one message
+	DefExportBatchSize = 1000
+
+	parquetRowGroupSize = 100000
+
+	exportChunkTimeLayout = "20060102T150405Z"
+)
+
+type (
+	// TraceExporter - writes traces in the format of the export
+	TraceExporter interface {
+		// Export writes the trace, false is returned when the trace can not be exported in the format
+		Export(*model.FetchTraceModel) (bool, error)
+		// Close flushes the export, the writer of the export is not closed
+		Close() error
+	}
+
+	// ExportDeps - dependencies of the export
+	ExportDeps struct {
+		Client   THClient
+		Exporter TraceExporter
+		// BatchSize - count of the traces the server sends in one message, 0 is the server default
+		BatchSize uint32
+	}
+
+	// ExportRow - stable column set of the tabular exports (csv, jsonl, parquet),
+	// columns may be added to the end only
+	ExportRow struct {
+		Timestamp     time.Time `json:"timestamp" parquet:"timestamp,timestamp(microsecond)" csv:"timestamp"`
+		Agent         string    `json:"agent" parquet:"agent,dict" csv:"agent"`
+		TraceId       uint32    `json:"trace_id" parquet:"trace_id" csv:"trace_id"`
+		Table         string    `json:"table" parquet:"table,dict" csv:"table"`
+		Chain         string    `json:"chain" parquet:"chain,dict" csv:"chain"`
+		JumpTarget    string    `json:"jump_target" parquet:"jump_target,dict" csv:"jump_target"`
+		RuleHandle    uint64    `json:"rule_handle" parquet:"rule_handle" csv:"rule_handle"`
+		Rule          string    `json:"rule" parquet:"rule,dict" csv:"rule"`
+		Verdict       string    `json:"verdict" parquet:"verdict,dict" csv:"verdict"`
+		Iifname       string    `json:"iif" parquet:"iif,dict" csv:"iif"`
+		Oifname       string    `json:"oif" parquet:"oif,dict" csv:"oif"`
+		Family        string    `json:"family" parquet:"family,dict" csv:"family"`
+		IpProto       string    `json:"proto" parquet:"proto,dict" csv:"proto"`
+		Length        uint32    `json:"len" parquet:"len" csv:"len"`
+		SMacAddr      string    `json:"hw_src" parquet:"hw_src" csv:"hw_src"`
+		DMacAddr      string    `json:"hw_dst" parquet:"hw_dst" csv:"hw_dst"`
+		SAddr         string    `json:"ip_src" parquet:"ip_src" csv:"ip_src"`
+		DAddr         string    `json:"ip_dst" parquet:"ip_dst" csv:"ip_dst"`
+		SPort         uint32    `json:"sport" parquet:"sport" csv:"sport"`
+		DPort         uint32    `json:"dport" parquet:"dport" csv:"dport"`
+		SSgName       string    `json:"sg_src" parquet:"sg_src,dict" csv:"sg_src"`
+		DSgName       string    `json:"sg_dst" parquet:"sg_dst,dict" csv:"sg_dst"`
+		SSgNet        string    `json:"net_src" parquet:"net_src,dict" csv:"net_src"`
+		DSgNet        string    `json:"net_dst" parquet:"net_dst,dict" csv:"net_dst"`
+		SgRule        string    `json:"sg_rule" parquet:"sg_rule,dict" csv:"sg_rule"`
+		SgRuleAction  string    `json:"sg_rule_action" parquet:"sg_rule_action,dict" csv:"sg_rule_action"`
+		NetNS         string    `json:"netns" parquet:"netns,dict" csv:"netns"`
+		ContainerId   string    `json:"container_id" parquet:"container_id,dict" csv:"container_id"`
+		ContainerName string    `json:"container" parquet:"container,dict" csv:"container"`
+		Pod           string    `json:"pod" parquet:"pod,dict" csv:"pod"`
+		PodNamespace  string    `json:"pod_ns" parquet:"pod_ns,dict" csv:"pod_ns"`
+		Labels        []string  `json:"labels" parquet:"labels,list" csv:"labels"`
+		SockInode     uint64    `json:"sock_inode" parquet:"sock_inode" csv:"sock_inode"`
+		Pid           uint32    `json:"pid" parquet:"pid" csv:"pid"`
+		Comm          string    `json:"comm" parquet:"comm,dict" csv:"comm"`
+		Cgroup        string    `json:"cgroup" parquet:"cgroup,dict" csv:"cgroup"`
+		CtState       string    `json:"ct_state" parquet:"ct_state,dict" csv:"ct_state"`
+		CtDirection   string    `json:"ct_dir" parquet:"ct_dir,dict" csv:"ct_dir"`
+		CtNat         string    `json:"ct_nat" parquet:"ct_nat,dict" csv:"ct_nat"`
+		CtMark        uint32    `json:"ct_mark" parquet:"ct_mark" csv:"ct_mark"`
+		CtZone        uint32    `json:"ct_zone" parquet:"ct_zone" csv:"ct_zone"`
+		CtOrig        string    `json:"ct_orig" parquet:"ct_orig" csv:"ct_orig"`
+		CtReply       string    `json:"ct_reply" parquet:"ct_reply" csv:"ct_reply"`
+		Source        string    `json:"source" parquet:"source,dict" csv:"source"`
+		LogPrefix     string    `json:"log_prefix" parquet:"log_prefix,dict" csv:"log_prefix"`
+		LogGroup      uint32    `json:"log_group" parquet:"log_group" csv:"log_group"`
+		Hook          string    `json:"hook" parquet:"hook,dict" csv:"hook"`
+	}
+
+	// ExportChunksDeps - dependencies of the chunked export
+	ExportChunksDeps struct {
+		Client      THClient
+		Format      string
+		Compression string
+		// Dir - directory of the chunk files
+		Dir string
+		// Chunk - time range of the traces exported into one file
+		Chunk     time.Duration
+		BatchSize uint32
+	}
+
+	pcapngExporter struct {
+		w *pcapng.Writer
+	}
+
+	csvExporter struct {
+		w *csv.Writer
+	}
+
+	jsonlExporter struct {
+		enc *json.Encoder
+	}
+
+	parquetExporter struct {
+		w *parquet.GenericWriter[ExportRow]
+	}
+
+	// compressedExporter - closes the compression of the stream after the export
+	compressedExporter struct {
+		TraceExporter
+		cw io.WriteCloser
+	}
+)
+
+// NewTraceExporter creates exporter of the traces in the format into 'w' with the compression,
+// empty compression is the default of the format
+func NewTraceExporter(format, compression string, w io.Writer) (TraceExporter, error) {
+	if !slices.Contains(ExportFormats, format) {
+		return nil, errors.Errorf("unknown export format '%s', expected one of: %s",
+			format, strings.Join(ExportFormats, ", "))
+	}
+	if compression != "" && !slices.Contains(ExportCompressions, compression) {
+		return nil, errors.Errorf("unknown export compression '%s', expected one of: %s",
+			compression, strings.Join(ExportCompressions, ", "))
+	}
+	if format == ExportParquet {
+		return newParquetExporter(compression, w), nil
+	}
+	var cw io.WriteCloser
+	switch compression {
+	case "", CompressNone:
+	case CompressGzip:
+		cw = gzip.NewWriter(w)
+	case CompressZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		cw = zw
+	default:
+		return nil, errors.Errorf("compression '%s' is supported by the '%s' format only",
+			compression, ExportParquet)
+	}
+	if cw != nil {
+		w = cw
+	}
+	var e TraceExporter
+	switch format {
+	case ExportPcapng:
+		pw, err := pcapng.NewWriter(w, "visor-cli")
+		if err != nil {
+			return nil, err
+		}
+		e = &pcapngExporter{w: pw}
+	case ExportCsv:
+		ce := &csvExporter{w: csv.NewWriter(w)}
+		if err := ce.w.Write(exportColumns()); err != nil {
+			return nil, err
+		}
+		e = ce
+	case ExportJsonl:
+		e = &jsonlExporter{enc: json.NewEncoder(w)}
+	}
+	if cw != nil {
+		e = &compressedExporter{TraceExporter: e, cw: cw}
+	}
+	return e, nil
+}
+
+// ExportFileExt - extension of the export file in the format with the compression
+func ExportFileExt(format, compression string) string {
+	ext := "." + format
+	switch {
+	case format == ExportParquet:
+	case compression == CompressGzip:
+		ext += ".gz"
+	case compression == CompressZstd:
+		ext += ".zst"
+	}
+	return ext
+}
+
+// ExportTraces exports traces of the scope ordered by time
+func ExportTraces(ctx context.Context, d ExportDeps, flt model.TraceScopeModel) (err error) {
+	log := logger.FromContext(ctx).Named("export")
+	log.Debug("start")
+	defer log.Debug("stop")
+
+	var dtoTraceScope dto.TraceScopeDTO
+	dtoTraceScope.InitFromModel(&flt)
+	stream, err := d.Client.ExportTraces(ctx, &proto.ExportTracesReq{
+		Scope:     dtoTraceScope.ToProto(),
+		BatchSize: d.BatchSize,
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to obtain trace export stream from server")
+	}
+	log.Debug("connected to trace-hub server")
+
+	var exported, skipped int
+	for err == nil {
+		var t *proto.TraceList
+		if t, err = stream.Recv(); err != nil {
+			break
+		}
+		for _, tr := range t.GetTraces() {
+			var dtoTrace dto.FetchTraceDTO
+			dtoTrace.InitFromProto(tr)
+			var ok bool
+			if ok, err = d.Exporter.Export(dtoTrace.ToModel()); err != nil {
+				break
+			}
+			if ok {
+				exported++
+			} else {
+				skipped++
+			}
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		log.Debug("receive completed")
+		err = nil
+	}
+	if e := d.Exporter.Close(); err == nil {
+		err = e
+	}
+	log.Infof("%d traces exported", exported)
+	if skipped > 0 {
+		log.Warnf("%d traces are skipped cause they can not be exported", skipped)
+	}
+	return err
+}
+
+// ExportChunks exports time range of the scope by chunks of the duration into separate files
+// of the directory, chunk files which are already exported are skipped, so the interrupted
+// export is resumed by the same command
+func ExportChunks(ctx context.Context, d ExportChunksDeps, flt model.TraceScopeModel) error {
+	if flt.Time == nil || flt.FollowMode {
+		return errors.New("chunked export requires time range of the traces")
+	}
+	if d.Chunk <= 0 {
+		return errors.Errorf("invalid duration of the chunk '%s'", d.Chunk)
+	}
+	if err := os.MkdirAll(d.Dir, 0o755); err != nil { //nolint:gosec
+		return err
+	}
+	log := logger.FromContext(ctx).Named("export")
+	ext := ExportFileExt(d.Format, d.Compression)
+	for from := flt.Time.From.UTC(); from.Before(flt.Time.To); {
+		// chunks are aligned to the duration for the names of the files not to depend on the range
+		to := from.Truncate(d.Chunk).Add(d.Chunk)
+		if to.After(flt.Time.To) {
+			to = flt.Time.To.UTC()
+		}
+		name := filepath.Join(d.Dir, fmt.Sprintf("traces_%s_%s%s",
+			from.Format(exportChunkTimeLayout), to.Format(exportChunkTimeLayout), ext))
+		if _, err := os.Stat(name); err == nil {
+			log.Infof("'%s' is already exported, skipped", name)
+			from = to
+			continue
+		}
+		chunkFlt := flt
+		chunkFlt.Time = &model.TimeRange{From: from, To: to}
+		if err := exportChunk(ctx, d, chunkFlt, name); err != nil {
+			return errors.WithMessagef(err, "on export of '%s'", name)
+		}
+		log.Infof("'%s' is exported", name)
+		from = to
+	}
+	return nil
+}
+
+// exportChunk exports the scope into temporary file which is renamed to the chunk file when done
+func exportChunk(ctx context.Context, d ExportChunksDeps, flt model.TraceScopeModel, name string) (err error) {
+	tmp := name + ".part"
+	f, err := os.Create(tmp) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := f.Close(); err == nil {
+			err = e
+		}
+		if err == nil {
+			err = os.Rename(tmp, name)
+		}
+		if err != nil {
+			_ = os.Remove(tmp)
+		}
+	}()
+	exporter, err := NewTraceExporter(d.Format, d.Compression, f)
+	if err != nil {
+		return err
+	}
+	return ExportTraces(ctx, ExportDeps{
+		Client:    d.Client,
+		Exporter:  exporter,
+		BatchSize: d.BatchSize,
+	}, flt)
+}
+
+// NewExportRow - row of the tabular exports made of the trace
+func NewExportRow(tr *model.FetchTraceModel) ExportRow {
+	return ExportRow{
+		Timestamp:     tr.Timestamp.UTC(),
+		Agent:         tr.UserAgent,
+		TraceId:       tr.TrId,
+		Table:         tr.Table,
+		Chain:         tr.Chain,
+		JumpTarget:    tr.JumpTarget,
+		RuleHandle:    tr.RuleHandle,
+		Rule:          tr.Rule,
+		Verdict:       tr.Verdict,
+		Iifname:       tr.Iifname,
+		Oifname:       tr.Oifname,
+		Family:        tr.Family,
+		IpProto:       tr.IpProto,
+		Length:        tr.Length,
+		SMacAddr:      tr.SMacAddr,
+		DMacAddr:      tr.DMacAddr,
+		SAddr:         tr.SAddr,
+		DAddr:         tr.DAddr,
+		SPort:         tr.SPort,
+		DPort:         tr.DPort,
+		SSgName:       tr.SSgName,
+		DSgName:       tr.DSgName,
+		SSgNet:        tr.SSgNet,
+		DSgNet:        tr.DSgNet,
+		SgRule:        tr.SgRule,
+		SgRuleAction:  tr.SgRuleAction,
+		NetNS:         tr.NetNS,
+		ContainerId:   tr.ContainerId,
+		ContainerName: tr.ContainerName,
+		Pod:           tr.Pod,
+		PodNamespace:  tr.PodNamespace,
+		Labels:        tr.ContainerLabels,
+		SockInode:     tr.SockInode,
+		Pid:           tr.Pid,
+		Comm:          tr.Comm,
+		Cgroup:        tr.Cgroup,
+		CtState:       tr.CtState,
+		CtDirection:   tr.CtDirection,
+		CtNat:         tr.CtNat,
+		CtMark:        tr.CtMark,
+		CtZone:        tr.CtZone,
+		CtOrig:        tr.CtOrig,
+		CtReply:       tr.CtReply,
+		Source:        tr.Source,
+		LogPrefix:     tr.LogPrefix,
+		LogGroup:      tr.LogGroup,
+		Hook:          tr.Hook,
+	}
+}
+
+// ToModel - trace made of the row of the tabular export
+func (r *ExportRow) ToModel() model.FetchTraceModel {
+	return model.FetchTraceModel{
+		TrId:            r.TraceId,
+		Table:           r.Table,
+		Chain:           r.Chain,
+		JumpTarget:      r.JumpTarget,
+		RuleHandle:      r.RuleHandle,
+		Rule:            r.Rule,
+		Verdict:         r.Verdict,
+		Iifname:         r.Iifname,
+		Oifname:         r.Oifname,
+		Family:          r.Family,
+		IpProto:         r.IpProto,
+		Length:          r.Length,
+		SMacAddr:        r.SMacAddr,
+		DMacAddr:        r.DMacAddr,
+		SAddr:           r.SAddr,
+		DAddr:           r.DAddr,
+		SPort:           r.SPort,
+		DPort:           r.DPort,
+		SSgName:         r.SSgName,
+		DSgName:         r.DSgName,
+		SSgNet:          r.SSgNet,
+		DSgNet:          r.DSgNet,
+		NetNS:           r.NetNS,
+		ContainerId:     r.ContainerId,
+		ContainerName:   r.ContainerName,
+		Pod:             r.Pod,
+		PodNamespace:    r.PodNamespace,
+		ContainerLabels: r.Labels,
+		SockInode:       r.SockInode,
+		Pid:             r.Pid,
+		Comm:            r.Comm,
+		Cgroup:          r.Cgroup,
+		CtState:         r.CtState,
+		CtDirection:     r.CtDirection,
+		CtNat:           r.CtNat,
+		CtMark:          r.CtMark,
+		CtZone:          r.CtZone,
+		CtOrig:          r.CtOrig,
+		CtReply:         r.CtReply,
+		Source:          r.Source,
+		LogPrefix:       r.LogPrefix,
+		LogGroup:        r.LogGroup,
+		Hook:            r.Hook,
+		SgRule:          r.SgRule,
+		SgRuleAction:    r.SgRuleAction,
+		UserAgent:       r.Agent,
+		Timestamp:       r.Timestamp,
+	}
+}
+
+// exportColumns - names of the columns of the export row
+func exportColumns() []string {
+	t := reflect.TypeOf(ExportRow{})
+	ret := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		ret = append(ret, t.Field(i).Tag.Get("csv"))
+	}
+	return ret
+}
+
+// csvRecord - values of the columns of the export row, lists are joined by ';'
+func (r *ExportRow) csvRecord() []string {
+	v := reflect.ValueOf(r).Elem()
+	ret := make([]string, 0, v.NumField())
+	for i := range v.NumField() {
+		switch f := v.Field(i).Interface().(type) {
+		case time.Time:
+			ret = append(ret, f.Format(time.RFC3339Nano))
+		case []string:
+			ret = append(ret, strings.Join(f, ";"))
+		default:
+			ret = append(ret, fmt.Sprint(f))
+		}
+	}
+	return ret
+}
+
+// Export -
+func (e *csvExporter) Export(tr *model.FetchTraceModel) (bool, error) {
+	row := NewExportRow(tr)
+	return true, e.w.Write(row.csvRecord())
+}
+
+// Close -
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Export -
+func (e *jsonlExporter) Export(tr *model.FetchTraceModel) (bool, error) {
+	return true, e.enc.Encode(NewExportRow(tr))
+}
+
+// Close -
+func (e *jsonlExporter) Close() error {
+	return nil
+}
+
+func newParquetExporter(compression string, w io.Writer) TraceExporter {
+	var codec compress.Codec
+	switch compression {
+	case "", CompressSnappy:
+		codec = &snappy.Codec{}
+	case CompressNone:
+		codec = &uncompressed.Codec{}
+	case CompressGzip:
+		codec = &gzipcodec.Codec{}
+	case CompressZstd:
+		codec = &zstdcodec.Codec{}
+	}
+	return &parquetExporter{
+		w: parquet.NewGenericWriter[ExportRow](w,
+			parquet.Compression(codec),
+			parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+			parquet.CreatedBy("visor-cli", "", ""),
+		),
+	}
+}
+
+// Export -
+func (e *parquetExporter) Export(tr *model.FetchTraceModel) (bool, error) {
+	_, err := e.w.Write([]ExportRow{NewExportRow(tr)})
+	return true, err
+}
+
+// Close writes footer of the parquet file
+func (e *parquetExporter) Close() error {
+	return e.w.Close()
+}
+
+// Close flushes the export and then the compression
+func (e *compressedExporter) Close() error {
+	err := e.TraceExporter.Close()
+	if e1 := e.cw.Close(); err == nil {
+		err = e1
+	}
+	return err
+}
+
+// Export writes packet of the trace made of its raw headers, traces collected by the agents
+// which do not keep raw headers are skipped
+func (e *pcapngExporter) Export(tr *model.FetchTraceModel) (bool, error) {
+	if len(tr.RawHeaders) == 0 {
+		return false, nil
+	}
+	iface := pcapng.Interface{LinkType: pcapng.LinkTypeRaw}
+	data, origLen := tr.RawHeaders, tr.Length
+	if len(tr.RawLlHeader) == nlheaders.LlHeaderLen {
+		iface.LinkType = pcapng.LinkTypeEthernet
+		data = append(append(make([]byte, 0, len(tr.RawLlHeader)+len(data)), tr.RawLlHeader...), data...)
+		origLen += nlheaders.LlHeaderLen
+	}
+	ifname := tr.Iifname
+	if ifname == "" {
+		ifname = tr.Oifname
+	}
+	if ifname == "" {
+		ifname = "any"
+	}
+	iface.Name = ifname
+	if tr.UserAgent != "" {
+		iface.Name = tr.UserAgent + "/" + ifname
+	}
+	return true, e.w.WritePacket(iface, tr.Timestamp, data, origLen, pcapngComment(tr))
+}
+
+// Close -
+func (e *pcapngExporter) Close() error {
+	return nil
+}
+
+// pcapngComment - comment of the packet describing the trace
+func pcapngComment(tr *model.FetchTraceModel) string {
+	var b strings.Builder
+	add := func(k, v string) {
+		if v == "" {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s=%s", k, v)
+	}
+	add("table", tr.Table)
+	add("chain", tr.Chain)
+	add("handle", fmt.Sprint(tr.RuleHandle))
+	add("verdict", tr.Verdict)
+	add("agent", tr.UserAgent)
+	add("iif", tr.Iifname)
+	add("oif", tr.Oifname)
+	add("sg-rule", tr.SgRule)
+	add("trace", fmt.Sprint(tr.TrId))
+	if tr.Rule != "" {
+		fmt.Fprintf(&b, "\nrule: %s", tr.Rule)
+	}
+	return b.String()
+}
+
+// parsePcapngComment fills the trace with the fields of the packet comment made by pcapngComment
+func parsePcapngComment(c string, tr *model.FetchTraceModel) {
+	kv, rule, _ := strings.Cut(c, "\nrule: ")
+	tr.Rule = rule
+	for _, f := range strings.Fields(kv) {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "table":
+			tr.Table = v
+		case "chain":
+			tr.Chain = v
+		case "handle":
+			tr.RuleHandle, _ = strconv.ParseUint(v, 10, 64)
+		case "verdict":
+			tr.Verdict = v
+		case "agent":
+			tr.UserAgent = v
+		case "iif":
+			tr.Iifname = v
+		case "oif":
+			tr.Oifname = v
+		case "sg-rule":
+			tr.SgRule = v
+		case "trace":
+			id, _ := strconv.ParseUint(v, 10, 32)
+			tr.TrId = uint32(id)
+		}
+	}
+}
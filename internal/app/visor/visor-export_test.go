@@ -0,0 +1,213 @@
+package visor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/dto"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	proto "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+)
+
+type exportTestSuite struct {
+	suite.Suite
+}
+
+func Test_Export(t *testing.T) {
+	suite.Run(t, new(exportTestSuite))
+}
+
+func exportTrace() *model.FetchTraceModel {
+	return &model.FetchTraceModel{
+		TrId: 7, Table: "filter", Chain: "FORWARD", RuleHandle: 12, Verdict: "rule::drop",
+		Iifname: "eth0", UserAgent: "agent1", Length: 40, Rule: "ip daddr 10.0.2.1 drop",
+		RawLlHeader: make([]byte, 14),
+		RawHeaders:  []byte{0x45, 0, 0, 40, 0, 0, 0, 0, 64, 6, 0, 0, 10, 0, 1, 1, 10, 0, 2, 1},
+		Timestamp:   time.Date(2024, 12, 2, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func (sui *exportTestSuite) Test_Pcapng() {
+	var buf bytes.Buffer
+	e, err := NewTraceExporter(ExportPcapng, "", &buf)
+	sui.Require().NoError(err)
+	headerLen := buf.Len()
+
+	tr := exportTrace()
+	ok, err := e.Export(tr)
+	sui.Require().NoError(err)
+	sui.Require().True(ok)
+	noRaw := exportTrace()
+	noRaw.RawHeaders = nil
+	ok, err = e.Export(noRaw)
+	sui.Require().NoError(err)
+	sui.Require().False(ok)
+	sui.Require().NoError(e.Close())
+
+	out := buf.Bytes()[headerLen:]
+	// IDB of the ethernet interface of the agent
+	idbLen := binary.LittleEndian.Uint32(out[4:])
+	sui.Require().Equal(uint16(1), binary.LittleEndian.Uint16(out[8:]))
+	sui.Require().Contains(string(out[:idbLen]), "agent1/eth0")
+	// EPB holds link layer and network headers, original length counts the link layer header
+	epb := out[idbLen:]
+	sui.Require().Equal(uint32(6), binary.LittleEndian.Uint32(epb))
+	sui.Require().Equal(uint32(14+20), binary.LittleEndian.Uint32(epb[20:]))
+	sui.Require().Equal(uint32(14+40), binary.LittleEndian.Uint32(epb[24:]))
+	sui.Require().Equal(uint32(len(out)-int(idbLen)), binary.LittleEndian.Uint32(epb[4:])) //nolint:gosec
+	sui.Require().Contains(string(epb), "table=filter chain=FORWARD handle=12 verdict=rule::drop agent=agent1 iif=eth0 trace=7")
+	sui.Require().Contains(string(epb), "rule: ip daddr 10.0.2.1 drop")
+}
+
+func (sui *exportTestSuite) Test_UnknownFormat() {
+	_, err := NewTraceExporter("xls", "", &bytes.Buffer{})
+	sui.Require().Error(err)
+	_, err = NewTraceExporter(ExportCsv, "lzma", &bytes.Buffer{})
+	sui.Require().Error(err)
+	_, err = NewTraceExporter(ExportCsv, CompressSnappy, &bytes.Buffer{})
+	sui.Require().Error(err)
+}
+
+func (sui *exportTestSuite) Test_Csv() {
+	var buf bytes.Buffer
+	e, err := NewTraceExporter(ExportCsv, CompressGzip, &buf)
+	sui.Require().NoError(err)
+	tr := exportTrace()
+	tr.ContainerLabels = []string{"app=web", "tier=front"}
+	ok, err := e.Export(tr)
+	sui.Require().NoError(err)
+	sui.Require().True(ok)
+	sui.Require().NoError(e.Close())
+
+	zr, err := gzip.NewReader(&buf)
+	sui.Require().NoError(err)
+	records, err := csv.NewReader(zr).ReadAll()
+	sui.Require().NoError(err)
+	sui.Require().Len(records, 2)
+	sui.Require().Equal(exportColumns(), records[0])
+	row := make(map[string]string)
+	for i, col := range records[0] {
+		row[col] = records[1][i]
+	}
+	sui.Require().Equal("2024-12-02T12:00:00Z", row["timestamp"])
+	sui.Require().Equal("agent1", row["agent"])
+	sui.Require().Equal("12", row["rule_handle"])
+	sui.Require().Equal("rule::drop", row["verdict"])
+	sui.Require().Equal("app=web;tier=front", row["labels"])
+}
+
+func (sui *exportTestSuite) Test_Jsonl() {
+	var buf bytes.Buffer
+	e, err := NewTraceExporter(ExportJsonl, CompressZstd, &buf)
+	sui.Require().NoError(err)
+	for range 2 {
+		_, err = e.Export(exportTrace())
+		sui.Require().NoError(err)
+	}
+	sui.Require().NoError(e.Close())
+
+	zr, err := zstd.NewReader(&buf)
+	sui.Require().NoError(err)
+	defer zr.Close()
+	dec := json.NewDecoder(zr)
+	var rows []ExportRow
+	for dec.More() {
+		var row ExportRow
+		sui.Require().NoError(dec.Decode(&row))
+		rows = append(rows, row)
+	}
+	sui.Require().Len(rows, 2)
+	sui.Require().Equal(NewExportRow(exportTrace()), rows[0])
+}
+
+func (sui *exportTestSuite) Test_Parquet() {
+	var buf bytes.Buffer
+	e, err := NewTraceExporter(ExportParquet, "", &buf)
+	sui.Require().NoError(err)
+	tr := exportTrace()
+	tr.ContainerLabels = []string{"app=web"}
+	_, err = e.Export(tr)
+	sui.Require().NoError(err)
+	sui.Require().NoError(e.Close())
+
+	rows, err := parquet.Read[ExportRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	sui.Require().NoError(err)
+	sui.Require().Len(rows, 1)
+	sui.Require().Equal(NewExportRow(tr), rows[0])
+}
+
+func (sui *exportTestSuite) Test_Chunks() {
+	from := time.Date(2024, 12, 2, 10, 30, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 2, 12, 15, 0, 0, time.UTC)
+	cl := &exportClientMock{}
+	d := ExportChunksDeps{
+		Client: THClient{TraceHubServiceClient: cl},
+		Format: ExportJsonl,
+		Dir:    sui.T().TempDir(),
+		Chunk:  time.Hour,
+	}
+	flt := model.TraceScopeModel{Time: &model.TimeRange{From: from, To: to}}
+	sui.Require().NoError(ExportChunks(context.Background(), d, flt))
+	sui.Require().Len(cl.ranges, 3)
+	sui.Require().Equal([2]time.Time{from, from.Add(30 * time.Minute)}, cl.ranges[0])
+	sui.Require().Equal([2]time.Time{to.Add(-15 * time.Minute), to}, cl.ranges[2])
+
+	files, err := filepath.Glob(filepath.Join(d.Dir, "*"))
+	sui.Require().NoError(err)
+	sui.Require().Equal([]string{
+		filepath.Join(d.Dir, "traces_20241202T103000Z_20241202T110000Z.jsonl"),
+		filepath.Join(d.Dir, "traces_20241202T110000Z_20241202T120000Z.jsonl"),
+		filepath.Join(d.Dir, "traces_20241202T120000Z_20241202T121500Z.jsonl"),
+	}, files)
+
+	// interrupted export is resumed from the chunk which is not exported yet
+	sui.Require().NoError(os.Remove(files[2]))
+	cl.ranges = nil
+	sui.Require().NoError(ExportChunks(context.Background(), d, flt))
+	sui.Require().Len(cl.ranges, 1)
+	sui.Require().Equal([2]time.Time{to.Add(-15 * time.Minute), to}, cl.ranges[0])
+
+	flt.Time = nil
+	sui.Require().Error(ExportChunks(context.Background(), d, flt))
+}
+
+type exportClientMock struct {
+	proto.TraceHubServiceClient
+	ranges [][2]time.Time
+}
+
+func (m *exportClientMock) ExportTraces(_ context.Context, in *proto.ExportTracesReq, _ ...grpc.CallOption) (proto.TraceHubService_ExportTracesClient, error) {
+	tm := in.GetScope().GetTime()
+	m.ranges = append(m.ranges, [2]time.Time{tm.GetFrom().AsTime(), tm.GetTo().AsTime()})
+	var tr dto.FetchTraceDTO
+	tr.InitFromModel(exportTrace())
+	return &exportStreamMock{lists: []*proto.TraceList{{Traces: []*proto.FetchTrace{tr.ToProto()}}}}, nil
+}
+
+type exportStreamMock struct {
+	grpc.ClientStream
+	lists []*proto.TraceList
+}
+
+func (m *exportStreamMock) Recv() (*proto.TraceList, error) {
+	if len(m.lists) == 0 {
+		return nil, io.EOF
+	}
+	l := m.lists[0]
+	m.lists = m.lists[1:]
+	return l, nil
+}
@@ -0,0 +1,490 @@
+package visor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/dto"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/agent"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nl/nlheaders"
+	"github.com/wildberries-tech/pkt-tracer/internal/pcapng"
+	proto "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+	"github.com/wildberries-tech/pkt-tracer/pkg/tracequery"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/parquet-go/parquet-go"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	gzipMagic    = []byte{0x1f, 0x8b}
+	zstdMagic    = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	parquetMagic = []byte("PAR1")
+	pcapngMagic  = []byte{0x0a, 0x0d, 0x0d, 0x0a}
+
+	// offlineFiles - traces of the files read yet, the file is shared by the clients of the app
+	offlineFiles sync.Map
+)
+
+// offlineBatchSize - count of the traces sent in one message of the offline streams
+const offlineBatchSize = 1000
+
+type (
+	// offlineTHClient - trace-hub API served in process by the traces of the file,
+	// filters and queries of the trace scopes are evaluated locally
+	offlineTHClient struct {
+		traces []model.FetchTraceModel
+	}
+
+	offlineFile struct {
+		once   sync.Once
+		traces []model.FetchTraceModel
+		err    error
+	}
+
+	// offlineFilter - predicate of the trace scope evaluated in process
+	offlineFilter struct {
+		scope *model.TraceScopeModel
+		query tracequery.Matcher[*model.TraceModel]
+	}
+
+	// offlineStream - stream of the trace lists, it is blocked at the end in the follow mode
+	offlineStream struct {
+		ctx    context.Context
+		lists  []*proto.TraceList
+		follow bool
+	}
+)
+
+// NewOfflineTHClient makes 'trace-hub' API client serving traces of the file exported by
+// 'visor-cli export' instead of trace-hub service
+func NewOfflineTHClient(fileName string) (*THClosableClient, error) {
+	v, _ := offlineFiles.LoadOrStore(fileName, new(offlineFile))
+	f := v.(*offlineFile)
+	f.once.Do(func() {
+		f.traces, f.err = ReadTracesFile(fileName)
+	})
+	if f.err != nil {
+		return nil, errors.WithMessagef(f.err, "failed to read traces from '%s'", fileName)
+	}
+	c := &offlineTHClient{traces: f.traces}
+	return &THClosableClient{TraceHubServiceClient: c, Closable: c}, nil
+}
+
+// ReadTracesFile reads traces of the file exported in jsonl, parquet or pcapng format,
+// the format and the compression are detected by the content, traces are ordered by time
+func ReadTracesFile(fileName string) ([]model.FetchTraceModel, error) {
+	data, err := os.ReadFile(fileName) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	traces, err := decodeTraces(data)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traces[i].Timestamp.Before(traces[j].Timestamp)
+	})
+	return traces, nil
+}
+
+func decodeTraces(data []byte) (ret []model.FetchTraceModel, err error) {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		var zr *gzip.Reader
+		if zr, err = gzip.NewReader(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, errors.WithMessage(err, "on gzip decompression")
+		}
+		return decodeTraces(data)
+	case bytes.HasPrefix(data, zstdMagic):
+		var zr *zstd.Decoder
+		if zr, err = zstd.NewReader(nil); err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		if data, err = zr.DecodeAll(data, nil); err != nil {
+			return nil, errors.WithMessage(err, "on zstd decompression")
+		}
+		return decodeTraces(data)
+	case bytes.HasPrefix(data, parquetMagic):
+		rows, err := parquet.Read[ExportRow](bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, errors.WithMessage(err, "on reading parquet")
+		}
+		ret = make([]model.FetchTraceModel, 0, len(rows))
+		for i := range rows {
+			ret = append(ret, rows[i].ToModel())
+		}
+		return ret, nil
+	case bytes.HasPrefix(data, pcapngMagic):
+		return decodePcapngTraces(data)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var row ExportRow
+		if err = dec.Decode(&row); err != nil {
+			return nil, errors.WithMessagef(err, "on reading jsonl line %d", len(ret)+1)
+		}
+		ret = append(ret, row.ToModel())
+	}
+	return ret, nil
+}
+
+// decodePcapngTraces makes traces of the packets and their comments written by the pcapng export
+func decodePcapngTraces(data []byte) ([]model.FetchTraceModel, error) {
+	r, err := pcapng.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var ret []model.FetchTraceModel
+	for {
+		p, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return ret, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		tr := model.FetchTraceModel{Timestamp: p.Timestamp, Length: p.OrigLen}
+		data := p.Data
+		if p.LinkType == pcapng.LinkTypeEthernet && len(data) >= nlheaders.LlHeaderLen {
+			var llh nlheaders.LlHeader
+			_ = llh.Decode(data[:nlheaders.LlHeaderLen])
+			tr.SMacAddr, tr.DMacAddr = llh.SAddr.String(), llh.DAddr.String()
+			tr.RawLlHeader, data = data[:nlheaders.LlHeaderLen], data[nlheaders.LlHeaderLen:]
+			tr.Length -= min(tr.Length, nlheaders.LlHeaderLen)
+		}
+		tr.RawHeaders = data
+		decodeRawHeaders(data, &tr)
+		parsePcapngComment(p.Comment, &tr)
+		ret = append(ret, tr)
+	}
+}
+
+// decodeRawHeaders fills addresses, protocol and ports of the trace from its network and transport headers
+func decodeRawHeaders(b []byte, tr *model.FetchTraceModel) {
+	if len(b) == 0 {
+		return
+	}
+	var (
+		nlh   nlheaders.NlHeader
+		thOff int
+	)
+	switch b[0] >> 4 {
+	case 4:
+		if nlh.Decode(b) != nil {
+			return
+		}
+		tr.Family = "ip"
+		thOff = int(nlh.IHL) * 4
+	case 6:
+		const ip6HeaderLen = 40
+		if len(b) < ip6HeaderLen {
+			return
+		}
+		tr.Family = "ip6"
+		nlh.Protocol = b[6]
+		nlh.SAddr, nlh.DAddr = net.IP(b[8:24]), net.IP(b[24:40])
+		thOff = ip6HeaderLen
+	default:
+		return
+	}
+	tr.SAddr, tr.DAddr, tr.IpProto = nlh.SAddr.String(), nlh.DAddr.String(), nlh.ProtoStr()
+	switch tr.IpProto {
+	case "tcp", "udp", "udplite", "sctp", "dccp":
+		if len(b) >= thOff+4 {
+			tr.SPort = uint32(binary.BigEndian.Uint16(b[thOff:]))
+			tr.DPort = uint32(binary.BigEndian.Uint16(b[thOff+2:]))
+		}
+	}
+}
+
+func newOfflineFilter(scope *proto.TraceScope) (*offlineFilter, error) {
+	var dtoTraceScope dto.TraceScopeDTO
+	dtoTraceScope.InitFromProto(scope)
+	ret := &offlineFilter{scope: dtoTraceScope.ToModel()}
+	var err error
+	if ret.scope.Query != "" && ret.scope.QueryDsl == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is accepted in the visor form only")
+	}
+	if ret.query, err = model.NewQueryMatcher(ret.scope.QueryDsl); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "bad query: %v", err)
+	}
+	return ret, nil
+}
+
+// match checks the trace against the filters and the query of the scope, time range is not checked
+func (f *offlineFilter) match(tr *model.FetchTraceModel) bool {
+	s := f.scope
+	if !(in(s.TrId, tr.TrId) && in(s.Table, tr.Table) && in(s.Chain, tr.Chain) &&
+		in(s.JumpTarget, tr.JumpTarget) && in(s.RuleHandle, tr.RuleHandle) && in(s.Family, tr.Family) &&
+		in(s.Iifname, tr.Iifname) && in(s.Oifname, tr.Oifname) &&
+		in(s.SMacAddr, tr.SMacAddr) && in(s.DMacAddr, tr.DMacAddr) &&
+		in(s.SAddr, tr.SAddr) && in(s.DAddr, tr.DAddr) && in(s.SPort, tr.SPort) && in(s.DPort, tr.DPort) &&
+		in(s.SSgName, tr.SSgName) && in(s.DSgName, tr.DSgName) &&
+		in(s.SSgNet, tr.SSgNet) && in(s.DSgNet, tr.DSgNet) && in(s.NetNS, tr.NetNS) &&
+		in(s.ContainerId, tr.ContainerId) && in(s.ContainerName, tr.ContainerName) &&
+		in(s.Pod, tr.Pod) && in(s.PodNamespace, tr.PodNamespace) &&
+		in(s.Pid, tr.Pid) && in(s.Comm, tr.Comm) && in(s.Cgroup, tr.Cgroup) &&
+		in(s.CtState, tr.CtState) && in(s.CtDirection, tr.CtDirection) && in(s.CtNat, tr.CtNat) &&
+		in(s.CtMark, tr.CtMark) && in(s.CtZone, tr.CtZone) &&
+		in(s.Source, tr.Source) && in(s.LogPrefix, tr.LogPrefix) &&
+		in(s.LogGroup, tr.LogGroup) && in(s.Hook, tr.Hook) &&
+		in(s.SgRule, tr.SgRule) && in(s.SgRuleAction, tr.SgRuleAction) &&
+		in(s.Length, tr.Length) && in(s.IpProto, tr.IpProto) && in(s.Verdict, tr.Verdict) &&
+		in(s.Rule, tr.Rule) && in(s.AgentsIds, tr.UserAgent)) {
+		return false
+	}
+	if len(s.ContainerLabels) > 0 &&
+		!slices.ContainsFunc(tr.ContainerLabels, func(l string) bool { return slices.Contains(s.ContainerLabels, l) }) {
+		return false
+	}
+	return f.query(offlineTraceModel(tr))
+}
+
+// in - empty filter matches any value
+func in[T comparable](vals []T, v T) bool {
+	return len(vals) == 0 || slices.Contains(vals, v)
+}
+
+// offlineTraceModel - trace the query is evaluated on
+func offlineTraceModel(tr *model.FetchTraceModel) *model.TraceModel {
+	return &model.TraceModel{
+		TrId:            tr.TrId,
+		Table:           tr.Table,
+		Chain:           tr.Chain,
+		JumpTarget:      tr.JumpTarget,
+		RuleHandle:      tr.RuleHandle,
+		Family:          tr.Family,
+		Iifname:         tr.Iifname,
+		Oifname:         tr.Oifname,
+		SMacAddr:        tr.SMacAddr,
+		DMacAddr:        tr.DMacAddr,
+		SAddr:           tr.SAddr,
+		DAddr:           tr.DAddr,
+		SPort:           tr.SPort,
+		DPort:           tr.DPort,
+		SSgName:         tr.SSgName,
+		DSgName:         tr.DSgName,
+		SSgNet:          tr.SSgNet,
+		DSgNet:          tr.DSgNet,
+		NetNS:           tr.NetNS,
+		ContainerId:     tr.ContainerId,
+		ContainerName:   tr.ContainerName,
+		Pod:             tr.Pod,
+		PodNamespace:    tr.PodNamespace,
+		ContainerLabels: tr.ContainerLabels,
+		SockInode:       tr.SockInode,
+		Pid:             tr.Pid,
+		Comm:            tr.Comm,
+		Cgroup:          tr.Cgroup,
+		CtState:         tr.CtState,
+		CtDirection:     tr.CtDirection,
+		CtNat:           tr.CtNat,
+		CtMark:          tr.CtMark,
+		CtZone:          tr.CtZone,
+		CtOrig:          tr.CtOrig,
+		CtReply:         tr.CtReply,
+		Source:          tr.Source,
+		LogPrefix:       tr.LogPrefix,
+		LogGroup:        tr.LogGroup,
+		Hook:            tr.Hook,
+		SgRule:          tr.SgRule,
+		SgRuleAction:    tr.SgRuleAction,
+		Length:          tr.Length,
+		IpProto:         tr.IpProto,
+		Verdict:         tr.Verdict,
+		Rule:            tr.Rule,
+		UserAgent:       tr.UserAgent,
+	}
+}
+
+// selectTraces - traces of the filter within the time range, the range is half-open when 'toExcl' is set
+func (c *offlineTHClient) selectTraces(f *offlineFilter, tm *model.TimeRange, toExcl bool) []*proto.TraceList {
+	var (
+		ret  []*proto.TraceList
+		list *proto.TraceList
+	)
+	for i := range c.traces {
+		tr := &c.traces[i]
+		if tm != nil && (tr.Timestamp.Before(tm.From) || tr.Timestamp.After(tm.To) ||
+			toExcl && tr.Timestamp.Equal(tm.To)) {
+			continue
+		}
+		if !f.match(tr) {
+			continue
+		}
+		if list == nil || len(list.Traces) == offlineBatchSize {
+			list = &proto.TraceList{Traces: make([]*proto.FetchTrace, 0, offlineBatchSize)}
+			ret = append(ret, list)
+		}
+		var dtoTrace dto.FetchTraceDTO
+		dtoTrace.InitFromModel(tr)
+		list.Traces = append(list.Traces, dtoTrace.ToProto())
+	}
+	return ret
+}
+
+// FetchTraces streams traces of the scope followed by the empty list meaning the end of the batch,
+// in the follow mode the time range is not applied cause the whole file is the tail of the traces
+func (c *offlineTHClient) FetchTraces(ctx context.Context, scope *proto.TraceScope, _ ...grpc.CallOption) (proto.TraceHubService_FetchTracesClient, error) {
+	f, err := newOfflineFilter(scope)
+	if err != nil {
+		return nil, err
+	}
+	tm := f.scope.Time
+	if f.scope.FollowMode {
+		tm = nil
+	}
+	lists := append(c.selectTraces(f, tm, false), &proto.TraceList{})
+	return &offlineStream{ctx: ctx, lists: lists, follow: f.scope.FollowMode}, nil
+}
+
+// ExportTraces streams traces of the scope within the half-open time range
+func (c *offlineTHClient) ExportTraces(ctx context.Context, req *proto.ExportTracesReq, _ ...grpc.CallOption) (proto.TraceHubService_ExportTracesClient, error) {
+	f, err := newOfflineFilter(req.GetScope())
+	if err != nil {
+		return nil, err
+	}
+	if f.scope.FollowMode {
+		return nil, status.Error(codes.InvalidArgument, "follow mode is not supported by the export")
+	}
+	return &offlineStream{ctx: ctx, lists: c.selectTraces(f, f.scope.Time, true)}, nil
+}
+
+// ListAgents lists agents the traces of the file are collected by, they are never online
+func (c *offlineTHClient) ListAgents(_ context.Context, req *proto.ListAgentsReq, _ ...grpc.CallOption) (*proto.AgentList, error) {
+	ret := &proto.AgentList{}
+	if req.GetOnlineOnly() {
+		return ret, nil
+	}
+	agents := make(map[string]*agent.AgentModel)
+	var ids []string
+	for i := range c.traces {
+		tr := &c.traces[i]
+		if !in(req.GetAgentsIds(), tr.UserAgent) {
+			continue
+		}
+		a := agents[tr.UserAgent]
+		if a == nil {
+			a = &agent.AgentModel{Id: tr.UserAgent}
+			agents[tr.UserAgent] = a
+			ids = append(ids, tr.UserAgent)
+		}
+		a.TracesTotal++
+		a.LastTraceAt = maxTime(a.LastTraceAt, tr.Timestamp)
+		a.LastSeenAt = a.LastTraceAt
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		var agentDto dto.AgentDTO
+		agentDto.InitFromModel(agents[id])
+		ret.Agents = append(ret.Agents, agentDto.Agent)
+	}
+	return ret, nil
+}
+
+// FetchNftTable - nftables are not kept by the file, so no table is found
+func (c *offlineTHClient) FetchNftTable(context.Context, *proto.FetchNftTableQry, ...grpc.CallOption) (*proto.NftTableList, error) {
+	return &proto.NftTableList{}, nil
+}
+
+// TraceStream -
+func (c *offlineTHClient) TraceStream(context.Context, ...grpc.CallOption) (proto.TraceHubService_TraceStreamClient, error) {
+	return nil, errOffline("TraceStream")
+}
+
+// SyncNftTables -
+func (c *offlineTHClient) SyncNftTables(context.Context, ...grpc.CallOption) (proto.TraceHubService_SyncNftTablesClient, error) {
+	return nil, errOffline("SyncNftTables")
+}
+
+// ListAlerts -
+func (c *offlineTHClient) ListAlerts(context.Context, *proto.ListAlertsReq, ...grpc.CallOption) (*proto.AlertList, error) {
+	return nil, errOffline("ListAlerts")
+}
+
+// UpsertAlertRules -
+func (c *offlineTHClient) UpsertAlertRules(context.Context, *proto.AlertRuleList, ...grpc.CallOption) (*emptypb.Empty, error) {
+	return nil, errOffline("UpsertAlertRules")
+}
+
+// DeleteAlertRules -
+func (c *offlineTHClient) DeleteAlertRules(context.Context, *proto.DeleteAlertRulesReq, ...grpc.CallOption) (*emptypb.Empty, error) {
+	return nil, errOffline("DeleteAlertRules")
+}
+
+// CloseConn -
+func (c *offlineTHClient) CloseConn() error {
+	return nil
+}
+
+func errOffline(method string) error {
+	return status.Errorf(codes.Unimplemented, "%s is not supported reading traces from file", method)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// Recv -
+func (s *offlineStream) Recv() (*proto.TraceList, error) {
+	if len(s.lists) > 0 {
+		l := s.lists[0]
+		s.lists = s.lists[1:]
+		return l, nil
+	}
+	if s.follow {
+		<-s.ctx.Done()
+		return nil, s.ctx.Err()
+	}
+	return nil, io.EOF
+}
+
+// Header -
+func (s *offlineStream) Header() (metadata.MD, error) { return nil, nil }
+
+// Trailer -
+func (s *offlineStream) Trailer() metadata.MD { return nil }
+
+// CloseSend -
+func (s *offlineStream) CloseSend() error { return nil }
+
+// Context -
+func (s *offlineStream) Context() context.Context { return s.ctx }
+
+// SendMsg -
+func (s *offlineStream) SendMsg(any) error { return errOffline("SendMsg") }
+
+// RecvMsg -
+func (s *offlineStream) RecvMsg(m any) error {
+	l, err := s.Recv()
+	if err == nil {
+		if dst, ok := m.(*proto.TraceList); ok {
+			dst.Traces = l.Traces
+		}
+	}
+	return err
+}
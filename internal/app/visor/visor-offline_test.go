@@ -0,0 +1,158 @@
+package visor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	vf "github.com/wildberries-tech/pkt-tracer/internal/app/visor/flags"
+	"github.com/wildberries-tech/pkt-tracer/internal/dto"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/agent"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	proto "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type offlineTestSuite struct {
+	suite.Suite
+}
+
+func Test_Offline(t *testing.T) {
+	suite.Run(t, new(offlineTestSuite))
+}
+
+func offlineTraces() []*model.FetchTraceModel {
+	var ret []*model.FetchTraceModel
+	for i, verdict := range []string{"rule::drop", "rule::accept", "rule::drop"} {
+		tr := exportTrace()
+		tr.TrId = uint32(i + 1) //nolint:gosec
+		tr.Verdict = verdict
+		tr.UserAgent = "agent1"
+		if i == 2 {
+			tr.UserAgent = "agent2"
+		}
+		tr.Timestamp = tr.Timestamp.Add(time.Duration(i) * time.Minute)
+		// tcp 10.0.1.1:40000 -> 10.0.2.1:443
+		tr.RawHeaders = append(tr.RawHeaders, 0x9c, 0x40, 0x01, 0xbb)
+		tr.SAddr, tr.DAddr, tr.Family, tr.IpProto, tr.SPort, tr.DPort = "10.0.1.1", "10.0.2.1", "ip", "tcp", 40000, 443
+		ret = append(ret, tr)
+	}
+	return ret
+}
+
+func (sui *offlineTestSuite) writeFile(format, compression string) string {
+	name := filepath.Join(sui.T().TempDir(), "traces"+ExportFileExt(format, compression))
+	f, err := os.Create(name)
+	sui.Require().NoError(err)
+	defer f.Close()
+	e, err := NewTraceExporter(format, compression, f)
+	sui.Require().NoError(err)
+	for _, tr := range offlineTraces() {
+		_, err = e.Export(tr)
+		sui.Require().NoError(err)
+	}
+	sui.Require().NoError(e.Close())
+	return name
+}
+
+func (sui *offlineTestSuite) fetch(cl proto.TraceHubServiceClient, scope model.TraceScopeModel) (ret []uint32) {
+	var scopeDto dto.TraceScopeDTO
+	scopeDto.InitFromModel(&scope)
+	stream, err := cl.FetchTraces(context.Background(), scopeDto.ToProto())
+	sui.Require().NoError(err)
+	for {
+		l, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return ret
+		}
+		sui.Require().NoError(err)
+		for _, tr := range l.GetTraces() {
+			ret = append(ret, tr.GetTrace().GetTrId())
+		}
+	}
+}
+
+func (sui *offlineTestSuite) Test_Formats() {
+	for _, f := range [][2]string{
+		{ExportJsonl, ""},
+		{ExportJsonl, CompressGzip},
+		{ExportParquet, ""},
+		{ExportPcapng, CompressZstd},
+	} {
+		sui.Run(f[0]+f[1], func() {
+			traces, err := ReadTracesFile(sui.writeFile(f[0], f[1]))
+			sui.Require().NoError(err)
+			sui.Require().Len(traces, 3)
+			exp := offlineTraces()[0]
+			tr := traces[0]
+			sui.Require().Equal(exp.TrId, tr.TrId)
+			sui.Require().Equal(exp.Timestamp, tr.Timestamp.UTC())
+			sui.Require().Equal(exp.Verdict, tr.Verdict)
+			sui.Require().Equal(exp.Chain, tr.Chain)
+			sui.Require().Equal(exp.RuleHandle, tr.RuleHandle)
+			sui.Require().Equal(exp.Iifname, tr.Iifname)
+			sui.Require().Equal(exp.UserAgent, tr.UserAgent)
+			sui.Require().Equal(exp.Rule, tr.Rule)
+			sui.Require().Equal(exp.SAddr, tr.SAddr)
+			sui.Require().Equal(exp.DAddr, tr.DAddr)
+			sui.Require().Equal(exp.IpProto, tr.IpProto)
+			sui.Require().Equal(exp.DPort, tr.DPort)
+		})
+	}
+}
+
+func (sui *offlineTestSuite) Test_Filters() {
+	c, err := NewOfflineTHClient(sui.writeFile(ExportParquet, ""))
+	sui.Require().NoError(err)
+	sui.Require().Equal([]uint32{1, 2, 3}, sui.fetch(c, model.TraceScopeModel{}))
+	sui.Require().Equal([]uint32{1, 3}, sui.fetch(c, model.TraceScopeModel{Verdict: []string{"rule::drop"}}))
+	sui.Require().Equal([]uint32{3}, sui.fetch(c, model.TraceScopeModel{AgentsIds: []string{"agent2"}}))
+
+	q := vf.QueryFlag(`verdict == "rule::drop" and trid != 3 and dport in (80, 443)`)
+	sql, err := q.ToSql()
+	sui.Require().NoError(err)
+	sui.Require().Equal([]uint32{1}, sui.fetch(c, model.TraceScopeModel{Query: sql, QueryDsl: string(q)}))
+	_, err = newOfflineFilter(&proto.TraceScope{Query: sql})
+	sui.Require().Error(err)
+
+	from := offlineTraces()[1].Timestamp
+	sui.Require().Equal([]uint32{2, 3}, sui.fetch(c, model.TraceScopeModel{
+		Time: &model.TimeRange{From: from, To: from.Add(time.Hour)},
+	}))
+
+	agents, err := FetchAgents(context.Background(), THClient{TraceHubServiceClient: c}, agent.AgentScopeModel{})
+	sui.Require().NoError(err)
+	sui.Require().Len(agents, 2)
+	sui.Require().Equal("agent1", agents[0].Id)
+	sui.Require().Equal(uint64(2), agents[0].TracesTotal)
+	sui.Require().False(agents[0].Online)
+}
+
+func (sui *offlineTestSuite) Test_Follow() {
+	c, err := NewOfflineTHClient(sui.writeFile(ExportJsonl, ""))
+	sui.Require().NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	now := time.Now()
+	var scopeDto dto.TraceScopeDTO
+	scopeDto.InitFromModel(&model.TraceScopeModel{
+		FollowMode: true,
+		Time:       &model.TimeRange{From: now.Add(-time.Second), To: now},
+	})
+	stream, err := c.FetchTraces(ctx, scopeDto.ToProto())
+	sui.Require().NoError(err)
+	// the whole file is the tail of the traces
+	l, err := stream.Recv()
+	sui.Require().NoError(err)
+	sui.Require().Len(l.GetTraces(), 3)
+	l, err = stream.Recv()
+	sui.Require().NoError(err)
+	sui.Require().Empty(l.GetTraces())
+	cancel()
+	_, err = stream.Recv()
+	sui.Require().ErrorIs(err, context.Canceled)
+}
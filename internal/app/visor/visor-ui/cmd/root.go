@@ -11,6 +11,7 @@ import (
 	vf "github.com/wildberries-tech/pkt-tracer/internal/app/visor/flags"
 	"github.com/wildberries-tech/pkt-tracer/internal/app/visor/visor-ui/view"
 	"github.com/wildberries-tech/pkt-tracer/internal/config"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/agent"
 	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
 	np "github.com/wildberries-tech/pkt-tracer/internal/providers/nft-provider"
 
@@ -54,7 +55,8 @@ func newRootCmd() *cobra.Command {
 		c.MarkFlagsMutuallyExclusive(qName, p.Name)
 	}
 
-	c.MarkFlagsOneRequired(fl.NameFromTag(&fl.ConfigPath), fl.NameFromTag(&fl.ServerUrl))
+	c.MarkFlagsOneRequired(fl.NameFromTag(&fl.ConfigPath), fl.NameFromTag(&fl.ServerUrl), fl.NameFromTag(&fl.FromFile))
+	c.MarkFlagsMutuallyExclusive(fl.NameFromTag(&fl.ServerUrl), fl.NameFromTag(&fl.FromFile))
 	SetupContext()
 	return c
 }
@@ -89,6 +91,7 @@ func run(cmd *cobra.Command, args []string) (err error) {
 		config.WithDefValue{Key: AppLoggerLevel, Val: "INFO"},
 
 		config.WithCmdFlag{Key: TrAddress, Flag: cmd.Flag(fl.NameFromTag(&fl.ServerUrl))},
+		config.WithCmdFlag{Key: TrFromFile, Flag: cmd.Flag(fl.NameFromTag(&fl.FromFile))},
 		config.WithDefValue{Key: TrAddress, Val: "tcp://127.0.0.1:9000"},
 
 		config.WithDefValue{Key: ServicesDefDialDuration, Val: 30 * time.Second},
@@ -112,8 +115,9 @@ func run(cmd *cobra.Command, args []string) (err error) {
 	app := view.SetupViewer(
 		ctx,
 		view.Deps{
-			Visor:       &visorProxy{},
-			TblProvider: &tblProviderDecorator{si: syncInterval},
+			Visor:          &visorProxy{},
+			TblProvider:    &tblProviderDecorator{si: syncInterval},
+			AgentsProvider: agentsProvider{cli: THClient{TraceHubServiceClient: thClient.TraceHubServiceClient}},
 		},
 		view.Cfg{
 			CmdFlags: fl,
@@ -142,6 +146,9 @@ type (
 		np.TableProvider
 		si time.Duration
 	}
+	agentsProvider struct {
+		cli THClient
+	}
 )
 
 func (v *visorDecorator) Run(ctx context.Context, flt model.TraceScopeModel) error {
@@ -203,3 +210,7 @@ func (t *tblProviderDecorator) Close() (err error) {
 	}
 	return err
 }
+
+func (p agentsProvider) FetchAgents(ctx context.Context) ([]agent.AgentModel, error) {
+	return FetchAgents(ctx, p.cli, agent.AgentScopeModel{})
+}
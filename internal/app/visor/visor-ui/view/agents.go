@@ -0,0 +1,79 @@
+package view
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/app/visor"
+	ui "github.com/wildberries-tech/pkt-tracer/internal/app/visor/visor-ui/tui"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/agent"
+
+	"github.com/rivo/tview"
+)
+
+const agentsRefreshInterval = 3 * time.Second
+
+// watchAgents - refreshes agents panel periodically while it is shown
+func (a *view) watchAgents(ctx context.Context) error {
+	tc := time.NewTicker(agentsRefreshInterval)
+	defer tc.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tc.C:
+			if name, _ := a.Page.GetFrontPage(); name == agentsLayoutName {
+				a.refreshAgents()
+			}
+		}
+	}
+}
+
+func (a *view) refreshAgents() {
+	if a.AgentsProvider == nil {
+		return
+	}
+	go func() {
+		agents, err := a.AgentsProvider.FetchAgents(a.ctxApp)
+		if err != nil {
+			a.handleErr(err)
+			return
+		}
+		var buf bytes.Buffer
+		if err = visor.PrintAgents(&buf, agents, false); err != nil {
+			a.handleErr(err)
+			return
+		}
+		text := colorizeAgents(tview.Escape(buf.String()), agents)
+		a.QueueUpdateDraw(func() {
+			agentsPanel := a.primitives.At(panelAgentsName).(*ui.Panel)
+			agentsPanel.SetText(text)
+		})
+	}()
+}
+
+// colorizeAgents - highlights status column of the agents table
+func colorizeAgents(tbl string, agents []agent.AgentModel) string {
+	lines := strings.Split(tbl, "\n")
+	for i, ag := range agents {
+		if i+1 >= len(lines) {
+			break
+		}
+		line, idLen := lines[i+1], len(tview.Escape(ag.Id))
+		status := visor.AgentStatus(ag)
+		color := SrvColorFail
+		if ag.Online {
+			color = SrvColorOK
+		}
+		if idLen > len(line) {
+			continue
+		}
+		if pos := strings.Index(line[idLen:], status); pos >= 0 {
+			pos += idLen
+			lines[i+1] = line[:pos] + "[" + color + "]" + status + "[white]" + line[pos+len(status):]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
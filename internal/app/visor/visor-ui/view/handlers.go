@@ -30,6 +30,17 @@ func (a *view) stopTraceHandler(evt *tcell.EventKey) *tcell.EventKey {
 	return evt
 }
 
+func (a *view) agentsMenuHandler(evt *tcell.EventKey) *tcell.EventKey {
+	a.Page.SwitchToPage(agentsLayoutName)
+	a.refreshAgents()
+	return evt
+}
+
+func (a *view) refreshAgentsHandler(evt *tcell.EventKey) *tcell.EventKey {
+	a.refreshAgents()
+	return evt
+}
+
 func (a *view) togglePanelsHandler(evt *tcell.EventKey) *tcell.EventKey {
 	a.SetFocus(a.next())
 	return evt
@@ -59,6 +59,26 @@ func (f filterInputsMap) updInputFields(fl vf.Flags) error {
 	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.DSgName)], fl.DSgName...))
 	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.SSgNet)], fl.SSgNet...))
 	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.DSgNet)], fl.DSgNet...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.NetNS)], fl.NetNS...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.ContainerId)], fl.ContainerId...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.ContainerName)], fl.ContainerName...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.Pod)], fl.Pod...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.PodNamespace)], fl.PodNamespace...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.ContainerLabels)], fl.ContainerLabels...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.Pid)], fl.Pid...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.Comm)], fl.Comm...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.Cgroup)], fl.Cgroup...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.CtState)], fl.CtState...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.CtDirection)], fl.CtDirection...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.CtNat)], fl.CtNat...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.CtMark)], fl.CtMark...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.CtZone)], fl.CtZone...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.Source)], fl.Source...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.LogPrefix)], fl.LogPrefix...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.LogGroup)], fl.LogGroup...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.Hook)], fl.Hook...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.SgRule)], fl.SgRule...))
+	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.SgRuleAction)], fl.SgRuleAction...))
 	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.Length)], fl.Length...))
 	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.IpProto)], fl.IpProto...))
 	errs = append(errs, ui.SetInputValues(f[fl.NameFromTag(&fl.Verdict)], fl.Verdict...))
@@ -112,6 +132,46 @@ func (m filterInputsMap) GetInputValues(f *vf.Flags) (err error) {
 	errs = append(errs, err)
 	f.DSgNet, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.DSgNet)], ",", f.DSgNet...)
 	errs = append(errs, err)
+	f.NetNS, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.NetNS)], ",", f.NetNS...)
+	errs = append(errs, err)
+	f.ContainerId, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.ContainerId)], ",", f.ContainerId...)
+	errs = append(errs, err)
+	f.ContainerName, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.ContainerName)], ",", f.ContainerName...)
+	errs = append(errs, err)
+	f.Pod, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.Pod)], ",", f.Pod...)
+	errs = append(errs, err)
+	f.PodNamespace, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.PodNamespace)], ",", f.PodNamespace...)
+	errs = append(errs, err)
+	f.ContainerLabels, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.ContainerLabels)], ",", f.ContainerLabels...)
+	errs = append(errs, err)
+	f.Pid, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.Pid)], ",", f.Pid...)
+	errs = append(errs, err)
+	f.Comm, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.Comm)], ",", f.Comm...)
+	errs = append(errs, err)
+	f.Cgroup, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.Cgroup)], ",", f.Cgroup...)
+	errs = append(errs, err)
+	f.CtState, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.CtState)], ",", f.CtState...)
+	errs = append(errs, err)
+	f.CtDirection, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.CtDirection)], ",", f.CtDirection...)
+	errs = append(errs, err)
+	f.CtNat, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.CtNat)], ",", f.CtNat...)
+	errs = append(errs, err)
+	f.CtMark, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.CtMark)], ",", f.CtMark...)
+	errs = append(errs, err)
+	f.CtZone, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.CtZone)], ",", f.CtZone...)
+	errs = append(errs, err)
+	f.Source, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.Source)], ",", f.Source...)
+	errs = append(errs, err)
+	f.LogPrefix, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.LogPrefix)], ",", f.LogPrefix...)
+	errs = append(errs, err)
+	f.LogGroup, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.LogGroup)], ",", f.LogGroup...)
+	errs = append(errs, err)
+	f.Hook, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.Hook)], ",", f.Hook...)
+	errs = append(errs, err)
+	f.SgRule, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.SgRule)], ",", f.SgRule...)
+	errs = append(errs, err)
+	f.SgRuleAction, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.SgRuleAction)], ",", f.SgRuleAction...)
+	errs = append(errs, err)
 	f.Length, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.Length)], ",", f.Length...)
 	errs = append(errs, err)
 	f.IpProto, err = ui.GetInputValuesByType(m[f.NameFromTag(&f.IpProto)], ",", f.IpProto...)
@@ -251,6 +311,126 @@ func newFilterInputs(fl vf.Flags) filterInputsMap {
 			PlaceHolder: fl.GetFieldFlagParams(&fl.DSgNet).Example,
 			FieldWidth:  fieldWidth,
 		}, fl.DSgNet...),
+		fl.NameFromTag(&fl.NetNS): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.NetNS).Name),
+			Label:       fl.GetFieldFlagParams(&fl.NetNS).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.NetNS).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.NetNS...),
+		fl.NameFromTag(&fl.ContainerId): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.ContainerId).Name),
+			Label:       fl.GetFieldFlagParams(&fl.ContainerId).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.ContainerId).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.ContainerId...),
+		fl.NameFromTag(&fl.ContainerName): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.ContainerName).Name),
+			Label:       fl.GetFieldFlagParams(&fl.ContainerName).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.ContainerName).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.ContainerName...),
+		fl.NameFromTag(&fl.Pod): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.Pod).Name),
+			Label:       fl.GetFieldFlagParams(&fl.Pod).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.Pod).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.Pod...),
+		fl.NameFromTag(&fl.PodNamespace): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.PodNamespace).Name),
+			Label:       fl.GetFieldFlagParams(&fl.PodNamespace).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.PodNamespace).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.PodNamespace...),
+		fl.NameFromTag(&fl.ContainerLabels): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.ContainerLabels).Name),
+			Label:       fl.GetFieldFlagParams(&fl.ContainerLabels).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.ContainerLabels).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.ContainerLabels...),
+		fl.NameFromTag(&fl.Pid): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.Pid).Name),
+			Label:       fl.GetFieldFlagParams(&fl.Pid).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.Pid).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.Pid...),
+		fl.NameFromTag(&fl.Comm): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.Comm).Name),
+			Label:       fl.GetFieldFlagParams(&fl.Comm).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.Comm).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.Comm...),
+		fl.NameFromTag(&fl.Cgroup): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.Cgroup).Name),
+			Label:       fl.GetFieldFlagParams(&fl.Cgroup).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.Cgroup).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.Cgroup...),
+		fl.NameFromTag(&fl.CtState): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.CtState).Name),
+			Label:       fl.GetFieldFlagParams(&fl.CtState).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.CtState).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.CtState...),
+		fl.NameFromTag(&fl.CtDirection): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.CtDirection).Name),
+			Label:       fl.GetFieldFlagParams(&fl.CtDirection).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.CtDirection).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.CtDirection...),
+		fl.NameFromTag(&fl.CtNat): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.CtNat).Name),
+			Label:       fl.GetFieldFlagParams(&fl.CtNat).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.CtNat).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.CtNat...),
+		fl.NameFromTag(&fl.CtMark): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.CtMark).Name),
+			Label:       fl.GetFieldFlagParams(&fl.CtMark).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.CtMark).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.CtMark...),
+		fl.NameFromTag(&fl.CtZone): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.CtZone).Name),
+			Label:       fl.GetFieldFlagParams(&fl.CtZone).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.CtZone).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.CtZone...),
+		fl.NameFromTag(&fl.Source): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.Source).Name),
+			Label:       fl.GetFieldFlagParams(&fl.Source).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.Source).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.Source...),
+		fl.NameFromTag(&fl.LogPrefix): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.LogPrefix).Name),
+			Label:       fl.GetFieldFlagParams(&fl.LogPrefix).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.LogPrefix).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.LogPrefix...),
+		fl.NameFromTag(&fl.LogGroup): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.LogGroup).Name),
+			Label:       fl.GetFieldFlagParams(&fl.LogGroup).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.LogGroup).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.LogGroup...),
+		fl.NameFromTag(&fl.Hook): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.Hook).Name),
+			Label:       fl.GetFieldFlagParams(&fl.Hook).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.Hook).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.Hook...),
+		fl.NameFromTag(&fl.SgRule): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.SgRule).Name),
+			Label:       fl.GetFieldFlagParams(&fl.SgRule).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.SgRule).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.SgRule...),
+		fl.NameFromTag(&fl.SgRuleAction): ui.NewInputByType(ui.InputOPtions{
+			Name:        directOrder(fl.GetFieldFlagParams(&fl.SgRuleAction).Name),
+			Label:       fl.GetFieldFlagParams(&fl.SgRuleAction).Name,
+			PlaceHolder: fl.GetFieldFlagParams(&fl.SgRuleAction).Example,
+			FieldWidth:  fieldWidth,
+		}, fl.SgRuleAction...),
 		fl.NameFromTag(&fl.Length): ui.NewInputByType(ui.InputOPtions{
 			Name:        directOrder(fl.GetFieldFlagParams(&fl.Length).Name),
 			Label:       fl.GetFieldFlagParams(&fl.Length).Name,
@@ -7,13 +7,15 @@ import (
 )
 
 const (
-	KeyHelp1  tcell.Key = tcell.KeyF1
-	KeyHelp2  tcell.Key = ui.KeyHelp
-	KeyFilter tcell.Key = ui.KeyF
-	KeyExit   tcell.Key = tcell.KeyCtrlC
-	KeySave   tcell.Key = tcell.KeyCtrlS
-	KeyPause  tcell.Key = ui.KeyP
-	KeyStart  tcell.Key = ui.KeyS
+	KeyHelp1   tcell.Key = tcell.KeyF1
+	KeyHelp2   tcell.Key = ui.KeyHelp
+	KeyFilter  tcell.Key = ui.KeyF
+	KeyExit    tcell.Key = tcell.KeyCtrlC
+	KeySave    tcell.Key = tcell.KeyCtrlS
+	KeyPause   tcell.Key = ui.KeyP
+	KeyStart   tcell.Key = ui.KeyS
+	KeyAgents  tcell.Key = ui.KeyA
+	KeyRefresh tcell.Key = ui.KeyR
 )
 
 func (a *view) keyboard(evt *tcell.EventKey) *tcell.EventKey {
@@ -12,6 +12,7 @@ import (
 const (
 	mainLayoutName    = "main"
 	helpLayoutName    = "help"
+	agentsLayoutName  = "agents-layout"
 	filtersLayoutName = "filters"
 	filterHelpDesc    = " Press [yellow]Esc[white] to exit without save, press [yellow]Ctrl-S[white] to save and exit"
 )
@@ -36,6 +37,7 @@ func (a *view) mainLayout(p panelsMap) ui.Primitive {
 		KeyFilter:    ui.NewKeyActionWithOpts("filterMenu", a.filterMenuHandler),
 		KeyStart:     ui.NewKeyActionWithOpts("startTrace", a.startTraceHandler),
 		KeyPause:     ui.NewKeyActionWithOpts("pauseTrace", a.stopTraceHandler),
+		KeyAgents:    ui.NewKeyActionWithOpts("agentsMenu", a.agentsMenuHandler),
 		tcell.KeyTab: ui.NewKeyActionWithOpts("togglePanels", a.togglePanelsHandler),
 	}))
 	ml.SetInputCapture(func(evt *tcell.EventKey) *tcell.EventKey {
@@ -64,6 +66,32 @@ func (a *view) helpLayout(p panelsMap) ui.Primitive {
 	return hl
 }
 
+func (a *view) agentsLayout(p panelsMap) ui.Primitive {
+	al := ui.NewLayout(
+		agentsLayoutName,
+		ui.NewRows(
+			ui.Row(
+				ui.NewColumns(ui.Column(p[panelAgentsName], 0, 1)), 0, 1,
+			),
+			ui.Row(
+				ui.NewColumns(ui.Column(p[panelAgentsFooterName], 0, 1)), 1, 1,
+			),
+		),
+		ui.LayoutWithTitle("Agents"),
+		ui.LayoutWithBorder(),
+	)
+	al.AddActions(ui.NewKeyActionsFromMap(ui.KeyMap{
+		KeyRefresh: ui.NewKeyActionWithOpts("refreshAgents", a.refreshAgentsHandler),
+	}))
+	al.SetInputCapture(func(evt *tcell.EventKey) *tcell.EventKey {
+		if k, ok := al.HasAction(ui.AsKey(evt)); ok && k.Action != nil {
+			return k.Action(evt)
+		}
+		return evt
+	})
+	return al
+}
+
 func (a *view) filtersLayout() ui.Primitive {
 	var (
 		rows []ui.GridItems
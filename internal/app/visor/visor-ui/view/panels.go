@@ -31,6 +31,9 @@ const (
 
 	panelHelpName       = "help-desc"
 	panelHelpFooterName = "help-footer"
+
+	panelAgentsName       = "agents"
+	panelAgentsFooterName = "agents-footer"
 )
 
 // Panels content
@@ -45,9 +48,11 @@ const (
 2) Use [yellow]Tab[white] to switch between panels.
 3) Use [yellow]f[white] to apply filters.
 4) Use [yellow]p[white] to pause trace capture.
-5) Use [yellow]s[white] to start trace capture.`
+5) Use [yellow]s[white] to start trace capture.
+6) Use [yellow]a[white] to show agents registered on server.`
 
-	helpFooterDesc = " Press [yellow]Esc[white] to return"
+	helpFooterDesc   = " Press [yellow]Esc[white] to return"
+	agentsFooterDesc = " Press [yellow]r[white] to refresh, press [yellow]Esc[white] to return"
 )
 
 type (
@@ -178,3 +183,20 @@ func (a *view) newHelpPagePanels() (p panelsMap) {
 		helpFooterPanel.Name(): helpFooterPanel,
 	}
 }
+
+func (a *view) newAgentsPagePanels() (p panelsMap) {
+	agentsPanel := ui.NewPanel(ui.PanelOption{
+		Name:   panelAgentsName,
+		Title:  "Agents",
+		Border: true,
+	})
+	agentsFooterPanel := ui.NewPanel(ui.PanelOption{
+		Name:        panelAgentsFooterName,
+		Description: agentsFooterDesc,
+		Align:       tview.AlignLeft,
+	})
+	return panelsMap{
+		agentsPanel.Name():       agentsPanel,
+		agentsFooterPanel.Name(): agentsFooterPanel,
+	}
+}
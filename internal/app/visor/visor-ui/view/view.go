@@ -10,6 +10,7 @@ import (
 	"github.com/wildberries-tech/pkt-tracer/internal/app/visor"
 	vf "github.com/wildberries-tech/pkt-tracer/internal/app/visor/flags"
 	ui "github.com/wildberries-tech/pkt-tracer/internal/app/visor/visor-ui/tui"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/agent"
 	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
 
 	"github.com/H-BF/corlib/pkg/parallel"
@@ -37,10 +38,14 @@ type (
 		GetTableById(id uint64) (tbl string, err error)
 		Close() error
 	}
+	AgentsProvider interface {
+		FetchAgents(context.Context) ([]agent.AgentModel, error)
+	}
 	//Deps - dependencies
 	Deps struct {
-		Visor       Visor
-		TblProvider TableProvider
+		Visor          Visor
+		TblProvider    TableProvider
+		AgentsProvider AgentsProvider
 	}
 
 	//Cfg - configs
@@ -89,16 +94,19 @@ func newView(ctx context.Context, d Deps, c Cfg) *view {
 	}
 	mainPanels := a.newMainPagePanels()
 	helpPanels := a.newHelpPagePanels()
+	agentsPanels := a.newAgentsPagePanels()
 	a.primitives.AddPrimitivesFromMap(componentMap[ui.Primitive]{
 		mainLayoutName:    a.mainLayout(mainPanels),
 		helpLayoutName:    a.helpLayout(helpPanels),
+		agentsLayoutName:  a.agentsLayout(agentsPanels),
 		filtersLayoutName: a.filtersLayout(),
 		panelTraceName:    mainPanels[panelTraceName],
 		panelTblName:      mainPanels[panelTblName],
+		panelAgentsName:   agentsPanels[panelAgentsName],
 		modalErrName:      a.newErrWindow(),
 	})
 	a.next = ui.NewNextPrimitive(mainPanels[panelTraceName], mainPanels[panelTblName])
-	a.Page.Add(a.primitives.GetPrimitivesByNames(mainLayoutName, helpLayoutName, filtersLayoutName, modalErrName)...)
+	a.Page.Add(a.primitives.GetPrimitivesByNames(mainLayoutName, helpLayoutName, agentsLayoutName, filtersLayoutName, modalErrName)...)
 	a.Page.SwitchToPage(mainLayoutName)
 	a.bindKeys()
 	a.SetInputCapture(a.keyboard).
@@ -123,6 +131,9 @@ func (a *view) Run() (err error) {
 		func() error {
 			return a.App.RunWithCtx(ctx1)
 		},
+		func() error {
+			return a.watchAgents(ctx1)
+		},
 	}
 
 	errs := make([]error, len(ff))
@@ -0,0 +1,324 @@
+package bqueue
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Policy - what to do with the value put into the full queue
+type Policy int
+
+const (
+	// DropOldest - evict the oldest value to make room for the new one
+	DropOldest Policy = iota
+	// DropNewest - reject the new value
+	DropNewest
+	// PreferDrops - evict the oldest value which is not prioritized, values are prioritized
+	// by the predicate given to the queue (e.g. traces of dropped packets over accepted ones)
+	PreferDrops
+)
+
+var policyNames = map[Policy]string{
+	DropOldest:  "drop-oldest",
+	DropNewest:  "drop-newest",
+	PreferDrops: "prefer-drops",
+}
+
+// ParsePolicy - policy by its name: drop-oldest/drop-newest/prefer-drops
+func ParsePolicy(s string) (Policy, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for p, name := range policyNames {
+		if name == s {
+			return p, nil
+		}
+	}
+	return 0, errors.Errorf("unknown queue overflow policy '%s'", s)
+}
+
+// String -
+func (p Policy) String() string {
+	if name, ok := policyNames[p]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Metrics - sink of the queue statistics
+type Metrics interface {
+	// AddDepth - size of the values in the queue has changed by delta
+	AddDepth(delta int)
+	// ObserveWait - value has spent the duration in the queue until the reader got it
+	ObserveWait(d time.Duration)
+	// AddDrops - value of the size has been dropped because of the overflow
+	AddDrops(n int)
+}
+
+type (
+	// FIFO - queue holding values of total size at most capacity, values which do not fit are dropped
+	// by the overflow policy. Zero capacity means the queue is unbounded
+	FIFO[T any] struct {
+		capacity  int
+		policy    Policy
+		priority  func(T) bool
+		size      func(T) int
+		used      int // total size of the values in the queue
+		metrics   Metrics
+		mu        sync.Mutex
+		cv        *sync.Cond
+		prior     list.List // prioritized values
+		other     list.List // the rest of values
+		seq       uint64
+		sealed    bool
+		closed    bool
+		ch        chan T
+		onceClose sync.Once
+		stop      chan struct{}
+		stopped   chan struct{}
+	}
+
+	item[T any] struct {
+		v    T
+		seq  uint64
+		at   time.Time
+		size int
+	}
+
+	// Opt - option of the queue
+	Opt[T any] func(*FIFO[T])
+
+	// Settings - capacity, overflow policy and statistics sink of the queue
+	Settings struct {
+		// Capacity - max total size of the values in the queue, zero means the queue is unbounded
+		Capacity int
+		Policy   Policy
+		// Metrics - optional sink of the queue statistics
+		Metrics Metrics
+	}
+)
+
+// WithCapacity - max total size of the values in the queue, the queue is unbounded by default
+func WithCapacity[T any](n int) Opt[T] {
+	if n < 0 {
+		panic("bqueue: capacity must be >= 0")
+	}
+	return func(q *FIFO[T]) {
+		q.capacity = n
+	}
+}
+
+// WithPolicy - overflow policy, DropOldest by default
+func WithPolicy[T any](p Policy) Opt[T] {
+	return func(q *FIFO[T]) {
+		q.policy = p
+	}
+}
+
+// WithPriority - predicate of the values kept by PreferDrops policy as long as possible
+func WithPriority[T any](f func(T) bool) Opt[T] {
+	return func(q *FIFO[T]) {
+		q.priority = f
+	}
+}
+
+// WithSize - size of the value counted by the capacity, depth and drops, every value is of size 1 by default,
+// e.g. batch of messages is sized by the number of messages
+func WithSize[T any](f func(T) int) Opt[T] {
+	return func(q *FIFO[T]) {
+		q.size = f
+	}
+}
+
+// WithMetrics - sink of the queue statistics
+func WithMetrics[T any](m Metrics) Opt[T] {
+	return func(q *FIFO[T]) {
+		if m != nil {
+			q.metrics = m
+		}
+	}
+}
+
+// WithSettings - apply all settings of the queue at once
+func WithSettings[T any](s Settings) Opt[T] {
+	return func(q *FIFO[T]) {
+		WithCapacity[T](s.Capacity)(q)
+		WithPolicy[T](s.Policy)(q)
+		WithMetrics[T](s.Metrics)(q)
+	}
+}
+
+// NewFIFO - creates queue, it is unbounded unless capacity is given
+func NewFIFO[T any](opts ...Opt[T]) *FIFO[T] {
+	q := &FIFO[T]{
+		metrics: noMetrics{},
+		ch:      make(chan T),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	q.cv = sync.NewCond(&q.mu)
+	for _, o := range opts {
+		o(q)
+	}
+	go q.run()
+	return q
+}
+
+// Reader - channel to read values from the queue, it is closed when the queue is closed
+func (q *FIFO[T]) Reader() <-chan T {
+	return q.ch
+}
+
+// Put values into the queue, returns false if the queue is sealed or closed
+func (q *FIFO[T]) Put(v ...T) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed || q.sealed {
+		return false
+	}
+	now := time.Now()
+	for i := range v {
+		q.push(v[i], now)
+	}
+	q.cv.Signal()
+	return true
+}
+
+// Len - number of values in the queue regardless of their size
+func (q *FIFO[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.len()
+}
+
+// Seal the queue, values are not accepted anymore, the reader gets values left in the queue
+// and then the reader is closed
+func (q *FIFO[T]) Seal() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.sealed = true
+	q.cv.Broadcast()
+}
+
+// Close the queue, values left in the queue are discarded
+func (q *FIFO[T]) Close() error {
+	_ = q.Discard()
+	return nil
+}
+
+// Discard closes the queue and returns values left in it
+func (q *FIFO[T]) Discard() (left []T) {
+	q.onceClose.Do(func() {
+		q.mu.Lock()
+		q.closed = true
+		used := q.used
+		for q.len() > 0 {
+			left = append(left, q.pop().v)
+		}
+		q.metrics.AddDepth(-used)
+		close(q.stop)
+		q.cv.Broadcast()
+		q.mu.Unlock()
+		<-q.stopped
+	})
+	return left
+}
+
+func (q *FIFO[T]) len() int {
+	return q.prior.Len() + q.other.Len()
+}
+
+func (q *FIFO[T]) push(v T, at time.Time) {
+	prior := q.priority != nil && q.priority(v)
+	size := 1
+	if q.size != nil {
+		size = q.size(v)
+	}
+	// the value bigger than the capacity is taken by the empty queue
+	for q.capacity > 0 && q.used+size > q.capacity && q.len() > 0 {
+		// either the new value or the evicted ones are dropped
+		evicted, ok := q.evict(prior)
+		if !ok {
+			q.metrics.AddDrops(size)
+			return
+		}
+		q.metrics.AddDrops(evicted)
+		q.metrics.AddDepth(-evicted)
+	}
+	q.seq++
+	it := item[T]{v: v, seq: q.seq, at: at, size: size}
+	if prior {
+		q.prior.PushBack(it)
+	} else {
+		q.other.PushBack(it)
+	}
+	q.used += size
+	q.metrics.AddDepth(size)
+}
+
+// evict - make room for the new value by the policy, it returns size of the evicted value,
+// false means the new value is to be dropped
+func (q *FIFO[T]) evict(prior bool) (int, bool) {
+	switch q.policy {
+	case DropNewest:
+		return 0, false
+	case PreferDrops:
+		if q.other.Len() > 0 {
+			return q.remove(&q.other).size, true
+		} else if !prior {
+			return 0, false
+		}
+		return q.remove(&q.prior).size, true
+	}
+	return q.pop().size, true
+}
+
+// pop - the oldest value of the queue
+func (q *FIFO[T]) pop() item[T] {
+	l := &q.other
+	if q.other.Len() == 0 ||
+		(q.prior.Len() > 0 && q.prior.Front().Value.(item[T]).seq < q.other.Front().Value.(item[T]).seq) {
+		l = &q.prior
+	}
+	return q.remove(l)
+}
+
+func (q *FIFO[T]) remove(l *list.List) item[T] {
+	it := l.Remove(l.Front()).(item[T])
+	q.used -= it.size
+	return it
+}
+
+func (q *FIFO[T]) run() {
+	defer func() {
+		close(q.ch)
+		close(q.stopped)
+	}()
+	for {
+		q.mu.Lock()
+		for !q.closed && !q.sealed && q.len() == 0 {
+			q.cv.Wait()
+		}
+		if q.closed || q.len() == 0 {
+			q.mu.Unlock()
+			return
+		}
+		it := q.pop()
+		q.metrics.AddDepth(-it.size)
+		q.mu.Unlock()
+		select {
+		case q.ch <- it.v:
+			q.metrics.ObserveWait(time.Since(it.at))
+		case <-q.stop:
+			return
+		}
+	}
+}
+
+type noMetrics struct{}
+
+func (noMetrics) AddDepth(int)              {}
+func (noMetrics) ObserveWait(time.Duration) {}
+func (noMetrics) AddDrops(int)              {}
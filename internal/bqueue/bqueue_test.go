@@ -0,0 +1,158 @@
+package bqueue
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type bqueueTestSuite struct {
+	suite.Suite
+}
+
+func Test_BQueue(t *testing.T) {
+	suite.Run(t, new(bqueueTestSuite))
+}
+
+type testMetrics struct {
+	depth, drops, waits atomic.Int64
+}
+
+func (m *testMetrics) AddDepth(delta int)        { m.depth.Add(int64(delta)) }
+func (m *testMetrics) ObserveWait(time.Duration) { m.waits.Add(1) }
+func (m *testMetrics) AddDrops(n int)            { m.drops.Add(int64(n)) }
+
+// fill - put values into the queue when the first one has been taken by the delivery routine,
+// so the rest of values stay in the queue until they are read
+func (sui *bqueueTestSuite) fill(q *FIFO[int], v ...int) {
+	sui.Require().True(q.Put(v[0]))
+	sui.Require().Eventually(func() bool {
+		return q.Len() == 0
+	}, time.Second, time.Millisecond)
+	sui.Require().True(q.Put(v[1:]...))
+}
+
+func (sui *bqueueTestSuite) read(q *FIFO[int], n int) (ret []int) {
+	for i := 0; i < n; i++ {
+		select {
+		case v := <-q.Reader():
+			ret = append(ret, v)
+		case <-time.After(time.Second):
+			sui.FailNow("timeout reading the queue")
+		}
+	}
+	return ret
+}
+
+func (sui *bqueueTestSuite) Test_Policies() {
+	isOdd := func(v int) bool { return v%2 != 0 }
+	testData := []struct {
+		name string
+		opts []Opt[int]
+		in   []int
+		exp  []int
+	}{
+		{"unbounded", nil, []int{0, 1, 2, 3, 4}, []int{0, 1, 2, 3, 4}},
+		{"drop-oldest", []Opt[int]{WithCapacity[int](2), WithPolicy[int](DropOldest)}, []int{0, 1, 2, 3, 4}, []int{0, 3, 4}},
+		{"drop-newest", []Opt[int]{WithCapacity[int](2), WithPolicy[int](DropNewest)}, []int{0, 1, 2, 3, 4}, []int{0, 1, 2}},
+		{"prefer-drops", []Opt[int]{WithCapacity[int](2), WithPolicy[int](PreferDrops), WithPriority(isOdd)}, []int{0, 1, 2, 3, 4, 6}, []int{0, 1, 3}},
+		{"prefer-drops-all-prior", []Opt[int]{WithCapacity[int](2), WithPolicy[int](PreferDrops), WithPriority(isOdd)}, []int{0, 1, 3, 5, 7}, []int{0, 5, 7}},
+		{"prefer-drops-no-priority", []Opt[int]{WithCapacity[int](2), WithPolicy[int](PreferDrops)}, []int{0, 1, 2, 3}, []int{0, 2, 3}},
+	}
+	for _, test := range testData {
+		sui.Run(test.name, func() {
+			m := new(testMetrics)
+			q := NewFIFO(append(test.opts, WithMetrics[int](m))...)
+			defer q.Close()
+			sui.fill(q, test.in...)
+			sui.Require().Equal(test.exp, sui.read(q, len(test.exp)))
+			sui.Require().Equal(int64(len(test.in)-len(test.exp)), m.drops.Load())
+			sui.Require().Equal(int64(0), m.depth.Load())
+			sui.Require().Eventually(func() bool {
+				return m.waits.Load() == int64(len(test.exp))
+			}, time.Second, time.Millisecond)
+		})
+	}
+}
+
+func (sui *bqueueTestSuite) Test_Size() {
+	m := new(testMetrics)
+	q := NewFIFO(WithCapacity[[]int](4), WithSize(func(v []int) int { return len(v) }), WithMetrics[[]int](m))
+	defer q.Close()
+	sui.Require().True(q.Put([]int{0}))
+	sui.Require().Eventually(func() bool {
+		return q.Len() == 0
+	}, time.Second, time.Millisecond)
+	// capacity is of the values size, the oldest values are evicted to make room for the new one
+	sui.Require().True(q.Put([]int{1, 2}, []int{3}, []int{4, 5}))
+	sui.Require().Equal(2, q.Len())
+	sui.Require().Equal(int64(2), m.drops.Load())
+	// the value bigger than the capacity evicts all of values
+	sui.Require().True(q.Put([]int{6, 7, 8, 9, 10}))
+	sui.Require().Equal(1, q.Len())
+	sui.Require().Equal(int64(5), m.drops.Load())
+	for _, exp := range [][]int{{0}, {6, 7, 8, 9, 10}} {
+		select {
+		case v := <-q.Reader():
+			sui.Require().Equal(exp, v)
+		case <-time.After(time.Second):
+			sui.FailNow("timeout reading the queue")
+		}
+	}
+	sui.Require().Equal(int64(0), m.depth.Load())
+}
+
+func (sui *bqueueTestSuite) Test_OrderIsKept() {
+	q := NewFIFO(WithCapacity[int](10), WithPolicy[int](PreferDrops), WithPriority(func(v int) bool { return v%3 == 0 }))
+	defer q.Close()
+	sui.fill(q, 0, 1, 2, 3, 4, 5, 6, 7)
+	sui.Require().Equal([]int{0, 1, 2, 3, 4, 5, 6, 7}, sui.read(q, 8))
+}
+
+func (sui *bqueueTestSuite) Test_Close() {
+	m := new(testMetrics)
+	q := NewFIFO(WithSettings[int](Settings{Capacity: 10, Metrics: m}))
+	sui.fill(q, 1, 2, 3)
+	sui.Require().NoError(q.Close())
+	_, ok := <-q.Reader()
+	sui.Require().False(ok)
+	sui.Require().False(q.Put(4))
+	sui.Require().Equal(0, q.Len())
+	sui.Require().Equal(int64(0), m.depth.Load())
+}
+
+func (sui *bqueueTestSuite) Test_Seal() {
+	q := NewFIFO[int]()
+	defer q.Close()
+	sui.fill(q, 1, 2, 3)
+	q.Seal()
+	sui.Require().False(q.Put(4))
+	sui.Require().Equal([]int{1, 2, 3}, sui.read(q, 3))
+	select {
+	case _, ok := <-q.Reader():
+		sui.Require().False(ok)
+	case <-time.After(time.Second):
+		sui.FailNow("reader of the sealed queue has not closed")
+	}
+}
+
+func (sui *bqueueTestSuite) Test_Discard() {
+	m := new(testMetrics)
+	q := NewFIFO(WithMetrics[int](m))
+	sui.fill(q, 1, 2, 3)
+	sui.Require().Equal([]int{2, 3}, q.Discard())
+	sui.Require().Nil(q.Discard())
+	sui.Require().Equal(int64(0), m.depth.Load())
+}
+
+func (sui *bqueueTestSuite) Test_ParsePolicy() {
+	for _, p := range []Policy{DropOldest, DropNewest, PreferDrops} {
+		parsed, err := ParsePolicy(p.String())
+		sui.Require().NoError(err)
+		sui.Require().Equal(p, parsed)
+	}
+	_, err := ParsePolicy("drop-random")
+	sui.Require().Error(err)
+}
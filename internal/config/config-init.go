@@ -1,6 +1,7 @@
 package config
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
@@ -17,6 +18,13 @@ var (
 	globalConfig atomic.Value
 )
 
+// withSourceData - content of the 'WithSource' consumed on init, it is kept to reload config
+type withSourceData struct {
+	Option
+	data []byte
+	typ  string
+}
+
 type (
 	//Option config init option
 	Option interface {
@@ -61,10 +69,33 @@ func configStore() *viper.Viper {
 	return ret
 }
 
-// InitGlobalConfig init global config
+// InitGlobalConfig init global config, options are kept to reload config later
 func InitGlobalConfig(opts ...Option) error {
 	const api = "InitGlobalConfig"
 
+	kept := make([]Option, 0, len(opts))
+	for _, opt := range opts {
+		if t, ok := opt.(WithSource); ok {
+			data, e := io.ReadAll(t.Source)
+			if e != nil {
+				return errors.Wrapf(e, "%s: read source type '%s'", api, t.Type)
+			}
+			opt = withSourceData{data: data, typ: t.Type}
+		}
+		kept = append(kept, opt)
+	}
+	cfgHolder, err := newConfig(api, kept)
+	if err != nil {
+		return err
+	}
+	reloader.mu.Lock()
+	reloader.opts = kept
+	reloader.mu.Unlock()
+	globalConfig.Store(cfgHolder)
+	return nil
+}
+
+func newConfig(api string, opts []Option) (*viper.Viper, error) {
 	cfgHolder := viper.NewWithOptions(viper.KeyDelimiter("/"),
 		viper.EnvKeyReplacer(strings.NewReplacer("/", "_")))
 
@@ -74,20 +105,20 @@ func InitGlobalConfig(opts ...Option) error {
 		switch t := opt.(type) {
 		case WithDefValue:
 			if !reflect.TypeOf(t.Key).ConvertibleTo(keyType) {
-				return errors.Wrapf(errors.New("no possible set default with key)"),
+				return nil, errors.Wrapf(errors.New("no possible set default with key)"),
 					"%s: key type '%T'", api, t)
 			}
 			k := reflect.ValueOf(t.Key).Convert(keyType).Interface().(string)
 			cfgHolder.SetDefault(k, t.Val)
 		case WithCmdFlag:
 			if !reflect.TypeOf(t.Key).ConvertibleTo(keyType) {
-				return errors.Wrapf(errors.New("no possible set default with key)"),
+				return nil, errors.Wrapf(errors.New("no possible set default with key)"),
 					"%s: key type '%T'", api, t)
 			}
 			k := reflect.ValueOf(t.Key).Convert(keyType).Interface().(string)
 
 			if e := cfgHolder.BindPFlag(k, t.Flag); e != nil {
-				return errors.Wrapf(e, "%s: consume cmd flag '%s'", api, t.Flag.Name)
+				return nil, errors.Wrapf(e, "%s: consume cmd flag '%s'", api, t.Flag.Name)
 			}
 		case WithSourceFile:
 			if len(t.FileName) == 0 {
@@ -95,34 +126,33 @@ func InitGlobalConfig(opts ...Option) error {
 			}
 			ext := filepath.Ext(t.FileName)
 			if len(ext) == 0 {
-				return errors.Wrapf(errors.New("no file type provided"),
+				return nil, errors.Wrapf(errors.New("no file type provided"),
 					"%s: open file '%s'", api, t.FileName)
 			}
 			f, e := os.Open(t.FileName)
 			if e != nil {
-				return errors.Wrapf(e, "%s: open file '%s'", api, t.FileName)
+				return nil, errors.Wrapf(e, "%s: open file '%s'", api, t.FileName)
 			}
 			cfgHolder.SetConfigType(ext[1:])
 			e = cfgHolder.MergeConfig(f)
 			_ = f.Close()
 			if e != nil {
-				return errors.Wrapf(e, "%s: consume config file '%s'", api, t.FileName)
+				return nil, errors.Wrapf(e, "%s: consume config file '%s'", api, t.FileName)
 			}
-		case WithSource:
-			cfgHolder.SetConfigType(t.Type)
-			if e := cfgHolder.MergeConfig(t.Source); e != nil {
-				return errors.Wrapf(e, "%s: consume source type '%s'", api, t.Type)
+		case withSourceData:
+			cfgHolder.SetConfigType(t.typ)
+			if e := cfgHolder.MergeConfig(bytes.NewReader(t.data)); e != nil {
+				return nil, errors.Wrapf(e, "%s: consume source type '%s'", api, t.typ)
 			}
 		case WithAcceptEnvironment:
 			cfgHolder.AutomaticEnv()
 			cfgHolder.SetEnvPrefix(t.EnvPrefix)
 		default:
-			return errors.Wrapf(errors.New("unexpected option"),
+			return nil, errors.Wrapf(errors.New("unexpected option"),
 				"%s: consume source type '%T'", api, opt)
 		}
 	}
-	globalConfig.Store(cfgHolder)
-	return nil
+	return cfgHolder, nil
 }
 
 func init() {
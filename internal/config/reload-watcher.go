@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/H-BF/corlib/pkg/patterns/observer"
+	"github.com/H-BF/corlib/pkg/signals"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// ReloadWatcher reloads global config on SIGHUP and when content of the config file changes
+type ReloadWatcher interface {
+	Run(ctx context.Context) error
+	Close() error
+}
+
+type reloadWatcherImpl struct {
+	fileName  string
+	target    string
+	content   []byte
+	onceRun   sync.Once
+	onceClose sync.Once
+	stop      chan struct{}
+	stopped   chan struct{}
+}
+
+// reloadDebounce - editors and config map updates touch the file several times in a row,
+// so config is reloaded when the file has been quiet for a while
+const reloadDebounce = 500 * time.Millisecond
+
+// k8sDataLink - the link k8s swaps to the new content of the mounted config map
+const k8sDataLink = "..data"
+
+// NewReloadWatcher creates watcher of the config file, empty file name means reload on SIGHUP only
+func NewReloadWatcher(fileName string) ReloadWatcher {
+	if fileName != "" {
+		fileName = filepath.Clean(fileName)
+	}
+	return &reloadWatcherImpl{
+		fileName: fileName,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Run -
+func (w *reloadWatcherImpl) Run(ctx context.Context) (err error) {
+	var doRun bool
+	w.onceRun.Do(func() {
+		doRun = true
+		w.stopped = make(chan struct{})
+	})
+	if !doRun {
+		return errors.New("config/ReloadWatcher: it has been run or closed yet")
+	}
+	log := logger.FromContext(ctx).Named("config-watcher")
+	log.Info("start")
+	defer func() {
+		log.Info("stop")
+		close(w.stopped)
+	}()
+
+	sighup := make(chan struct{}, 1)
+	obs := observer.NewObserver(func(ev observer.EventType) {
+		if ev.(signals.SignalFromOS).Signal == syscall.SIGHUP {
+			select {
+			case sighup <- struct{}{}:
+			default:
+			}
+		}
+	}, false, signals.SignalFromOS{})
+	signals.SubjOfSignalsFromOS().ObserversAttach(obs)
+	defer signals.SubjOfSignalsFromOS().ObserversDetach(obs)
+
+	var (
+		fsEvents <-chan fsnotify.Event
+		fsErrors <-chan error
+		settled  <-chan time.Time
+	)
+	if w.fileName != "" {
+		fw, e := fsnotify.NewWatcher()
+		if e != nil {
+			return errors.Wrap(e, "config/ReloadWatcher: create file watcher")
+		}
+		defer fw.Close() //nolint:errcheck
+		// the directory is watched since the file may be replaced by rename or symlink swap
+		if e = fw.Add(filepath.Dir(w.fileName)); e != nil {
+			return errors.Wrapf(e, "config/ReloadWatcher: watch config file '%s'", w.fileName)
+		}
+		fsEvents, fsErrors = fw.Events, fw.Errors
+		w.content, _ = os.ReadFile(w.fileName)
+		w.resolveTarget()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("will exit cause ctx canceled")
+			return ctx.Err()
+		case <-w.stop:
+			log.Info("will exit cause it has closed")
+			return nil
+		case <-sighup:
+			log.Info("SIGHUP is caught, reload config")
+			if w.fileName != "" {
+				w.content, _ = os.ReadFile(w.fileName)
+			}
+			w.reload(ctx, log)
+		case ev, ok := <-fsEvents:
+			if !ok {
+				return errors.New("config/ReloadWatcher: file watcher has closed")
+			}
+			if w.isConfigEvent(ev.Name) {
+				settled = time.After(reloadDebounce)
+			}
+		case e := <-fsErrors:
+			log.Warnf("on watching config file '%s': %v", w.fileName, e)
+		case <-settled:
+			settled = nil
+			w.resolveTarget()
+			content, e := os.ReadFile(w.fileName)
+			if e != nil || bytes.Equal(content, w.content) {
+				continue
+			}
+			w.content = content
+			log.Infof("config file '%s' has changed, reload config", w.fileName)
+			w.reload(ctx, log)
+		}
+	}
+}
+
+// isConfigEvent tells if the event of the watched directory is about the config file:
+// the file itself, the file its symlink points to or the k8s config map link swap,
+// other files of the directory do not restart the debounce
+func (w *reloadWatcherImpl) isConfigEvent(name string) bool {
+	name = filepath.Clean(name)
+	return name == w.fileName ||
+		(w.target != "" && name == w.target) ||
+		filepath.Base(name) == k8sDataLink
+}
+
+// resolveTarget remembers the file the config file symlink points to, it changes on symlink swap
+func (w *reloadWatcherImpl) resolveTarget() {
+	w.target = ""
+	if t, e := filepath.EvalSymlinks(w.fileName); e == nil {
+		w.target = filepath.Clean(t)
+	}
+}
+
+// Close -
+func (w *reloadWatcherImpl) Close() error {
+	w.onceClose.Do(func() {
+		close(w.stop)
+		w.onceRun.Do(func() {})
+		if w.stopped != nil {
+			<-w.stopped
+		}
+	})
+	return nil
+}
+
+func (w *reloadWatcherImpl) reload(ctx context.Context, log logger.TypeOfLogger) {
+	err := Reload(ctx)
+	for _, e := range multierr.Errors(err) {
+		var ch ErrChange
+		switch {
+		case !errors.As(e, &ch):
+			log.Errorf("failed to reload config: %v", e)
+		case errors.Is(ch.Err, ErrNeedRestart):
+			log.Warnf("'%s' has changed but %v", ch.Key, ch.Err)
+		default:
+			log.Errorf("failed to apply change of '%s': %v", ch.Key, ch.Err)
+		}
+	}
+	if err == nil {
+		log.Info("config has been reloaded")
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReloadWatcherConfigEvent(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "config.yaml")
+	target := filepath.Join(dir, "..2024_01_01", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(target), 0o700))
+	require.NoError(t, os.WriteFile(target, []byte("a: 1"), 0o600))
+	require.NoError(t, os.Symlink(target, fileName))
+
+	w := NewReloadWatcher(fileName + "/").(*reloadWatcherImpl)
+	w.resolveTarget()
+	tgt, err := filepath.EvalSymlinks(target)
+	require.NoError(t, err)
+	require.Equal(t, tgt, w.target)
+
+	require.True(t, w.isConfigEvent(fileName))
+	require.True(t, w.isConfigEvent(filepath.Join(dir, ".", "config.yaml")))
+	require.True(t, w.isConfigEvent(tgt))
+	require.True(t, w.isConfigEvent(filepath.Join(dir, "..data")))
+	require.False(t, w.isConfigEvent(filepath.Join(dir, "other.yaml")))
+	require.False(t, w.isConfigEvent(filepath.Join(dir, ".config.yaml.swp")))
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"go.uber.org/multierr"
+)
+
+// ErrNeedRestart change of the value can not be applied without restart
+var ErrNeedRestart = errors.New("restart is required to apply the change")
+
+type (
+	// Change value of the key has changed on reload
+	Change[T any] struct {
+		Key ValueT[T]
+		Old T
+		New T
+	}
+
+	// ErrChange change of the key has not been applied
+	ErrChange struct {
+		Key string
+		Err error
+	}
+
+	subscriber struct {
+		apply func(ctx context.Context, old, new any) error
+	}
+)
+
+var reloader struct {
+	mu   sync.Mutex // serializes reloads
+	opts []Option
+
+	subsMu sync.RWMutex
+	subs   map[string][]*subscriber
+}
+
+// Error -
+func (e ErrChange) Error() string {
+	return fmt.Sprintf("config: key '%s': %v", e.Key, e.Err)
+}
+
+// Cause -
+func (e ErrChange) Cause() error {
+	return e.Err
+}
+
+// OnChange subscribes f to changes of the value made by Reload. f applies the change live
+// or returns ErrNeedRestart when it is not possible. Returned func unsubscribes f
+func (v ValueT[T]) OnChange(f func(ctx context.Context, ch Change[T]) error) (unsubscribe func()) {
+	key := strings.ToLower(v.String())
+	s := &subscriber{
+		apply: func(ctx context.Context, old, new any) error {
+			ch := Change[T]{Key: v}
+			if old != nil {
+				if err := typeCast(old, &ch.Old); err != nil {
+					return err
+				}
+			}
+			if new != nil {
+				if err := typeCast(new, &ch.New); err != nil {
+					return err
+				}
+			}
+			return f(ctx, ch)
+		},
+	}
+	reloader.subsMu.Lock()
+	if reloader.subs == nil {
+		reloader.subs = make(map[string][]*subscriber)
+	}
+	reloader.subs[key] = append(reloader.subs[key], s)
+	reloader.subsMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			reloader.subsMu.Lock()
+			defer reloader.subsMu.Unlock()
+			subs := reloader.subs[key]
+			for i := range subs {
+				if subs[i] == s {
+					reloader.subs[key] = append(subs[:i:i], subs[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// Reload rebuilds global config from options given to InitGlobalConfig, e.g. to consume changes
+// of the config file, and notifies subscribers of the values changed. Config is left intact when
+// it fails to be rebuilt. Returned error combines ErrChange of every change not applied,
+// changes of the values no one is subscribed to require restart
+func Reload(ctx context.Context) error {
+	const api = "config/Reload"
+
+	reloader.mu.Lock()
+	defer reloader.mu.Unlock()
+	if reloader.opts == nil {
+		return errors.Errorf("%s: config has not been initialized", api)
+	}
+	next, err := newConfig(api, reloader.opts)
+	if err != nil {
+		return err
+	}
+	prev := configStore()
+	globalConfig.Store(next)
+
+	var errs []error
+	for _, key := range changedKeys(prev, next) {
+		reloader.subsMu.RLock()
+		subs := append([]*subscriber(nil), reloader.subs[key]...)
+		reloader.subsMu.RUnlock()
+		if len(subs) == 0 {
+			errs = append(errs, ErrChange{Key: key, Err: ErrNeedRestart})
+			continue
+		}
+		old, new := prev.Get(key), next.Get(key)
+		for _, s := range subs {
+			if e := s.apply(ctx, old, new); e != nil {
+				errs = append(errs, ErrChange{Key: key, Err: e})
+			}
+		}
+	}
+	return multierr.Combine(errs...)
+}
+
+// changedKeys - sorted keys of the values which differ in configs
+func changedKeys(prev, next *viper.Viper) []string {
+	keys := make(map[string]struct{})
+	for _, k := range prev.AllKeys() {
+		keys[k] = struct{}{}
+	}
+	for _, k := range next.AllKeys() {
+		keys[k] = struct{}{}
+	}
+	var ret []string
+	for k := range keys {
+		if !reflect.DeepEqual(prev.Get(k), next.Get(k)) {
+			ret = append(ret, k)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
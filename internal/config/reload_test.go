@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+)
+
+func Test_Reload(t *testing.T) {
+	const (
+		level    ValueT[string]        = "logger/level"
+		interval ValueT[time.Duration] = "sync/interval"
+		address  ValueT[string]        = "svc/address"
+	)
+	ctx := context.Background()
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+	writeConf := func(s string) {
+		require.NoError(t, os.WriteFile(fileName, []byte(s), 0o600))
+	}
+	writeConf("logger:\n  level: INFO\nsvc:\n  address: tcp://127.0.0.1:9000\n")
+	require.NoError(t, InitGlobalConfig(
+		WithSourceFile{FileName: fileName},
+		WithDefValue{Key: interval, Val: "3s"},
+	))
+
+	var levels []Change[string]
+	unsubscribe := level.OnChange(func(_ context.Context, ch Change[string]) error {
+		levels = append(levels, ch)
+		return nil
+	})
+	var intervals []Change[time.Duration]
+	interval.OnChange(func(_ context.Context, ch Change[time.Duration]) error {
+		intervals = append(intervals, ch)
+		return errors.New("failed")
+	})
+
+	// nothing has changed
+	require.NoError(t, Reload(ctx))
+	require.Empty(t, levels)
+
+	writeConf("logger:\n  level: DEBUG\nsvc:\n  address: tcp://127.0.0.1:9001\nsync:\n  interval: 5s\n")
+	err := Reload(ctx)
+	require.Equal(t, []Change[string]{{Key: level, Old: "INFO", New: "DEBUG"}}, levels)
+	require.Equal(t, []Change[time.Duration]{{Key: interval, Old: 3 * time.Second, New: 5 * time.Second}}, intervals)
+	require.Equal(t, "DEBUG", level.MustValue(ctx))
+	require.Equal(t, "tcp://127.0.0.1:9001", address.MustValue(ctx))
+
+	errs := multierr.Errors(err)
+	require.Len(t, errs, 2)
+	byKey := make(map[string]error)
+	for _, e := range errs {
+		var ch ErrChange
+		require.True(t, errors.As(e, &ch))
+		byKey[ch.Key] = ch.Err
+	}
+	require.ErrorIs(t, byKey[address.String()], ErrNeedRestart)
+	require.EqualError(t, byKey[interval.String()], "failed")
+
+	// config is left intact when the file is broken
+	writeConf("logger: [")
+	require.Error(t, Reload(ctx))
+	require.Equal(t, "DEBUG", level.MustValue(ctx))
+
+	unsubscribe()
+	writeConf("logger:\n  level: WARN\nsvc:\n  address: tcp://127.0.0.1:9001\nsync:\n  interval: 5s\n")
+	err = Reload(ctx)
+	require.Len(t, levels, 1)
+	var ch ErrChange
+	require.True(t, errors.As(err, &ch))
+	require.Equal(t, level.String(), ch.Key)
+	require.ErrorIs(t, ch.Err, ErrNeedRestart)
+}
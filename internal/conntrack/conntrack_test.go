@@ -0,0 +1,198 @@
+package conntrack
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/mdlayher/netlink"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/sys/unix"
+)
+
+type conntrackTestSuite struct {
+	suite.Suite
+}
+
+func Test_Conntrack(t *testing.T) {
+	suite.Run(t, new(conntrackTestSuite))
+}
+
+func encodeTuple(t Tuple) func(*netlink.AttributeEncoder) error {
+	return func(ae *netlink.AttributeEncoder) error {
+		ae.Nested(CTA_TUPLE_IP, func(ae *netlink.AttributeEncoder) error {
+			ae.Bytes(CTA_IP_V4_SRC, t.SAddr.To4())
+			ae.Bytes(CTA_IP_V4_DST, t.DAddr.To4())
+			return nil
+		})
+		ae.Nested(CTA_TUPLE_PROTO, func(ae *netlink.AttributeEncoder) error {
+			ae.Uint8(CTA_PROTO_NUM, t.Proto)
+			ae.Uint16(CTA_PROTO_SRC_PORT, t.SPort)
+			ae.Uint16(CTA_PROTO_DST_PORT, t.DPort)
+			return nil
+		})
+		return nil
+	}
+}
+
+func encodeEntry(e Entry) []byte {
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = binary.BigEndian
+	ae.Nested(CTA_TUPLE_ORIG, encodeTuple(e.Orig))
+	ae.Nested(CTA_TUPLE_REPLY, encodeTuple(e.Reply))
+	ae.Uint32(CTA_STATUS, e.Status)
+	ae.Uint32(CTA_MARK, e.Mark)
+	ae.Uint32(CTA_ID, e.Id)
+	ae.Uint16(CTA_ZONE, e.Zone)
+	b, err := ae.Encode()
+	if err != nil {
+		panic(err)
+	}
+	return append([]byte{unix.AF_INET, unix.NFNETLINK_V0, 0, 0}, b...)
+}
+
+// dnatEntry - client 10.0.0.1 connects to 1.1.1.1:80 which is translated into 192.168.0.2:8080
+func dnatEntry() Entry {
+	return Entry{
+		Id: 7,
+		Orig: Tuple{
+			Proto: unix.IPPROTO_TCP,
+			SAddr: net.IPv4(10, 0, 0, 1).To4(), DAddr: net.IPv4(1, 1, 1, 1).To4(),
+			SPort: 40000, DPort: 80,
+		},
+		Reply: Tuple{
+			Proto: unix.IPPROTO_TCP,
+			SAddr: net.IPv4(192, 168, 0, 2).To4(), DAddr: net.IPv4(10, 0, 0, 1).To4(),
+			SPort: 8080, DPort: 40000,
+		},
+		Status: IPS_SEEN_REPLY | IPS_CONFIRMED | IPS_DST_NAT,
+		Mark:   0x10,
+		Zone:   3,
+	}
+}
+
+func (sui *conntrackTestSuite) Test_Decode() {
+	exp := dnatEntry()
+	var e Entry
+	sui.Require().NoError(e.InitFromMsg(encodeEntry(exp)))
+	sui.Require().Equal(exp, e)
+	sui.Require().Equal("established", e.State())
+	sui.Require().Equal("dnat", e.Nat())
+	sui.Require().Equal("tcp 10.0.0.1:40000->1.1.1.1:80", e.Orig.String())
+	sui.Require().Equal("tcp 192.168.0.2:8080->10.0.0.1:40000", e.Reply.String())
+}
+
+func (sui *conntrackTestSuite) Test_State() {
+	sui.Require().Equal("new", Entry{}.State())
+	sui.Require().Equal("related", Entry{Status: IPS_EXPECTED | IPS_SEEN_REPLY}.State())
+	sui.Require().Equal("snat,dnat", Entry{Status: IPS_SRC_NAT | IPS_DST_NAT}.Nat())
+	sui.Require().Equal("", Entry{}.Nat())
+}
+
+func (sui *conntrackTestSuite) Test_Cache() {
+	e := dnatEntry()
+	e.Orig.Zone, e.Reply.Zone = e.Zone, e.Zone
+	var c Cache
+	c.Upsert(e)
+	sui.Require().Equal(1, c.Len())
+
+	testData := []struct {
+		name string
+		t    Tuple
+		dir  string
+	}{
+		{"before dnat", e.Orig, DirOriginal},
+		{"after dnat", e.Reply.Reverse(), DirOriginal},
+		{"reply before de-nat", e.Reply, DirReply},
+		{"reply after de-nat", e.Orig.Reverse(), DirReply},
+	}
+	for _, tc := range testData {
+		m, ok := c.Find(tc.t)
+		sui.Require().True(ok, tc.name)
+		sui.Require().Equal(tc.dir, m.Direction, tc.name)
+		sui.Require().Equal(e.Id, m.Id, tc.name)
+	}
+
+	other := e.Orig
+	other.SPort++
+	_, ok := c.Find(other)
+	sui.Require().False(ok)
+
+	upd := e
+	upd.Mark = 0x20
+	c.Upsert(upd)
+	sui.Require().Equal(1, c.Len())
+	m, ok := c.Find(e.Orig)
+	sui.Require().True(ok)
+	sui.Require().Equal(uint32(0x20), m.Mark)
+
+	c.Remove(e)
+	sui.Require().Equal(0, c.Len())
+	_, ok = c.Find(e.Reply)
+	sui.Require().False(ok)
+}
+
+func (sui *conntrackTestSuite) Test_CacheZones() {
+	var c Cache
+	e1, e2 := dnatEntry(), dnatEntry()
+	e1.Zone, e2.Zone, e2.Id = 0, 3, 8
+	c.Upsert(e1)
+	c.Upsert(e2)
+	sui.Require().Equal(2, c.Len())
+
+	t := e1.Orig
+	m, ok := c.Find(t)
+	sui.Require().True(ok)
+	sui.Require().Equal(e1.Id, m.Id)
+	t.Zone = 3
+	m, ok = c.Find(t)
+	sui.Require().True(ok)
+	sui.Require().Equal(e2.Id, m.Id)
+	t.Zone = 4
+	_, ok = c.Find(t)
+	sui.Require().False(ok)
+
+	c.Remove(e2)
+	_, ok = c.Find(e1.Orig)
+	sui.Require().True(ok)
+
+	// traces carry no zone, tuple of the only zone is matched
+	c.Remove(e1)
+	c.Upsert(e2)
+	m, ok = c.Find(e1.Orig)
+	sui.Require().True(ok)
+	sui.Require().Equal(e2.Id, m.Id)
+	sui.Require().Equal(uint16(3), m.Zone)
+	c.Remove(e2)
+	_, ok = c.Find(e1.Orig)
+	sui.Require().False(ok)
+}
+
+func (sui *conntrackTestSuite) Test_Handle() {
+	m := &mirrorImpl{}
+	e := dnatEntry()
+	msg := syscall.NetlinkMessage{
+		Header: syscall.NlMsghdr{Type: unix.NFNL_SUBSYS_CTNETLINK<<8 | IPCTNL_MSG_CT_NEW},
+		Data:   encodeEntry(e),
+	}
+	sui.Require().NoError(m.handle(msg))
+	e.Orig.Zone = e.Zone
+	found, err := m.Find(e.Orig)
+	sui.Require().NoError(err)
+	sui.Require().Equal(DirOriginal, found.Direction)
+
+	msg.Header.Type = unix.NFNL_SUBSYS_CTNETLINK<<8 | IPCTNL_MSG_CT_DELETE
+	sui.Require().NoError(m.handle(msg))
+	_, err = m.Find(e.Orig)
+	sui.Require().ErrorIs(err, ErrConnMiss)
+}
+
+func (sui *conntrackTestSuite) Test_DumpRequest() {
+	b := dumpRequest()
+	msgs, err := syscall.ParseNetlinkMessage(b)
+	sui.Require().NoError(err)
+	sui.Require().Len(msgs, 1)
+	sui.Require().Equal(uint16(unix.NFNL_SUBSYS_CTNETLINK<<8|IPCTNL_MSG_CT_GET), msgs[0].Header.Type)
+	sui.Require().Equal(uint16(unix.NLM_F_REQUEST|unix.NLM_F_DUMP), msgs[0].Header.Flags)
+}
@@ -0,0 +1,63 @@
+package conntrack
+
+// messages of the ctnetlink subsystem (enum cntl_msg_types)
+const (
+	IPCTNL_MSG_CT_NEW uint16 = iota
+	IPCTNL_MSG_CT_GET
+	IPCTNL_MSG_CT_DELETE
+)
+
+// attributes of the conntrack entry (enum ctattr_type)
+const (
+	CTA_UNSPEC uint16 = iota
+	CTA_TUPLE_ORIG
+	CTA_TUPLE_REPLY
+	CTA_STATUS
+	CTA_PROTOINFO
+	CTA_HELP
+	CTA_NAT_SRC
+	CTA_TIMEOUT
+	CTA_MARK
+	CTA_COUNTERS_ORIG
+	CTA_COUNTERS_REPLY
+	CTA_USE
+	CTA_ID
+	CTA_NAT_DST
+	CTA_TUPLE_MASTER
+	CTA_SEQ_ADJ_ORIG
+	CTA_SEQ_ADJ_REPLY
+	CTA_SECMARK
+	CTA_ZONE
+)
+
+// attributes of the tuple (enum ctattr_tuple)
+const (
+	CTA_TUPLE_IP uint16 = iota + 1
+	CTA_TUPLE_PROTO
+	CTA_TUPLE_ZONE
+)
+
+// attributes of the network layer of the tuple (enum ctattr_ip)
+const (
+	CTA_IP_V4_SRC uint16 = iota + 1
+	CTA_IP_V4_DST
+	CTA_IP_V6_SRC
+	CTA_IP_V6_DST
+)
+
+// attributes of the transport layer of the tuple (enum ctattr_l4proto)
+const (
+	CTA_PROTO_NUM uint16 = iota + 1
+	CTA_PROTO_SRC_PORT
+	CTA_PROTO_DST_PORT
+)
+
+// status bits of the conntrack entry (enum ip_conntrack_status)
+const (
+	IPS_EXPECTED   uint32 = 1 << 0
+	IPS_SEEN_REPLY uint32 = 1 << 1
+	IPS_ASSURED    uint32 = 1 << 2
+	IPS_CONFIRMED  uint32 = 1 << 3
+	IPS_SRC_NAT    uint32 = 1 << 4
+	IPS_DST_NAT    uint32 = 1 << 5
+)
@@ -0,0 +1,75 @@
+package conntrack
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/mdlayher/netlink"
+	"github.com/pkg/errors"
+)
+
+// sizeofNfgenmsg - length of the header of the netfilter message (struct nfgenmsg)
+const sizeofNfgenmsg = 4
+
+// InitFromMsg decodes entry from the data of the ctnetlink message
+func (e *Entry) InitFromMsg(data []byte) error {
+	if len(data) < sizeofNfgenmsg {
+		return errors.New("truncated ctnetlink message")
+	}
+	ad, err := netlink.NewAttributeDecoder(data[sizeofNfgenmsg:])
+	if err != nil {
+		return err
+	}
+	ad.ByteOrder = binary.BigEndian
+	for ad.Next() {
+		switch ad.Type() {
+		case CTA_TUPLE_ORIG:
+			ad.Nested(e.Orig.decode)
+		case CTA_TUPLE_REPLY:
+			ad.Nested(e.Reply.decode)
+		case CTA_STATUS:
+			e.Status = ad.Uint32()
+		case CTA_MARK:
+			e.Mark = ad.Uint32()
+		case CTA_ZONE:
+			e.Zone = ad.Uint16()
+		case CTA_ID:
+			e.Id = ad.Uint32()
+		}
+	}
+	return ad.Err()
+}
+
+func (t *Tuple) decode(ad *netlink.AttributeDecoder) error {
+	for ad.Next() {
+		switch ad.Type() {
+		case CTA_TUPLE_IP:
+			ad.Nested(func(ad *netlink.AttributeDecoder) error {
+				for ad.Next() {
+					switch ad.Type() {
+					case CTA_IP_V4_SRC, CTA_IP_V6_SRC:
+						t.SAddr = net.IP(ad.Bytes())
+					case CTA_IP_V4_DST, CTA_IP_V6_DST:
+						t.DAddr = net.IP(ad.Bytes())
+					}
+				}
+				return nil
+			})
+		case CTA_TUPLE_PROTO:
+			ad.Nested(func(ad *netlink.AttributeDecoder) error {
+				for ad.Next() {
+					switch ad.Type() {
+					case CTA_PROTO_NUM:
+						t.Proto = ad.Uint8()
+					case CTA_PROTO_SRC_PORT:
+						t.SPort = ad.Uint16()
+					case CTA_PROTO_DST_PORT:
+						t.DPort = ad.Uint16()
+					}
+				}
+				return nil
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package conntrack
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"syscall"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/nl"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/H-BF/corlib/pkg/patterns/observer"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+type (
+	// Mirror - keeps the copy of the conntrack table in sync with the kernel
+	Mirror interface {
+		Run(ctx context.Context) error
+		Find(t Tuple) (Match, error)
+		Close() error
+	}
+
+	// MirrorOpt - option of the conntrack mirror
+	MirrorOpt func(*mirrorImpl)
+
+	// CountConntrackNlErrMemEvent - conntrack events have been lost by the socket
+	CountConntrackNlErrMemEvent struct {
+		observer.EventType
+		// Lost - estimated number of netlink messages lost on the overrun
+		Lost uint32
+	}
+
+	mirrorImpl struct {
+		agentSubject observer.Subject
+		netnsFd      int
+		cache        Cache
+		onceRun      sync.Once
+		onceClose    sync.Once
+		stop         chan struct{}
+		stopped      chan struct{}
+	}
+)
+
+var _ Mirror = (*mirrorImpl)(nil)
+
+// MirrorWithNetNS - mirror conntrack table of the network namespace referred by file descriptor
+func MirrorWithNetNS(fd int) MirrorOpt {
+	return func(m *mirrorImpl) {
+		m.netnsFd = fd
+	}
+}
+
+// NewMirror creates conntrack mirror fed by the ctnetlink events
+func NewMirror(as observer.Subject, opts ...MirrorOpt) (Mirror, error) {
+	m := &mirrorImpl{
+		agentSubject: as,
+		netnsFd:      -1,
+		stop:         make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m, nil
+}
+
+// Run subscribes to conntrack events and dumps the table to get existing entries
+func (m *mirrorImpl) Run(ctx context.Context) (err error) {
+	var doRun bool
+	m.onceRun.Do(func() {
+		doRun = true
+		m.stopped = make(chan struct{})
+	})
+	if !doRun {
+		return ErrConntrack{Err: errors.New("it has been run or closed yet")}
+	}
+
+	nlWatcher, err := nl.NewNetlinkWatcher(1, unix.NETLINK_NETFILTER,
+		nl.SkWithBufLen(nl.SockBufLen16MB),
+		nl.SkWithNlMs(
+			unix.NFNLGRP_CONNTRACK_NEW,
+			unix.NFNLGRP_CONNTRACK_UPDATE,
+			unix.NFNLGRP_CONNTRACK_DESTROY,
+		),
+		nl.NlWithNetNS(m.netnsFd),
+	)
+	if err != nil {
+		return ErrConntrack{Err: fmt.Errorf("failed to create conntrack-watcher: %v", err)}
+	}
+
+	log := logger.FromContext(ctx).Named("conntrack")
+	log.Info("start")
+	defer func() {
+		log.Info("stop")
+		nlWatcher.Close()
+		close(m.stopped)
+	}()
+	if err = nlWatcher.Send(dumpRequest()); err != nil {
+		return ErrConntrack{Err: err}
+	}
+	reader := nlWatcher.Reader(0)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("will exit cause ctx canceled")
+			return ctx.Err()
+		case <-m.stop:
+			log.Info("will exit cause it has closed")
+			return nil
+		case nlData, ok := <-reader.Read():
+			if !ok {
+				log.Info("will exit cause conntrack watcher has already closed")
+				return ErrConntrack{Err: errors.New("conntrack watcher has already closed")}
+			}
+			if err = nlData.Err; err != nil {
+				if errors.Is(err, nl.ErrNlMem) {
+					// events have been lost so the mirror is not consistent anymore
+					m.agentSubject.Notify(CountConntrackNlErrMemEvent{Lost: nlData.Lost})
+					m.cache.Clear()
+					if err = nlWatcher.Send(dumpRequest()); err != nil {
+						return ErrConntrack{Err: err}
+					}
+					continue
+				}
+				if errors.Is(err, nl.ErrNlDataNotReady) ||
+					errors.Is(err, nl.ErrNlReadInterrupted) {
+					continue
+				}
+				return ErrConntrack{Err: errors.WithMessage(err, "failed to rcv nl message")}
+			}
+			for _, msg := range nlData.Messages {
+				if err = m.handle(msg); err != nil {
+					log.Warnf("failed to handle conntrack message: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// Find returns conntrack entry the packet with tuple belongs to
+func (m *mirrorImpl) Find(t Tuple) (Match, error) {
+	if ret, ok := m.cache.Find(t); ok {
+		return ret, nil
+	}
+	return Match{}, ErrConnMiss
+}
+
+// Close -
+func (m *mirrorImpl) Close() error {
+	m.onceClose.Do(func() {
+		close(m.stop)
+		m.onceRun.Do(func() {})
+		if m.stopped != nil {
+			<-m.stopped
+		}
+		m.cache.Clear()
+	})
+	return nil
+}
+
+func (m *mirrorImpl) handle(msg syscall.NetlinkMessage) error {
+	switch msg.Header.Type {
+	case unix.NLMSG_DONE, unix.NLMSG_NOOP:
+		return nil
+	case unix.NLMSG_ERROR:
+		if len(msg.Data) >= 4 {
+			if errno := int32(binary.NativeEndian.Uint32(msg.Data)); errno != 0 {
+				return syscall.Errno(-errno)
+			}
+		}
+		return nil
+	}
+	if msg.Header.Type>>8 != unix.NFNL_SUBSYS_CTNETLINK {
+		return nil
+	}
+	var e Entry
+	if err := e.InitFromMsg(msg.Data); err != nil {
+		return err
+	}
+	switch msg.Header.Type & 0xff {
+	case IPCTNL_MSG_CT_NEW:
+		m.cache.Upsert(e)
+	case IPCTNL_MSG_CT_DELETE:
+		m.cache.Remove(e)
+	}
+	return nil
+}
+
+// dumpRequest builds request to dump all entries of the conntrack table
+func dumpRequest() []byte {
+	const size = unix.SizeofNlMsghdr + sizeofNfgenmsg
+	b := make([]byte, size)
+	binary.NativeEndian.PutUint32(b[0:], size)
+	binary.NativeEndian.PutUint16(b[4:], unix.NFNL_SUBSYS_CTNETLINK<<8|IPCTNL_MSG_CT_GET)
+	binary.NativeEndian.PutUint16(b[6:], unix.NLM_F_REQUEST|unix.NLM_F_DUMP)
+	b[unix.SizeofNlMsghdr] = unix.AF_UNSPEC
+	b[unix.SizeofNlMsghdr+1] = unix.NFNETLINK_V0
+	return b
+}
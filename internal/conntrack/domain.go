@@ -0,0 +1,241 @@
+package conntrack
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// DirOriginal - packet goes in direction of the connection initiator
+	DirOriginal = "original"
+	// DirReply - packet goes back to the connection initiator
+	DirReply = "reply"
+)
+
+type (
+	// Tuple - connection tuple as conntrack sees it
+	Tuple struct {
+		Proto uint8
+		SAddr net.IP
+		DAddr net.IP
+		SPort uint16
+		DPort uint16
+		// Zone - conntrack zone the tuple is looked up in when the tuple is held by several zones,
+		// the zone of the entry is used by the cache
+		Zone uint16
+	}
+
+	// Entry - conntrack entry
+	Entry struct {
+		Id     uint32
+		Orig   Tuple
+		Reply  Tuple
+		Status uint32
+		Mark   uint32
+		Zone   uint16
+	}
+
+	// Match - entry the packet belongs to and direction of the packet
+	Match struct {
+		Entry
+		Direction string
+	}
+
+	tupleKey struct {
+		zone         uint16
+		proto        uint8
+		saddr, daddr [16]byte
+		sport, dport uint16
+	}
+
+	ref struct {
+		entry *Entry
+		dir   string
+	}
+
+	// Cache - mirror of the conntrack table indexed by tuples of both directions,
+	// tuples are indexed regardless of zone and refer to the entries of all zones holding them
+	Cache struct {
+		mu      sync.RWMutex
+		entries map[tupleKey]*Entry
+		tuples  map[tupleKey][]ref
+	}
+)
+
+// Reverse returns tuple of the opposite direction
+func (t Tuple) Reverse() Tuple {
+	return Tuple{
+		Proto: t.Proto,
+		SAddr: t.DAddr,
+		DAddr: t.SAddr,
+		SPort: t.DPort,
+		DPort: t.SPort,
+		Zone:  t.Zone,
+	}
+}
+
+// String -
+func (t Tuple) String() string {
+	if t.SAddr == nil && t.DAddr == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %s->%s", protoName(t.Proto),
+		net.JoinHostPort(t.SAddr.String(), strconv.Itoa(int(t.SPort))),
+		net.JoinHostPort(t.DAddr.String(), strconv.Itoa(int(t.DPort))),
+	)
+}
+
+func (t Tuple) key(zone uint16) (k tupleKey) {
+	k.zone, k.proto = zone, t.Proto
+	copy(k.saddr[:], t.SAddr.To16())
+	copy(k.daddr[:], t.DAddr.To16())
+	if hasPorts(t.Proto) {
+		k.sport, k.dport = t.SPort, t.DPort
+	}
+	return k
+}
+
+// State returns state of the connection in terms of the 'ct state' expression
+func (e Entry) State() string {
+	switch {
+	case e.Status&IPS_EXPECTED != 0:
+		return "related"
+	case e.Status&IPS_SEEN_REPLY != 0:
+		return "established"
+	}
+	return "new"
+}
+
+// Nat returns kinds of address translation applied to the connection
+func (e Entry) Nat() string {
+	var ret []string
+	if e.Status&IPS_SRC_NAT != 0 {
+		ret = append(ret, "snat")
+	}
+	if e.Status&IPS_DST_NAT != 0 {
+		ret = append(ret, "dnat")
+	}
+	return strings.Join(ret, ",")
+}
+
+// Upsert adds entry or replaces the one having the same original tuple
+func (c *Cache) Upsert(e Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[tupleKey]*Entry)
+		c.tuples = make(map[tupleKey][]ref)
+	}
+	id := e.Orig.key(e.Zone)
+	c.remove(id)
+	p := &e
+	c.entries[id] = p
+	// packets translated by NAT match reversed tuple of the opposite direction
+	for _, r := range [...]struct {
+		t   Tuple
+		dir string
+	}{
+		{e.Orig, DirOriginal},
+		{e.Reply.Reverse(), DirOriginal},
+		{e.Reply, DirReply},
+		{e.Orig.Reverse(), DirReply},
+	} {
+		k := r.t.key(0)
+		refs := slices.DeleteFunc(c.tuples[k], func(x ref) bool {
+			return x.entry.Zone == e.Zone
+		})
+		c.tuples[k] = append(refs, ref{entry: p, dir: r.dir})
+	}
+}
+
+// Remove removes entry having the same original tuple
+func (c *Cache) Remove(e Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(e.Orig.key(e.Zone))
+}
+
+// Find returns entry the packet with tuple belongs to, the tuple held by the only zone is matched
+// whatever zone is asked, identical tuples of several zones are different connections told apart by the zone
+func (c *Cache) Find(t Tuple) (Match, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	refs := c.tuples[t.key(0)]
+	if len(refs) == 1 {
+		return Match{Entry: *refs[0].entry, Direction: refs[0].dir}, true
+	}
+	for _, r := range refs {
+		if r.entry.Zone == t.Zone {
+			return Match{Entry: *r.entry, Direction: r.dir}, true
+		}
+	}
+	return Match{}, false
+}
+
+// Len -
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Clear -
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+	c.tuples = nil
+}
+
+func (c *Cache) remove(id tupleKey) {
+	p, ok := c.entries[id]
+	if !ok {
+		return
+	}
+	delete(c.entries, id)
+	for _, t := range [...]Tuple{p.Orig, p.Reply.Reverse(), p.Reply, p.Orig.Reverse()} {
+		k := t.key(0)
+		refs := slices.DeleteFunc(c.tuples[k], func(x ref) bool {
+			return x.entry == p
+		})
+		if len(refs) == 0 {
+			delete(c.tuples, k)
+		} else {
+			c.tuples[k] = refs
+		}
+	}
+}
+
+func hasPorts(proto uint8) bool {
+	switch proto {
+	case unix.IPPROTO_TCP, unix.IPPROTO_UDP, unix.IPPROTO_UDPLITE, unix.IPPROTO_SCTP, unix.IPPROTO_DCCP:
+		return true
+	}
+	return false
+}
+
+func protoName(proto uint8) string {
+	switch proto {
+	case unix.IPPROTO_TCP:
+		return "tcp"
+	case unix.IPPROTO_UDP:
+		return "udp"
+	case unix.IPPROTO_UDPLITE:
+		return "udplite"
+	case unix.IPPROTO_SCTP:
+		return "sctp"
+	case unix.IPPROTO_DCCP:
+		return "dccp"
+	case unix.IPPROTO_ICMP:
+		return "icmp"
+	case unix.IPPROTO_ICMPV6:
+		return "icmpv6"
+	}
+	return strconv.Itoa(int(proto))
+}
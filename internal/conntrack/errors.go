@@ -0,0 +1,24 @@
+package conntrack
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ErrConntrack -
+type ErrConntrack struct {
+	Err error
+}
+
+// Error -
+func (e ErrConntrack) Error() string {
+	return fmt.Sprintf("Conntrack: %v", e.Err)
+}
+
+// Cause -
+func (e ErrConntrack) Cause() error {
+	return e.Err
+}
+
+var ErrConnMiss = errors.New("conntrack entry is not found")
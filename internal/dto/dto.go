@@ -2,11 +2,16 @@ package dto
 
 import (
 	"context"
+	"strings"
 
+	agent "github.com/wildberries-tech/pkt-tracer/internal/models/agent"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/alert"
 	models "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
 	proto "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
 
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -28,6 +33,35 @@ type (
 	FetchNftTableDTO struct {
 		*proto.NftTableResp
 	}
+
+	AgentInfoDTO struct {
+		Md         metadata.MD
+		RemoteAddr string
+	}
+	AgentDTO struct {
+		*proto.Agent
+	}
+	AgentScopeDTO struct {
+		*proto.ListAgentsReq
+	}
+
+	AlertRuleDTO struct {
+		*proto.AlertRule
+	}
+	AlertDTO struct {
+		*proto.Alert
+	}
+	AlertScopeDTO struct {
+		*proto.ListAlertsReq
+	}
+)
+
+// agent metadata keys
+const (
+	mdUserAgent     = "user-agent"
+	mdAgentVersion  = "x-agent-version"
+	mdAgentHostname = "x-agent-hostname"
+	mdAgentKernel   = "x-agent-kernel"
 )
 
 func (t *NftTableDTO) ToModel() *models.NftTableModel {
@@ -35,6 +69,7 @@ func (t *NftTableDTO) ToModel() *models.NftTableModel {
 		TableName:   t.GetTableName(),
 		TableFamily: t.GetTableFamily(),
 		TableStr:    t.GetTableStr(),
+		NetNS:       t.GetNetns(),
 	}
 	for _, rl := range t.GetRules() {
 		model.Rules = append(model.Rules, &models.NftRule{
@@ -66,36 +101,58 @@ func (t *NftTableDTO) InitFromModel(md *models.NftTableModel) {
 		TableFamily: md.TableFamily,
 		TableStr:    md.TableStr,
 		Rules:       rules,
+		Netns:       md.NetNS,
 	}
 }
 
 func (ft *TraceScopeDTO) ToModel() *models.TraceScopeModel {
 	model := &models.TraceScopeModel{
-		TrId:       ft.GetTrId(),
-		Table:      ft.GetTable(),
-		Chain:      ft.GetChain(),
-		JumpTarget: ft.GetJumpTarget(),
-		RuleHandle: ft.GetRuleHandle(),
-		Family:     ft.GetFamily(),
-		Iifname:    ft.GetIifname(),
-		Oifname:    ft.GetOifname(),
-		SMacAddr:   ft.GetSMacAddr(),
-		DMacAddr:   ft.GetDMacAddr(),
-		SAddr:      ft.GetSAddr(),
-		DAddr:      ft.GetDAddr(),
-		SPort:      ft.GetSPort(),
-		DPort:      ft.GetDPort(),
-		SSgName:    ft.GetSSgName(),
-		DSgName:    ft.GetDSgName(),
-		SSgNet:     ft.GetSSgNet(),
-		DSgNet:     ft.GetDSgNet(),
-		Length:     ft.GetLength(),
-		IpProto:    ft.GetIpProto(),
-		Verdict:    ft.GetVerdict(),
-		Rule:       ft.GetRule(),
-		FollowMode: ft.GetFollowMode(),
-		Query:      ft.GetQuery(),
-		AgentsIds:  ft.GetAgentsIds(),
+		TrId:            ft.GetTrId(),
+		Table:           ft.GetTable(),
+		Chain:           ft.GetChain(),
+		JumpTarget:      ft.GetJumpTarget(),
+		RuleHandle:      ft.GetRuleHandle(),
+		Family:          ft.GetFamily(),
+		Iifname:         ft.GetIifname(),
+		Oifname:         ft.GetOifname(),
+		SMacAddr:        ft.GetSMacAddr(),
+		DMacAddr:        ft.GetDMacAddr(),
+		SAddr:           ft.GetSAddr(),
+		DAddr:           ft.GetDAddr(),
+		SPort:           ft.GetSPort(),
+		DPort:           ft.GetDPort(),
+		SSgName:         ft.GetSSgName(),
+		DSgName:         ft.GetDSgName(),
+		SSgNet:          ft.GetSSgNet(),
+		DSgNet:          ft.GetDSgNet(),
+		NetNS:           ft.GetNetns(),
+		ContainerId:     ft.GetContainerId(),
+		ContainerName:   ft.GetContainerName(),
+		Pod:             ft.GetPod(),
+		PodNamespace:    ft.GetPodNamespace(),
+		ContainerLabels: ft.GetContainerLabels(),
+		Pid:             ft.GetPid(),
+		Comm:            ft.GetComm(),
+		Cgroup:          ft.GetCgroup(),
+		CtState:         ft.GetCtState(),
+		CtDirection:     ft.GetCtDirection(),
+		CtNat:           ft.GetCtNat(),
+		CtMark:          ft.GetCtMark(),
+		CtZone:          ft.GetCtZone(),
+		Source:          ft.GetSource(),
+		LogPrefix:       ft.GetLogPrefix(),
+		LogGroup:        ft.GetLogGroup(),
+		Hook:            ft.GetHook(),
+		SgRule:          ft.GetSgRule(),
+		SgRuleAction:    ft.GetSgRuleAction(),
+		Length:          ft.GetLength(),
+		IpProto:         ft.GetIpProto(),
+		Verdict:         ft.GetVerdict(),
+		Rule:            ft.GetRule(),
+		FollowMode:      ft.GetFollowMode(),
+		Query:           ft.GetQuery(),
+		QueryDsl:        ft.GetQueryDsl(),
+		AgentsIds:       ft.GetAgentsIds(),
 	}
 
 	timeRange := ft.GetTime()
@@ -119,31 +176,52 @@ func (ft *TraceScopeDTO) InitFromProto(msg *proto.TraceScope) {
 
 func (ft *TraceScopeDTO) InitFromModel(md *models.TraceScopeModel) {
 	ft.TraceScope = &proto.TraceScope{
-		TrId:       md.TrId,
-		Table:      md.Table,
-		Chain:      md.Chain,
-		JumpTarget: md.JumpTarget,
-		RuleHandle: md.RuleHandle,
-		Family:     md.Family,
-		Iifname:    md.Iifname,
-		Oifname:    md.Oifname,
-		SMacAddr:   md.SMacAddr,
-		DMacAddr:   md.DMacAddr,
-		SAddr:      md.SAddr,
-		DAddr:      md.DAddr,
-		SPort:      md.SPort,
-		DPort:      md.DPort,
-		SSgName:    md.SSgName,
-		DSgName:    md.DSgName,
-		SSgNet:     md.SSgNet,
-		DSgNet:     md.DSgNet,
-		Length:     md.Length,
-		IpProto:    md.IpProto,
-		Verdict:    md.Verdict,
-		Rule:       md.Rule,
-		FollowMode: md.FollowMode,
-		Query:      md.Query,
-		AgentsIds:  md.AgentsIds,
+		TrId:            md.TrId,
+		Table:           md.Table,
+		Chain:           md.Chain,
+		JumpTarget:      md.JumpTarget,
+		RuleHandle:      md.RuleHandle,
+		Family:          md.Family,
+		Iifname:         md.Iifname,
+		Oifname:         md.Oifname,
+		SMacAddr:        md.SMacAddr,
+		DMacAddr:        md.DMacAddr,
+		SAddr:           md.SAddr,
+		DAddr:           md.DAddr,
+		SPort:           md.SPort,
+		DPort:           md.DPort,
+		SSgName:         md.SSgName,
+		DSgName:         md.DSgName,
+		SSgNet:          md.SSgNet,
+		DSgNet:          md.DSgNet,
+		Length:          md.Length,
+		IpProto:         md.IpProto,
+		Verdict:         md.Verdict,
+		Rule:            md.Rule,
+		FollowMode:      md.FollowMode,
+		Query:           md.Query,
+		QueryDsl:        md.QueryDsl,
+		AgentsIds:       md.AgentsIds,
+		Netns:           md.NetNS,
+		ContainerId:     md.ContainerId,
+		ContainerName:   md.ContainerName,
+		Pod:             md.Pod,
+		PodNamespace:    md.PodNamespace,
+		ContainerLabels: md.ContainerLabels,
+		Pid:             md.Pid,
+		Comm:            md.Comm,
+		Cgroup:          md.Cgroup,
+		CtState:         md.CtState,
+		CtDirection:     md.CtDirection,
+		CtNat:           md.CtNat,
+		CtMark:          md.CtMark,
+		CtZone:          md.CtZone,
+		Source:          md.Source,
+		LogPrefix:       md.LogPrefix,
+		LogGroup:        md.LogGroup,
+		Hook:            md.Hook,
+		SgRule:          md.SgRule,
+		SgRuleAction:    md.SgRuleAction,
 	}
 	if md.Time != nil {
 		ft.Time = &proto.TimeRange{
@@ -155,30 +233,55 @@ func (ft *TraceScopeDTO) InitFromModel(md *models.TraceScopeModel) {
 
 func (t *TraceDTO) ToModel() *models.TraceModel {
 	model := &models.TraceModel{
-		TrId:       t.GetTrId(),
-		Table:      t.GetTable(),
-		Chain:      t.GetChain(),
-		JumpTarget: t.GetJumpTarget(),
-		RuleHandle: t.GetRuleHandle(),
-		Family:     t.GetFamily(),
-		Iifname:    t.GetIifname(),
-		Oifname:    t.GetOifname(),
-		SMacAddr:   t.GetSMacAddr(),
-		DMacAddr:   t.GetDMacAddr(),
-		SAddr:      t.GetSAddr(),
-		DAddr:      t.GetDAddr(),
-		SPort:      t.GetSPort(),
-		DPort:      t.GetDPort(),
-		SSgName:    t.GetSSgName(),
-		DSgName:    t.GetDSgName(),
-		SSgNet:     t.GetSSgNet(),
-		DSgNet:     t.GetDSgNet(),
-		Length:     t.GetLength(),
-		IpProto:    t.GetIpProto(),
-		Verdict:    t.GetVerdict(),
-		Rule:       t.GetRule(),
-	}
-	if md := t.Md.Get("user-agent"); len(md) > 0 {
+		TrId:            t.GetTrId(),
+		Table:           t.GetTable(),
+		Chain:           t.GetChain(),
+		JumpTarget:      t.GetJumpTarget(),
+		RuleHandle:      t.GetRuleHandle(),
+		Family:          t.GetFamily(),
+		Iifname:         t.GetIifname(),
+		Oifname:         t.GetOifname(),
+		SMacAddr:        t.GetSMacAddr(),
+		DMacAddr:        t.GetDMacAddr(),
+		SAddr:           t.GetSAddr(),
+		DAddr:           t.GetDAddr(),
+		SPort:           t.GetSPort(),
+		DPort:           t.GetDPort(),
+		SSgName:         t.GetSSgName(),
+		DSgName:         t.GetDSgName(),
+		SSgNet:          t.GetSSgNet(),
+		DSgNet:          t.GetDSgNet(),
+		NetNS:           t.GetNetns(),
+		ContainerId:     t.GetContainerId(),
+		ContainerName:   t.GetContainerName(),
+		Pod:             t.GetPod(),
+		PodNamespace:    t.GetPodNamespace(),
+		ContainerLabels: t.GetContainerLabels(),
+		SockInode:       t.GetSockInode(),
+		Pid:             t.GetPid(),
+		Comm:            t.GetComm(),
+		Cgroup:          t.GetCgroup(),
+		CtState:         t.GetCtState(),
+		CtDirection:     t.GetCtDirection(),
+		CtNat:           t.GetCtNat(),
+		CtMark:          t.GetCtMark(),
+		CtZone:          t.GetCtZone(),
+		CtOrig:          t.GetCtOrig(),
+		CtReply:         t.GetCtReply(),
+		Source:          t.GetSource(),
+		LogPrefix:       t.GetLogPrefix(),
+		LogGroup:        t.GetLogGroup(),
+		Hook:            t.GetHook(),
+		SgRule:          t.GetSgRule(),
+		SgRuleAction:    t.GetSgRuleAction(),
+		RawLlHeader:     t.GetRawLlHeader(),
+		RawHeaders:      t.GetRawHeaders(),
+		Length:          t.GetLength(),
+		IpProto:         t.GetIpProto(),
+		Verdict:         t.GetVerdict(),
+		Rule:            t.GetRule(),
+	}
+	if md := t.Md.Get(mdUserAgent); len(md) > 0 {
 		model.UserAgent = md[0]
 	}
 
@@ -196,57 +299,108 @@ func (t *TraceDTO) InitFromProto(ctx context.Context, msg *proto.Trace) {
 
 func (t *TraceDTO) InitFromModel(md *models.TraceModel) {
 	t.Trace = &proto.Trace{
-		TrId:       md.TrId,
-		Table:      md.Table,
-		Chain:      md.Chain,
-		JumpTarget: md.JumpTarget,
-		RuleHandle: md.RuleHandle,
-		Family:     md.Family,
-		Iifname:    md.Iifname,
-		Oifname:    md.Oifname,
-		SMacAddr:   md.SMacAddr,
-		DMacAddr:   md.DMacAddr,
-		SAddr:      md.SAddr,
-		DAddr:      md.DAddr,
-		SPort:      md.SPort,
-		DPort:      md.DPort,
-		SSgName:    md.SSgName,
-		DSgName:    md.DSgName,
-		SSgNet:     md.SSgNet,
-		DSgNet:     md.DSgNet,
-		Length:     md.Length,
-		IpProto:    md.IpProto,
-		Verdict:    md.Verdict,
-		Rule:       md.Rule,
+		TrId:            md.TrId,
+		Table:           md.Table,
+		Chain:           md.Chain,
+		JumpTarget:      md.JumpTarget,
+		RuleHandle:      md.RuleHandle,
+		Family:          md.Family,
+		Iifname:         md.Iifname,
+		Oifname:         md.Oifname,
+		SMacAddr:        md.SMacAddr,
+		DMacAddr:        md.DMacAddr,
+		SAddr:           md.SAddr,
+		DAddr:           md.DAddr,
+		SPort:           md.SPort,
+		DPort:           md.DPort,
+		SSgName:         md.SSgName,
+		DSgName:         md.DSgName,
+		SSgNet:          md.SSgNet,
+		DSgNet:          md.DSgNet,
+		Netns:           md.NetNS,
+		ContainerId:     md.ContainerId,
+		ContainerName:   md.ContainerName,
+		Pod:             md.Pod,
+		PodNamespace:    md.PodNamespace,
+		ContainerLabels: md.ContainerLabels,
+		SockInode:       md.SockInode,
+		Pid:             md.Pid,
+		Comm:            md.Comm,
+		Cgroup:          md.Cgroup,
+		CtState:         md.CtState,
+		CtDirection:     md.CtDirection,
+		CtNat:           md.CtNat,
+		CtMark:          md.CtMark,
+		CtZone:          md.CtZone,
+		CtOrig:          md.CtOrig,
+		CtReply:         md.CtReply,
+		Source:          md.Source,
+		LogPrefix:       md.LogPrefix,
+		LogGroup:        md.LogGroup,
+		Hook:            md.Hook,
+		SgRule:          md.SgRule,
+		SgRuleAction:    md.SgRuleAction,
+		RawLlHeader:     md.RawLlHeader,
+		RawHeaders:      md.RawHeaders,
+		Length:          md.Length,
+		IpProto:         md.IpProto,
+		Verdict:         md.Verdict,
+		Rule:            md.Rule,
 	}
 }
 
 func (t *FetchTraceDTO) ToModel() *models.FetchTraceModel {
 	return &models.FetchTraceModel{
-		TrId:       t.Trace.TrId,
-		TableId:    t.TableId,
-		Table:      t.Trace.Table,
-		Chain:      t.Trace.Chain,
-		JumpTarget: t.Trace.JumpTarget,
-		RuleHandle: t.Trace.RuleHandle,
-		Rule:       t.Trace.Rule,
-		Verdict:    t.Trace.Verdict,
-		Iifname:    t.Trace.Iifname,
-		Oifname:    t.Trace.Oifname,
-		Family:     t.Trace.Family,
-		IpProto:    t.Trace.IpProto,
-		Length:     t.Trace.Length,
-		SMacAddr:   t.Trace.SMacAddr,
-		DMacAddr:   t.Trace.DMacAddr,
-		SAddr:      t.Trace.SAddr,
-		DAddr:      t.Trace.DAddr,
-		SPort:      t.Trace.SPort,
-		DPort:      t.Trace.DPort,
-		SSgName:    t.Trace.SSgName,
-		DSgName:    t.Trace.DSgName,
-		SSgNet:     t.Trace.SSgNet,
-		DSgNet:     t.Trace.DSgNet,
-		Timestamp:  t.Timestamp.AsTime(),
+		TrId:            t.Trace.TrId,
+		TableId:         t.TableId,
+		Table:           t.Trace.Table,
+		Chain:           t.Trace.Chain,
+		JumpTarget:      t.Trace.JumpTarget,
+		RuleHandle:      t.Trace.RuleHandle,
+		Rule:            t.Trace.Rule,
+		Verdict:         t.Trace.Verdict,
+		Iifname:         t.Trace.Iifname,
+		Oifname:         t.Trace.Oifname,
+		Family:          t.Trace.Family,
+		IpProto:         t.Trace.IpProto,
+		Length:          t.Trace.Length,
+		SMacAddr:        t.Trace.SMacAddr,
+		DMacAddr:        t.Trace.DMacAddr,
+		SAddr:           t.Trace.SAddr,
+		DAddr:           t.Trace.DAddr,
+		SPort:           t.Trace.SPort,
+		DPort:           t.Trace.DPort,
+		SSgName:         t.Trace.SSgName,
+		DSgName:         t.Trace.DSgName,
+		SSgNet:          t.Trace.SSgNet,
+		DSgNet:          t.Trace.DSgNet,
+		NetNS:           t.Trace.Netns,
+		ContainerId:     t.Trace.ContainerId,
+		ContainerName:   t.Trace.ContainerName,
+		Pod:             t.Trace.Pod,
+		PodNamespace:    t.Trace.PodNamespace,
+		ContainerLabels: t.Trace.ContainerLabels,
+		SockInode:       t.Trace.SockInode,
+		Pid:             t.Trace.Pid,
+		Comm:            t.Trace.Comm,
+		Cgroup:          t.Trace.Cgroup,
+		CtState:         t.Trace.CtState,
+		CtDirection:     t.Trace.CtDirection,
+		CtNat:           t.Trace.CtNat,
+		CtMark:          t.Trace.CtMark,
+		CtZone:          t.Trace.CtZone,
+		CtOrig:          t.Trace.CtOrig,
+		CtReply:         t.Trace.CtReply,
+		Source:          t.Trace.Source,
+		LogPrefix:       t.Trace.LogPrefix,
+		LogGroup:        t.Trace.LogGroup,
+		Hook:            t.Trace.Hook,
+		SgRule:          t.Trace.SgRule,
+		SgRuleAction:    t.Trace.SgRuleAction,
+		RawLlHeader:     t.Trace.RawLlHeader,
+		RawHeaders:      t.Trace.RawHeaders,
+		UserAgent:       t.AgentId,
+		Timestamp:       t.Timestamp.AsTime(),
 	}
 }
 
@@ -261,31 +415,57 @@ func (t *FetchTraceDTO) InitFromProto(msg *proto.FetchTrace) {
 func (t *FetchTraceDTO) InitFromModel(md *models.FetchTraceModel) {
 	t.FetchTrace = &proto.FetchTrace{
 		Trace: &proto.Trace{
-			TrId:       md.TrId,
-			Table:      md.Table,
-			Chain:      md.Chain,
-			JumpTarget: md.JumpTarget,
-			RuleHandle: md.RuleHandle,
-			Family:     md.Family,
-			Iifname:    md.Iifname,
-			Oifname:    md.Oifname,
-			SMacAddr:   md.SMacAddr,
-			DMacAddr:   md.DMacAddr,
-			SAddr:      md.SAddr,
-			DAddr:      md.DAddr,
-			SPort:      md.SPort,
-			DPort:      md.DPort,
-			Length:     md.Length,
-			IpProto:    md.IpProto,
-			Verdict:    md.Verdict,
-			Rule:       md.Rule,
-			SSgName:    md.SSgName,
-			DSgName:    md.DSgName,
-			SSgNet:     md.SSgNet,
-			DSgNet:     md.DSgNet,
+			TrId:            md.TrId,
+			Table:           md.Table,
+			Chain:           md.Chain,
+			JumpTarget:      md.JumpTarget,
+			RuleHandle:      md.RuleHandle,
+			Family:          md.Family,
+			Iifname:         md.Iifname,
+			Oifname:         md.Oifname,
+			SMacAddr:        md.SMacAddr,
+			DMacAddr:        md.DMacAddr,
+			SAddr:           md.SAddr,
+			DAddr:           md.DAddr,
+			SPort:           md.SPort,
+			DPort:           md.DPort,
+			Length:          md.Length,
+			IpProto:         md.IpProto,
+			Verdict:         md.Verdict,
+			Rule:            md.Rule,
+			SSgName:         md.SSgName,
+			DSgName:         md.DSgName,
+			SSgNet:          md.SSgNet,
+			DSgNet:          md.DSgNet,
+			Netns:           md.NetNS,
+			ContainerId:     md.ContainerId,
+			ContainerName:   md.ContainerName,
+			Pod:             md.Pod,
+			PodNamespace:    md.PodNamespace,
+			ContainerLabels: md.ContainerLabels,
+			SockInode:       md.SockInode,
+			Pid:             md.Pid,
+			Comm:            md.Comm,
+			Cgroup:          md.Cgroup,
+			CtState:         md.CtState,
+			CtDirection:     md.CtDirection,
+			CtNat:           md.CtNat,
+			CtMark:          md.CtMark,
+			CtZone:          md.CtZone,
+			CtOrig:          md.CtOrig,
+			CtReply:         md.CtReply,
+			Source:          md.Source,
+			LogPrefix:       md.LogPrefix,
+			LogGroup:        md.LogGroup,
+			Hook:            md.Hook,
+			SgRule:          md.SgRule,
+			SgRuleAction:    md.SgRuleAction,
+			RawLlHeader:     md.RawLlHeader,
+			RawHeaders:      md.RawHeaders,
 		},
 		TableId:   md.TableId,
 		Timestamp: timestamppb.New(md.Timestamp),
+		AgentId:   md.UserAgent,
 	}
 }
 
@@ -293,6 +473,7 @@ func (t *FetchNftTableDTO) InitFromModel(md *models.FetchNftTableModel) {
 	t.NftTableResp = &proto.NftTableResp{
 		TableId:   md.TableId,
 		TableStr:  md.TableStr,
+		Netns:     md.NetNS,
 		Timestamp: timestamppb.New(md.Timestamp),
 	}
 }
@@ -301,6 +482,7 @@ func (t *FetchNftTableDTO) ToModel() *models.FetchNftTableModel {
 	return &models.FetchNftTableModel{
 		TableId:   t.TableId,
 		TableStr:  t.TableStr,
+		NetNS:     t.GetNetns(),
 		Timestamp: t.Timestamp.AsTime(),
 	}
 }
@@ -312,3 +494,207 @@ func (t *FetchNftTableDTO) InitFromProto(msg *proto.NftTableResp) {
 func (t *FetchNftTableDTO) ToProto() *proto.NftTableResp {
 	return t.NftTableResp
 }
+
+func (a *AgentInfoDTO) InitFromContext(ctx context.Context) {
+	a.Md, _ = metadata.FromIncomingContext(ctx)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		a.RemoteAddr = p.Addr.String()
+	}
+}
+
+func (a *AgentInfoDTO) InitFromModel(md *agent.AgentInfo) {
+	a.Md = metadata.Pairs(
+		mdAgentVersion, md.Version,
+		mdAgentHostname, md.Hostname,
+		mdAgentKernel, md.Kernel,
+	)
+}
+
+// AgentId returns agent identifier the same as it is stored with traces
+func (a *AgentInfoDTO) AgentId() string {
+	if md := a.Md.Get(mdUserAgent); len(md) > 0 {
+		return md[0]
+	}
+	return ""
+}
+
+func (a *AgentInfoDTO) ToModel() *agent.AgentInfo {
+	get := func(k string) string {
+		return strings.Join(a.Md.Get(k), ",")
+	}
+	return &agent.AgentInfo{
+		Version:  get(mdAgentVersion),
+		Hostname: get(mdAgentHostname),
+		Kernel:   get(mdAgentKernel),
+	}
+}
+
+// AppendToOutgoingContext attaches agent info to the outgoing grpc metadata
+func (a *AgentInfoDTO) AppendToOutgoingContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return metadata.NewOutgoingContext(ctx, metadata.Join(md, a.Md))
+}
+
+func (a *AgentDTO) InitFromModel(md *agent.AgentModel) {
+	a.Agent = &proto.Agent{
+		Id:          md.Id,
+		Version:     md.Version,
+		Hostname:    md.Hostname,
+		Kernel:      md.Kernel,
+		RemoteAddr:  md.RemoteAddr,
+		ConnectedAt: timestamppb.New(md.ConnectedAt),
+		LastSeenAt:  timestamppb.New(md.LastSeenAt),
+		TraceRate:   md.TraceRate,
+		TracesTotal: md.TracesTotal,
+		Online:      md.Online,
+	}
+	if !md.LastTraceAt.IsZero() {
+		a.LastTraceAt = timestamppb.New(md.LastTraceAt)
+	}
+}
+
+func (a *AgentDTO) ToModel() *agent.AgentModel {
+	ret := &agent.AgentModel{
+		AgentInfo: agent.AgentInfo{
+			Version:  a.GetVersion(),
+			Hostname: a.GetHostname(),
+			Kernel:   a.GetKernel(),
+		},
+		Id:          a.GetId(),
+		RemoteAddr:  a.GetRemoteAddr(),
+		ConnectedAt: a.GetConnectedAt().AsTime(),
+		LastSeenAt:  a.GetLastSeenAt().AsTime(),
+		TraceRate:   a.GetTraceRate(),
+		TracesTotal: a.GetTracesTotal(),
+		Online:      a.GetOnline(),
+	}
+	if a.GetLastTraceAt() != nil {
+		ret.LastTraceAt = a.GetLastTraceAt().AsTime()
+	}
+	return ret
+}
+
+func (a *AgentDTO) InitFromProto(msg *proto.Agent) {
+	a.Agent = msg
+}
+
+func (a *AgentDTO) ToProto() *proto.Agent {
+	return a.Agent
+}
+
+func (a *AgentScopeDTO) InitFromModel(md *agent.AgentScopeModel) {
+	a.ListAgentsReq = &proto.ListAgentsReq{
+		AgentsIds:  md.AgentsIds,
+		OnlineOnly: md.OnlineOnly,
+	}
+}
+
+func (a *AgentScopeDTO) ToModel() *agent.AgentScopeModel {
+	return &agent.AgentScopeModel{
+		AgentsIds:  a.GetAgentsIds(),
+		OnlineOnly: a.GetOnlineOnly(),
+	}
+}
+
+func (a *AgentScopeDTO) InitFromProto(msg *proto.ListAgentsReq) {
+	a.ListAgentsReq = msg
+}
+
+func (a *AgentScopeDTO) ToProto() *proto.ListAgentsReq {
+	return a.ListAgentsReq
+}
+
+func (a *AlertRuleDTO) InitFromModel(md *alert.AlertRuleModel) {
+	a.AlertRule = &proto.AlertRule{
+		Name:      md.Name,
+		Query:     md.Query,
+		Threshold: md.Threshold,
+		Window:    durationpb.New(md.Window),
+		Webhook:   md.Webhook,
+	}
+	if md.RepeatInterval != 0 {
+		a.RepeatInterval = durationpb.New(md.RepeatInterval)
+	}
+}
+
+func (a *AlertRuleDTO) ToModel() *alert.AlertRuleModel {
+	return &alert.AlertRuleModel{
+		Name:           a.GetName(),
+		Query:          a.GetQuery(),
+		Threshold:      a.GetThreshold(),
+		Window:         a.GetWindow().AsDuration(),
+		Webhook:        a.GetWebhook(),
+		RepeatInterval: a.GetRepeatInterval().AsDuration(),
+	}
+}
+
+func (a *AlertRuleDTO) InitFromProto(msg *proto.AlertRule) {
+	a.AlertRule = msg
+}
+
+func (a *AlertRuleDTO) ToProto() *proto.AlertRule {
+	return a.AlertRule
+}
+
+func (a *AlertDTO) InitFromModel(md *alert.AlertModel) {
+	var rule AlertRuleDTO
+	rule.InitFromModel(&md.Rule)
+	a.Alert = &proto.Alert{
+		Rule:   rule.ToProto(),
+		Source: md.Source,
+		Firing: md.Firing,
+		Count:  md.Count,
+	}
+	if !md.FiringSince.IsZero() {
+		a.FiringSince = timestamppb.New(md.FiringSince)
+	}
+	if !md.NotifiedAt.IsZero() {
+		a.NotifiedAt = timestamppb.New(md.NotifiedAt)
+	}
+}
+
+func (a *AlertDTO) ToModel() *alert.AlertModel {
+	var rule AlertRuleDTO
+	rule.InitFromProto(a.GetRule())
+	ret := &alert.AlertModel{
+		Rule:   *rule.ToModel(),
+		Source: a.GetSource(),
+		Firing: a.GetFiring(),
+		Count:  a.GetCount(),
+	}
+	if a.GetFiringSince() != nil {
+		ret.FiringSince = a.GetFiringSince().AsTime()
+	}
+	if a.GetNotifiedAt() != nil {
+		ret.NotifiedAt = a.GetNotifiedAt().AsTime()
+	}
+	return ret
+}
+
+func (a *AlertDTO) InitFromProto(msg *proto.Alert) {
+	a.Alert = msg
+}
+
+func (a *AlertDTO) ToProto() *proto.Alert {
+	return a.Alert
+}
+
+func (a *AlertScopeDTO) InitFromModel(md *alert.AlertScopeModel) {
+	a.ListAlertsReq = &proto.ListAlertsReq{
+		FiringOnly: md.FiringOnly,
+	}
+}
+
+func (a *AlertScopeDTO) ToModel() *alert.AlertScopeModel {
+	return &alert.AlertScopeModel{
+		FiringOnly: a.GetFiringOnly(),
+	}
+}
+
+func (a *AlertScopeDTO) InitFromProto(msg *proto.ListAlertsReq) {
+	a.ListAlertsReq = msg
+}
+
+func (a *AlertScopeDTO) ToProto() *proto.ListAlertsReq {
+	return a.ListAlertsReq
+}
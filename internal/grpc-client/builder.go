@@ -30,12 +30,16 @@ type (
 	// TransportCredentials is an alias to credentials.TransportCredentials
 	TransportCredentials = credentials.TransportCredentials
 
+	// PerRPCCredentials is an alias to credentials.PerRPCCredentials
+	PerRPCCredentials = credentials.PerRPCCredentials
+
 	clientConnBuilder struct {
 		addr           string
 		dialDuration   time.Duration
 		retriesBackoff Backoff
 		maxRetries     uint
 		creds          TransportCredentials
+		perRPCCreds    PerRPCCredentials
 		userAgent      string
 		compressor     string
 	}
@@ -68,6 +72,18 @@ func (bld clientConnBuilder) WithUserAgent(userAgent string) clientConnBuilder {
 	return bld
 }
 
+// WithCreds sets transport credentials (insecure by default)
+func (bld clientConnBuilder) WithCreds(creds TransportCredentials) clientConnBuilder {
+	bld.creds = creds
+	return bld
+}
+
+// WithPerRPCCreds attaches credentials to every call
+func (bld clientConnBuilder) WithPerRPCCreds(creds PerRPCCredentials) clientConnBuilder {
+	bld.perRPCCreds = creds
+	return bld
+}
+
 // WithCompression comress data
 func (bld clientConnBuilder) WithCompression(compressor string) clientConnBuilder {
 	bld.compressor = compressor
@@ -94,6 +110,9 @@ func (bld clientConnBuilder) New(ctx context.Context) (*grpc.ClientConn, error)
 		bld.creds = insecure.NewCredentials()
 	}
 	dialOpts = append(dialOpts, grpc.WithTransportCredentials(bld.creds))
+	if bld.perRPCCreds != nil {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(bld.perRPCCreds))
+	}
 	if dialDuration := bld.dialDuration; dialDuration <= 0 {
 		dialOpts = append(dialOpts, grpc.WithReturnConnectionError()) //nolint:staticcheck
 	} else {
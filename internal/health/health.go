@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/H-BF/corlib/pkg/patterns/observer"
+	"github.com/pkg/errors"
+)
+
+// components of the agent reporting their health
+const (
+	Collector    = "collector"
+	Merger       = "merger"
+	Sender       = "sender"
+	TableWatcher = "table-watcher"
+	SgCollector  = "sg-collector"
+)
+
+// ErrStalled - instance of the component is running but makes no progress
+var ErrStalled = errors.New("stalled")
+
+// StateEvent - instance of the component has changed its health state
+type StateEvent struct {
+	observer.EventType
+	Component string
+	// Instance - instance of the component, e.g. network namespace of the pipeline, empty for the single one
+	Instance string
+	// Err - reason of the instance being unhealthy, nil when it is healthy
+	Err error
+	// Gone - instance has stopped on purpose and is not taken into account anymore
+	Gone bool
+}
+
+// Report - instance of the component is healthy when err is nil
+func Report(s observer.Subject, component, instance string, err error) {
+	if s != nil {
+		s.Notify(StateEvent{Component: component, Instance: instance, Err: err})
+	}
+}
+
+// ReportExit - instance of the component has finished its work, it is unhealthy unless
+// it has finished cleanly or on cancel
+func ReportExit(s observer.Subject, component, instance string, err error) {
+	if s == nil {
+		return
+	}
+	if err == nil || errors.Is(errors.Cause(err), context.Canceled) {
+		s.Notify(StateEvent{Component: component, Instance: instance, Gone: true})
+		return
+	}
+	s.Notify(StateEvent{Component: component, Instance: instance, Err: err})
+}
+
+// WatchStall - report instance of the component unhealthy while the time of its last progress
+// is older than timeout and healthy again when it progresses, it returns when ctx is done
+func WatchStall(ctx context.Context, s observer.Subject, component, instance string,
+	lastProgress func() time.Time, timeout time.Duration) {
+	ticker := time.NewTicker(timeout / 2)
+	defer ticker.Stop()
+	var stalled bool
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			at := lastProgress()
+			if st := !at.IsZero() && now.Sub(at) > timeout; st != stalled {
+				stalled = st
+				var err error
+				if stalled {
+					err = errors.WithMessagef(ErrStalled, "no progress since %s", at.Format(time.RFC3339))
+				}
+				Report(s, component, instance, err)
+			}
+		}
+	}
+}
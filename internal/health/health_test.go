@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/H-BF/corlib/pkg/patterns/observer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type errComponent struct {
+	Err error
+}
+
+func (e errComponent) Error() string { return "component: " + e.Err.Error() }
+
+func (e errComponent) Cause() error { return e.Err }
+
+func Test_ReportExit(t *testing.T) {
+	var events []StateEvent
+	subject := observer.NewSubject()
+	subject.ObserversAttach(observer.NewObserver(func(ev observer.EventType) {
+		events = append(events, ev.(StateEvent))
+	}, false, StateEvent{}))
+
+	failure := errors.New("socket is closed")
+	Report(subject, Collector, "host", nil)
+	ReportExit(subject, Collector, "host", errComponent{Err: context.Canceled})
+	ReportExit(subject, Merger, "host", nil)
+	ReportExit(subject, Sender, "", errComponent{Err: failure})
+	Report(nil, Sender, "", nil)
+
+	require.Equal(t, []StateEvent{
+		{Component: Collector, Instance: "host"},
+		{Component: Collector, Instance: "host", Gone: true},
+		{Component: Merger, Instance: "host", Gone: true},
+		{Component: Sender, Err: errComponent{Err: failure}},
+	}, events)
+}
+
+func Test_WatchStall(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []StateEvent
+	)
+	subject := observer.NewSubject()
+	subject.ObserversAttach(observer.NewObserver(func(ev observer.EventType) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, ev.(StateEvent))
+	}, false, StateEvent{}))
+	eventsOf := func() []StateEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]StateEvent(nil), events...)
+	}
+
+	var polled atomic.Int64
+	polled.Store(time.Now().UnixNano())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		WatchStall(ctx, subject, Collector, "host", func() time.Time {
+			return time.Unix(0, polled.Load())
+		}, 20*time.Millisecond)
+	}()
+	require.Eventually(t, func() bool {
+		ev := eventsOf()
+		return len(ev) == 1 && errors.Is(ev[0].Err, ErrStalled)
+	}, time.Second, 5*time.Millisecond)
+
+	polled.Store(time.Now().Add(time.Hour).UnixNano())
+	require.Eventually(t, func() bool {
+		ev := eventsOf()
+		return len(ev) == 2 && ev[1].Err == nil
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+	require.Equal(t, Collector, eventsOf()[1].Component)
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"unsafe"
 
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
 	"github.com/wildberries-tech/pkt-tracer/internal/nl"
 
 	"github.com/H-BF/corlib/logger"
@@ -27,6 +28,7 @@ type Iface interface {
 type (
 	ifaceImpl struct {
 		agentSubject observer.Subject
+		netnsFd      int
 		cache        *IfaceCache
 		onceRun      sync.Once
 		onceClose    sync.Once
@@ -37,21 +39,46 @@ type (
 	// CountIfaceNlErrMemEvent -
 	CountIfaceNlErrMemEvent struct {
 		observer.EventType
+		// Lost - estimated number of netlink messages lost on the overrun
+		Lost uint32
 	}
+
+	// IfaceCacheMissEvent - interface of the trace has not been found in the cache
+	IfaceCacheMissEvent struct {
+		observer.EventType
+	}
+
+	// IfaceOpt - option of the iface tracer
+	IfaceOpt func(*ifaceImpl)
 )
 
 var _ Iface = (*ifaceImpl)(nil)
 
-func NewIface(as observer.Subject) Iface {
-	return &ifaceImpl{
+// IfaceWithNetNS - trace ifaces of the network namespace referred by file descriptor
+func IfaceWithNetNS(fd int) IfaceOpt {
+	return func(i *ifaceImpl) {
+		i.netnsFd = fd
+	}
+}
+
+func NewIface(as observer.Subject, opts ...IfaceOpt) Iface {
+	i := &ifaceImpl{
 		agentSubject: as,
+		netnsFd:      -1,
 		cache:        NewCache(),
 		stop:         make(chan struct{}),
 	}
+	for _, o := range opts {
+		o(i)
+	}
+	return i
 }
 
 func (i *ifaceImpl) GetIface(index int) (ifname string, err error) {
 	ifc, err := i.cache.GetItemById(index)
+	if err != nil && i.agentSubject != nil {
+		i.agentSubject.Notify(IfaceCacheMissEvent{})
+	}
 	return ifc.ifName, err
 }
 
@@ -68,13 +95,14 @@ func (i *ifaceImpl) Run(ctx context.Context) (err error) {
 	nlWatcher, err := nl.NewNetlinkWatcher(1, unix.NETLINK_ROUTE,
 		nl.SkWithBufLen(nl.SockBufLen16MB),
 		nl.SkWithNlMs(unix.RTMGRP_LINK, unix.RTMGRP_IPV4_IFADDR), //TODO Add support for IPv6
+		nl.NlWithNetNS(i.netnsFd),
 	)
 
 	if err != nil {
 		return ErrIface{Err: fmt.Errorf("failed to create iface netlink watcher to monitor new ifaces: %v", err)}
 	}
 
-	if err = i.cache.Reload(); err != nil {
+	if err = netns.Do(i.netnsFd, i.cache.Reload); err != nil {
 		return ErrIface{Err: fmt.Errorf("failed to refresh iface cache: %v", err)}
 	}
 
@@ -105,7 +133,12 @@ func (i *ifaceImpl) Run(ctx context.Context) (err error) {
 
 			if err != nil {
 				if errors.Is(err, nl.ErrNlMem) {
-					i.agentSubject.Notify(CountIfaceNlErrMemEvent{})
+					// link changes have been lost so the cache is not consistent anymore
+					i.agentSubject.Notify(CountIfaceNlErrMemEvent{Lost: nlData.Lost})
+					log.Warnf("netlink buffer overrun, about %d messages lost, reload ifaces", nlData.Lost)
+					if err = netns.Do(i.netnsFd, i.cache.Reload); err != nil {
+						return ErrIface{Err: fmt.Errorf("failed to reload iface cache: %v", err)}
+					}
 					continue
 				}
 				if errors.Is(err, nl.ErrNlDataNotReady) ||
@@ -0,0 +1,84 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache is a fixed size cache evicting least recently used entries. It is safe for concurrent use
+type Cache[K comparable, V any] struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[K]*list.Element
+}
+
+type entry[K comparable, V any] struct {
+	key K
+	val V
+}
+
+// NewCache creates cache holding at most 'size' entries
+func NewCache[K comparable, V any](size int) *Cache[K, V] {
+	if size <= 0 {
+		panic("lru: size must be > 0")
+	}
+	return &Cache[K, V]{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[K]*list.Element, size),
+	}
+}
+
+// Get returns value of the key and marks it as recently used
+func (c *Cache[K, V]) Get(k K) (v V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, found := c.items[k]; found {
+		c.ll.MoveToFront(el)
+		return el.Value.(*entry[K, V]).val, true
+	}
+	return v, false
+}
+
+// Put inserts or updates value of the key, the least recently used entry is evicted when cache is full
+func (c *Cache[K, V]) Put(k K, v V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, found := c.items[k]; found {
+		c.ll.MoveToFront(el)
+		el.Value.(*entry[K, V]).val = v
+		return
+	}
+	c.items[k] = c.ll.PushFront(&entry[K, V]{key: k, val: v})
+	if c.ll.Len() > c.size {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.items, el.Value.(*entry[K, V]).key)
+	}
+}
+
+// Remove removes value of the key
+func (c *Cache[K, V]) Remove(k K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, found := c.items[k]; found {
+		c.ll.Remove(el)
+		delete(c.items, k)
+	}
+}
+
+// Len -
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Clear -
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.items = make(map[K]*list.Element, c.size)
+}
@@ -0,0 +1,49 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type lruTestSuite struct {
+	suite.Suite
+}
+
+func Test_LRU(t *testing.T) {
+	suite.Run(t, new(lruTestSuite))
+}
+
+func (sui *lruTestSuite) Test_Eviction() {
+	c := NewCache[int, string](2)
+	c.Put(1, "a")
+	c.Put(2, "b")
+	_, ok := c.Get(1)
+	sui.Require().True(ok)
+	c.Put(3, "c")
+	sui.Require().Equal(2, c.Len())
+	_, ok = c.Get(2)
+	sui.Require().False(ok)
+	v, ok := c.Get(1)
+	sui.Require().True(ok)
+	sui.Require().Equal("a", v)
+}
+
+func (sui *lruTestSuite) Test_Update() {
+	c := NewCache[int, string](2)
+	c.Put(1, "a")
+	c.Put(1, "b")
+	sui.Require().Equal(1, c.Len())
+	v, _ := c.Get(1)
+	sui.Require().Equal("b", v)
+	c.Put(2, "c")
+	c.Remove(1)
+	c.Remove(3)
+	sui.Require().Equal(1, c.Len())
+	_, ok := c.Get(1)
+	sui.Require().False(ok)
+	c.Clear()
+	sui.Require().Equal(0, c.Len())
+	_, ok = c.Get(2)
+	sui.Require().False(ok)
+}
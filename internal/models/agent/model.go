@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"time"
+)
+
+type (
+	AgentInfo struct {
+		// agent version
+		Version string `json:"version,omitempty"`
+		// agent host name
+		Hostname string `json:"hostname,omitempty"`
+		// kernel release of the agent host
+		Kernel string `json:"kernel,omitempty"`
+	}
+
+	AgentModel struct {
+		AgentInfo
+		// agent identifier
+		Id string `json:"id"`
+		// remote address of the agent connection
+		RemoteAddr string `json:"remote_addr,omitempty"`
+		// time of the last connection
+		ConnectedAt time.Time `json:"connected_at"`
+		// time of the last heartbeat or trace batch
+		LastSeenAt time.Time `json:"last_seen_at"`
+		// time of the last received trace
+		LastTraceAt time.Time `json:"last_trace_at"`
+		// rate of the received traces (traces per second)
+		TraceRate float64 `json:"trace_rate"`
+		// total count of the received traces
+		TracesTotal uint64 `json:"traces_total"`
+		// agent is connected and alive
+		Online bool `json:"online"`
+	}
+
+	AgentScopeModel struct {
+		// agents identifiers
+		AgentsIds []string
+		// online agents only
+		OnlineOnly bool
+	}
+)
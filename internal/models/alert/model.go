@@ -0,0 +1,48 @@
+package alert
+
+import (
+	"time"
+)
+
+// sources of the alert rules
+const (
+	SourceConfig = "config"
+	SourceApi    = "api"
+)
+
+type (
+	AlertRuleModel struct {
+		// unique name of the rule
+		Name string `json:"name" yaml:"name"`
+		// visor query expression traces are matched by
+		Query string `json:"query" yaml:"query"`
+		// number of the matched traces the alert fires above
+		Threshold uint64 `json:"threshold" yaml:"threshold"`
+		// sliding window the matched traces are counted within
+		Window time.Duration `json:"window" yaml:"window"`
+		// URL of the webhook (empty means the default one)
+		Webhook string `json:"webhook,omitempty" yaml:"webhook"`
+		// min interval of repeated notifications of the firing alert (zero means the default one)
+		RepeatInterval time.Duration `json:"repeat_interval,omitempty" yaml:"repeat-interval"`
+	}
+
+	AlertModel struct {
+		// alert rule
+		Rule AlertRuleModel `json:"rule"`
+		// where the rule is defined: config/api
+		Source string `json:"source"`
+		// the alert is firing
+		Firing bool `json:"firing"`
+		// number of the traces matched within the window
+		Count uint64 `json:"count"`
+		// time the alert has fired at
+		FiringSince time.Time `json:"firing_since,omitempty"`
+		// time of the last notification
+		NotifiedAt time.Time `json:"notified_at,omitempty"`
+	}
+
+	AlertScopeModel struct {
+		// firing alerts only
+		FiringOnly bool
+	}
+)
@@ -55,4 +55,23 @@ type NetlinkTrace struct {
 	Oiftype    uint16
 	Flags      uint32
 	At         time.Time
+	// Source - subsystem the trace is collected from
+	Source string
+	// Prefix - prefix of the log rule (nflog source only)
+	Prefix string
+	// Group - log group the packet is sent to (nflog source only)
+	Group uint16
+	// Hook - netfilter hook the packet is logged at (nflog source only)
+	Hook string
+	// RawLh, RawNh, RawTh - raw bytes of the link layer, network and transport headers
+	RawLh []byte
+	RawNh []byte
+	RawTh []byte
 }
+
+const (
+	// SourceNftrace - traces of the nftables rules ('meta nftrace set 1')
+	SourceNftrace = "nftrace"
+	// SourceNflog - packets logged by the 'log group' or '-j NFLOG' rules
+	SourceNflog = "nflog"
+)
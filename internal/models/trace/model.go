@@ -44,6 +44,56 @@ type (
 		SSgNet string `json:"net-src,omitempty"`
 		// name of the network for dst ip
 		DSgNet string `json:"net-dst,omitempty"`
+		// network namespace
+		NetNS string `json:"netns,omitempty"`
+		// id of the container
+		ContainerId string `json:"container-id,omitempty"`
+		// name of the container
+		ContainerName string `json:"container,omitempty"`
+		// name of the pod
+		Pod string `json:"pod,omitempty"`
+		// namespace of the pod
+		PodNamespace string `json:"pod-ns,omitempty"`
+		// labels of the container in the form key=value
+		ContainerLabels []string `json:"labels,omitempty"`
+		// inode of the local socket
+		SockInode uint64 `json:"sock-inode,omitempty"`
+		// id of the process owning the socket
+		Pid uint32 `json:"pid,omitempty"`
+		// command name of the process
+		Comm string `json:"comm,omitempty"`
+		// cgroup path of the process
+		Cgroup string `json:"cgroup,omitempty"`
+		// conntrack state of the connection: new/established/related
+		CtState string `json:"ct-state,omitempty"`
+		// direction of the packet in the connection: original/reply
+		CtDirection string `json:"ct-dir,omitempty"`
+		// address translation applied to the connection: snat/dnat
+		CtNat string `json:"ct-nat,omitempty"`
+		// conntrack mark of the connection
+		CtMark uint32 `json:"ct-mark,omitempty"`
+		// conntrack zone of the connection
+		CtZone uint32 `json:"ct-zone,omitempty"`
+		// original tuple of the connection
+		CtOrig string `json:"ct-orig,omitempty"`
+		// reply tuple of the connection
+		CtReply string `json:"ct-reply,omitempty"`
+		// subsystem the trace is collected from: nftrace/nflog
+		Source string `json:"source,omitempty"`
+		// prefix of the log rule (nflog source)
+		LogPrefix string `json:"log-prefix,omitempty"`
+		// log group of the packet (nflog source)
+		LogGroup uint32 `json:"log-group,omitempty"`
+		// netfilter hook the packet is logged at (nflog source)
+		Hook string `json:"hook,omitempty"`
+		// sgroups rule the verdict is resolved to
+		SgRule string `json:"sg-rule,omitempty"`
+		// action of the sgroups rule: accept/drop
+		SgRuleAction string `json:"sg-rule-action,omitempty"`
+		// raw link layer header of the packet
+		RawLlHeader []byte `json:"raw-ll-header,omitempty"`
+		// raw network and transport headers of the packet
+		RawHeaders []byte `json:"raw-headers,omitempty"`
 		// length packet
 		Length uint32 `json:"len"`
 		// ip protocol (tcp/udp/icmp/...)
@@ -54,6 +104,8 @@ type (
 		Rule string `json:"rule"`
 		// user agent id
 		UserAgent string `json:"agent,omitempty"`
+		// time the trace has been merged by the agent, it is not sent to the server
+		MergedAt time.Time `json:"-"`
 	}
 
 	FetchTraceModel struct {
@@ -103,6 +155,56 @@ type (
 		SSgNet string `json:"net-src,omitempty"`
 		// name of the network for dst ip
 		DSgNet string `json:"net-dst,omitempty"`
+		// network namespace
+		NetNS string `json:"netns,omitempty"`
+		// id of the container
+		ContainerId string `json:"container-id,omitempty"`
+		// name of the container
+		ContainerName string `json:"container,omitempty"`
+		// name of the pod
+		Pod string `json:"pod,omitempty"`
+		// namespace of the pod
+		PodNamespace string `json:"pod-ns,omitempty"`
+		// labels of the container in the form key=value
+		ContainerLabels []string `json:"labels,omitempty"`
+		// inode of the local socket
+		SockInode uint64 `json:"sock-inode,omitempty"`
+		// id of the process owning the socket
+		Pid uint32 `json:"pid,omitempty"`
+		// command name of the process
+		Comm string `json:"comm,omitempty"`
+		// cgroup path of the process
+		Cgroup string `json:"cgroup,omitempty"`
+		// conntrack state of the connection: new/established/related
+		CtState string `json:"ct-state,omitempty"`
+		// direction of the packet in the connection: original/reply
+		CtDirection string `json:"ct-dir,omitempty"`
+		// address translation applied to the connection: snat/dnat
+		CtNat string `json:"ct-nat,omitempty"`
+		// conntrack mark of the connection
+		CtMark uint32 `json:"ct-mark,omitempty"`
+		// conntrack zone of the connection
+		CtZone uint32 `json:"ct-zone,omitempty"`
+		// original tuple of the connection
+		CtOrig string `json:"ct-orig,omitempty"`
+		// reply tuple of the connection
+		CtReply string `json:"ct-reply,omitempty"`
+		// subsystem the trace is collected from: nftrace/nflog
+		Source string `json:"source,omitempty"`
+		// prefix of the log rule (nflog source)
+		LogPrefix string `json:"log-prefix,omitempty"`
+		// log group of the packet (nflog source)
+		LogGroup uint32 `json:"log-group,omitempty"`
+		// netfilter hook the packet is logged at (nflog source)
+		Hook string `json:"hook,omitempty"`
+		// sgroups rule the verdict is resolved to
+		SgRule string `json:"sg-rule,omitempty"`
+		// action of the sgroups rule: accept/drop
+		SgRuleAction string `json:"sg-rule-action,omitempty"`
+		// raw link layer header of the packet
+		RawLlHeader []byte `json:"raw-ll-header,omitempty"`
+		// raw network and transport headers of the packet
+		RawHeaders []byte `json:"raw-headers,omitempty"`
 		// agent identifier
 		UserAgent string `json:"agent,omitempty"`
 		// time stamps
@@ -151,6 +253,46 @@ type (
 		SSgNet []string
 		// names of the network for dst ip
 		DSgNet []string
+		// network namespaces
+		NetNS []string
+		// ids of the containers
+		ContainerId []string
+		// names of the containers
+		ContainerName []string
+		// names of the pods
+		Pod []string
+		// namespaces of the pods
+		PodNamespace []string
+		// labels of the containers (any of them is matched)
+		ContainerLabels []string
+		// ids of the processes
+		Pid []uint32
+		// command names of the processes
+		Comm []string
+		// cgroup paths of the processes
+		Cgroup []string
+		// conntrack states of the connections
+		CtState []string
+		// directions of the packets in the connections
+		CtDirection []string
+		// address translations applied to the connections
+		CtNat []string
+		// conntrack marks of the connections
+		CtMark []uint32
+		// conntrack zones of the connections
+		CtZone []uint32
+		// subsystems the traces are collected from
+		Source []string
+		// prefixes of the log rules
+		LogPrefix []string
+		// log groups of the packets
+		LogGroup []uint32
+		// netfilter hooks the packets are logged at
+		Hook []string
+		// sgroups rules the verdicts are resolved to
+		SgRule []string
+		// actions of the sgroups rules
+		SgRuleAction []string
 		// lengths of packets
 		Length []uint32
 		// ip protocols (tcp/udp/icmp/...)
@@ -167,6 +309,8 @@ type (
 		FollowMode bool
 		// complex query filter parameter
 		Query string
+		// visor query the Query is converted from
+		QueryDsl string
 	}
 
 	NftRule struct {
@@ -185,12 +329,16 @@ type (
 		TableStr string
 		// nftables rules items
 		Rules []*NftRule
+		// network namespace
+		NetNS string
 	}
 	FetchNftTableModel struct {
 		// nftables table id
 		TableId uint64
 		// nftables table represented as string
 		TableStr string
+		// network namespace the table is in
+		NetNS string
 		// time stamps
 		Timestamp time.Time
 	}
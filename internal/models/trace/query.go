@@ -0,0 +1,54 @@
+package trace
+
+import (
+	"github.com/wildberries-tech/pkt-tracer/pkg/tracequery"
+)
+
+// queryGetters - values of the trace by the query parameters
+var queryGetters = tracequery.Getters[*TraceModel]{
+	"trid":           func(t *TraceModel) any { return int64(t.TrId) },
+	"table":          func(t *TraceModel) any { return t.Table },
+	"chain":          func(t *TraceModel) any { return t.Chain },
+	"jt":             func(t *TraceModel) any { return t.JumpTarget },
+	"handle":         func(t *TraceModel) any { return int64(t.RuleHandle) }, //nolint:gosec
+	"family":         func(t *TraceModel) any { return t.Family },
+	"iif":            func(t *TraceModel) any { return t.Iifname },
+	"oif":            func(t *TraceModel) any { return t.Oifname },
+	"hw-src":         func(t *TraceModel) any { return t.SMacAddr },
+	"hw-dst":         func(t *TraceModel) any { return t.DMacAddr },
+	"ip-src":         func(t *TraceModel) any { return t.SAddr },
+	"ip-dst":         func(t *TraceModel) any { return t.DAddr },
+	"sport":          func(t *TraceModel) any { return int64(t.SPort) },
+	"dport":          func(t *TraceModel) any { return int64(t.DPort) },
+	"sg-src":         func(t *TraceModel) any { return t.SSgName },
+	"sg-dst":         func(t *TraceModel) any { return t.DSgName },
+	"net-src":        func(t *TraceModel) any { return t.SSgNet },
+	"net-dst":        func(t *TraceModel) any { return t.DSgNet },
+	"netns":          func(t *TraceModel) any { return t.NetNS },
+	"container-id":   func(t *TraceModel) any { return t.ContainerId },
+	"container":      func(t *TraceModel) any { return t.ContainerName },
+	"pod":            func(t *TraceModel) any { return t.Pod },
+	"pod-ns":         func(t *TraceModel) any { return t.PodNamespace },
+	"pid":            func(t *TraceModel) any { return int64(t.Pid) },
+	"comm":           func(t *TraceModel) any { return t.Comm },
+	"cgroup":         func(t *TraceModel) any { return t.Cgroup },
+	"ct-state":       func(t *TraceModel) any { return t.CtState },
+	"ct-dir":         func(t *TraceModel) any { return t.CtDirection },
+	"ct-nat":         func(t *TraceModel) any { return t.CtNat },
+	"ct-mark":        func(t *TraceModel) any { return int64(t.CtMark) },
+	"ct-zone":        func(t *TraceModel) any { return int64(t.CtZone) },
+	"source":         func(t *TraceModel) any { return t.Source },
+	"log-prefix":     func(t *TraceModel) any { return t.LogPrefix },
+	"log-group":      func(t *TraceModel) any { return int64(t.LogGroup) },
+	"hook":           func(t *TraceModel) any { return t.Hook },
+	"sg-rule":        func(t *TraceModel) any { return t.SgRule },
+	"sg-rule-action": func(t *TraceModel) any { return t.SgRuleAction },
+	"len":            func(t *TraceModel) any { return int64(t.Length) },
+	"proto":          func(t *TraceModel) any { return t.IpProto },
+	"verdict":        func(t *TraceModel) any { return t.Verdict },
+}
+
+// NewQueryMatcher makes predicate of the trace evaluating the query, see tracequery
+func NewQueryMatcher(q string) (tracequery.Matcher[*TraceModel], error) {
+	return tracequery.NewMatcher(q, queryGetters)
+}
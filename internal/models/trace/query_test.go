@@ -0,0 +1,50 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/wildberries-tech/pkt-tracer/pkg/tracequery"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_QueryMatcher(t *testing.T) {
+	for _, p := range tracequery.Params() {
+		require.Contains(t, queryGetters, p, "query parameter has no value of the trace")
+	}
+	tr := &TraceModel{
+		TrId:    123,
+		SSgName: "sg-a",
+		DSgName: "sg-b",
+		SPort:   40000,
+		DPort:   443,
+		IpProto: "tcp",
+		Verdict: "rule::drop",
+	}
+	testCases := []struct {
+		query string
+		exp   bool
+	}{
+		{"", true},
+		{`sg-src == "sg-a" and sg-dst == "sg-b"`, true},
+		{`sg-src == 'sg-a' && sg-dst == 'sg-c'`, false},
+		{`sg-src == "sg-c" or dport == 443`, true},
+		{`verdict in ("rule::drop", "policy::drop")`, true},
+		{`verdict not in ("rule::drop", "policy::drop")`, false},
+		{`dport in (80, 443) and proto == "tcp"`, true},
+		{`dport in (80, 8080)`, false},
+		{`dport >= 443 and dport < 444`, true},
+		{`sport > 40000`, false},
+		{`!(trid == 123)`, false},
+		{`trid != 123 || sg-src != "sg-a"`, false},
+		{`(dport == 80 or dport == 443) and sg-dst == "sg-b"`, true},
+		{`dport == "443"`, false},
+	}
+	for _, tc := range testCases {
+		m, err := NewQueryMatcher(tc.query)
+		require.NoError(t, err, tc.query)
+		require.Equal(t, tc.exp, m(tr), tc.query)
+	}
+	_, err := NewQueryMatcher(`unknown == 1`)
+	require.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package netns
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// DefNetnsDir - directory where named network namespaces are mounted by 'ip netns'
+	DefNetnsDir = "/var/run/netns"
+	// DefProcDir - procfs mount point
+	DefProcDir = "/proc"
+)
+
+type (
+	// NetNS - network namespace on the host
+	NetNS struct {
+		// Id - identifier of the namespace: the name when it is mounted into netns directory,
+		// 'ino:<inode>' when it is found through processes and empty for the own agent namespace
+		Id string
+		// Ino - inode of the namespace file
+		Ino uint64
+		// Path - path to the namespace file
+		Path string
+	}
+
+	// Scanner - discovers network namespaces on the host
+	Scanner struct {
+		// NetnsDir - directory with named namespaces
+		NetnsDir string
+		// ProcDir - procfs mount point
+		ProcDir string
+	}
+)
+
+// IsHost - it is the namespace the agent runs in
+func (n NetNS) IsHost() bool {
+	return n.Id == ""
+}
+
+// String -
+func (n NetNS) String() string {
+	if n.IsHost() {
+		return "host"
+	}
+	return n.Id
+}
+
+// NewScanner creates scanner with default locations of the namespaces
+func NewScanner() Scanner {
+	return Scanner{
+		NetnsDir: DefNetnsDir,
+		ProcDir:  DefProcDir,
+	}
+}
+
+// Host returns own agent namespace
+func (s Scanner) Host() (NetNS, error) {
+	p := filepath.Join(s.ProcDir, "self", "ns", "net")
+	ino, err := Inode(p)
+	if err != nil {
+		return NetNS{}, errors.WithMessage(err, "failed to obtain own network namespace")
+	}
+	return NetNS{Ino: ino, Path: p}, nil
+}
+
+// Scan returns own agent namespace followed by named namespaces and namespaces of processes
+// sorted by identifier. Namespaces are unique by inode
+func (s Scanner) Scan() ([]NetNS, error) {
+	host, err := s.Host()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[uint64]struct{}{host.Ino: {}}
+	var named, anon []NetNS
+
+	entries, err := os.ReadDir(s.NetnsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.WithMessagef(err, "failed to read '%s'", s.NetnsDir)
+	}
+	for _, e := range entries {
+		p := filepath.Join(s.NetnsDir, e.Name())
+		ino, e1 := Inode(p)
+		if e1 != nil {
+			continue //namespace has been just removed or it is not a namespace
+		}
+		if _, ok := seen[ino]; !ok {
+			seen[ino] = struct{}{}
+			named = append(named, NetNS{Id: e.Name(), Ino: ino, Path: p})
+		}
+	}
+
+	procs, err := os.ReadDir(s.ProcDir)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read '%s'", s.ProcDir)
+	}
+	for _, e := range procs {
+		if _, e1 := strconv.ParseUint(e.Name(), 10, 32); e1 != nil {
+			continue
+		}
+		p := filepath.Join(s.ProcDir, e.Name(), "ns", "net")
+		ino, e1 := Inode(p)
+		if e1 != nil {
+			continue //process has gone or it is inaccessible
+		}
+		if _, ok := seen[ino]; !ok {
+			seen[ino] = struct{}{}
+			anon = append(anon, NetNS{Id: fmt.Sprintf("ino:%d", ino), Ino: ino, Path: p})
+		}
+	}
+
+	sort.Slice(named, func(i, j int) bool { return named[i].Id < named[j].Id })
+	sort.Slice(anon, func(i, j int) bool { return anon[i].Ino < anon[j].Ino })
+	ret := make([]NetNS, 0, 1+len(named)+len(anon))
+	ret = append(ret, host)
+	ret = append(ret, named...)
+	return append(ret, anon...), nil
+}
+
+// Do calls 'fn' on the OS thread switched into the network namespace referred by 'fd'.
+// Negative 'fd' means the current namespace. Sockets created by 'fn' stay bound to the namespace
+func Do(fd int, fn func() error) error {
+	if fd < 0 {
+		return fn()
+	}
+	errc := make(chan error, 1)
+	go func() {
+		// thread is never unlocked so it is terminated together with goroutine
+		// and does not return into the pool being switched into the foreign namespace
+		runtime.LockOSThread()
+		if err := unix.Setns(fd, unix.CLONE_NEWNET); err != nil {
+			errc <- errors.WithMessage(err, "failed to switch network namespace")
+			return
+		}
+		errc <- fn()
+	}()
+	return <-errc
+}
+
+// Inode returns inode of the namespace file
+func Inode(p string) (uint64, error) {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return 0, err
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, errors.Errorf("unable to stat '%s'", p)
+	}
+	return st.Ino, nil
+}
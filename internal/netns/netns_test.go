@@ -0,0 +1,88 @@
+package netns
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type netnsTestSuite struct {
+	suite.Suite
+	scanner Scanner
+}
+
+func Test_NetNS(t *testing.T) {
+	suite.Run(t, new(netnsTestSuite))
+}
+
+func (sui *netnsTestSuite) SetupTest() {
+	root := sui.T().TempDir()
+	sui.scanner = Scanner{
+		NetnsDir: filepath.Join(root, "netns"),
+		ProcDir:  filepath.Join(root, "proc"),
+	}
+}
+
+func (sui *netnsTestSuite) touch(p string) string {
+	sui.Require().NoError(os.MkdirAll(filepath.Dir(p), 0o755))
+	sui.Require().NoError(os.WriteFile(p, nil, 0o600))
+	return p
+}
+
+func (sui *netnsTestSuite) link(target, p string) {
+	sui.Require().NoError(os.MkdirAll(filepath.Dir(p), 0o755))
+	sui.Require().NoError(os.Symlink(target, p))
+}
+
+func (sui *netnsTestSuite) procNs(pid string) string {
+	return filepath.Join(sui.scanner.ProcDir, pid, "ns", "net")
+}
+
+func (sui *netnsTestSuite) Test_Scan() {
+	self := sui.touch(sui.procNs("self"))
+	nsB := sui.touch(filepath.Join(sui.scanner.NetnsDir, "b"))
+	sui.touch(filepath.Join(sui.scanner.NetnsDir, "a"))
+	anon := sui.touch(sui.procNs("11"))
+	sui.link(nsB, sui.procNs("10"))
+	sui.link(self, sui.procNs("12"))
+	sui.touch(sui.procNs("not-a-pid"))
+	sui.Require().NoError(os.MkdirAll(filepath.Join(sui.scanner.ProcDir, "13"), 0o755))
+
+	ret, err := sui.scanner.Scan()
+	sui.Require().NoError(err)
+	ids := make([]string, 0, len(ret))
+	for _, ns := range ret {
+		ids = append(ids, ns.Id)
+	}
+	anonIno, err := Inode(anon)
+	sui.Require().NoError(err)
+	sui.Require().Equal([]string{"", "a", "b", fmt.Sprintf("ino:%d", anonIno)}, ids)
+	sui.Require().True(ret[0].IsHost())
+	sui.Require().Equal(self, ret[0].Path)
+	sui.Require().Equal(anon, ret[3].Path)
+}
+
+func (sui *netnsTestSuite) Test_ScanWithoutNamedNamespaces() {
+	sui.touch(sui.procNs("self"))
+	ret, err := sui.scanner.Scan()
+	sui.Require().NoError(err)
+	sui.Require().Len(ret, 1)
+	sui.Require().Equal("host", ret[0].String())
+}
+
+func (sui *netnsTestSuite) Test_ScanFailsWithoutProcfs() {
+	_, err := sui.scanner.Scan()
+	sui.Require().Error(err)
+}
+
+func (sui *netnsTestSuite) Test_DoInCurrentNamespace() {
+	called := false
+	sui.Require().NoError(Do(-1, func() error {
+		called = true
+		return nil
+	}))
+	sui.Require().True(called)
+}
@@ -0,0 +1,69 @@
+package nflog
+
+// messages of the nfnetlink_log subsystem (enum nfulnl_msg_types)
+const (
+	NFULNL_MSG_PACKET uint16 = iota
+	NFULNL_MSG_CONFIG
+)
+
+// attributes of the logged packet (enum nfulnl_attr_type)
+const (
+	NFULA_UNSPEC uint16 = iota
+	NFULA_PACKET_HDR
+	NFULA_MARK
+	NFULA_TIMESTAMP
+	NFULA_IFINDEX_INDEV
+	NFULA_IFINDEX_OUTDEV
+	NFULA_IFINDEX_PHYSINDEV
+	NFULA_IFINDEX_PHYSOUTDEV
+	NFULA_HWADDR
+	NFULA_PAYLOAD
+	NFULA_PREFIX
+	NFULA_UID
+	NFULA_SEQ
+	NFULA_SEQ_GLOBAL
+	NFULA_GID
+	NFULA_HWTYPE
+	NFULA_HWHEADER
+	NFULA_HWLEN
+	NFULA_CT
+	NFULA_CT_INFO
+	NFULA_VLAN
+	NFULA_L2HDR
+)
+
+// attributes of the config message (enum nfulnl_attr_config)
+const (
+	NFULA_CFG_UNSPEC uint16 = iota
+	NFULA_CFG_CMD
+	NFULA_CFG_MODE
+	NFULA_CFG_NLBUFSIZ
+	NFULA_CFG_TIMEOUT
+	NFULA_CFG_QTHRESH
+	NFULA_CFG_FLAGS
+)
+
+// commands of the config message (enum nfulnl_msg_config_cmds)
+const (
+	NFULNL_CFG_CMD_NONE uint8 = iota
+	NFULNL_CFG_CMD_BIND
+	NFULNL_CFG_CMD_UNBIND
+	NFULNL_CFG_CMD_PF_BIND
+	NFULNL_CFG_CMD_PF_UNBIND
+)
+
+// copy modes of the packet
+const (
+	NFULNL_COPY_NONE uint8 = iota
+	NFULNL_COPY_META
+	NFULNL_COPY_PACKET
+)
+
+// hooks of the inet families (enum nf_inet_hooks)
+const (
+	NF_INET_PRE_ROUTING uint8 = iota
+	NF_INET_LOCAL_IN
+	NF_INET_FORWARD
+	NF_INET_LOCAL_OUT
+	NF_INET_POST_ROUTING
+)
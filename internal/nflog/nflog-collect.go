@@ -0,0 +1,291 @@
+package nflog
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/bqueue"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/nltrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nftrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nl"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/H-BF/corlib/pkg/patterns/observer"
+	"github.com/mdlayher/netlink"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// DefCopyRange - default number of bytes of the packet copied to the log message,
+// it is enough for the link, network and transport headers
+const DefCopyRange = 128
+
+type (
+	collectorImpl struct {
+		agentSubject observer.Subject
+		netnsFd      int
+		groups       []uint16
+		copyRange    uint32
+		id           uint32
+		queSettings  bqueue.Settings
+		que          *bqueue.FIFO[[]model.NetlinkTrace]
+		onceRun      sync.Once
+		onceStop     sync.Once
+		onceClose    sync.Once
+		stop         chan struct{}
+		stopped      chan struct{}
+		nlWatcher    atomic.Value // nl.NetlinkWatcher of the running collector
+	}
+
+	// CountNflogDecodeErrEvent - message of the logged packet has been skipped as it can not be decoded
+	CountNflogDecodeErrEvent struct {
+		observer.EventType
+	}
+
+	// CountNflogNlErrMemEvent - logged packets have been lost by the socket
+	CountNflogNlErrMemEvent struct {
+		observer.EventType
+		// Lost - estimated number of netlink messages lost on the overrun
+		Lost uint32
+	}
+
+	// CollectorOpt - option of the nflog collector
+	CollectorOpt func(*collectorImpl)
+)
+
+var _ nftrace.TraceCollector = (*collectorImpl)(nil)
+
+// CollectWithNetNS - collect logged packets in the network namespace referred by file descriptor
+func CollectWithNetNS(fd int) CollectorOpt {
+	return func(c *collectorImpl) {
+		c.netnsFd = fd
+	}
+}
+
+// CollectWithCopyRange - number of bytes of the packet copied to the log message, default is DefCopyRange
+func CollectWithCopyRange(n uint32) CollectorOpt {
+	return func(c *collectorImpl) {
+		c.copyRange = n
+	}
+}
+
+// CollectWithQueue - bound the queue of collected traces
+func CollectWithQueue(s bqueue.Settings) CollectorOpt {
+	return func(c *collectorImpl) {
+		c.queSettings = s
+	}
+}
+
+// NewCollector creates collector of the packets logged into the nflog groups,
+// packets are converted into traces to be merged like nftrace ones
+func NewCollector(as observer.Subject, groups []uint16, opts ...CollectorOpt) (nftrace.TraceCollector, error) {
+	if len(groups) == 0 {
+		return nil, ErrNflog{Err: errors.New("no log groups to collect")}
+	}
+	c := &collectorImpl{
+		agentSubject: as,
+		netnsFd:      -1,
+		groups:       groups,
+		copyRange:    DefCopyRange,
+		stop:         make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	c.que = bqueue.NewFIFO(
+		bqueue.WithSettings[[]model.NetlinkTrace](c.queSettings),
+		bqueue.WithSize(func(b []model.NetlinkTrace) int { return len(b) }),
+	)
+	return c, nil
+}
+
+// ParseGroups parses comma separated list of the log groups
+func ParseGroups(s string) (ret []uint16, err error) {
+	for _, g := range strings.Split(s, ",") {
+		if g = strings.TrimSpace(g); g == "" {
+			continue
+		}
+		n, e := strconv.ParseUint(g, 10, 16)
+		if e != nil {
+			return nil, ErrNflog{Err: errors.Errorf("incorrect log group '%s'", g)}
+		}
+		ret = append(ret, uint16(n))
+	}
+	return ret, nil
+}
+
+func (c *collectorImpl) Run(ctx context.Context) (err error) {
+	var doRun bool
+	c.onceRun.Do(func() {
+		doRun = true
+		c.stopped = make(chan struct{})
+	})
+	if !doRun {
+		return ErrNflog{Err: errors.New("it has been run or closed yet")}
+	}
+
+	nlWatcher, err := nl.NewNetlinkWatcher(1, unix.NETLINK_NETFILTER,
+		nl.SkWithBufLen(nl.SockBufLen16MB),
+		nl.NlWithNetNS(c.netnsFd),
+	)
+	if err != nil {
+		return ErrNflog{Err: fmt.Errorf("failed to create nflog-watcher: %v", err)}
+	}
+
+	c.nlWatcher.Store(nlWatcher)
+	log := logger.FromContext(ctx).Named("nflog")
+	log.Info("start")
+	defer func() {
+		log.Info("stop")
+		nlWatcher.Close()
+		close(c.stopped)
+	}()
+	for _, g := range c.groups {
+		if err = nlWatcher.Send(bindRequest(g, c.copyRange)); err != nil {
+			return ErrNflog{Err: errors.WithMessagef(err, "failed to bind log group %d", g)}
+		}
+	}
+	reader := nlWatcher.Reader(0)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("will exit cause ctx canceled")
+			return ctx.Err()
+		case <-c.stop:
+			log.Info("will exit cause it has closed")
+			return nil
+		case nlData, ok := <-reader.Read():
+			if !ok {
+				log.Info("will exit cause nflog watcher has already closed")
+				return ErrNflog{Err: errors.New("nflog watcher has already closed")}
+			}
+			if err = nlData.Err; err != nil {
+				if errors.Is(err, nl.ErrNlMem) {
+					c.agentSubject.Notify(CountNflogNlErrMemEvent{Lost: nlData.Lost})
+					continue
+				}
+				if errors.Is(err, nl.ErrNlDataNotReady) ||
+					errors.Is(err, nl.ErrNlReadInterrupted) {
+					continue
+				}
+				return ErrNflog{Err: errors.WithMessage(err, "failed to rcv nl message")}
+			}
+			traces, errs := c.decode(nlData.Messages)
+			for _, e := range errs {
+				log.Warnf("message is skipped: %v", e)
+				c.agentSubject.Notify(CountNflogDecodeErrEvent{})
+			}
+			if len(traces) > 0 {
+				c.que.Put(traces)
+			}
+		}
+	}
+}
+
+// LastPoll impl nftrace.TraceCollector
+func (c *collectorImpl) LastPoll() time.Time {
+	if w, ok := c.nlWatcher.Load().(nl.NetlinkWatcher); ok {
+		return nl.LastPollOf(w)
+	}
+	return time.Time{}
+}
+
+func (c *collectorImpl) Reader() <-chan []model.NetlinkTrace {
+	return c.que.Reader()
+}
+
+// Drain stops collecting, traces collected yet are left in the queue until they are read
+// and then the reader is closed
+func (c *collectorImpl) Drain() error {
+	c.stopRun()
+	c.que.Seal()
+	return nil
+}
+
+// Close collector, traces left in the queue are abandoned
+func (c *collectorImpl) Close() error {
+	c.onceClose.Do(func() {
+		c.stopRun()
+		nftrace.NotifyAbandoned(c.agentSubject, nftrace.AbandonCollector, nftrace.CountMessages(c.que.Discard()))
+	})
+	return nil
+}
+
+func (c *collectorImpl) stopRun() {
+	c.onceStop.Do(func() {
+		close(c.stop)
+		c.onceRun.Do(func() {})
+		if c.stopped != nil {
+			<-c.stopped
+		}
+	})
+}
+
+// decode decodes logged packets of the messages, messages failed to be decoded are skipped with their errors
+func (c *collectorImpl) decode(messages []syscall.NetlinkMessage) (traces []model.NetlinkTrace, errs []error) {
+	t := time.Now()
+	for _, msg := range messages {
+		switch {
+		case msg.Header.Type == unix.NLMSG_ERROR:
+			// acknowledgement of the bind request, group may be bound by another listener
+			if len(msg.Data) >= 4 {
+				if errno := int32(binary.NativeEndian.Uint32(msg.Data)); errno != 0 {
+					errs = append(errs, errors.WithMessage(syscall.Errno(-errno), "failed to bind log group"))
+				}
+			}
+			continue
+		case msg.Header.Type != unix.NFNL_SUBSYS_ULOG<<8|NFULNL_MSG_PACKET:
+			continue
+		}
+		p := new(NflogPacket)
+		if err := p.InitFromMsg(netlink.Message{
+			Data: msg.Data,
+			Header: netlink.Header{
+				Length:   msg.Header.Len,
+				Type:     netlink.HeaderType(msg.Header.Type),
+				Flags:    netlink.HeaderFlags(msg.Header.Flags),
+				Sequence: msg.Header.Seq,
+				PID:      msg.Header.Pid,
+			},
+		}); err != nil {
+			errs = append(errs, errors.WithMessage(err, "failed to decode logged packet"))
+			continue
+		}
+		c.id++
+		m := p.ToModel()
+		m.Id = c.id
+		m.At = t
+		traces = append(traces, m)
+	}
+	return traces, errs
+}
+
+// bindRequest builds request to bind the socket to the log group and to copy packets into messages
+func bindRequest(group uint16, copyRange uint32) []byte {
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = binary.BigEndian
+	ae.Uint8(NFULA_CFG_CMD, NFULNL_CFG_CMD_BIND)
+	// struct nfulnl_msg_config_mode
+	mode := make([]byte, 6)
+	binary.BigEndian.PutUint32(mode, copyRange)
+	mode[4] = NFULNL_COPY_PACKET
+	ae.Bytes(NFULA_CFG_MODE, mode)
+	attrs, _ := ae.Encode()
+
+	size := unix.SizeofNlMsghdr + sizeofNfgenmsg + len(attrs)
+	b := make([]byte, unix.SizeofNlMsghdr+sizeofNfgenmsg, size)
+	binary.NativeEndian.PutUint32(b[0:], uint32(size))
+	binary.NativeEndian.PutUint16(b[4:], unix.NFNL_SUBSYS_ULOG<<8|NFULNL_MSG_CONFIG)
+	binary.NativeEndian.PutUint16(b[6:], unix.NLM_F_REQUEST|unix.NLM_F_ACK)
+	b[unix.SizeofNlMsghdr] = unix.AF_UNSPEC
+	b[unix.SizeofNlMsghdr+1] = unix.NFNETLINK_V0
+	binary.BigEndian.PutUint16(b[unix.SizeofNlMsghdr+2:], group)
+	return append(b, attrs...)
+}
@@ -0,0 +1,193 @@
+package nflog
+
+import (
+	"encoding/binary"
+	"strconv"
+
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/nltrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nftrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nl/nlheaders"
+
+	"github.com/mdlayher/netlink"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// sizeofNfgenmsg - length of the header of the netfilter message (struct nfgenmsg)
+const sizeofNfgenmsg = 4
+
+// NflogPacket - packet logged by the nfnetlink_log subsystem
+type NflogPacket struct {
+	Family     byte
+	Group      uint16
+	HwProtocol uint16
+	Hook       uint8
+	Prefix     string
+	Lh         nlheaders.LlHeader
+	Nh         nlheaders.NlHeader
+	Th         nlheaders.TlHeader
+	RawLh      []byte
+	RawNh      []byte
+	RawTh      []byte
+	Iif        uint32
+	Oif        uint32
+	Mark       uint32
+	Flags      uint32
+}
+
+// ToModel converts packet into the trace, flags of the trace are the same as nftrace ones
+func (p *NflogPacket) ToModel() model.NetlinkTrace {
+	return model.NetlinkTrace{
+		Lh:      p.Lh,
+		Nh:      p.Nh,
+		Th:      p.Th,
+		RawLh:   p.RawLh,
+		RawNh:   p.RawNh,
+		RawTh:   p.RawTh,
+		Family:  model.FamilyTable(p.Family),
+		Iif:     p.Iif,
+		Oif:     p.Oif,
+		Mark:    p.Mark,
+		Nfproto: uint32(p.Family),
+		Flags:   p.Flags,
+		Source:  model.SourceNflog,
+		Prefix:  p.Prefix,
+		Group:   p.Group,
+		Hook:    hookName(p.Family, p.Hook),
+	}
+}
+
+// InitFromMsg decodes packet from the NFULNL_MSG_PACKET message
+func (p *NflogPacket) InitFromMsg(msg netlink.Message) error {
+	if len(msg.Data) < sizeofNfgenmsg {
+		return errors.New("truncated nflog message")
+	}
+	p.Family = msg.Data[0]
+	p.Group = binary.BigEndian.Uint16(msg.Data[2:4])
+
+	ad, err := netlink.NewAttributeDecoder(msg.Data[sizeofNfgenmsg:])
+	if err != nil {
+		return err
+	}
+	ad.ByteOrder = binary.BigEndian
+
+	var hwType uint16
+	var hwHeader, payload []byte
+	for ad.Next() {
+		switch ad.Type() {
+		case NFULA_PACKET_HDR:
+			// struct nfulnl_msg_packet_hdr
+			if b := ad.Bytes(); len(b) >= 3 {
+				p.HwProtocol = binary.BigEndian.Uint16(b[:2])
+				p.Hook = b[2]
+			}
+		case NFULA_PREFIX:
+			p.Prefix = ad.String()
+		case NFULA_MARK:
+			p.Mark = ad.Uint32()
+			p.Flags |= (1 << nftrace.NFTNL_TRACE_MARK)
+		case NFULA_IFINDEX_INDEV:
+			p.Iif = ad.Uint32()
+			p.Flags |= (1 << nftrace.NFTNL_TRACE_IIF)
+		case NFULA_IFINDEX_OUTDEV:
+			p.Oif = ad.Uint32()
+			p.Flags |= (1 << nftrace.NFTNL_TRACE_OIF)
+		case NFULA_HWTYPE:
+			hwType = ad.Uint16()
+		case NFULA_HWHEADER:
+			hwHeader = ad.Bytes()
+		case NFULA_PAYLOAD:
+			payload = ad.Bytes()
+		}
+	}
+	if err = ad.Err(); err != nil {
+		return err
+	}
+
+	if hwType == unix.ARPHRD_ETHER && len(hwHeader) == nlheaders.LlHeaderLen {
+		if err = p.Lh.Decode(hwHeader); err != nil {
+			return err
+		}
+		p.RawLh = hwHeader
+		p.Flags |= (1 << nftrace.NFTNL_TRACE_LL_HEADER)
+	}
+	return p.decodePayload(payload)
+}
+
+// decodePayload decodes headers of the IPv4 or IPv6 packet, payload of the other protocols is skipped.
+// Extension headers of IPv6 are not walked: transport header is decoded when it is the next header
+func (p *NflogPacket) decodePayload(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	var hl int
+	switch b[0] >> 4 {
+	case 4:
+		if len(b) < nlheaders.NlHeaderLen {
+			return nil
+		}
+		hl = int(b[0]&0x0f) * 4
+		if hl < nlheaders.NlHeaderLen || hl > len(b) {
+			return errors.Errorf("incorrect IPv4 header length=%d", hl)
+		}
+	case 6:
+		if len(b) < nlheaders.NlHeaderV6Len {
+			return nil
+		}
+		hl = nlheaders.NlHeaderV6Len
+	default:
+		return nil
+	}
+	if err := p.Nh.Decode(b[:hl]); err != nil {
+		return err
+	}
+	// the rest of the copied bytes starts with the transport header
+	p.RawNh, p.RawTh = b[:hl], b[hl:]
+	p.Flags |= (1 << nftrace.NFTNL_TRACE_NETWORK_HEADER)
+
+	switch p.Nh.Protocol {
+	case unix.IPPROTO_TCP, unix.IPPROTO_UDP, unix.IPPROTO_UDPLITE, unix.IPPROTO_SCTP, unix.IPPROTO_DCCP:
+		if len(b[hl:]) >= nlheaders.TlHeaderLen {
+			if err := p.Th.Decode(b[hl : hl+nlheaders.TlHeaderLen]); err != nil {
+				return err
+			}
+			p.Flags |= (1 << nftrace.NFTNL_TRACE_TRANSPORT_HEADER)
+		}
+	}
+	return nil
+}
+
+func hookName(family, hook uint8) string {
+	switch family {
+	case unix.NFPROTO_NETDEV:
+		switch hook {
+		case 0:
+			return "ingress"
+		case 1:
+			return "egress"
+		}
+	case unix.NFPROTO_ARP:
+		switch hook {
+		case 0:
+			return "input"
+		case 1:
+			return "output"
+		case 2:
+			return "forward"
+		}
+	default:
+		switch hook {
+		case NF_INET_PRE_ROUTING:
+			return "prerouting"
+		case NF_INET_LOCAL_IN:
+			return "input"
+		case NF_INET_FORWARD:
+			return "forward"
+		case NF_INET_LOCAL_OUT:
+			return "output"
+		case NF_INET_POST_ROUTING:
+			return "postrouting"
+		}
+	}
+	return strconv.Itoa(int(hook))
+}
@@ -0,0 +1,20 @@
+package nflog
+
+import (
+	"fmt"
+)
+
+// ErrNflog -
+type ErrNflog struct {
+	Err error
+}
+
+// Error -
+func (e ErrNflog) Error() string {
+	return fmt.Sprintf("Nflog: %v", e.Err)
+}
+
+// Cause -
+func (e ErrNflog) Cause() error {
+	return e.Err
+}
@@ -0,0 +1,196 @@
+package nflog
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/nltrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nftrace"
+
+	"github.com/mdlayher/netlink"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/sys/unix"
+)
+
+type nflogTestSuite struct {
+	suite.Suite
+}
+
+func Test_Nflog(t *testing.T) {
+	suite.Run(t, new(nflogTestSuite))
+}
+
+// packetMsg builds NFULNL_MSG_PACKET of the TCP packet 10.0.0.1:40000 -> 10.0.0.2:80
+func packetMsg(group uint16) []byte {
+	ip := []byte{
+		0x45, 0, 0, 60, 0, 0, 0x40, 0, 64, unix.IPPROTO_TCP, 0, 0,
+		10, 0, 0, 1,
+		10, 0, 0, 2,
+	}
+	return packetMsgOf(unix.AF_INET, group, ip)
+}
+
+// packetMsgV6 builds NFULNL_MSG_PACKET of the TCP packet [fd00::1]:40000 -> [fd00::2]:80
+func packetMsgV6(group uint16) []byte {
+	ip := make([]byte, 40)
+	ip[0], ip[1] = 0x60, 0x10 // version 6, traffic class 1
+	binary.BigEndian.PutUint16(ip[4:], 20)
+	ip[6], ip[7] = unix.IPPROTO_TCP, 64
+	copy(ip[8:], net.ParseIP("fd00::1"))
+	copy(ip[24:], net.ParseIP("fd00::2"))
+	return packetMsgOf(unix.AF_INET6, group, ip)
+}
+
+func packetMsgOf(family byte, group uint16, ip []byte) []byte {
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = binary.BigEndian
+	ae.Bytes(NFULA_PACKET_HDR, []byte{0x08, 0x00, NF_INET_LOCAL_IN, 0})
+	ae.Uint32(NFULA_MARK, 0x10)
+	ae.Uint32(NFULA_IFINDEX_INDEV, 2)
+	ae.Uint16(NFULA_HWTYPE, unix.ARPHRD_ETHER)
+	ae.Bytes(NFULA_HWHEADER, []byte{
+		0x02, 0, 0, 0, 0, 0x02, //dst
+		0x02, 0, 0, 0, 0, 0x01, //src
+		0x08, 0x00,
+	})
+	tcp := make([]byte, 20)
+	binary.BigEndian.PutUint16(tcp[0:], 40000)
+	binary.BigEndian.PutUint16(tcp[2:], 80)
+	ae.Bytes(NFULA_PAYLOAD, append(ip, tcp...))
+	ae.String(NFULA_PREFIX, "drop-in")
+	b, err := ae.Encode()
+	if err != nil {
+		panic(err)
+	}
+	hdr := []byte{family, unix.NFNETLINK_V0, 0, 0}
+	binary.BigEndian.PutUint16(hdr[2:], group)
+	return append(hdr, b...)
+}
+
+func (sui *nflogTestSuite) Test_Decode() {
+	var p NflogPacket
+	sui.Require().NoError(p.InitFromMsg(netlink.Message{Data: packetMsg(3)}))
+	tr := p.ToModel()
+
+	sui.Require().Equal(model.SourceNflog, tr.Source)
+	sui.Require().Equal("drop-in", tr.Prefix)
+	sui.Require().Equal(uint16(3), tr.Group)
+	sui.Require().Equal("input", tr.Hook)
+	sui.Require().Equal("ip", tr.Family.String())
+	sui.Require().Equal(uint32(0x10), tr.Mark)
+	sui.Require().Equal(uint32(2), tr.Iif)
+	sui.Require().Equal("02:00:00:00:00:01", tr.Lh.SAddr.String())
+	sui.Require().Equal("02:00:00:00:00:02", tr.Lh.DAddr.String())
+	sui.Require().True(net.IPv4(10, 0, 0, 1).Equal(tr.Nh.SAddr))
+	sui.Require().True(net.IPv4(10, 0, 0, 2).Equal(tr.Nh.DAddr))
+	sui.Require().Equal("tcp", tr.Nh.ProtoStr())
+	sui.Require().Equal(uint16(60), tr.Nh.Length)
+	sui.Require().Equal(uint16(40000), tr.Th.SPort)
+	sui.Require().Equal(uint16(80), tr.Th.DPort)
+
+	for _, f := range []uint{
+		nftrace.NFTNL_TRACE_IIF,
+		nftrace.NFTNL_TRACE_MARK,
+		nftrace.NFTNL_TRACE_LL_HEADER,
+		nftrace.NFTNL_TRACE_NETWORK_HEADER,
+		nftrace.NFTNL_TRACE_TRANSPORT_HEADER,
+	} {
+		sui.Require().NotZero(tr.Flags&(1<<f), f)
+	}
+	sui.Require().Zero(tr.Flags & (1 << nftrace.NFTNL_TRACE_OIF))
+}
+
+func (sui *nflogTestSuite) Test_DecodeIPv6() {
+	var p NflogPacket
+	sui.Require().NoError(p.InitFromMsg(netlink.Message{Data: packetMsgV6(3)}))
+	tr := p.ToModel()
+
+	sui.Require().Equal("ip6", tr.Family.String())
+	sui.Require().Equal(uint8(6), tr.Nh.Version)
+	sui.Require().Equal("fd00::1", tr.Nh.SAddr.String())
+	sui.Require().Equal("fd00::2", tr.Nh.DAddr.String())
+	sui.Require().Equal("tcp", tr.Nh.ProtoStr())
+	sui.Require().Equal(uint8(64), tr.Nh.TTL)
+	sui.Require().Equal(uint16(20), tr.Nh.Length)
+	sui.Require().Len(tr.RawNh, 40)
+	sui.Require().Equal(uint16(40000), tr.Th.SPort)
+	sui.Require().Equal(uint16(80), tr.Th.DPort)
+	sui.Require().NotZero(tr.Flags & (1 << nftrace.NFTNL_TRACE_NETWORK_HEADER))
+	sui.Require().NotZero(tr.Flags & (1 << nftrace.NFTNL_TRACE_TRANSPORT_HEADER))
+}
+
+func (sui *nflogTestSuite) Test_CollectorDecode() {
+	c := &collectorImpl{}
+	ack := syscall.NetlinkMessage{
+		Header: syscall.NlMsghdr{Type: unix.NLMSG_ERROR},
+		Data:   make([]byte, 4),
+	}
+	pkt := syscall.NetlinkMessage{
+		Header: syscall.NlMsghdr{Type: unix.NFNL_SUBSYS_ULOG<<8 | NFULNL_MSG_PACKET},
+		Data:   packetMsg(1),
+	}
+	traces, errs := c.decode([]syscall.NetlinkMessage{ack, pkt, pkt})
+	sui.Require().Empty(errs)
+	sui.Require().Len(traces, 2)
+	sui.Require().NotEqual(traces[0].Id, traces[1].Id)
+
+	// broken messages and bind errors are skipped, the rest of messages are decoded
+	errno := -int32(unix.EBUSY)
+	binary.NativeEndian.PutUint32(ack.Data, uint32(errno))
+	broken := syscall.NetlinkMessage{Header: pkt.Header, Data: []byte{unix.AF_INET}}
+	traces, errs = c.decode([]syscall.NetlinkMessage{ack, broken, pkt})
+	sui.Require().Len(errs, 2)
+	sui.Require().ErrorIs(errs[0], syscall.EBUSY)
+	sui.Require().Len(traces, 1)
+}
+
+func (sui *nflogTestSuite) Test_BindRequest() {
+	msgs, err := syscall.ParseNetlinkMessage(bindRequest(7, 96))
+	sui.Require().NoError(err)
+	sui.Require().Len(msgs, 1)
+	m := msgs[0]
+	sui.Require().Equal(uint16(unix.NFNL_SUBSYS_ULOG<<8|NFULNL_MSG_CONFIG), m.Header.Type)
+	sui.Require().Equal(uint16(7), binary.BigEndian.Uint16(m.Data[2:4]))
+
+	ad, err := netlink.NewAttributeDecoder(m.Data[sizeofNfgenmsg:])
+	sui.Require().NoError(err)
+	ad.ByteOrder = binary.BigEndian
+	var cmd uint8
+	var mode []byte
+	for ad.Next() {
+		switch ad.Type() {
+		case NFULA_CFG_CMD:
+			cmd = ad.Uint8()
+		case NFULA_CFG_MODE:
+			mode = ad.Bytes()
+		}
+	}
+	sui.Require().NoError(ad.Err())
+	sui.Require().Equal(NFULNL_CFG_CMD_BIND, cmd)
+	sui.Require().Len(mode, 6)
+	sui.Require().Equal(uint32(96), binary.BigEndian.Uint32(mode))
+	sui.Require().Equal(NFULNL_COPY_PACKET, mode[4])
+}
+
+func (sui *nflogTestSuite) Test_ParseGroups() {
+	g, err := ParseGroups("0, 5,100")
+	sui.Require().NoError(err)
+	sui.Require().Equal([]uint16{0, 5, 100}, g)
+	g, err = ParseGroups("")
+	sui.Require().NoError(err)
+	sui.Require().Empty(g)
+	_, err = ParseGroups("1,x")
+	sui.Require().Error(err)
+	_, err = ParseGroups("70000")
+	sui.Require().Error(err)
+}
+
+func (sui *nflogTestSuite) Test_HookName() {
+	sui.Require().Equal("prerouting", hookName(unix.NFPROTO_IPV4, NF_INET_PRE_ROUTING))
+	sui.Require().Equal("postrouting", hookName(unix.NFPROTO_BRIDGE, NF_INET_POST_ROUTING))
+	sui.Require().Equal("ingress", hookName(unix.NFPROTO_NETDEV, 0))
+	sui.Require().Equal("output", hookName(unix.NFPROTO_ARP, 1))
+	sui.Require().Equal("9", hookName(unix.NFPROTO_IPV6, 9))
+}
@@ -1,6 +1,7 @@
 package nfrule
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -12,10 +13,11 @@ import (
 )
 
 type (
+	// RuleEntry - version of the rule, it is valid since At until the next version of the rule
 	RuleEntry struct {
 		RuleNative *nftLib.Rule
-		RuleStr    string
-		removed    bool
+		RuleStr    string // rendered rule, empty until the rule is needed by trace
+		removed    bool   // version marks removal of the rule
 		At         time.Time
 	}
 
@@ -26,10 +28,14 @@ type (
 		Handle      uint64
 	}
 
-	// RuleCache - cache to store nftables rules
+	// ruleHistory - versions of the rule ordered by time
+	ruleHistory []RuleEntry
+
+	// RuleCache - cache to store nftables rules along with the short history of their versions
 	RuleCache struct {
-		cache     dict.HDict[RuleEntryKey, RuleEntry]
+		cache     dict.HDict[RuleEntryKey, ruleHistory]
 		ttl       time.Duration
+		connOpts  []nftLib.ConnOption
 		mu        sync.RWMutex
 		onceClose sync.Once
 		stop      chan struct{}
@@ -37,15 +43,20 @@ type (
 	}
 )
 
-// NewRuleCache - creator for the cache
-func NewRuleCache(ttl time.Duration) *RuleCache {
+// maxRuleVersions - limit of versions kept per rule
+const maxRuleVersions = 16
+
+// NewRuleCache - creator for the cache, connection options are used to refresh the cache,
+// replaced and removed versions of rules are kept during ttl
+func NewRuleCache(ttl time.Duration, connOpts ...nftLib.ConnOption) *RuleCache {
 	if ttl < time.Second {
 		panic("'RuleCache/ttl' is less than 1s")
 	}
 	r := &RuleCache{
-		ttl:     ttl,
-		stop:    make(chan struct{}),
-		stopped: make(chan struct{}),
+		ttl:      ttl,
+		connOpts: connOpts,
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
 	}
 
 	go r.startCleaner()
@@ -75,62 +86,179 @@ func (r *RuleCache) clean() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	keys := make([]RuleEntryKey, 0, r.cache.Len())
-
-	r.cache.Iterate(func(k RuleEntryKey, re RuleEntry) bool {
-		if re.removed && (time.Since(re.At) >= r.ttl) {
+	var (
+		keys []RuleEntryKey
+		upd  dict.HDict[RuleEntryKey, ruleHistory]
+	)
+	r.cache.Iterate(func(k RuleEntryKey, h ruleHistory) bool {
+		if last := h[len(h)-1]; last.removed && time.Since(last.At) >= r.ttl {
 			keys = append(keys, k)
+			return true
+		}
+		if n := h.expired(r.ttl); n > 0 {
+			upd.Put(k, h[n:])
 		}
 		return true
 	})
 	r.cache.Del(keys...)
+	upd.Iterate(func(k RuleEntryKey, h ruleHistory) bool {
+		r.cache.Put(k, h)
+		return true
+	})
+}
+
+// expired - number of the oldest versions superseded more than ttl ago
+func (h ruleHistory) expired(ttl time.Duration) (n int) {
+	for n < len(h)-1 && time.Since(h[n+1].At) >= ttl {
+		n++
+	}
+	return n
+}
+
+// at - version of the rule valid at the moment, the earliest one when the moment precedes all versions
+// as the rule event may be handled later than the trace of the rule
+func (h ruleHistory) at(t time.Time) RuleEntry {
+	i := sort.Search(len(h), func(i int) bool {
+		return h[i].At.After(t)
+	})
+	if i == 0 {
+		return h[0]
+	}
+	return h[i-1]
 }
 
 // Refresh - update rule cache
 func (r *RuleCache) Refresh() error {
-	conn, err := nftLib.New()
+	rules, err := r.fetchRules()
+	if err != nil {
+		return err
+	}
+	t := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, re := range rules {
+		re.At = t
+		r.putVersion(k, re)
+	}
+	return nil
+}
+
+// Resync - bring the cache in line with the ruleset when rule changes have been lost:
+// new versions of changed rules are added, rules absent from the ruleset are marked removed,
+// unchanged rules are kept as is
+func (r *RuleCache) Resync() error {
+	rules, err := r.fetchRules()
+	if err != nil {
+		return err
+	}
+	r.resync(rules, time.Now())
+	return nil
+}
+
+func (r *RuleCache) resync(rules map[RuleEntryKey]RuleEntry, t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var gone []RuleEntry
+	r.cache.Iterate(func(k RuleEntryKey, h ruleHistory) bool {
+		if _, ok := rules[k]; !ok && !h[len(h)-1].removed {
+			gone = append(gone, h[len(h)-1])
+		}
+		return true
+	})
+	for _, re := range gone {
+		re.removed, re.At = true, t
+		r.putVersion(entryKey(re), re)
+	}
+	for k, re := range rules {
+		if h, ok := r.cache.Get(k); ok && !h[len(h)-1].removed && sameRule(h[len(h)-1], re) {
+			continue
+		}
+		re.At = t
+		r.putVersion(k, re)
+	}
+}
+
+// fetchRules - obtain all rules from the netfilter
+func (r *RuleCache) fetchRules() (map[RuleEntryKey]RuleEntry, error) {
+	conn, err := nftLib.New(r.connOpts...)
 	if err != nil {
-		return errors.WithMessage(err, "failed to create netlink connection")
+		return nil, errors.WithMessage(err, "failed to create netlink connection")
 	}
 	defer conn.CloseLasting() //nolint:errcheck
 
 	rules, err := conn.GetAllRules()
 	if err != nil {
-		return errors.WithMessage(err, "failed to obtain rules from the netfilter")
+		return nil, errors.WithMessage(err, "failed to obtain rules from the netfilter")
 	}
-	t := time.Now()
+	ret := make(map[RuleEntryKey]RuleEntry, len(rules))
 	for _, rl := range rules {
-		strRule, err := (*parser.Rule)(rl).String()
-		if err != nil {
-			return err
-		}
+		ret[RuleEntryKey{
+			TableName:   rl.Table.Name,
+			TableFamily: rl.Table.Family,
+			ChainName:   rl.Chain.Name,
+			Handle:      rl.Handle,
+		}] = RuleEntry{RuleNative: rl}
+	}
+	return ret, nil
+}
 
-		r.mu.Lock()
-		r.cache.Put(
-			RuleEntryKey{
-				TableName:   rl.Table.Name,
-				TableFamily: rl.Table.Family,
-				ChainName:   rl.Chain.Name,
-				Handle:      rl.Handle},
-			RuleEntry{
-				RuleNative: rl,
-				RuleStr:    strRule,
-				At:         t,
-			})
-		r.mu.Unlock()
+// Render - render the rule unless it is rendered yet, result is memoized in the cache
+func (r *RuleCache) Render(re RuleEntry) (RuleEntry, error) {
+	if re.RuleStr != "" {
+		return re, nil
 	}
+	strRule, err := (*parser.Rule)(re.RuleNative).String()
+	if err != nil {
+		return re, err
+	}
+	re.RuleStr = strRule
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, _ := r.cache.Get(entryKey(re))
+	for i := range h {
+		if h[i].RuleNative == re.RuleNative {
+			h[i].RuleStr = strRule
+		}
+	}
+	return re, nil
+}
 
-	return nil
+// sameRule - rules are rendered equally
+func sameRule(a, b RuleEntry) bool {
+	render := func(re RuleEntry) (string, error) {
+		if re.RuleStr != "" {
+			return re.RuleStr, nil
+		}
+		return (*parser.Rule)(re.RuleNative).String()
+	}
+	strA, errA := render(a)
+	strB, errB := render(b)
+	return errA == nil && errB == nil && strA == strB
 }
 
-// Get rule by key
+// Get rule by key, it is the latest version of the rule
 func (r *RuleCache) GetRule(k RuleEntryKey) (RuleEntry, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	return r.cache.Get(k)
+	h, ok := r.cache.Get(k)
+	if !ok {
+		return RuleEntry{}, false
+	}
+	return h[len(h)-1], true
+}
+
+// GetRuleAt - version of the rule valid at the moment
+func (r *RuleCache) GetRuleAt(k RuleEntryKey, t time.Time) (RuleEntry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.cache.Get(k)
+	if !ok {
+		return RuleEntry{}, false
+	}
+	return h.at(t), true
 }
 
-// RmRule remove rule by key
+// RmRule remove rule with its history by key
 func (r *RuleCache) RmRule(k RuleEntryKey) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -139,28 +267,36 @@ func (r *RuleCache) RmRule(k RuleEntryKey) {
 
 // InsertRule insert rule into cache
 func (r *RuleCache) InsertRule(rl RuleEntry) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.cache.Put(
-		RuleEntryKey{
-			rl.RuleNative.Table.Name,
-			rl.RuleNative.Table.Family,
-			rl.RuleNative.Chain.Name,
-			rl.RuleNative.Handle},
-		rl)
+	r.UpdRule(rl)
 }
 
-// UpdRule update rule in cache
+// UpdRule add new version of the rule into cache
 func (r *RuleCache) UpdRule(rl RuleEntry) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.cache.Put(
-		RuleEntryKey{
-			rl.RuleNative.Table.Name,
-			rl.RuleNative.Table.Family,
-			rl.RuleNative.Chain.Name,
-			rl.RuleNative.Handle},
-		rl)
+	r.putVersion(entryKey(rl), rl)
+}
+
+func (r *RuleCache) putVersion(k RuleEntryKey, rl RuleEntry) {
+	h, _ := r.cache.Get(k)
+	if len(h) >= maxRuleVersions {
+		h = h[len(h)-maxRuleVersions+1:]
+	}
+	// keep versions ordered even if the event comes out of order
+	i := sort.Search(len(h), func(i int) bool {
+		return h[i].At.After(rl.At)
+	})
+	h = append(h[:i:i], append(ruleHistory{rl}, h[i:]...)...)
+	r.cache.Put(k, h)
+}
+
+func entryKey(rl RuleEntry) RuleEntryKey {
+	return RuleEntryKey{
+		rl.RuleNative.Table.Name,
+		rl.RuleNative.Table.Family,
+		rl.RuleNative.Chain.Name,
+		rl.RuleNative.Handle,
+	}
 }
 
 // Close rule cache
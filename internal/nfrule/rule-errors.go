@@ -24,5 +24,4 @@ func (e ErrRule) Cause() error {
 var (
 	ErrNotFoundRule      = errors.New("rule is not found")
 	ErrConvertRuleToJson = errors.New("failed conversion rule to json")
-	ErrExpiredTrace      = errors.New("expired trace")
 )
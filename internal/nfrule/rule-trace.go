@@ -39,6 +39,7 @@ type (
 type (
 	ruleTracerImpl struct {
 		Deps
+		connOpts  []nftLib.ConnOption
 		cache     *RuleCache
 		onceRun   sync.Once
 		onceClose sync.Once
@@ -48,70 +49,47 @@ type (
 	// CountRulerNlErrMemEvent -
 	CountRulerNlErrMemEvent struct {
 		observer.EventType
+		// Lost - estimated number of netlink messages lost on the overrun
+		Lost uint32
 	}
+
+	// RuleTraceOpt - option of the rule tracer
+	RuleTraceOpt func(*ruleTracerImpl)
 )
 
 var _ RuleTracer = (*ruleTracerImpl)(nil)
 
-func NewRuleTrace(d Deps) (rt RuleTracer) {
+// RuleTraceWithNetNS - obtain rules from the network namespace referred by file descriptor
+func RuleTraceWithNetNS(fd int) RuleTraceOpt {
+	return func(r *ruleTracerImpl) {
+		if fd >= 0 {
+			r.connOpts = append(r.connOpts, nftLib.WithNetNSFd(fd))
+		}
+	}
+}
+
+func NewRuleTrace(d Deps, opts ...RuleTraceOpt) (rt RuleTracer) {
 	const ttl = 3 * time.Second
-	return &ruleTracerImpl{
-		Deps:  d,
-		stop:  make(chan struct{}),
-		cache: NewRuleCache(ttl),
+	r := &ruleTracerImpl{
+		Deps: d,
+		stop: make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(r)
 	}
+	r.cache = NewRuleCache(ttl, r.connOpts...)
+	return r
 }
 
+// GetRuleForTrace - find version of the trace rule valid when the packet was traced,
+// so traces of the rules removed or replaced in the meantime are attributed as well.
+// Cache is seeded from the ruleset at start and maintained from rule events, a miss means the rule is unknown yet
 func (r *ruleTracerImpl) GetRuleForTrace(tr *model.NetlinkTrace) (re RuleEntry, err error) {
-	table, chain, handle := tr.Table, tr.Chain, tr.RuleHandle
-	re, ok := r.cache.GetRule(RuleEntryKey{table, nftLib.TableFamily(tr.Family), chain, handle})
+	re, ok := r.cache.GetRuleAt(RuleEntryKey{tr.Table, nftLib.TableFamily(tr.Family), tr.Chain, tr.RuleHandle}, tr.At)
 	if !ok {
-		conn, err := nftLib.New()
-		if err != nil {
-			return re, err
-		}
-		defer conn.CloseLasting() //nolint:errcheck
-		rules, err := conn.GetRules(
-			&nftLib.Table{
-				Name:   table,
-				Family: nftLib.TableFamily(tr.Family)},
-			&nftLib.Chain{Name: chain},
-		)
-		if err != nil {
-			return re, err
-		}
-		var rl *nftLib.Rule
-		for _, rule := range rules {
-			if rule.Handle == handle {
-				rl = rule
-				break
-			}
-		}
-
-		if rl == nil {
-			return re, ErrNotFoundRule
-		}
-
-		strRule, err := (*parser.Rule)(rl).String()
-		if err != nil {
-			return re, err
-		}
-		re = RuleEntry{
-			RuleNative: rl,
-			RuleStr:    strRule,
-			At:         time.Now()}
-
-		r.cache.InsertRule(re)
-		return re, nil
+		return re, ErrNotFoundRule
 	}
-
-	if re.removed || re.At.After(tr.At) ||
-		!(re.RuleNative.Table.Name == tr.Table &&
-			re.RuleNative.Chain.Name == tr.Chain) {
-		return re, ErrExpiredTrace
-	}
-
-	return re, nil
+	return r.cache.Render(re)
 }
 
 func (r *ruleTracerImpl) Run(ctx context.Context) (err error) {
@@ -157,7 +135,12 @@ func (r *ruleTracerImpl) Run(ctx context.Context) (err error) {
 
 			if err != nil {
 				if errors.Is(err, nl.ErrNlMem) {
-					r.Deps.AgentSubject.Notify(CountRulerNlErrMemEvent{})
+					// rule changes have been lost so the cache is not consistent anymore
+					r.Deps.AgentSubject.Notify(CountRulerNlErrMemEvent{Lost: nlData.Lost})
+					log.Warnf("netlink buffer overrun, about %d messages lost, refetch ruleset", nlData.Lost)
+					if err = r.cache.Resync(); err != nil {
+						return ErrRule{Err: fmt.Errorf("failed to resync rule cache: %v", err)}
+					}
 					continue
 				}
 				if errors.Is(err, nl.ErrNlDataNotReady) ||
@@ -197,24 +180,16 @@ func (r *ruleTracerImpl) handleMsg(ctx context.Context, msg nl.NetlinkNfMsg) err
 		if err != nil {
 			return errors.WithMessage(err, "failed to fetch rule from netlink message")
 		}
-		strRule, err := rule.String()
-		if err != nil {
-			return err
-		}
-
 		re := RuleEntry{
 			RuleNative: (*nftLib.Rule)(rule),
-			RuleStr:    strRule,
-			removed:    false,
+			removed:    t == unix.NFT_MSG_DELRULE,
 			At:         time.Now()}
+		r.cache.UpdRule(re)
 
-		if t == unix.NFT_MSG_DELRULE {
-			re.removed = true
-			r.cache.UpdRule(re)
-			log.Debugf("removed rule=%d, expr: %s", rule.Handle, strRule)
+		if re.removed {
+			log.Debugf("removed rule=%d, table='%s', chain='%s'", rule.Handle, rule.Table.Name, rule.Chain.Name)
 		} else {
-			r.cache.UpdRule(re)
-			log.Debugf("added new rule=%d, expr: %s", rule.Handle, strRule)
+			log.Debugf("added new rule=%d, table='%s', chain='%s'", rule.Handle, rule.Table.Name, rule.Chain.Name)
 		}
 	}
 	return nil
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/nltrace"
 	nfte "github.com/wildberries-tech/pkt-tracer/internal/nftables/encoders"
 	"github.com/wildberries-tech/pkt-tracer/internal/nftables/parser"
 
@@ -163,6 +164,149 @@ func (sui *ruleTestSuite) Test_RuleGetRmMultiThread() {
 	wg.Wait()
 }
 
+func (sui *ruleTestSuite) Test_RuleCacheResync() {
+	entry := func(handle uint64, ruleStr string) (RuleEntryKey, RuleEntry) {
+		rl := &nftables.Rule{
+			Table:  &nftables.Table{Name: "filter", Family: nftables.TableFamilyIPv4},
+			Chain:  &nftables.Chain{Name: "input"},
+			Handle: handle,
+		}
+		return RuleEntryKey{rl.Table.Name, rl.Table.Family, rl.Chain.Name, rl.Handle},
+			RuleEntry{RuleNative: rl, RuleStr: ruleStr}
+	}
+	rlcache := NewRuleCache(time.Second)
+	defer rlcache.Close()
+
+	before := time.Now().Add(-time.Minute)
+	for h, str := range map[uint64]string{1: "accept", 2: "drop", 3: "log"} {
+		_, re := entry(h, str)
+		re.At = before
+		rlcache.InsertRule(re)
+	}
+	rules := make(map[RuleEntryKey]RuleEntry)
+	for h, str := range map[uint64]string{1: "accept", 2: "counter drop", 4: "reject"} {
+		k, re := entry(h, str)
+		rules[k] = re
+	}
+	now := time.Now()
+	rlcache.resync(rules, now)
+
+	for _, exp := range []struct {
+		handle  uint64
+		ruleStr string
+		removed bool
+		at      time.Time
+	}{
+		{1, "accept", false, before},
+		{2, "counter drop", false, now},
+		{3, "log", true, now},
+		{4, "reject", false, now},
+	} {
+		k, _ := entry(exp.handle, "")
+		re, ok := rlcache.GetRule(k)
+		sui.Require().True(ok)
+		sui.Require().Equal(exp.ruleStr, re.RuleStr)
+		sui.Require().Equal(exp.removed, re.removed)
+		sui.Require().True(exp.at.Equal(re.At))
+	}
+}
+
+func (sui *ruleTestSuite) Test_GetRuleForTrace() {
+	rt := NewRuleTrace(Deps{}).(*ruleTracerImpl)
+	defer rt.Close()
+
+	rl := &nftables.Rule{
+		Table:  &nftables.Table{Name: "filter", Family: nftables.TableFamilyIPv4},
+		Chain:  &nftables.Chain{Name: "input"},
+		Handle: 5,
+		Exprs:  []expr.Any{&expr.Counter{}, &expr.Verdict{Kind: expr.VerdictAccept}},
+	}
+	expStr, err := (*parser.Rule)(rl).String()
+	sui.Require().NoError(err)
+	at := time.Now()
+	rt.cache.UpdRule(RuleEntry{RuleNative: rl, At: at})
+
+	tr := &model.NetlinkTrace{
+		Table:      "filter",
+		Family:     model.FamilyTable(nftables.TableFamilyIPv4),
+		Chain:      "input",
+		RuleHandle: 6,
+		At:         at.Add(time.Millisecond),
+	}
+	_, err = rt.GetRuleForTrace(tr)
+	sui.Require().ErrorIs(err, ErrNotFoundRule)
+
+	tr.RuleHandle = 5
+	re, err := rt.GetRuleForTrace(tr)
+	sui.Require().NoError(err)
+	sui.Require().Equal(expStr, re.RuleStr)
+	cached, ok := rt.cache.GetRule(RuleEntryKey{"filter", nftables.TableFamilyIPv4, "input", 5})
+	sui.Require().True(ok)
+	sui.Require().Equal(expStr, cached.RuleStr, "rendered rule is memoized")
+
+}
+
+func (sui *ruleTestSuite) Test_RuleHistory() {
+	rt := NewRuleTrace(Deps{}).(*ruleTracerImpl)
+	defer rt.Close()
+
+	version := func(verdict expr.VerdictKind) *nftables.Rule {
+		return &nftables.Rule{
+			Table:  &nftables.Table{Name: "filter", Family: nftables.TableFamilyIPv4},
+			Chain:  &nftables.Chain{Name: "input"},
+			Handle: 5,
+			Exprs:  []expr.Any{&expr.Verdict{Kind: verdict}},
+		}
+	}
+	v1, v2 := version(expr.VerdictAccept), version(expr.VerdictDrop)
+	str1, err := (*parser.Rule)(v1).String()
+	sui.Require().NoError(err)
+	str2, err := (*parser.Rule)(v2).String()
+	sui.Require().NoError(err)
+
+	t0 := time.Now().Add(-time.Minute)
+	t1, t2, t3 := t0.Add(time.Second), t0.Add(2*time.Second), t0.Add(3*time.Second)
+	// events of the replacement and removal may come out of order
+	rt.cache.UpdRule(RuleEntry{RuleNative: v1, At: t1})
+	rt.cache.UpdRule(RuleEntry{RuleNative: version(expr.VerdictDrop), removed: true, At: t3})
+	rt.cache.UpdRule(RuleEntry{RuleNative: v2, At: t2})
+
+	tr := &model.NetlinkTrace{
+		Table:      "filter",
+		Family:     model.FamilyTable(nftables.TableFamilyIPv4),
+		Chain:      "input",
+		RuleHandle: 5,
+	}
+	for _, c := range []struct {
+		at  time.Time
+		exp string
+	}{
+		{t0, str1}, // rule event is handled later than the trace
+		{t1, str1},
+		{t2.Add(-time.Millisecond), str1},
+		{t2, str2},
+		{t3.Add(time.Second), str2}, // trace of the just deleted rule
+	} {
+		tr.At = c.at
+		re, err := rt.GetRuleForTrace(tr)
+		sui.Require().NoError(err)
+		sui.Require().Equal(c.exp, re.RuleStr)
+	}
+
+	// superseded versions and removed rules are forgotten after ttl
+	rt.cache.clean()
+	_, ok := rt.cache.GetRule(RuleEntryKey{"filter", nftables.TableFamilyIPv4, "input", 5})
+	sui.Require().False(ok)
+
+	rt.cache.UpdRule(RuleEntry{RuleNative: v1, At: t1})
+	rt.cache.UpdRule(RuleEntry{RuleNative: v2, At: t2})
+	rt.cache.clean()
+	tr.At = t0
+	re, err := rt.GetRuleForTrace(tr)
+	sui.Require().NoError(err)
+	sui.Require().Equal(str2, re.RuleStr)
+}
+
 func Test_Rule(t *testing.T) {
 	suite.Run(t, new(ruleTestSuite))
 }
@@ -10,11 +10,12 @@ import (
 
 type tableCache struct {
 	dict.HDict[TableEntryKey, TableEntry]
+	connOpts []nftLib.ConnOption
 }
 
 // Refresh - update table cache
 func (t *tableCache) Refresh() error {
-	conn, err := nftLib.New()
+	conn, err := nftLib.New(t.connOpts...)
 	if err != nil {
 		return errors.WithMessage(err, "failed to create netlink connection")
 	}
@@ -51,6 +51,7 @@ type (
 	tableWatcherImpl struct {
 		Deps
 		syncInterval time.Duration
+		netns        string
 		cache        cacheFace
 		onceRun      sync.Once
 		onceClose    sync.Once
@@ -60,21 +61,43 @@ type (
 	// CountTableWatcherNlErrMemEvent -
 	CountTableWatcherNlErrMemEvent struct {
 		observer.EventType
+		// Lost - estimated number of netlink messages lost on the overrun
+		Lost uint32
 	}
+
+	// TableWatcherOpt - option of the table watcher
+	TableWatcherOpt func(*tableWatcherImpl)
 )
 
-func NewTableWatcher(d Deps, si time.Duration) TableWatcher {
+// WatchWithNetNS - watch tables of the network namespace referred by file descriptor (negative is the current one)
+// and mark them with the namespace identifier
+func WatchWithNetNS(id string, fd int) TableWatcherOpt {
+	return func(t *tableWatcherImpl) {
+		t.netns = id
+		if fd >= 0 {
+			t.cache = &tableCache{
+				connOpts: []nftLib.ConnOption{nftLib.WithNetNSFd(fd)},
+			}
+		}
+	}
+}
+
+func NewTableWatcher(d Deps, si time.Duration, opts ...TableWatcherOpt) TableWatcher {
 	if si < time.Second {
 		panic(
 			fmt.Errorf("'TableWatcher/TableSyncInterval' is (%v) less than 1s", si),
 		)
 	}
-	return &tableWatcherImpl{
+	t := &tableWatcherImpl{
 		Deps:         d,
 		syncInterval: si,
 		cache:        &tableCache{},
 		stop:         make(chan struct{}),
 	}
+	for _, o := range opts {
+		o(t)
+	}
+	return t
 }
 
 func (t *tableWatcherImpl) Run(ctx context.Context) (err error) {
@@ -140,6 +163,7 @@ func (t *tableWatcherImpl) Run(ctx context.Context) (err error) {
 					TableName:   te.Table.Name,
 					TableFamily: parser.TableFamily(te.Table.Family).String(),
 					TableStr:    tblStr,
+					NetNS:       t.netns,
 				}
 				ruleStr := ""
 				te.OrderedChains.Iterate(func(ce *ChainEntry) bool {
@@ -175,7 +199,13 @@ func (t *tableWatcherImpl) Run(ctx context.Context) (err error) {
 
 			if err != nil {
 				if errors.Is(err, nl.ErrNlMem) {
-					t.Deps.AgentSubject.Notify(CountTableWatcherNlErrMemEvent{})
+					// table changes have been lost, so snapshot of tables is taken and sent again
+					t.Deps.AgentSubject.Notify(CountTableWatcherNlErrMemEvent{Lost: nlData.Lost})
+					log.Warnf("netlink buffer overrun, about %d messages lost, take snapshot of tables", nlData.Lost)
+					t.cache.Clear()
+					if err = t.cache.Refresh(); err != nil {
+						return ErrTableWatcher{Err: errors.WithMessage(err, "failed to refresh cache of tables")}
+					}
 					continue
 				}
 				if errors.Is(err, nl.ErrNlDataNotReady) ||
@@ -3,6 +3,7 @@ package nftmonitor
 import (
 	"context"
 	"net"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
@@ -15,6 +16,7 @@ import (
 	nftLib "github.com/google/nftables"
 	"github.com/google/nftables/expr"
 	"github.com/mdlayher/netlink"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -91,10 +93,12 @@ func NewRecorder() *Recorder {
 
 type mockTableCache struct {
 	tableCache
+	refreshed int
 }
 
 // override stub method
 func (m *mockTableCache) Refresh() error {
+	m.refreshed++
 	return nil
 }
 
@@ -265,3 +269,40 @@ func fillRuleset(c *ConnMock) error {
 	})
 	return nil
 }
+
+func (sui *tableTestSuite) Test_TableWatcherOverrun() {
+	nlWatcher := NewMockNetlinkWatcher(sui.T())
+	out := make(chan nl.NlData, 1)
+	out <- nl.NlData{Err: errors.Wrap(nl.ErrNlMem, "no buffer space available"), Lost: 7}
+	nlWatcher.On("Read").Return(out)
+	cli := NewMockStreamCli(sui.T())
+	cli.On("CloseAndRecv").Return(nil, nil)
+
+	var lost atomic.Uint32
+	subject := observer.NewSubject()
+	subject.ObserversAttach(observer.NewObserver(func(ev observer.EventType) {
+		if e, ok := ev.(CountTableWatcherNlErrMemEvent); ok {
+			lost.Store(e.Lost)
+		}
+	}, false, CountTableWatcherNlErrMemEvent{}))
+
+	tc := &mockTableCache{}
+	tc.PutTable(TableEntry{Table: &nftLib.Table{Name: "stale", Family: nftLib.TableFamilyIPv4}})
+	tblWatcher := tableWatcherImpl{
+		Deps: Deps{
+			Client:       cli,
+			AgentSubject: subject,
+			NlWatcher:    nlWatcher,
+		},
+		syncInterval: time.Minute,
+		cache:        tc,
+		stop:         make(chan struct{}),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := tblWatcher.Run(ctx)
+	sui.Require().ErrorIs(err, context.DeadlineExceeded)
+	sui.Require().Equal(2, tc.refreshed)
+	sui.Require().Zero(tc.Len())
+	sui.Require().Equal(uint32(7), lost.Load())
+}
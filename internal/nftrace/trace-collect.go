@@ -4,16 +4,18 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/wildberries-tech/pkt-tracer/internal/bqueue"
 	model "github.com/wildberries-tech/pkt-tracer/internal/models/nltrace"
 	"github.com/wildberries-tech/pkt-tracer/internal/nl"
 
 	"github.com/H-BF/corlib/logger"
 	"github.com/H-BF/corlib/pkg/patterns/observer"
-	"github.com/H-BF/corlib/pkg/queue"
 	"github.com/mdlayher/netlink"
 	"github.com/pkg/errors"
+	"golang.org/x/net/bpf"
 	"golang.org/x/sys/unix"
 )
 
@@ -21,32 +23,83 @@ import (
 type TraceCollector interface {
 	Run(ctx context.Context) error
 	Reader() <-chan []model.NetlinkTrace
+	// Drain - stop collecting, the reader is closed after traces collected yet
+	Drain() error
 	Close() error
+	// LastPoll - time netlink has been polled by the running collector last time, zero before it runs
+	LastPoll() time.Time
 }
 
 // traceCollectorImpl - implementation of the TraceCollector interface
 type (
 	traceCollectorImpl struct {
 		agentSubject observer.Subject
-		que          queue.FIFO[[]model.NetlinkTrace]
+		netnsFd      int
+		filter       FilterSpec
+		bpfProg      []bpf.RawInstruction
+		queSettings  bqueue.Settings
+		que          *bqueue.FIFO[[]model.NetlinkTrace]
 		onceRun      sync.Once
+		onceStop     sync.Once
 		onceClose    sync.Once
 		stop         chan struct{}
 		stopped      chan struct{}
+		nlWatcher    atomic.Value // nl.NetlinkWatcher of the running collector
 	}
 	CountCollectNlErrMemEvent struct {
 		observer.EventType
+		// Lost - estimated number of netlink messages lost on the overrun
+		Lost uint32
 	}
+
+	// CollectorOpt - option of the trace collector
+	CollectorOpt func(*traceCollectorImpl)
 )
 
 var _ TraceCollector = (*traceCollectorImpl)(nil)
 
-func NewCollector(as observer.Subject) (TraceCollector, error) {
+// CollectWithNetNS - collect traces in the network namespace referred by file descriptor
+func CollectWithNetNS(fd int) CollectorOpt {
+	return func(c *traceCollectorImpl) {
+		c.netnsFd = fd
+	}
+}
+
+// CollectWithFilter - drop traces rejected by the filter in kernel before they reach the collector
+func CollectWithFilter(spec FilterSpec) CollectorOpt {
+	return func(c *traceCollectorImpl) {
+		c.filter = spec
+	}
+}
+
+// CollectWithQueue - bound the queue of collected traces, batches holding drop decisions
+// are kept by the PreferDrops policy
+func CollectWithQueue(s bqueue.Settings) CollectorOpt {
+	return func(c *traceCollectorImpl) {
+		c.queSettings = s
+	}
+}
+
+func NewCollector(as observer.Subject, opts ...CollectorOpt) (TraceCollector, error) {
 	cl := &traceCollectorImpl{
 		agentSubject: as,
-		que:          queue.NewFIFO[[]model.NetlinkTrace](),
+		netnsFd:      -1,
 		stop:         make(chan struct{}),
 	}
+	for _, o := range opts {
+		o(cl)
+	}
+	cl.que = bqueue.NewFIFO(
+		bqueue.WithSettings[[]model.NetlinkTrace](cl.queSettings),
+		bqueue.WithSize(func(b []model.NetlinkTrace) int { return len(b) }),
+		bqueue.WithPriority(hasDropDecision),
+	)
+	if !cl.filter.IsEmpty() {
+		var err error
+		if cl.bpfProg, err = cl.filter.Assemble(); err != nil {
+			return nil, ErrCollect{Err: fmt.Errorf("failed to assemble trace filter: %v", err)}
+		}
+	}
 
 	return cl, nil
 }
@@ -63,15 +116,22 @@ func (c *traceCollectorImpl) Run(ctx context.Context) (err error) {
 
 	nlWatcher, err := nl.NewNetlinkWatcher(1, unix.NETLINK_NETFILTER,
 		nl.SkWithBufLen(nl.SockBufLen16MB),
+		nl.SkWithBPF(c.bpfProg),
 		nl.SkWithNlMs(unix.NFNLGRP_NFTRACE),
+		nl.NlWithNetNS(c.netnsFd),
 	)
 
 	if err != nil {
 		return ErrCollect{Err: fmt.Errorf("failed to create trace-watcher: %v", err)}
 	}
 
+	c.nlWatcher.Store(nlWatcher)
 	log := logger.FromContext(ctx).Named("collector")
-	log.Info("start")
+	if c.bpfProg != nil {
+		log.Infof("start with filter '%s'", c.filter)
+	} else {
+		log.Info("start")
+	}
 	defer func() {
 		log.Info("stop")
 		nlWatcher.Close()
@@ -96,7 +156,7 @@ func (c *traceCollectorImpl) Run(ctx context.Context) (err error) {
 
 			if err != nil {
 				if errors.Is(err, nl.ErrNlMem) {
-					c.agentSubject.Notify(CountCollectNlErrMemEvent{})
+					c.agentSubject.Notify(CountCollectNlErrMemEvent{Lost: nlData.Lost})
 					continue
 				}
 				if errors.Is(err, nl.ErrNlDataNotReady) ||
@@ -132,19 +192,41 @@ func (c *traceCollectorImpl) Run(ctx context.Context) (err error) {
 	}
 }
 
+// LastPoll impl TraceCollector
+func (c *traceCollectorImpl) LastPoll() time.Time {
+	if w, ok := c.nlWatcher.Load().(nl.NetlinkWatcher); ok {
+		return nl.LastPollOf(w)
+	}
+	return time.Time{}
+}
+
 func (c *traceCollectorImpl) Reader() <-chan []model.NetlinkTrace {
 	return c.que.Reader()
 }
 
-// Close collector
+// Drain stops collecting, traces collected yet are left in the queue until they are read
+// and then the reader is closed
+func (c *traceCollectorImpl) Drain() error {
+	c.stopRun()
+	c.que.Seal()
+	return nil
+}
+
+// Close collector, traces left in the queue are abandoned
 func (c *traceCollectorImpl) Close() error {
 	c.onceClose.Do(func() {
+		c.stopRun()
+		NotifyAbandoned(c.agentSubject, AbandonCollector, CountMessages(c.que.Discard()))
+	})
+	return nil
+}
+
+func (c *traceCollectorImpl) stopRun() {
+	c.onceStop.Do(func() {
 		close(c.stop)
 		c.onceRun.Do(func() {})
 		if c.stopped != nil {
 			<-c.stopped
 		}
-		_ = c.que.Close()
 	})
-	return nil
 }
@@ -18,6 +18,9 @@ type NftnlTrace struct {
 	Lh         nlheaders.LlHeader
 	Nh         nlheaders.NlHeader
 	Th         nlheaders.TlHeader
+	RawLh      []byte
+	RawNh      []byte
+	RawTh      []byte
 	Family     byte
 	Type       uint32
 	Id         uint32
@@ -41,6 +44,9 @@ func (tr *NftnlTrace) ToModel() model.NetlinkTrace {
 		Lh:         tr.Lh,
 		Nh:         tr.Nh,
 		Th:         tr.Th,
+		RawLh:      tr.RawLh,
+		RawNh:      tr.RawNh,
+		RawTh:      tr.RawTh,
 		Family:     model.FamilyTable(tr.Family),
 		Type:       tr.Type,
 		Id:         tr.Id,
@@ -53,6 +59,7 @@ func (tr *NftnlTrace) ToModel() model.NetlinkTrace {
 		Iiftype:    tr.Iiftype,
 		Oiftype:    tr.Oiftype,
 		Flags:      tr.Flags,
+		Source:     model.SourceNftrace,
 	}
 }
 
@@ -115,17 +122,20 @@ func (tr *NftnlTrace) InitFromMsg(msg netlink.Message) error {
 			tr.RuleHandle = ad.Uint64()
 			tr.Flags |= (1 << NFTNL_TRACE_RULE_HANDLE)
 		case unix.NFTA_TRACE_LL_HEADER:
-			if err := tr.Lh.Decode(ad.Bytes()); err != nil {
+			tr.RawLh = ad.Bytes()
+			if err := tr.Lh.Decode(tr.RawLh); err != nil {
 				return err
 			}
 			tr.Flags |= (1 << NFTNL_TRACE_LL_HEADER)
 		case unix.NFTA_TRACE_NETWORK_HEADER:
-			if err := tr.Nh.Decode(ad.Bytes()); err != nil {
+			tr.RawNh = ad.Bytes()
+			if err := tr.Nh.Decode(tr.RawNh); err != nil {
 				return err
 			}
 			tr.Flags |= (1 << NFTNL_TRACE_NETWORK_HEADER)
 		case unix.NFTA_TRACE_TRANSPORT_HEADER:
-			if err := tr.Th.Decode(ad.Bytes()); err != nil {
+			tr.RawTh = ad.Bytes()
+			if err := tr.Th.Decode(tr.RawTh); err != nil {
 				return err
 			}
 			tr.Flags |= (1 << NFTNL_TRACE_TRANSPORT_HEADER)
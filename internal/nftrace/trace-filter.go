@@ -0,0 +1,298 @@
+package nftrace
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/mdlayher/netlink/nlenc"
+	"github.com/pkg/errors"
+	"golang.org/x/net/bpf"
+	"golang.org/x/sys/unix"
+)
+
+// FilterSpec - spec of the classic BPF filter attached to the collector socket,
+// kernel drops nftrace messages rejected by the filter before they reach userspace.
+// Zero value of a field means no restriction on it
+type FilterSpec struct {
+	Family uint8    // nfgenmsg family of the traced table (NFPROTO_*)
+	Tables []string // names of the traced tables
+	Types  []uint32 // trace types (NFT_TRACETYPE_*)
+}
+
+const (
+	// offset of the nfgenmsg family within netlink message
+	nfgenFamilyOff = unix.NLMSG_HDRLEN
+	// offset of the first attribute within netlink message
+	nfgenAttrsOff = unix.NLMSG_HDRLEN + 4 // sizeof(struct nfgenmsg)
+	// netlink attribute header length
+	nlaHdrLen = unix.SizeofNlAttr
+	// max length of the table name (NFT_NAME_MAXLEN without NUL)
+	tableNameMaxLen = 255
+	// max number of instructions in the classic BPF program (BPF_MAXINSNS)
+	bpfMaxInsns = 4096
+
+	retAccept = math.MaxUint32
+	retDrop   = 0
+)
+
+var filterFamilies = map[string]uint8{
+	"ip":     unix.NFPROTO_IPV4,
+	"ip6":    unix.NFPROTO_IPV6,
+	"inet":   unix.NFPROTO_INET,
+	"arp":    unix.NFPROTO_ARP,
+	"bridge": unix.NFPROTO_BRIDGE,
+	"netdev": unix.NFPROTO_NETDEV,
+}
+
+var filterTypes = map[string]uint32{
+	"rule":   unix.NFT_TRACETYPE_RULE,
+	"return": unix.NFT_TRACETYPE_RETURN,
+	"policy": unix.NFT_TRACETYPE_POLICY,
+}
+
+// ParseFilterSpec - build filter spec from its textual parts: family name ("ip", "inet", ...),
+// comma separated table names and comma separated trace types ("rule", "return", "policy").
+// Empty part means no restriction. Types must have "rule" and "policy": trace is completed by
+// the accept or drop decision of the rule or the policy, so traces without them are never merged
+func ParseFilterSpec(family, tables, types string) (spec FilterSpec, err error) {
+	if family = strings.TrimSpace(family); family != "" {
+		var ok bool
+		if spec.Family, ok = filterFamilies[family]; !ok {
+			return spec, errors.Errorf("unknown table family '%s'", family)
+		}
+	}
+	for _, t := range splitList(tables) {
+		if len(t) > tableNameMaxLen {
+			return spec, errors.Errorf("table name '%s' is too long", t)
+		}
+		spec.Tables = append(spec.Tables, t)
+	}
+	for _, t := range splitList(types) {
+		tp, ok := filterTypes[t]
+		if !ok {
+			return spec, errors.Errorf("unknown trace type '%s'", t)
+		}
+		spec.Types = append(spec.Types, tp)
+	}
+	if len(spec.Types) > 0 &&
+		!(slices.Contains(spec.Types, unix.NFT_TRACETYPE_RULE) && slices.Contains(spec.Types, unix.NFT_TRACETYPE_POLICY)) {
+		return spec, errors.Errorf("trace types '%s' must include 'rule' and 'policy' to complete traces", types)
+	}
+	return spec, nil
+}
+
+// IsEmpty - filter passes all messages
+func (f FilterSpec) IsEmpty() bool {
+	return f.Family == 0 && len(f.Tables) == 0 && len(f.Types) == 0
+}
+
+// String -
+func (f FilterSpec) String() string {
+	var parts []string
+	if f.Family != 0 {
+		parts = append(parts, "family="+lookupName(filterFamilies, f.Family))
+	}
+	if len(f.Tables) > 0 {
+		parts = append(parts, "tables="+strings.Join(f.Tables, ","))
+	}
+	if len(f.Types) > 0 {
+		types := make([]string, 0, len(f.Types))
+		for _, tp := range f.Types {
+			types = append(types, lookupName(filterTypes, tp))
+		}
+		parts = append(parts, "types="+strings.Join(types, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Assemble - compile filter spec into the classic BPF program.
+// Messages carrying packet headers always pass the trace type check,
+// because only the first trace message of the packet holds them and merger needs them
+func (f FilterSpec) Assemble() ([]bpf.RawInstruction, error) {
+	var p progBuilder
+	if f.Family != 0 {
+		p.emit(bpf.LoadAbsolute{Off: nfgenFamilyOff, Size: 1})
+		p.dropUnless(bpf.JumpEqual, uint32(f.Family))
+	}
+	if len(f.Types) > 0 {
+		p.findAttr(unix.NFTA_TRACE_NETWORK_HEADER)
+		p.jumpIf(bpf.JumpNotEqual, 0, "types-end", "")
+		p.findAttr(unix.NFTA_TRACE_TYPE)
+		p.dropUnless(bpf.JumpNotEqual, 0)
+		p.emit(bpf.TAX{})
+		p.emit(bpf.LoadIndirect{Off: nlaHdrLen, Size: 4})
+		for _, tp := range f.Types {
+			p.jumpIf(bpf.JumpEqual, tp, "types-end", "")
+		}
+		p.jump(lblDrop)
+		p.label("types-end")
+	}
+	if len(f.Tables) > 0 {
+		p.findAttr(unix.NFTA_TRACE_TABLE)
+		p.dropUnless(bpf.JumpNotEqual, 0)
+		p.emit(bpf.TAX{})
+		for i, name := range f.Tables {
+			next := fmt.Sprintf("table-%d", i+1)
+			if i == len(f.Tables)-1 {
+				next = lblDrop
+			}
+			p.matchString(name, next)
+			p.jump(lblAccept)
+			p.label(next)
+		}
+	}
+	p.label(lblAccept)
+	p.emit(bpf.RetConstant{Val: retAccept})
+	p.label(lblDrop)
+	p.emit(bpf.RetConstant{Val: retDrop})
+
+	prog, err := p.build()
+	if err != nil {
+		return nil, err
+	}
+	return bpf.Assemble(prog)
+}
+
+const (
+	lblAccept = "accept"
+	lblDrop   = "drop"
+)
+
+type (
+	// progBuilder - assembles BPF program with symbolic jump targets
+	progBuilder struct {
+		prog   []bpf.Instruction
+		labels map[string]int
+		fixups []jumpFixup
+	}
+	jumpFixup struct {
+		pc          int
+		jt, jf, jmp string
+	}
+)
+
+func (p *progBuilder) emit(ins ...bpf.Instruction) {
+	p.prog = append(p.prog, ins...)
+}
+
+func (p *progBuilder) label(name string) {
+	if p.labels == nil {
+		p.labels = make(map[string]int)
+	}
+	p.labels[name] = len(p.prog)
+}
+
+// jumpIf - conditional jump, empty label means next instruction
+func (p *progBuilder) jumpIf(cond bpf.JumpTest, val uint32, jt, jf string) {
+	p.fixups = append(p.fixups, jumpFixup{pc: len(p.prog), jt: jt, jf: jf})
+	p.emit(bpf.JumpIf{Cond: cond, Val: val})
+}
+
+// dropUnless - drop message unless the condition is true, far jump keeps it independent of the program size
+func (p *progBuilder) dropUnless(cond bpf.JumpTest, val uint32) {
+	p.emit(bpf.JumpIf{Cond: cond, Val: val, SkipTrue: 1})
+	p.jump(lblDrop)
+}
+
+func (p *progBuilder) jump(to string) {
+	p.fixups = append(p.fixups, jumpFixup{pc: len(p.prog), jmp: to})
+	p.emit(bpf.Jump{})
+}
+
+// findAttr - load offset of the top level attribute into A, zero if it is absent
+func (p *progBuilder) findAttr(attr uint32) {
+	p.emit(
+		bpf.LoadConstant{Dst: bpf.RegA, Val: nfgenAttrsOff},
+		bpf.LoadConstant{Dst: bpf.RegX, Val: attr},
+		bpf.LoadExtension{Num: bpf.ExtNetlinkAttr},
+	)
+}
+
+// matchString - compare NUL terminated string attribute at offset X with the value,
+// jumps to the label on mismatch
+func (p *progBuilder) matchString(s, mismatch string) {
+	data := append([]byte(s), 0)
+	// nla_len is in host byte order while BPF loads are big endian
+	nlaLen := nlenc.Uint16Bytes(uint16(nlaHdrLen + len(data)))
+	p.emit(bpf.LoadIndirect{Off: 0, Size: 2})
+	p.jumpIf(bpf.JumpEqual, uint32(binary.BigEndian.Uint16(nlaLen)), "", mismatch)
+	for off := 0; off < len(data); {
+		var val uint32
+		size := 4
+		switch rest := len(data) - off; {
+		case rest >= 4:
+			val = binary.BigEndian.Uint32(data[off:])
+		case rest >= 2:
+			size = 2
+			val = uint32(binary.BigEndian.Uint16(data[off:]))
+		default:
+			size = 1
+			val = uint32(data[off])
+		}
+		p.emit(bpf.LoadIndirect{Off: uint32(nlaHdrLen + off), Size: size})
+		p.jumpIf(bpf.JumpEqual, val, "", mismatch)
+		off += size
+	}
+}
+
+func (p *progBuilder) build() ([]bpf.Instruction, error) {
+	target := func(pc int, lbl string) (int, error) {
+		if lbl == "" {
+			return 0, nil
+		}
+		to, ok := p.labels[lbl]
+		if !ok {
+			return 0, errors.Errorf("undefined label '%s'", lbl)
+		}
+		return to - pc - 1, nil
+	}
+	for _, fx := range p.fixups {
+		if fx.jmp != "" {
+			skip, err := target(fx.pc, fx.jmp)
+			if err != nil {
+				return nil, err
+			}
+			p.prog[fx.pc] = bpf.Jump{Skip: uint32(skip)}
+			continue
+		}
+		jt, err := target(fx.pc, fx.jt)
+		if err != nil {
+			return nil, err
+		}
+		jf, err := target(fx.pc, fx.jf)
+		if err != nil {
+			return nil, err
+		}
+		if jt > math.MaxUint8 || jf > math.MaxUint8 {
+			return nil, errors.New("conditional jump is out of range")
+		}
+		ins := p.prog[fx.pc].(bpf.JumpIf)
+		ins.SkipTrue, ins.SkipFalse = uint8(jt), uint8(jf)
+		p.prog[fx.pc] = ins
+	}
+	if len(p.prog) > bpfMaxInsns {
+		return nil, errors.Errorf("filter is too large (%d instructions), reduce the number of tables", len(p.prog))
+	}
+	return p.prog, nil
+}
+
+func splitList(s string) (ret []string) {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func lookupName[T comparable](names map[string]T, v T) string {
+	for name, val := range names {
+		if val == v {
+			return name
+		}
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,257 @@
+package nftrace
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/nl"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// userSockGroup - multicast group of the NETLINK_USERSOCK protocol used to run filters in kernel
+const userSockGroup = 1
+
+type traceMsgSpec struct {
+	id      uint32
+	family  uint8
+	table   string
+	tp      uint32
+	headers bool
+}
+
+// traceMsg - encode nftrace message as kernel does it
+func traceMsg(t testing.TB, spec traceMsgSpec) []byte {
+	ae := netlink.NewAttributeEncoder()
+	ae.ByteOrder = binary.BigEndian
+	ae.Uint32(unix.NFTA_TRACE_ID, spec.id)
+	ae.Uint32(unix.NFTA_TRACE_TYPE, spec.tp)
+	ae.String(unix.NFTA_TRACE_TABLE, spec.table)
+	ae.String(unix.NFTA_TRACE_CHAIN, "input")
+	ae.Uint32(unix.NFTA_TRACE_NFPROTO, unix.NFPROTO_IPV4)
+	ae.Nested(unix.NFTA_TRACE_VERDICT, func(nae *netlink.AttributeEncoder) error {
+		nae.Uint32(unix.NFTA_VERDICT_CODE, NF_ACCEPT)
+		return nil
+	})
+	if spec.headers {
+		ae.Uint32(unix.NFTA_TRACE_IIF, 1)
+		ae.Uint16(unix.NFTA_TRACE_IIFTYPE, 1)
+		ae.Bytes(unix.NFTA_TRACE_LL_HEADER, make([]byte, 14))
+		ae.Bytes(unix.NFTA_TRACE_NETWORK_HEADER, []byte{
+			0x45, 0, 0, 60, 0, 0, 0x40, 0, 64, 6, 0, 0, 10, 0, 0, 1, 10, 0, 0, 2,
+		})
+		ae.Bytes(unix.NFTA_TRACE_TRANSPORT_HEADER, make([]byte, 20))
+	}
+	attrs, err := ae.Encode()
+	require.NoError(t, err)
+
+	data := append([]byte{spec.family, unix.NFNETLINK_V0, 0, 0}, attrs...)
+	msg := make([]byte, unix.NLMSG_HDRLEN, unix.NLMSG_HDRLEN+len(data))
+	nlenc.PutUint32(msg[0:4], uint32(unix.NLMSG_HDRLEN+len(data)))
+	nlenc.PutUint16(msg[4:6], unix.NFNL_SUBSYS_NFTABLES<<8|unix.NFT_MSG_TRACE)
+	return append(msg, data...)
+}
+
+// filteredWatcher - netlink watcher with the filter which receives messages sent by sender
+type filteredWatcher struct {
+	nl.NetlinkWatcher
+	sender int
+}
+
+func newFilteredWatcher(t testing.TB, spec FilterSpec) *filteredWatcher {
+	prog, err := spec.Assemble()
+	require.NoError(t, err)
+	w, err := nl.NewNetlinkWatcher(1, unix.NETLINK_USERSOCK,
+		nl.SkWithBufLen(nl.SockBufLen16MB),
+		nl.SkWithBPF(prog),
+		nl.SkWithNlMs(userSockGroup),
+		nl.NlWithTimeout(&unix.Timeval{Usec: 100000}),
+	)
+	if err != nil {
+		t.Skipf("netlink socket is not available: %v", err)
+	}
+	sender, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW, unix.NETLINK_USERSOCK)
+	if err != nil {
+		_ = w.Close()
+		t.Skipf("netlink socket is not available: %v", err)
+	}
+	return &filteredWatcher{NetlinkWatcher: w, sender: sender}
+}
+
+func (w *filteredWatcher) Close() error {
+	_ = unix.Close(w.sender)
+	return w.NetlinkWatcher.Close()
+}
+
+func (w *filteredWatcher) send(t testing.TB, msg []byte) {
+	err := unix.Sendto(w.sender, msg, 0, &unix.SockaddrNetlink{
+		Family: unix.AF_NETLINK,
+		Groups: 1 << (userSockGroup - 1),
+	})
+	// message is broadcasted yet, refusal comes from the absent kernel socket of the NETLINK_USERSOCK
+	if !errors.Is(err, unix.ECONNREFUSED) {
+		require.NoError(t, err)
+	}
+}
+
+// receive - decode received traces until the trace with the last id comes
+func (w *filteredWatcher) receive(t testing.TB, last uint32) (ids []uint32) {
+	reader := w.Reader(0)
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			require.FailNow(t, "timeout waiting for traces")
+		case nlData := <-reader.Read():
+			if nlData.Err != nil {
+				if errors.Is(nlData.Err, nl.ErrNlDataNotReady) ||
+					errors.Is(nlData.Err, nl.ErrNlReadInterrupted) {
+					continue
+				}
+				require.NoError(t, nlData.Err)
+			}
+			for _, msg := range nlData.Messages {
+				id := decodeTraceId(t, msg)
+				ids = append(ids, id)
+				if id == last {
+					return ids
+				}
+			}
+		}
+	}
+}
+
+func decodeTraceId(t testing.TB, msg syscall.NetlinkMessage) uint32 {
+	tr := new(NftnlTrace)
+	err := tr.InitFromMsg(netlink.Message{Data: msg.Data})
+	require.NoError(t, err)
+	_ = tr.ToModel()
+	return tr.Id
+}
+
+func Test_FilterSpecParse(t *testing.T) {
+	spec, err := ParseFilterSpec("inet", "filter, nat,", "rule,policy")
+	require.NoError(t, err)
+	require.Equal(t, FilterSpec{
+		Family: unix.NFPROTO_INET,
+		Tables: []string{"filter", "nat"},
+		Types:  []uint32{unix.NFT_TRACETYPE_RULE, unix.NFT_TRACETYPE_POLICY},
+	}, spec)
+	require.Equal(t, "family=inet tables=filter,nat types=rule,policy", spec.String())
+
+	spec, err = ParseFilterSpec("", "", "")
+	require.NoError(t, err)
+	require.True(t, spec.IsEmpty())
+
+	_, err = ParseFilterSpec("ipx", "", "")
+	require.Error(t, err)
+	_, err = ParseFilterSpec("", "", "drop")
+	require.Error(t, err)
+	// traces would never be completed
+	for _, types := range []string{"rule", "return", "policy,return"} {
+		_, err = ParseFilterSpec("", "", types)
+		require.Error(t, err, types)
+	}
+	_, err = ParseFilterSpec("", "", "return,policy,rule")
+	require.NoError(t, err)
+	_, err = ParseFilterSpec("", strings.Repeat("t", tableNameMaxLen+1), "")
+	require.Error(t, err)
+}
+
+func Test_FilterSpecAssemble(t *testing.T) {
+	prog, err := FilterSpec{}.Assemble()
+	require.NoError(t, err)
+	require.Len(t, prog, 2)
+
+	tables := make([]string, 300)
+	for i := range tables {
+		tables[i] = strings.Repeat("t", 64)
+	}
+	_, err = FilterSpec{Tables: tables[:100]}.Assemble()
+	require.NoError(t, err)
+	_, err = FilterSpec{Tables: tables}.Assemble()
+	require.Error(t, err)
+}
+
+func Test_FilterInKernel(t *testing.T) {
+	w := newFilteredWatcher(t, FilterSpec{
+		Family: unix.NFPROTO_INET,
+		Tables: []string{"filter", "sg-rules-v1"},
+		Types:  []uint32{unix.NFT_TRACETYPE_POLICY},
+	})
+	defer w.Close()
+
+	msgs := []struct {
+		traceMsgSpec
+		pass bool
+	}{
+		{traceMsgSpec{id: 1, family: unix.NFPROTO_INET, table: "filter", tp: unix.NFT_TRACETYPE_POLICY}, true},
+		{traceMsgSpec{id: 2, family: unix.NFPROTO_IPV4, table: "filter", tp: unix.NFT_TRACETYPE_POLICY}, false},
+		{traceMsgSpec{id: 3, family: unix.NFPROTO_INET, table: "filter", tp: unix.NFT_TRACETYPE_RULE}, false},
+		{traceMsgSpec{id: 4, family: unix.NFPROTO_INET, table: "filter", tp: unix.NFT_TRACETYPE_RULE, headers: true}, true},
+		{traceMsgSpec{id: 5, family: unix.NFPROTO_INET, table: "filte", tp: unix.NFT_TRACETYPE_POLICY}, false},
+		{traceMsgSpec{id: 6, family: unix.NFPROTO_INET, table: "filter1", tp: unix.NFT_TRACETYPE_POLICY}, false},
+		{traceMsgSpec{id: 7, family: unix.NFPROTO_INET, table: "sg-rules-v1", tp: unix.NFT_TRACETYPE_POLICY}, true},
+		{traceMsgSpec{id: 8, family: unix.NFPROTO_INET, table: "sg-rules-v2", tp: unix.NFT_TRACETYPE_POLICY}, false},
+		{traceMsgSpec{id: 9, family: unix.NFPROTO_INET, table: "nat", tp: unix.NFT_TRACETYPE_POLICY, headers: true}, false},
+		{traceMsgSpec{id: 10, family: unix.NFPROTO_INET, table: "sg-rules-v1", tp: unix.NFT_TRACETYPE_POLICY, headers: true}, true},
+	}
+	var exp []uint32
+	for _, m := range msgs {
+		w.send(t, traceMsg(t, m.traceMsgSpec))
+		if m.pass {
+			exp = append(exp, m.id)
+		}
+	}
+	require.Equal(t, exp, w.receive(t, exp[len(exp)-1]))
+}
+
+// BenchmarkCollectorFilter - receive and decode batch of traces where only part of them is interesting,
+// with the filter kernel drops the rest before they reach userspace
+func BenchmarkCollectorFilter(b *testing.B) {
+	const (
+		batchSize = 100
+		matchEach = 10
+	)
+	batch := make([][]byte, batchSize)
+	for i := range batch {
+		spec := traceMsgSpec{
+			id:      uint32(i + 1),
+			family:  unix.NFPROTO_INET,
+			table:   "nat",
+			tp:      unix.NFT_TRACETYPE_RULE,
+			headers: true,
+		}
+		if (i+1)%matchEach == 0 {
+			spec.table = "filter"
+		}
+		batch[i] = traceMsg(b, spec)
+	}
+	for _, bm := range []struct {
+		name string
+		spec FilterSpec
+	}{
+		{"no-filter", FilterSpec{}},
+		{"filter", FilterSpec{Tables: []string{"filter"}}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			w := newFilteredWatcher(b, bm.spec)
+			defer w.Close()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, msg := range batch {
+					w.send(b, msg)
+				}
+				w.receive(b, batchSize)
+			}
+		})
+	}
+}
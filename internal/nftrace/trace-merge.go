@@ -4,21 +4,31 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/wildberries-tech/pkt-tracer/internal/bqueue"
+	"github.com/wildberries-tech/pkt-tracer/internal/conntrack"
 	nl "github.com/wildberries-tech/pkt-tracer/internal/models/nltrace"
 	"github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
 	"github.com/wildberries-tech/pkt-tracer/internal/nfrule"
+	cmeta "github.com/wildberries-tech/pkt-tracer/internal/providers/container-meta"
+	procowner "github.com/wildberries-tech/pkt-tracer/internal/providers/proc-owner"
 	sgnw "github.com/wildberries-tech/pkt-tracer/internal/providers/sg-network"
 
 	"github.com/H-BF/corlib/logger"
-	"github.com/H-BF/corlib/pkg/queue"
+	"github.com/H-BF/corlib/pkg/patterns/observer"
 	"github.com/pkg/errors"
 	"golang.org/x/sys/unix"
 )
 
 type TraceArray []*NftnlTrace
 
+// verdictLog - verdict of the packet logged by the nflog source, log rules do not decide fate of packets
+const verdictLog = "log"
+
 type MergedTrace struct {
 	TrId       uint32
 	Table      string
@@ -107,6 +117,7 @@ type traceDecision struct {
 	tr            *nl.NetlinkTrace
 	verdictCache  map[uint32]bool
 	decisionChain []decision
+	updated       time.Time // time the last message of the trace has been merged
 }
 
 func (t *traceDecision) addDecision(d decision) {
@@ -128,10 +139,31 @@ func (t *traceDecision) iterate(fn func(d decision) bool) {
 	}
 }
 
+// traceVerdict - verdict made by decisions of the trace, final is the last decision
+// which has accepted or dropped the packet
+type traceVerdict struct {
+	str   string
+	final decision
+}
+
+func (t *traceDecision) verdict() (v traceVerdict) {
+	t.iterate(func(d decision) bool {
+		v.str += d.getVerdict()
+		if d.value == NF_ACCEPT || d.value == NF_DROP {
+			v.final = d
+		}
+		return true
+	})
+	return v
+}
+
 type (
 	TraceMerger interface {
 		Run(ctx context.Context) error
 		Reader() <-chan trace.TraceModel
+		// Drain - flush traces left in the merge buffer and finish as soon as the collector
+		// has been drained, the reader is closed after the last trace
+		Drain() error
 		Close() error
 	}
 	traceCollector interface {
@@ -150,32 +182,177 @@ type (
 		GetSGByIP(net.IP) (sgnw.SgNet, error)
 	}
 
+	sgRuleProviderFace interface {
+		GetSgRule(p sgnw.Packet, action string) (sgnw.SgRule, error)
+	}
+
+	containerProviderFace interface {
+		GetContainer(ns netns.NetNS, ifaces ...string) (cmeta.Container, error)
+	}
+
+	processProviderFace interface {
+		GetProcess(c procowner.Conn) (procowner.Process, error)
+	}
+
+	conntrackProviderFace interface {
+		Find(t conntrack.Tuple) (conntrack.Match, error)
+	}
+
 	traceMergeImpl struct {
 		collector     traceCollector
 		ifTracer      iface
 		ruler         ruleTracer
 		sgNetProvider sgNetProviderFace
+		sgRules       sgRuleProviderFace
+		sgRuleScope   sgnw.RuleScope
+		containers    containerProviderFace
+		processes     processProviderFace
+		conntrack     conntrackProviderFace
+		netns         netns.NetNS
+		rawHeaders    bool
+		agentSubject  observer.Subject
 		mergeBuf      map[uint32]*traceDecision
-		que           queue.FIFO[trace.TraceModel]
+		mergeBufLen   int       // size of the merge buffer reported yet
+		expiredAt     time.Time // time the merge buffer has been checked for expired traces last time
+		pending       []pendingTrace
+		queSettings   bqueue.Settings
+		que           *bqueue.FIFO[trace.TraceModel]
 		onceRun       sync.Once
+		onceDrain     sync.Once
 		onceClose     sync.Once
+		drain         chan struct{}
 		stop          chan struct{}
 		stopped       chan struct{}
 	}
+
+	// pendingTrace - complete trace waiting for its rule to be known
+	pendingTrace struct {
+		trD     *traceDecision
+		verdict traceVerdict
+		until   time.Time
+	}
+
+	// MergeBufSizeEvent - size of the merge buffer has changed by Delta
+	MergeBufSizeEvent struct {
+		observer.EventType
+		Delta int
+	}
+
+	// RuleLookupEvent - rule of the complete trace has been looked up in the rule cache,
+	// retries of the pending traces are not counted
+	RuleLookupEvent struct {
+		observer.EventType
+		Hit bool
+	}
+
+	// RuleTimeoutEvent - rule of the complete trace has not come in time,
+	// the trace is sent with the unknown rule
+	RuleTimeoutEvent struct {
+		observer.EventType
+	}
+
+	// TraceMergeOpt - option of the trace merger
+	TraceMergeOpt func(*traceMergeImpl)
+)
+
+const (
+	// ruleWaitTimeout - how long complete trace waits for its rule event
+	ruleWaitTimeout = 3 * time.Second
+	// ruleWaitRetryInterval - interval to retry resolving rules of the pending traces
+	ruleWaitRetryInterval = 100 * time.Millisecond
+	// mergeBufTTL - incomplete trace is abandoned when no messages of it come for the time,
+	// e.g. the rest of messages are dropped by the full queue or filtered out
+	mergeBufTTL = 10 * time.Second
+	// mergeBufExpireInterval - interval to check the merge buffer for expired traces
+	mergeBufExpireInterval = time.Second
 )
 
 var _ TraceMerger = (*traceMergeImpl)(nil)
 
-func NewTraceMerge(col traceCollector, ift iface, rl ruleTracer, sgc sgNetProviderFace) TraceMerger {
-	return &traceMergeImpl{
+// MergeWithNetNS - mark merged traces with the network namespace identifier
+func MergeWithNetNS(ns netns.NetNS) TraceMergeOpt {
+	return func(t *traceMergeImpl) {
+		t.netns = ns
+	}
+}
+
+// MergeWithSgRules - resolve verdict of the trace to the sgroups rule which has made it
+func MergeWithSgRules(p sgRuleProviderFace) TraceMergeOpt {
+	return func(t *traceMergeImpl) {
+		t.sgRules = p
+	}
+}
+
+// MergeWithSgRuleScope - resolve to sgroups rules only verdicts made in tables and chains of the scope,
+// verdicts of the rest of nftables are not made by sgroups
+func MergeWithSgRuleScope(s sgnw.RuleScope) TraceMergeOpt {
+	return func(t *traceMergeImpl) {
+		t.sgRuleScope = s
+	}
+}
+
+// MergeWithContainers - attach metadata of the container owning interfaces
+// or network namespace of the trace
+func MergeWithContainers(p containerProviderFace) TraceMergeOpt {
+	return func(t *traceMergeImpl) {
+		t.containers = p
+	}
+}
+
+// MergeWithProcesses - attach local process owning socket of the traced packet
+func MergeWithProcesses(p processProviderFace) TraceMergeOpt {
+	return func(t *traceMergeImpl) {
+		t.processes = p
+	}
+}
+
+// MergeWithConntrack - attach state of the connection the traced packet belongs to
+func MergeWithConntrack(p conntrackProviderFace) TraceMergeOpt {
+	return func(t *traceMergeImpl) {
+		t.conntrack = p
+	}
+}
+
+// MergeWithRawHeaders - keep raw bytes of the packet headers in merged traces
+func MergeWithRawHeaders() TraceMergeOpt {
+	return func(t *traceMergeImpl) {
+		t.rawHeaders = true
+	}
+}
+
+// MergeWithAgentSubject - notify subject on merge statistics
+func MergeWithAgentSubject(as observer.Subject) TraceMergeOpt {
+	return func(t *traceMergeImpl) {
+		t.agentSubject = as
+	}
+}
+
+// MergeWithQueue - bound the queue of merged traces, traces of dropped packets
+// are kept by the PreferDrops policy
+func MergeWithQueue(s bqueue.Settings) TraceMergeOpt {
+	return func(t *traceMergeImpl) {
+		t.queSettings = s
+	}
+}
+
+func NewTraceMerge(col traceCollector, ift iface, rl ruleTracer, sgc sgNetProviderFace, opts ...TraceMergeOpt) TraceMerger {
+	t := &traceMergeImpl{
 		collector:     col,
 		ifTracer:      ift,
 		ruler:         rl,
 		sgNetProvider: sgc,
 		mergeBuf:      make(map[uint32]*traceDecision),
-		que:           queue.NewFIFO[trace.TraceModel](),
+		drain:         make(chan struct{}),
 		stop:          make(chan struct{}),
 	}
+	for _, o := range opts {
+		o(t)
+	}
+	t.que = bqueue.NewFIFO(
+		bqueue.WithSettings[trace.TraceModel](t.queSettings),
+		bqueue.WithPriority(IsDropped),
+	)
+	return t
 }
 
 func (t *traceMergeImpl) Run(ctx context.Context) (err error) {
@@ -189,13 +366,17 @@ func (t *traceMergeImpl) Run(ctx context.Context) (err error) {
 	}
 
 	log := logger.FromContext(ctx).Named("merger")
+	ctx1 := logger.ToContext(ctx, log)
 	log.Info("start")
 	defer func() {
+		t.notify(MergeBufSizeEvent{Delta: -t.mergeBufLen})
 		log.Info("stop")
 		close(t.stopped)
 	}()
 
 	que := t.collector.Reader()
+	retry := time.NewTicker(ruleWaitRetryInterval)
+	defer retry.Stop()
 
 	for {
 		select {
@@ -207,14 +388,21 @@ func (t *traceMergeImpl) Run(ctx context.Context) (err error) {
 			return nil
 		case traces, ok := <-que:
 			if !ok {
+				select {
+				case <-t.drain:
+					log.Info("will exit cause trace collector has been drained")
+					t.flush(ctx1)
+					t.que.Seal()
+					return nil
+				default:
+				}
 				log.Info("will exit cause trace collector queue channel has closed")
 				return ErrMerge{Err: errors.New("trace collector queue channel has closed")}
 			}
 			for _, tr := range traces {
 				msg, err := t.prepareTraceMsg(tr)
 				if err != nil {
-					if errors.Is(err, ErrTraceDataNotReady) ||
-						errors.Is(err, nfrule.ErrExpiredTrace) {
+					if errors.Is(err, ErrTraceDataNotReady) {
 						continue //skip error
 					}
 
@@ -222,16 +410,38 @@ func (t *traceMergeImpl) Run(ctx context.Context) (err error) {
 				}
 				t.que.Put(msg)
 			}
+		case <-retry.C:
+			if err = t.resolvePending(ctx1); err != nil {
+				return ErrMerge{Err: fmt.Errorf("failed to add trace msg: %v", err)}
+			}
+			if now := time.Now(); now.Sub(t.expiredAt) >= mergeBufExpireInterval {
+				t.expireMergeBuf(now)
+				t.expiredAt = now
+			}
+			if n := len(t.mergeBuf); n != t.mergeBufLen {
+				t.notify(MergeBufSizeEvent{Delta: n - t.mergeBufLen})
+				t.mergeBufLen = n
+			}
 		}
 	}
 }
 
+func (t *traceMergeImpl) notify(ev observer.EventType) {
+	if t.agentSubject != nil {
+		t.agentSubject.Notify(ev)
+	}
+}
+
 // Reader return prepared trace from the queue
 func (t *traceMergeImpl) Reader() <-chan trace.TraceModel {
 	return t.que.Reader()
 }
 
 func (t *traceMergeImpl) prepareTraceMsg(tr nl.NetlinkTrace) (msg trace.TraceModel, err error) {
+	if tr.Source == nl.SourceNflog {
+		// logged packet is the complete trace, there are no decisions to merge
+		return t.makeTraceMsg(&tr, traceVerdict{str: verdictLog}, logRuleStr(&tr))
+	}
 	trD := t.mergeBuf[tr.Id]
 	if trD == nil {
 		trD = &traceDecision{
@@ -239,6 +449,7 @@ func (t *traceMergeImpl) prepareTraceMsg(tr nl.NetlinkTrace) (msg trace.TraceMod
 		}
 		t.mergeBuf[tr.Id] = trD
 	}
+	trD.updated = time.Now()
 
 	if (tr.Flags&(1<<NFTNL_TRACE_LL_HEADER))|
 		(tr.Flags&(1<<NFTNL_TRACE_NETWORK_HEADER)) != 0 {
@@ -272,33 +483,134 @@ func (t *traceMergeImpl) prepareTraceMsg(tr nl.NetlinkTrace) (msg trace.TraceMod
 	if !trD.isReady() {
 		return msg, ErrTraceDataNotReady
 	}
-	var verdict string
-	trD.iterate(func(d decision) bool {
-		verdict += d.getVerdict()
-		return true
-	})
+	verdict := trD.verdict()
 
+	// trace is complete, it will not be merged anymore
+	delete(t.mergeBuf, tr.Id)
 	re, err := t.ruler.GetRuleForTrace(trD.tr)
+	t.notify(RuleLookupEvent{Hit: err == nil})
+	if errors.Is(err, nfrule.ErrNotFoundRule) {
+		// rule event may be not handled yet, so the trace waits for it
+		t.pending = append(t.pending, pendingTrace{
+			trD:     trD,
+			verdict: verdict,
+			until:   time.Now().Add(ruleWaitTimeout),
+		})
+		return msg, ErrTraceDataNotReady
+	}
 	if err != nil {
 		return msg, err
 	}
+	return t.makeTraceMsg(trD.tr, verdict, re.RuleStr)
+}
+
+// expireMergeBuf - abandon incomplete traces which have got no messages for mergeBufTTL
+func (t *traceMergeImpl) expireMergeBuf(now time.Time) {
+	var n int
+	for id, trD := range t.mergeBuf {
+		if now.Sub(trD.updated) >= mergeBufTTL {
+			delete(t.mergeBuf, id)
+			n++
+		}
+	}
+	NotifyAbandoned(t.agentSubject, AbandonMerger, n)
+}
+
+// unknownRule - rule of the trace whose rule event has not come
+func unknownRule(handle uint64) string {
+	return fmt.Sprintf("unknown rule handle=%d", handle)
+}
+
+// resolvePending - retry to find rules of the pending traces,
+// traces whose rules have not come until timeout are sent with the unknown rule
+func (t *traceMergeImpl) resolvePending(ctx context.Context) error {
+	if len(t.pending) == 0 {
+		return nil
+	}
+	log := logger.FromContext(ctx)
+	now := time.Now()
+	rest := t.pending[:0]
+	for _, p := range t.pending {
+		re, err := t.ruler.GetRuleForTrace(p.trD.tr)
+		if errors.Is(err, nfrule.ErrNotFoundRule) {
+			if now.Before(p.until) {
+				rest = append(rest, p)
+				continue
+			}
+			log.Warnf("trace id=%d is sent with unknown rule: rule handle=%d of table='%s' chain='%s' has not come in %v",
+				p.trD.tr.Id, p.trD.tr.RuleHandle, p.trD.tr.Table, p.trD.tr.Chain, ruleWaitTimeout)
+			t.notify(RuleTimeoutEvent{})
+			re.RuleStr, err = unknownRule(p.trD.tr.RuleHandle), nil
+		}
+		if err != nil {
+			return err
+		}
+		msg, err := t.makeTraceMsg(p.trD.tr, p.verdict, re.RuleStr)
+		if err != nil {
+			return err
+		}
+		t.que.Put(msg)
+	}
+	clear(t.pending[len(rest):])
+	t.pending = rest
+	return nil
+}
+
+// flush - send traces left in the merge buffer and pending ones as they are: decisions seen yet
+// make the verdict and the rule is empty unless it is known. Traces whose packet has not been seen
+// are abandoned
+func (t *traceMergeImpl) flush(ctx context.Context) {
+	log := logger.FromContext(ctx)
+	var flushed, abandoned int
+	send := func(tr *nl.NetlinkTrace, verdict traceVerdict) {
+		var rule string
+		if re, err := t.ruler.GetRuleForTrace(tr); err == nil {
+			rule = re.RuleStr
+		}
+		msg, err := t.makeTraceMsg(tr, verdict, rule)
+		if err != nil {
+			log.Warnf("abandon trace id=%d: %v", tr.Id, err)
+			abandoned++
+			return
+		}
+		t.que.Put(msg)
+		flushed++
+	}
+	for _, p := range t.pending {
+		send(p.trD.tr, p.verdict)
+	}
+	clear(t.pending)
+	t.pending = nil
+	for id, trD := range t.mergeBuf {
+		delete(t.mergeBuf, id)
+		if trD.tr == nil {
+			abandoned++
+			continue
+		}
+		send(trD.tr, trD.verdict())
+	}
+	log.Infof("flushed %d traces, %d traces abandoned", flushed, abandoned)
+	NotifyAbandoned(t.agentSubject, AbandonMerger, abandoned)
+}
 
+// makeTraceMsg builds trace of the packet decided by the rule
+func (t *traceMergeImpl) makeTraceMsg(tr *nl.NetlinkTrace, verdict traceVerdict, rule string) (msg trace.TraceModel, err error) {
 	var iifname, oifname string
 
-	if (trD.tr.Flags & (1 << NFTNL_TRACE_IIF)) != 0 {
-		iifname, err = t.ifTracer.GetIface(int(trD.tr.Iif))
+	if (tr.Flags & (1 << NFTNL_TRACE_IIF)) != 0 {
+		iifname, err = t.ifTracer.GetIface(int(tr.Iif))
 		if err != nil {
 			return msg, errors.WithMessagef(err,
 				"failed to find ifname for the ingress traffic by interface id=%d",
-				int(trD.tr.Iif))
+				int(tr.Iif))
 		}
 	}
-	if (trD.tr.Flags & (1 << NFTNL_TRACE_OIF)) != 0 {
-		oifname, err = t.ifTracer.GetIface(int(trD.tr.Oif))
+	if (tr.Flags & (1 << NFTNL_TRACE_OIF)) != 0 {
+		oifname, err = t.ifTracer.GetIface(int(tr.Oif))
 		if err != nil {
 			return msg, errors.WithMessagef(err,
 				"failed to find ifname for the egress traffic by interface id=%d",
-				int(trD.tr.Oif))
+				int(tr.Oif))
 		}
 	}
 	sgTr := struct {
@@ -308,18 +620,18 @@ func (t *traceMergeImpl) prepareTraceMsg(tr nl.NetlinkTrace) (msg trace.TraceMod
 		dNet  string
 	}{}
 
-	if sg, err := t.sgNetProvider.GetSGByIP(trD.tr.Nh.SAddr); err != nil {
+	if sg, err := t.sgNetProvider.GetSGByIP(tr.Nh.SAddr); err != nil {
 		if !errors.Is(err, sgnw.ErrSgMiss) {
-			return msg, errors.WithMessagef(err, "failed to find security group name for the source IP %s", trD.tr.Nh.SAddr)
+			return msg, errors.WithMessagef(err, "failed to find security group name for the source IP %s", tr.Nh.SAddr)
 		}
 	} else {
 		sgTr.sName = sg.SgName
 		sgTr.sNet = sg.Network.Name
 	}
 
-	if sg, err := t.sgNetProvider.GetSGByIP(trD.tr.Nh.DAddr); err != nil {
+	if sg, err := t.sgNetProvider.GetSGByIP(tr.Nh.DAddr); err != nil {
 		if !errors.Is(err, sgnw.ErrSgMiss) {
-			return msg, errors.WithMessagef(err, "failed to find security group name for the destination IP %s", trD.tr.Nh.DAddr)
+			return msg, errors.WithMessagef(err, "failed to find security group name for the destination IP %s", tr.Nh.DAddr)
 		}
 	} else {
 		sgTr.dName = sg.SgName
@@ -327,35 +639,149 @@ func (t *traceMergeImpl) prepareTraceMsg(tr nl.NetlinkTrace) (msg trace.TraceMod
 	}
 
 	msg = trace.TraceModel{
-		TrId:       trD.tr.Id,
-		Table:      trD.tr.Table,
-		Chain:      trD.tr.Chain,
-		JumpTarget: trD.tr.JumpTarget,
-		RuleHandle: trD.tr.RuleHandle,
-		Family:     trD.tr.Family.String(),
+		TrId:       tr.Id,
+		Table:      tr.Table,
+		Chain:      tr.Chain,
+		JumpTarget: tr.JumpTarget,
+		RuleHandle: tr.RuleHandle,
+		Family:     tr.Family.String(),
 		Iifname:    iifname,
 		Oifname:    oifname,
-		SMacAddr:   trD.tr.Lh.SAddr.String(),
-		DMacAddr:   trD.tr.Lh.DAddr.String(),
-		SAddr:      trD.tr.Nh.SAddr.String(),
-		DAddr:      trD.tr.Nh.DAddr.String(),
-		SPort:      uint32(trD.tr.Th.SPort),
-		DPort:      uint32(trD.tr.Th.DPort),
-		Length:     uint32(trD.tr.Nh.Length),
-		IpProto:    trD.tr.Nh.ProtoStr(),
-		Verdict:    verdict,
-		Rule:       re.RuleStr,
+		SMacAddr:   tr.Lh.SAddr.String(),
+		DMacAddr:   tr.Lh.DAddr.String(),
+		SAddr:      tr.Nh.SAddr.String(),
+		DAddr:      tr.Nh.DAddr.String(),
+		SPort:      uint32(tr.Th.SPort),
+		DPort:      uint32(tr.Th.DPort),
+		Length:     uint32(tr.Nh.Length),
+		IpProto:    tr.Nh.ProtoStr(),
+		Verdict:    verdict.str,
+		Rule:       rule,
 		SSgName:    sgTr.sName,
 		DSgName:    sgTr.dName,
 		SSgNet:     sgTr.sNet,
 		DSgNet:     sgTr.dNet,
+		NetNS:      t.netns.Id,
+		Source:     tr.Source,
+		LogPrefix:  tr.Prefix,
+		LogGroup:   uint32(tr.Group),
+		Hook:       tr.Hook,
+	}
+	if t.rawHeaders && len(tr.RawNh) > 0 {
+		msg.RawLlHeader = tr.RawLh
+		msg.RawHeaders = append(append(make([]byte, 0, len(tr.RawNh)+len(tr.RawTh)), tr.RawNh...), tr.RawTh...)
+	}
+
+	// packets decided by the chains not made by sgroups have no rule, nftrace dumps the packet once
+	// so the table and chain of the trace are not the deciding ones
+	if t.sgRules != nil && t.sgRuleScope.Contains(verdict.final.table, verdict.final.chain) {
+		if action := verdictAction(verdict.str); action != "" {
+			p := sgnw.Packet{
+				Proto: tr.Nh.Protocol,
+				SAddr: tr.Nh.SAddr,
+				DAddr: tr.Nh.DAddr,
+				SPort: tr.Th.SPort,
+				DPort: tr.Th.DPort,
+				SSg:   sgTr.sName,
+				DSg:   sgTr.dName,
+			}
+			if p.Proto == unix.IPPROTO_ICMP || p.Proto == unix.IPPROTO_ICMPV6 {
+				// type and code of ICMP are in place of the source port
+				p.IcmpType = uint8(tr.Th.SPort >> 8)
+			}
+			// verdicts of the packets matching no sgroups rule are left unresolved
+			if r, err := t.sgRules.GetSgRule(p, action); err == nil {
+				msg.SgRule = r.Id
+				msg.SgRuleAction = r.Action
+			}
+		}
+	}
+
+	if t.containers != nil {
+		if ct, err := t.containers.GetContainer(t.netns, iifname, oifname); err != nil {
+			if !errors.Is(err, cmeta.ErrContainerMiss) {
+				return msg, errors.WithMessagef(err, "failed to find container for the interfaces '%s'/'%s'", iifname, oifname)
+			}
+		} else {
+			msg.ContainerId = ct.Id
+			msg.ContainerName = ct.Name
+			msg.Pod = ct.Pod
+			msg.PodNamespace = ct.Namespace
+			msg.ContainerLabels = ct.LabelsList()
+		}
 	}
-	delete(t.mergeBuf, tr.Id)
 
+	if t.processes != nil {
+		// attribution is best effort and never blocks the merge: first packets of the connection
+		// are left unattributed until it is resolved in background, socket may be closed already
+		if p, err := t.processes.GetProcess(procowner.Conn{
+			Proto: tr.Nh.Protocol,
+			SAddr: tr.Nh.SAddr,
+			DAddr: tr.Nh.DAddr,
+			SPort: tr.Th.SPort,
+			DPort: tr.Th.DPort,
+		}); err == nil {
+			msg.SockInode = p.Inode
+			msg.Pid = p.Pid
+			msg.Comm = p.Comm
+			msg.Cgroup = p.Cgroup
+		}
+	}
+
+	if t.conntrack != nil {
+		// packets untracked or not confirmed yet have no entry in the mirror, traces do not carry
+		// conntrack zone so the connection is matched in any zone unless the tuple is held by several ones
+		if m, err := t.conntrack.Find(conntrack.Tuple{
+			Proto: tr.Nh.Protocol,
+			SAddr: tr.Nh.SAddr,
+			DAddr: tr.Nh.DAddr,
+			SPort: tr.Th.SPort,
+			DPort: tr.Th.DPort,
+		}); err == nil {
+			msg.CtState = m.State()
+			msg.CtDirection = m.Direction
+			msg.CtNat = m.Nat()
+			msg.CtMark = m.Mark
+			msg.CtZone = uint32(m.Zone)
+			msg.CtOrig = m.Orig.String()
+			msg.CtReply = m.Reply.String()
+		}
+	}
+
+	msg.MergedAt = time.Now()
+	t.notify(LatencyEvent{Stage: LatencyKernelToMerge, Latency: msg.MergedAt.Sub(tr.At)})
 	return msg, nil
 }
 
-// Close merge
+// verdictAction - final action of the verdict, empty when the packet has not been accepted
+// or dropped yet
+func verdictAction(verdict string) string {
+	switch {
+	case strings.HasSuffix(verdict, "::"+sgnw.RuleActionAccept):
+		return sgnw.RuleActionAccept
+	case strings.HasSuffix(verdict, "::"+sgnw.RuleActionDrop):
+		return sgnw.RuleActionDrop
+	}
+	return ""
+}
+
+// logRuleStr describes the log rule of the packet as nft does
+func logRuleStr(tr *nl.NetlinkTrace) string {
+	if tr.Prefix == "" {
+		return fmt.Sprintf("log group %d", tr.Group)
+	}
+	return fmt.Sprintf("log prefix \"%s\" group %d", tr.Prefix, tr.Group)
+}
+
+// Drain -
+func (t *traceMergeImpl) Drain() error {
+	t.onceDrain.Do(func() {
+		close(t.drain)
+	})
+	return nil
+}
+
+// Close merger, traces left in the merge buffer and in the queue are abandoned
 func (t *traceMergeImpl) Close() error {
 	t.onceClose.Do(func() {
 		close(t.stop)
@@ -363,7 +789,8 @@ func (t *traceMergeImpl) Close() error {
 		if t.stopped != nil {
 			<-t.stopped
 		}
-		_ = t.que.Close()
+		NotifyAbandoned(t.agentSubject, AbandonMerger,
+			len(t.mergeBuf)+len(t.pending)+len(t.que.Discard()))
 	})
 	return nil
 }
@@ -1,10 +1,17 @@
 package nftrace
 
 import (
+	"context"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/wildberries-tech/pkt-tracer/internal/bqueue"
 	model "github.com/wildberries-tech/pkt-tracer/internal/models/nltrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nfrule"
+	sgnw "github.com/wildberries-tech/pkt-tracer/internal/providers/sg-network"
 
+	"github.com/H-BF/corlib/pkg/patterns/observer"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sys/unix"
 )
@@ -46,3 +53,358 @@ func Test_DecisionChain(t *testing.T) {
 
 	require.Equal(t, "rule::goto->return::continue->policy::accept", verdictChain)
 }
+
+type fakeIfaces map[int]string
+
+func (f fakeIfaces) GetIface(index int) (string, error) {
+	return f[index], nil
+}
+
+type fakeSgNet struct{}
+
+func (fakeSgNet) GetSGByIP(net.IP) (sgnw.SgNet, error) {
+	return sgnw.SgNet{}, sgnw.ErrSgMiss
+}
+
+func Test_NflogTrace(t *testing.T) {
+	m := NewTraceMerge(nil, fakeIfaces{2: "eth0"}, nil, fakeSgNet{}).(*traceMergeImpl)
+	tr := model.NetlinkTrace{
+		Id:     1,
+		Family: unix.NFPROTO_IPV4,
+		Iif:    2,
+		Flags:  1 << NFTNL_TRACE_IIF,
+		Source: model.SourceNflog,
+		Prefix: "drop-in",
+		Group:  3,
+		Hook:   "input",
+	}
+	tr.Nh.Protocol = unix.IPPROTO_TCP
+	tr.Nh.SAddr = net.IPv4(10, 0, 0, 1)
+	tr.Nh.DAddr = net.IPv4(10, 0, 0, 2)
+	tr.Th.DPort = 80
+
+	msg, err := m.prepareTraceMsg(tr)
+	require.NoError(t, err)
+	require.Equal(t, "nflog", msg.Source)
+	require.Equal(t, "log", msg.Verdict)
+	require.Equal(t, `log prefix "drop-in" group 3`, msg.Rule)
+	require.Equal(t, "drop-in", msg.LogPrefix)
+	require.Equal(t, uint32(3), msg.LogGroup)
+	require.Equal(t, "input", msg.Hook)
+	require.Equal(t, "eth0", msg.Iifname)
+	require.Equal(t, "10.0.0.2", msg.DAddr)
+	require.Equal(t, uint32(80), msg.DPort)
+	require.Empty(t, m.mergeBuf)
+}
+
+func Test_RawHeaders(t *testing.T) {
+	tr := model.NetlinkTrace{
+		Id:     1,
+		Family: unix.NFPROTO_IPV4,
+		Source: model.SourceNflog,
+		RawLh:  []byte{1, 2},
+		RawNh:  []byte{0x45, 0},
+		RawTh:  []byte{0, 80},
+	}
+	m := NewTraceMerge(nil, fakeIfaces{}, nil, fakeSgNet{}).(*traceMergeImpl)
+	msg, err := m.prepareTraceMsg(tr)
+	require.NoError(t, err)
+	require.Nil(t, msg.RawLlHeader)
+	require.Nil(t, msg.RawHeaders)
+
+	m = NewTraceMerge(nil, fakeIfaces{}, nil, fakeSgNet{}, MergeWithRawHeaders()).(*traceMergeImpl)
+	msg, err = m.prepareTraceMsg(tr)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, msg.RawLlHeader)
+	require.Equal(t, []byte{0x45, 0, 0, 80}, msg.RawHeaders)
+}
+
+type fakeSgNetOf map[string]string
+
+func (f fakeSgNetOf) GetSGByIP(ip net.IP) (sgnw.SgNet, error) {
+	name, ok := f[ip.String()]
+	if !ok {
+		return sgnw.SgNet{}, sgnw.ErrSgMiss
+	}
+	return sgnw.SgNet{SgName: name}, nil
+}
+
+type fakeSgRules struct {
+	asked []sgnw.Packet
+}
+
+func (f *fakeSgRules) GetSgRule(p sgnw.Packet, action string) (sgnw.SgRule, error) {
+	f.asked = append(f.asked, p)
+	if p.SSg == "web" && p.DSg == "db" && p.DPort == 5432 && action == sgnw.RuleActionDrop {
+		return sgnw.SgRule{Id: "sg-sg:web->db:tcp", Action: sgnw.RuleActionDrop}, nil
+	}
+	return sgnw.SgRule{}, sgnw.ErrSgRuleMiss
+}
+
+func Test_SgRuleOfVerdict(t *testing.T) {
+	rules := new(fakeSgRules)
+	sgNet := fakeSgNetOf{"10.0.0.1": "web", "10.0.0.2": "db"}
+	scope, err := sgnw.ParseRuleScope("main*, filter/sg-*")
+	require.NoError(t, err)
+	m := NewTraceMerge(nil, fakeIfaces{}, nil, sgNet,
+		MergeWithSgRules(rules), MergeWithSgRuleScope(scope)).(*traceMergeImpl)
+	tr := &model.NetlinkTrace{Id: 1, Family: unix.NFPROTO_IPV4, Table: "main-1", Chain: "input"}
+	tr.Nh.Protocol = unix.IPPROTO_TCP
+	tr.Nh.SAddr = net.IPv4(10, 0, 0, 1)
+	tr.Nh.DAddr = net.IPv4(10, 0, 0, 2)
+	tr.Th.SPort = 40000
+	tr.Th.DPort = 5432
+	verdictOf := func(table, chain string, ds ...decision) traceVerdict {
+		trD := &traceDecision{verdictCache: make(map[uint32]bool)}
+		for _, d := range ds {
+			d.table, d.chain = table, chain
+			trD.addDecision(d)
+		}
+		return trD.verdict()
+	}
+	jumpV, contV := NFT_JUMP, NFT_CONTINUE
+	jump := decision{dtype: unix.NFT_TRACETYPE_RULE, value: uint32(jumpV)}
+	cont := decision{dtype: unix.NFT_TRACETYPE_RULE, value: uint32(contV)}
+	drop := decision{dtype: unix.NFT_TRACETYPE_RULE, value: NF_DROP}
+	accept := decision{dtype: unix.NFT_TRACETYPE_RULE, value: NF_ACCEPT}
+
+	msg, err := m.makeTraceMsg(tr, verdictOf("main-1", "egress", jump, drop), "")
+	require.NoError(t, err)
+	require.Equal(t, "rule::jump->rule::drop", msg.Verdict)
+	require.Equal(t, "sg-sg:web->db:tcp", msg.SgRule)
+	require.Equal(t, sgnw.RuleActionDrop, msg.SgRuleAction)
+
+	msg, err = m.makeTraceMsg(tr, verdictOf("filter", "sg-in", accept), "")
+	require.NoError(t, err)
+	require.Empty(t, msg.SgRule)
+	require.Len(t, rules.asked, 2)
+
+	// packet is decided by the table not made by sgroups though it is traced in the sgroups one
+	msg, err = m.makeTraceMsg(tr, verdictOf("filter", "input", drop), "")
+	require.NoError(t, err)
+	require.Empty(t, msg.SgRule)
+	require.Len(t, rules.asked, 2)
+
+	// packet is not decided yet
+	_, err = m.makeTraceMsg(tr, verdictOf("main-1", "input", cont), "")
+	require.NoError(t, err)
+	require.Len(t, rules.asked, 2)
+
+	_, err = sgnw.ParseRuleScope("main/in/put")
+	require.Error(t, err)
+	_, err = sgnw.ParseRuleScope("main[")
+	require.Error(t, err)
+	require.True(t, sgnw.RuleScope(nil).Contains("any", "chain"))
+}
+
+type fakeRuler struct {
+	rules map[uint64]string
+}
+
+func (f *fakeRuler) GetRuleForTrace(tr *model.NetlinkTrace) (nfrule.RuleEntry, error) {
+	str, ok := f.rules[tr.RuleHandle]
+	if !ok {
+		return nfrule.RuleEntry{}, nfrule.ErrNotFoundRule
+	}
+	return nfrule.RuleEntry{RuleStr: str}, nil
+}
+
+func Test_PendingTrace(t *testing.T) {
+	ruler := &fakeRuler{rules: map[uint64]string{}}
+	m := NewTraceMerge(nil, fakeIfaces{2: "eth0"}, ruler, fakeSgNet{}).(*traceMergeImpl)
+	ctx := context.Background()
+	policyTrace := func(id uint32, handle uint64) model.NetlinkTrace {
+		tr := model.NetlinkTrace{
+			Id:         id,
+			Type:       unix.NFT_TRACETYPE_POLICY,
+			Table:      "filter",
+			Chain:      "input",
+			RuleHandle: handle,
+			Policy:     NF_ACCEPT,
+			Family:     unix.NFPROTO_IPV4,
+			Iif:        2,
+			Flags:      1<<NFTNL_TRACE_IIF | 1<<NFTNL_TRACE_NETWORK_HEADER | 1<<NFTNL_TRACE_POLICY,
+			Source:     model.SourceNftrace,
+		}
+		tr.Nh.SAddr = net.IPv4(10, 0, 0, 1)
+		tr.Nh.DAddr = net.IPv4(10, 0, 0, 2)
+		return tr
+	}
+
+	// rule event comes after the trace
+	_, err := m.prepareTraceMsg(policyTrace(1, 7))
+	require.ErrorIs(t, err, ErrTraceDataNotReady)
+	_, err = m.prepareTraceMsg(policyTrace(2, 8))
+	require.ErrorIs(t, err, ErrTraceDataNotReady)
+	require.Len(t, m.pending, 2)
+	require.Empty(t, m.mergeBuf)
+
+	ruler.rules[7] = "accept"
+	require.NoError(t, m.resolvePending(ctx))
+	require.Len(t, m.pending, 1)
+	msg := <-m.Reader()
+	require.Equal(t, "accept", msg.Rule)
+	require.Equal(t, "eth0", msg.Iifname)
+
+	// rule never comes, the trace is sent with the unknown rule
+	var timeouts int
+	m.agentSubject = observer.NewSubject()
+	m.agentSubject.ObserversAttach(observer.NewObserver(func(observer.EventType) {
+		timeouts++
+	}, false, RuleTimeoutEvent{}))
+	m.pending[0].until = time.Now().Add(-time.Second)
+	require.NoError(t, m.resolvePending(ctx))
+	require.Empty(t, m.pending)
+	msg = <-m.Reader()
+	require.Equal(t, "unknown rule handle=8", msg.Rule)
+	require.Equal(t, uint64(8), msg.RuleHandle)
+	require.Equal(t, 1, timeouts)
+}
+
+func Test_MergeStatsEvents(t *testing.T) {
+	var (
+		lookups   []bool
+		latencies []LatencyEvent
+	)
+	subject := observer.NewSubject()
+	subject.ObserversAttach(observer.NewObserver(func(ev observer.EventType) {
+		switch e := ev.(type) {
+		case RuleLookupEvent:
+			lookups = append(lookups, e.Hit)
+		case LatencyEvent:
+			latencies = append(latencies, e)
+		}
+	}, false, RuleLookupEvent{}, LatencyEvent{}))
+
+	ruler := &fakeRuler{rules: map[uint64]string{7: "accept"}}
+	m := NewTraceMerge(nil, fakeIfaces{2: "eth0"}, ruler, fakeSgNet{}, MergeWithAgentSubject(subject)).(*traceMergeImpl)
+	policyTrace := func(id uint32, handle uint64) model.NetlinkTrace {
+		tr := model.NetlinkTrace{
+			Id:         id,
+			Type:       unix.NFT_TRACETYPE_POLICY,
+			RuleHandle: handle,
+			Policy:     NF_ACCEPT,
+			Family:     unix.NFPROTO_IPV4,
+			Iif:        2,
+			Flags:      1<<NFTNL_TRACE_IIF | 1<<NFTNL_TRACE_NETWORK_HEADER | 1<<NFTNL_TRACE_POLICY,
+			At:         time.Now().Add(-time.Second),
+		}
+		tr.Nh.SAddr = net.IPv4(10, 0, 0, 1)
+		tr.Nh.DAddr = net.IPv4(10, 0, 0, 2)
+		return tr
+	}
+
+	msg, err := m.prepareTraceMsg(policyTrace(1, 7))
+	require.NoError(t, err)
+	require.False(t, msg.MergedAt.IsZero())
+	_, err = m.prepareTraceMsg(policyTrace(2, 8))
+	require.ErrorIs(t, err, ErrTraceDataNotReady)
+	// retries of the pending trace are not lookups of the rule cache
+	require.NoError(t, m.resolvePending(context.Background()))
+
+	require.Equal(t, []bool{true, false}, lookups)
+	require.Len(t, latencies, 1)
+	require.Equal(t, LatencyKernelToMerge, latencies[0].Stage)
+	require.GreaterOrEqual(t, latencies[0].Latency, time.Second)
+}
+
+type fakeCollector struct {
+	que *bqueue.FIFO[[]model.NetlinkTrace]
+}
+
+func (f fakeCollector) Reader() <-chan []model.NetlinkTrace {
+	return f.que.Reader()
+}
+
+func Test_MergeDrain(t *testing.T) {
+	var abandoned []AbandonEvent
+	subject := observer.NewSubject()
+	subject.ObserversAttach(observer.NewObserver(func(ev observer.EventType) {
+		abandoned = append(abandoned, ev.(AbandonEvent))
+	}, false, AbandonEvent{}))
+
+	col := fakeCollector{que: bqueue.NewFIFO[[]model.NetlinkTrace]()}
+	defer col.que.Close()
+	ruler := &fakeRuler{rules: map[uint64]string{7: "accept"}}
+	m := NewTraceMerge(col, fakeIfaces{2: "eth0"}, ruler, fakeSgNet{}, MergeWithAgentSubject(subject))
+	defer m.Close()
+	newTrace := func(id uint32, typ uint32, handle uint64, withHeader bool) model.NetlinkTrace {
+		tr := model.NetlinkTrace{
+			Id:         id,
+			Type:       typ,
+			Table:      "filter",
+			Chain:      "input",
+			RuleHandle: handle,
+			Family:     unix.NFPROTO_IPV4,
+			Iif:        2,
+			Flags:      1 << NFTNL_TRACE_IIF,
+			Source:     model.SourceNftrace,
+		}
+		if withHeader {
+			tr.Flags |= 1 << NFTNL_TRACE_NETWORK_HEADER
+		}
+		if typ == unix.NFT_TRACETYPE_POLICY {
+			tr.Policy = NF_ACCEPT
+			tr.Flags |= 1 << NFTNL_TRACE_POLICY
+		} else {
+			verdict := NFT_JUMP
+			tr.Verdict = uint32(verdict)
+			tr.Flags |= 1<<NFTNL_TRACE_RULE_HANDLE | 1<<NFTNL_TRACE_VERDICT
+		}
+		tr.Nh.SAddr = net.IPv4(10, 0, 0, 1)
+		tr.Nh.DAddr = net.IPv4(10, 0, 0, 2)
+		return tr
+	}
+	col.que.Put([]model.NetlinkTrace{
+		newTrace(1, unix.NFT_TRACETYPE_POLICY, 7, true), // complete
+		newTrace(2, unix.NFT_TRACETYPE_POLICY, 8, true), // waits for its rule
+		newTrace(3, unix.NFT_TRACETYPE_RULE, 9, true),   // incomplete
+		newTrace(4, unix.NFT_TRACETYPE_RULE, 9, false),  // packet is not seen
+	})
+	require.NoError(t, m.Drain())
+	col.que.Seal()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- m.Run(context.Background())
+	}()
+	verdicts := make(map[uint32]string)
+	rules := make(map[uint32]string)
+	for msg := range m.Reader() {
+		verdicts[msg.TrId] = msg.Verdict
+		rules[msg.TrId] = msg.Rule
+	}
+	require.NoError(t, <-errc)
+	require.Equal(t, map[uint32]string{1: "policy::accept", 2: "policy::accept", 3: "rule::jump->"}, verdicts)
+	require.Equal(t, map[uint32]string{1: "accept", 2: "", 3: ""}, rules)
+	require.Equal(t, []AbandonEvent{{Stage: AbandonMerger, Cnt: 1}}, abandoned)
+}
+
+func Test_MergeBufExpire(t *testing.T) {
+	var abandoned []AbandonEvent
+	subject := observer.NewSubject()
+	subject.ObserversAttach(observer.NewObserver(func(ev observer.EventType) {
+		abandoned = append(abandoned, ev.(AbandonEvent))
+	}, false, AbandonEvent{}))
+	m := NewTraceMerge(nil, fakeIfaces{2: "eth0"}, &fakeRuler{}, fakeSgNet{},
+		MergeWithAgentSubject(subject)).(*traceMergeImpl)
+	for _, id := range []uint32{1, 2} {
+		// header without decisions, the rest of messages of the trace are lost
+		_, err := m.prepareTraceMsg(model.NetlinkTrace{
+			Id:     id,
+			Type:   unix.NFT_TRACETYPE_RULE,
+			Family: unix.NFPROTO_IPV4,
+			Flags:  1 << NFTNL_TRACE_NETWORK_HEADER,
+			Source: model.SourceNftrace,
+		})
+		require.ErrorIs(t, err, ErrTraceDataNotReady)
+	}
+	require.Len(t, m.mergeBuf, 2)
+	now := time.Now()
+	m.mergeBuf[1].updated = now.Add(-mergeBufTTL)
+
+	m.expireMergeBuf(now)
+	require.Len(t, m.mergeBuf, 1)
+	require.Contains(t, m.mergeBuf, uint32(2))
+	require.Equal(t, []AbandonEvent{{Stage: AbandonMerger, Cnt: 1}}, abandoned)
+}
@@ -0,0 +1,61 @@
+package nftrace
+
+import (
+	"strings"
+
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/nltrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+
+	"github.com/H-BF/corlib/pkg/patterns/observer"
+	"golang.org/x/sys/unix"
+)
+
+// AbandonEvent - traces have been abandoned at the stage of the agent pipeline, e.g. on shutdown
+type AbandonEvent struct {
+	observer.EventType
+	Stage string
+	// Cnt - number of traces, collectors count netlink messages since traces are not merged there yet
+	Cnt int
+}
+
+// stages of the agent pipeline reported by AbandonEvent
+const (
+	AbandonCollector = "collector"
+	AbandonMerger    = "merger"
+	AbandonTracer    = "tracer"
+)
+
+// NotifyAbandoned - notify subject on traces abandoned at the stage, nothing is sent when there are none
+func NotifyAbandoned(s observer.Subject, stage string, cnt int) {
+	if s != nil && cnt > 0 {
+		s.Notify(AbandonEvent{Stage: stage, Cnt: cnt})
+	}
+}
+
+// CountMessages - number of netlink messages in the batches
+func CountMessages(batches [][]model.NetlinkTrace) (n int) {
+	for _, b := range batches {
+		n += len(b)
+	}
+	return n
+}
+
+// IsDropped - merged trace ends with the drop decision,
+// such traces are kept by the PreferDrops queue policy over accepted ones
+func IsDropped(tr trace.TraceModel) bool {
+	return strings.HasSuffix(tr.Verdict, "::drop")
+}
+
+// hasDropDecision - batch of collected traces holds decision to drop the packet
+func hasDropDecision(batch []model.NetlinkTrace) bool {
+	for i := range batch {
+		tr := &batch[i]
+		switch {
+		case tr.Type == unix.NFT_TRACETYPE_RULE && tr.Flags&(1<<NFTNL_TRACE_VERDICT) != 0 && tr.Verdict == NF_DROP:
+			return true
+		case tr.Type == unix.NFT_TRACETYPE_POLICY && tr.Flags&(1<<NFTNL_TRACE_POLICY) != 0 && tr.Policy == NF_DROP:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package nftrace
+
+import (
+	"testing"
+
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/nltrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func Test_QueuePriority(t *testing.T) {
+	require.True(t, IsDropped(trace.TraceModel{Verdict: "rule::goto->policy::drop"}))
+	require.False(t, IsDropped(trace.TraceModel{Verdict: "rule::accept"}))
+	require.False(t, IsDropped(trace.TraceModel{Verdict: verdictLog}))
+
+	ruleAccept := model.NetlinkTrace{Type: unix.NFT_TRACETYPE_RULE, Verdict: NF_ACCEPT, Flags: 1 << NFTNL_TRACE_VERDICT}
+	ruleDrop := model.NetlinkTrace{Type: unix.NFT_TRACETYPE_RULE, Verdict: NF_DROP, Flags: 1 << NFTNL_TRACE_VERDICT}
+	policyDrop := model.NetlinkTrace{Type: unix.NFT_TRACETYPE_POLICY, Policy: NF_DROP, Flags: 1 << NFTNL_TRACE_POLICY}
+	noVerdict := model.NetlinkTrace{Type: unix.NFT_TRACETYPE_RULE}
+
+	require.False(t, hasDropDecision([]model.NetlinkTrace{ruleAccept, noVerdict}))
+	require.True(t, hasDropDecision([]model.NetlinkTrace{ruleAccept, ruleDrop}))
+	require.True(t, hasDropDecision([]model.NetlinkTrace{policyDrop}))
+}
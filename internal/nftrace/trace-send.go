@@ -3,9 +3,12 @@ package nftrace
 import (
 	"context"
 	"sync"
+	"time"
 
 	thAPI "github.com/wildberries-tech/pkt-tracer/internal/api/tracehub"
 	"github.com/wildberries-tech/pkt-tracer/internal/dto"
+	"github.com/wildberries-tech/pkt-tracer/internal/health"
+	agent "github.com/wildberries-tech/pkt-tracer/internal/models/agent"
 	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
 	proto "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
 
@@ -26,6 +29,13 @@ type (
 		observer.EventType
 	}
 
+	// LatencyEvent - trace has passed the stage of the agent pipeline
+	LatencyEvent struct {
+		observer.EventType
+		Stage   string
+		Latency time.Duration
+	}
+
 	TraceSender interface {
 		Run(ctx context.Context) (err error)
 		Close() error
@@ -36,25 +46,58 @@ type (
 	}
 
 	traceSendImpl struct {
-		agentSubject observer.Subject
-		client       THClient
-		traceSourse  mergedTracesSource
-		onceRun      sync.Once
-		onceClose    sync.Once
-		stop         chan struct{}
-		stopped      chan struct{}
+		agentSubject      observer.Subject
+		client            THClient
+		traceSourse       mergedTracesSource
+		agentInfo         *agent.AgentInfo
+		heartbeatInterval time.Duration
+		onceRun           sync.Once
+		onceClose         sync.Once
+		stop              chan struct{}
+		stopped           chan struct{}
 	}
+
+	// TraceSendOpt - option of the trace sender
+	TraceSendOpt func(*traceSendImpl)
+)
+
+// stages of the agent pipeline measured by LatencyEvent
+const (
+	// LatencyKernelToMerge - since the trace is received from kernel until it is merged
+	LatencyKernelToMerge = "kernel-to-merge"
+	// LatencyMergeToSend - since the trace is merged until the sender takes it
+	LatencyMergeToSend = "merge-to-send"
+	// LatencySend - sending of the trace to the trace-hub stream
+	LatencySend = "send"
 )
 
 var _ TraceSender = (*traceSendImpl)(nil)
 
-func NewTraceSend(cl THClient, m mergedTracesSource, subj observer.Subject) TraceSender {
-	return &traceSendImpl{
+// SendWithAgentInfo - describe agent to the server on stream connection
+func SendWithAgentInfo(info agent.AgentInfo) TraceSendOpt {
+	return func(t *traceSendImpl) {
+		t.agentInfo = &info
+	}
+}
+
+// SendWithHeartbeat - send heartbeat to the server when there are no traces during interval
+func SendWithHeartbeat(interval time.Duration) TraceSendOpt {
+	return func(t *traceSendImpl) {
+		t.heartbeatInterval = interval
+	}
+}
+
+func NewTraceSend(cl THClient, m mergedTracesSource, subj observer.Subject, opts ...TraceSendOpt) TraceSender {
+	t := &traceSendImpl{
 		agentSubject: subj,
 		client:       cl,
 		traceSourse:  m,
 		stop:         make(chan struct{}),
 	}
+	for _, o := range opts {
+		o(t)
+	}
+	return t
 }
 
 func (t *traceSendImpl) Run(ctx context.Context) (err error) {
@@ -79,10 +122,22 @@ func (t *traceSendImpl) Run(ctx context.Context) (err error) {
 		close(t.stopped)
 	}()
 
+	defer func() {
+		health.ReportExit(t.agentSubject, health.Sender, "", err)
+	}()
 	streamer, err = t.newStreamer(ctx)
 	if err != nil {
 		return ErrSend{Err: errors.WithMessage(err, "on create 'trace-send' stream")}
 	}
+	health.Report(t.agentSubject, health.Sender, "", nil)
+
+	var heartbeat <-chan time.Time
+	if t.heartbeatInterval > 0 {
+		tc := time.NewTicker(t.heartbeatInterval)
+		defer tc.Stop()
+		heartbeat = tc.C
+	}
+	lastSentAt := time.Now()
 
 	for que := t.traceSourse.Reader(); err == nil; {
 		select {
@@ -94,15 +149,32 @@ func (t *traceSendImpl) Run(ctx context.Context) (err error) {
 			return nil
 		case trace, ok := <-que:
 			if !ok {
-				log.Info("failed to read merged trace from queue")
-				err = ErrSend{Err: errors.New("failed to read merged trace from queue")}
+				// source is closed on shutdown after the last trace, so the stream is closed cleanly
+				log.Info("will exit cause source of traces has finished")
+				return nil
+			}
+			sendAt := time.Now()
+			if !trace.MergedAt.IsZero() {
+				t.agentSubject.Notify(LatencyEvent{Stage: LatencyMergeToSend, Latency: sendAt.Sub(trace.MergedAt)})
+			}
+			e := streamer.sendTraceMsg(trace)
+			if e != nil {
+				err = ErrSend{Err: e}
 			} else {
-				e := streamer.sendTraceMsg(trace)
-				if e != nil {
-					err = ErrSend{Err: e}
-				} else {
-					t.agentSubject.Notify(CountTraceEvent{Cnt: 1})
-				}
+				lastSentAt = time.Now()
+				t.agentSubject.Notify(
+					CountTraceEvent{Cnt: 1},
+					LatencyEvent{Stage: LatencySend, Latency: lastSentAt.Sub(sendAt)},
+				)
+			}
+		case <-heartbeat:
+			if time.Since(lastSentAt) < t.heartbeatInterval {
+				break
+			}
+			if e := streamer.sendHeartbeat(); e != nil {
+				err = ErrSend{Err: errors.WithMessage(e, "on send heartbeat")}
+			} else {
+				lastSentAt = time.Now()
 			}
 		}
 	}
@@ -122,6 +194,11 @@ func (t *traceSendImpl) Close() error {
 }
 
 func (t *traceSendImpl) newStreamer(ctx context.Context) (*traceSendStream, error) {
+	if t.agentInfo != nil {
+		var agentInfo dto.AgentInfoDTO
+		agentInfo.InitFromModel(t.agentInfo)
+		ctx = agentInfo.AppendToOutgoingContext(ctx)
+	}
 	s, err := t.client.TraceStream(ctx)
 	if err != nil {
 		return nil, err
@@ -148,3 +225,7 @@ func (ts *traceSendStream) sendTraceMsg(m model.TraceModel) error {
 	obj.Traces = append(obj.Traces, dtoTrace.ToProto())
 	return ts.stream.Send(&obj)
 }
+
+func (ts *traceSendStream) sendHeartbeat() error {
+	return ts.stream.Send(&proto.Traces{})
+}
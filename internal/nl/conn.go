@@ -1,6 +1,8 @@
 package nl
 
 import (
+	"unsafe"
+
 	"github.com/mdlayher/socket"
 	"github.com/pkg/errors"
 	"golang.org/x/sys/unix"
@@ -48,3 +50,27 @@ func (c *Conn) TryRecv(p []byte, timeout *unix.Timeval) (n int, err error) {
 
 	return c.Read(p)
 }
+
+// Drops - counter of packets dropped by kernel on the socket, e.g. on the receive buffer overrun
+func (c *Conn) Drops() (uint32, error) {
+	rawConn, err := c.SyscallConn()
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get raw connection")
+	}
+	var (
+		meminfo [unix.SK_MEMINFO_VARS]uint32
+		errno   unix.Errno
+	)
+	err = rawConn.Control(func(fd uintptr) {
+		l := uint32(unsafe.Sizeof(meminfo))
+		_, _, errno = unix.Syscall6(unix.SYS_GETSOCKOPT, fd, unix.SOL_SOCKET, unix.SO_MEMINFO,
+			uintptr(unsafe.Pointer(&meminfo[0])), uintptr(unsafe.Pointer(&l)), 0)
+	})
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get file descriptor")
+	}
+	if errno != 0 {
+		return 0, errors.WithMessage(errno, "failed to get option(unix.SO_MEMINFO) of socket")
+	}
+	return meminfo[unix.SK_MEMINFO_DROPS], nil
+}
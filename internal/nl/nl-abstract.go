@@ -2,6 +2,7 @@ package nl
 
 import (
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -31,6 +32,9 @@ type (
 	NlData struct {
 		Messages []syscall.NetlinkMessage
 		Err      error
+		// Lost - estimated number of messages dropped by kernel since the previous overrun,
+		// it is set along with ErrNlMem
+		Lost uint32
 	}
 	NlReader interface {
 		Read() chan NlData
@@ -38,9 +42,16 @@ type (
 	// NetlinkWatcher netlink watch streamer
 	NetlinkWatcher interface {
 		Reader(num int) NlReader
+		Send(msg []byte) error
 		Close() error
 	}
 
+	// Poller - watcher reporting time it has polled its socket last time, the socket is polled
+	// at least once per read timeout while readers keep up with the data
+	Poller interface {
+		LastPoll() time.Time
+	}
+
 	// NlNfMsg netlink netfilter message type
 	NlNfMsg interface {
 		MsgType() uint16
@@ -53,3 +64,11 @@ var (
 	ErrNlReadInterrupted = errors.New("nl read operation interrupted")
 	ErrNlMem             = errors.New("memory failed")
 )
+
+// LastPollOf - time the watcher has polled its socket last time, zero when the watcher does not report it
+func LastPollOf(w NetlinkWatcher) time.Time {
+	if p, ok := w.(Poller); ok {
+		return p.LastPoll()
+	}
+	return time.Time{}
+}
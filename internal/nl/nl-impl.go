@@ -3,10 +3,15 @@ package nl
 import (
 	"os"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
 
 	"github.com/mdlayher/socket"
 	"github.com/pkg/errors"
+	"golang.org/x/net/bpf"
 	"golang.org/x/sys/unix"
 )
 
@@ -30,6 +35,8 @@ type (
 		close     chan struct{}
 		stopped   chan struct{}
 		data      []chan NlData
+		drops     uint32       // drops counter of the socket seen on the previous overrun
+		polled    atomic.Int64 // unix time in ns the socket has been polled last time
 		mu        sync.Mutex
 		closeOnce sync.Once
 	}
@@ -41,9 +48,15 @@ type (
 	}
 
 	nlOptFunc func(*Nl) error
+
+	// nlNetNSOpt - it is applied before the socket is created
+	nlNetNSOpt int
 )
 
-var _ NetlinkWatcher = (*Nl)(nil)
+var (
+	_ NetlinkWatcher = (*Nl)(nil)
+	_ Poller         = (*Nl)(nil)
+)
 
 func NewNetlinkWatcher(nwatchers int, proto int, opts ...nlOpt) (NetlinkWatcher, error) {
 	var err error
@@ -60,13 +73,22 @@ func NewNetlinkWatcher(nwatchers int, proto int, opts ...nlOpt) (NetlinkWatcher,
 		data:    make([]chan NlData, nwatchers),
 	}
 
-	watcher.sock.Conn, err = socket.Socket(
-		unix.AF_NETLINK,
-		unix.SOCK_RAW,
-		proto,
-		"netlink",
-		nil,
-	)
+	netnsFd := -1
+	for _, o := range opts {
+		if fd, ok := o.(nlNetNSOpt); ok {
+			netnsFd = int(fd)
+		}
+	}
+	err = netns.Do(netnsFd, func() (e error) {
+		watcher.sock.Conn, e = socket.Socket(
+			unix.AF_NETLINK,
+			unix.SOCK_RAW,
+			proto,
+			"netlink",
+			nil,
+		)
+		return e
+	})
 
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to create 'netlink' socket")
@@ -120,16 +142,28 @@ func (n *Nl) run() {
 			return
 		default:
 		}
+		var lost uint32
+		if errors.Is(err, ErrNlMem) {
+			lost = n.lostMessages()
+		}
 		for i := range n.data {
 			select {
 			case <-n.close:
 				return
-			case n.data[i] <- NlData{messages, err}:
+			case n.data[i] <- NlData{Messages: messages, Err: err, Lost: lost}:
 			}
 		}
 	}
 }
 
+// LastPoll impl Poller
+func (n *Nl) LastPoll() time.Time {
+	if t := n.polled.Load(); t != 0 {
+		return time.Unix(0, t)
+	}
+	return time.Time{}
+}
+
 func (n *nlReaderImpl) Read() chan NlData {
 	return n.data
 }
@@ -151,6 +185,7 @@ loop:
 	n.mu.Lock()
 	length, err = n.sock.TryRecv(rcvBuff, n.timeout)
 	n.mu.Unlock()
+	n.polled.Store(time.Now().UnixNano())
 	if err != nil {
 		var ern syscall.Errno
 		if errors.As(err, &ern) {
@@ -177,6 +212,25 @@ loop:
 	return messages, nil
 }
 
+// lostMessages - number of messages dropped by kernel since the previous call
+func (n *Nl) lostMessages() uint32 {
+	drops, err := n.sock.Drops()
+	if err != nil {
+		return 0
+	}
+	lost := drops - n.drops
+	n.drops = drops
+	return lost
+}
+
+// Send writes netlink request into the socket, replies are delivered to readers
+func (n *Nl) Send(msg []byte) error {
+	if _, err := n.sock.Write(msg); err != nil {
+		return errors.WithMessage(err, "failed to send netlink message")
+	}
+	return nil
+}
+
 func (n *Nl) Close() (err error) {
 	n.closeOnce.Do(func() {
 		n.mu.Lock()
@@ -200,6 +254,16 @@ func NlWithTimeout(t *unix.Timeval) nlOpt {
 	})
 }
 
+func (nlNetNSOpt) apply(*Nl) error {
+	return nil
+}
+
+// NlWithNetNS - create socket in the network namespace referred by file descriptor,
+// negative value means the current namespace
+func NlWithNetNS(fd int) nlOpt {
+	return nlNetNSOpt(fd)
+}
+
 // SkWithBufLen - set receive buffer size, default is Page size
 func SkWithBufLen(buflen int) nlOpt {
 	return nlOptFunc(func(o *Nl) error {
@@ -218,3 +282,14 @@ func SkWithNlMs(nlms ...int) nlOpt {
 		return nil
 	})
 }
+
+// SkWithBPF - attach classic BPF filter to the socket, kernel drops messages the filter rejects,
+// empty filter means no filtering
+func SkWithBPF(filter []bpf.RawInstruction) nlOpt {
+	return nlOptFunc(func(o *Nl) error {
+		if len(filter) == 0 {
+			return nil
+		}
+		return o.sock.SetBPF(filter)
+	})
+}
@@ -0,0 +1,49 @@
+package nl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/netlink/nlenc"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func Test_OverrunLostMessages(t *testing.T) {
+	const group = 1
+	w, err := NewNetlinkWatcher(1, unix.NETLINK_USERSOCK, SkWithNlMs(group))
+	if err != nil {
+		t.Skipf("netlink socket is not available: %v", err)
+	}
+	defer w.Close()
+	sender, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW, unix.NETLINK_USERSOCK)
+	require.NoError(t, err)
+	defer unix.Close(sender)
+
+	// receive buffer is one page long, so the most of messages are dropped
+	const sent = 1000
+	msg := make([]byte, 256)
+	nlenc.PutUint32(msg[:4], uint32(len(msg)))
+	for i := 0; i < sent; i++ {
+		err = unix.Sendto(sender, msg, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: 1 << (group - 1)})
+		// message is broadcasted yet, refusal comes from the absent kernel socket of the NETLINK_USERSOCK
+		if !errors.Is(err, unix.ECONNREFUSED) {
+			require.NoError(t, err)
+		}
+	}
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			require.FailNow(t, "timeout waiting for overrun")
+		case nlData := <-w.Reader(0).Read():
+			if errors.Is(nlData.Err, ErrNlMem) {
+				require.Greater(t, nlData.Lost, uint32(sent/2))
+				require.Less(t, nlData.Lost, uint32(sent))
+				return
+			}
+			require.Zero(t, nlData.Lost)
+		}
+	}
+}
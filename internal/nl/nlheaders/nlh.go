@@ -13,15 +13,18 @@ const (
 	ICMP_REDIRECT = 5
 	// Network layer header length
 	NlHeaderLen = 20
+	// IPv6 fixed header length
+	NlHeaderV6Len = 40
 )
 
-// TODO: add other protocol support
-// Network layer header
+// Network layer header, IPv4 one or the fixed IPv6 one: Length is the payload length,
+// TTL is the hop limit and Protocol is the next header of IPv6
 type NlHeader struct {
-	Version        uint8 // 4 bits
-	IHL            uint8 // 4 bits
-	DSCP           uint8 // 6 bits
-	ECN            uint8 // 2 bits
+	Version        uint8  // 4 bits
+	IHL            uint8  // 4 bits
+	DSCP           uint8  // 6 bits
+	ECN            uint8  // 2 bits
+	FlowLabel      uint32 // 20 bits, IPv6 only
 	Length         uint16
 	Identification uint16
 	Flags          uint8  // 3 bits
@@ -76,6 +79,9 @@ func (h *NlHeader) ProtoStr() string {
 
 // Decode - decode header from byte stream
 func (h *NlHeader) Decode(b []byte) error {
+	if len(b) > 0 && b[0]>>4 == 6 {
+		return h.decodeV6(b)
+	}
 	l := len(b)
 	if l < NlHeaderLen {
 		return errors.Errorf("incorrect NlHeader binary length=%d", l)
@@ -113,3 +119,23 @@ func (h *NlHeader) Decode(b []byte) error {
 
 	return nil
 }
+
+func (h *NlHeader) decodeV6(b []byte) error {
+	if l := len(b); l < NlHeaderV6Len {
+		return errors.Errorf("incorrect IPv6 NlHeader binary length=%d", l)
+	}
+	*h = NlHeader{
+		Version:   b[0] >> 4,
+		DSCP:      (b[0]&0x0f)<<2 | b[1]>>6,
+		ECN:       (b[1] >> 4) & 0x03,
+		FlowLabel: binary.BigEndian.Uint32(b[0:4]) & 0x000fffff,
+		Length:    binary.BigEndian.Uint16(b[4:6]),
+		Protocol:  b[6],
+		TTL:       b[7],
+		SAddr:     make(net.IP, net.IPv6len),
+		DAddr:     make(net.IP, net.IPv6len),
+	}
+	copy(h.SAddr, b[8:24])
+	copy(h.DAddr, b[24:40])
+	return nil
+}
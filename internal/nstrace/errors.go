@@ -0,0 +1,20 @@
+package nstrace
+
+import (
+	"fmt"
+)
+
+// ErrNsTracer -
+type ErrNsTracer struct {
+	Err error
+}
+
+// Error -
+func (e ErrNsTracer) Error() string {
+	return fmt.Sprintf("NsTracer: %v", e.Err)
+}
+
+// Cause -
+func (e ErrNsTracer) Cause() error {
+	return e.Err
+}
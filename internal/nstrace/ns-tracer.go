@@ -0,0 +1,433 @@
+package nstrace
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/bqueue"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
+	"github.com/wildberries-tech/pkt-tracer/internal/nftmonitor"
+	"github.com/wildberries-tech/pkt-tracer/internal/nftrace"
+	cmeta "github.com/wildberries-tech/pkt-tracer/internal/providers/container-meta"
+	sgnw "github.com/wildberries-tech/pkt-tracer/internal/providers/sg-network"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/H-BF/corlib/pkg/patterns/observer"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+type (
+	// Tracer - runs trace pipelines in the host network namespace and optionally
+	// in every other network namespace discovered on the host
+	Tracer interface {
+		Run(ctx context.Context) error
+		Reader() <-chan trace.TraceModel
+		// RestartTableSync - restart sync of tables of all network namespaces, so streams of tables
+		// are opened again by 'Deps.TablesStream' and tables are synced every 'syncInterval'
+		RestartTableSync(ctx context.Context, syncInterval time.Duration) error
+		// Drain - stop collecting traces in all network namespaces and close the reader after traces
+		// collected yet have been merged, it returns when the last trace is in the reader or ctx is done
+		Drain(ctx context.Context) error
+		Close() error
+	}
+
+	// SgNetProvider - provides security group of the ip address
+	SgNetProvider interface {
+		GetSGByIP(net.IP) (sgnw.SgNet, error)
+	}
+
+	// SgRuleProvider - provides sgroups rule the verdict of the packet is resolved to
+	SgRuleProvider interface {
+		GetSgRule(p sgnw.Packet, action string) (sgnw.SgRule, error)
+	}
+
+	// ContainerProvider - provides container owning interfaces of the network namespace
+	ContainerProvider interface {
+		GetContainer(ns netns.NetNS, ifaces ...string) (cmeta.Container, error)
+	}
+
+	// Deps - dependency
+	Deps struct {
+		// Adapters
+		AgentSubject  observer.Subject
+		SgNetProvider SgNetProvider
+		// SgRuleProvider is optional, verdicts are not resolved to sgroups rules when it is nil
+		SgRuleProvider SgRuleProvider
+		// ContainerProvider is optional, traces are not attributed to containers when it is nil
+		ContainerProvider ContainerProvider
+		// TablesStream opens stream to sync nftables state on server
+		TablesStream func(ctx context.Context) (nftmonitor.StreamCli, error)
+	}
+
+	// TracerOpt - option of the tracer
+	TracerOpt func(*tracerImpl)
+
+	tracerImpl struct {
+		Deps
+		conf           pipelineConf
+		scanner        *netns.Scanner
+		rescanInterval time.Duration
+		que            *bqueue.FIFO[trace.TraceModel]
+		tblSync        chan tableSyncReq
+		drainReq       chan chan error
+		onceRun        sync.Once
+		onceClose      sync.Once
+		stop           chan struct{}
+		stopped        chan struct{}
+	}
+
+	pipelineResult struct {
+		p   *pipeline
+		err error
+	}
+)
+
+var _ Tracer = (*tracerImpl)(nil)
+
+// WithNetNSDiscovery - trace every network namespace found by scanner,
+// namespaces created or destroyed at runtime are found by rescan after interval
+func WithNetNSDiscovery(s netns.Scanner, rescanInterval time.Duration) TracerOpt {
+	return func(t *tracerImpl) {
+		t.scanner = &s
+		t.rescanInterval = rescanInterval
+	}
+}
+
+// WithProcessAttribution - attach local process owning socket to traces using socket diagnostics,
+// connections resolved are kept in cache of 'cacheSize' per network namespace
+func WithProcessAttribution(cacheSize int, procDir string) TracerOpt {
+	return func(t *tracerImpl) {
+		t.conf.procs = &procsConf{
+			cacheSize: cacheSize,
+			procDir:   procDir,
+		}
+	}
+}
+
+// WithSgRuleScope - resolve to sgroups rules only verdicts made in tables and chains of the scope
+func WithSgRuleScope(s sgnw.RuleScope) TracerOpt {
+	return func(t *tracerImpl) {
+		t.conf.sgRuleScope = s
+	}
+}
+
+// WithConntrack - attach conntrack state of the connection to traces,
+// conntrack table of every network namespace is mirrored from ctnetlink events
+func WithConntrack() TracerOpt {
+	return func(t *tracerImpl) {
+		t.conf.conntrack = true
+	}
+}
+
+// WithRawHeaders - keep raw bytes of the link, network and transport headers in traces,
+// e.g. to export traces as pcapng
+func WithRawHeaders() TracerOpt {
+	return func(t *tracerImpl) {
+		t.conf.rawHeaders = true
+	}
+}
+
+// WithNflog - collect packets logged into the nflog groups besides nftrace,
+// 'copyRange' bytes of every packet are copied to decode its headers
+func WithNflog(groups []uint16, copyRange uint32) TracerOpt {
+	return func(t *tracerImpl) {
+		t.conf.nflog = &nflogConf{
+			groups:    groups,
+			copyRange: copyRange,
+		}
+	}
+}
+
+// WithoutNftrace - do not collect nftrace, e.g. when nflog is the only source of traces
+func WithoutNftrace() TracerOpt {
+	return func(t *tracerImpl) {
+		t.conf.nftrace = false
+	}
+}
+
+// WithNftraceFilter - drop nftrace messages rejected by the filter in kernel
+func WithNftraceFilter(spec nftrace.FilterSpec) TracerOpt {
+	return func(t *tracerImpl) {
+		t.conf.nftraceFilter = spec
+	}
+}
+
+// WithQueues - bound queues between stages of the pipelines, 'metrics' provides sink
+// of the queue statistics by the stage name, it may be nil
+func WithQueues(capacity int, policy bqueue.Policy, metrics func(stage string) bqueue.Metrics) TracerOpt {
+	return func(t *tracerImpl) {
+		t.conf.queue = queueConf{
+			capacity: capacity,
+			policy:   policy,
+			metrics:  metrics,
+		}
+	}
+}
+
+// NewTracer creates tracer
+func NewTracer(d Deps, tableSyncInterval time.Duration, opts ...TracerOpt) Tracer {
+	t := &tracerImpl{
+		Deps:     d,
+		conf:     pipelineConf{tableSyncInterval: tableSyncInterval, nftrace: true},
+		tblSync:  make(chan tableSyncReq),
+		drainReq: make(chan chan error),
+		stop:     make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(t)
+	}
+	t.que = bqueue.NewFIFO(
+		bqueue.WithSettings[trace.TraceModel](t.conf.queue.settings(StageTracer)),
+		bqueue.WithPriority(nftrace.IsDropped),
+	)
+	return t
+}
+
+func (t *tracerImpl) Run(ctx context.Context) (err error) {
+	var doRun bool
+	t.onceRun.Do(func() {
+		doRun = true
+		t.stopped = make(chan struct{})
+	})
+	if !doRun {
+		return ErrNsTracer{Err: errors.New("it has been run or closed yet")}
+	}
+
+	log := logger.FromContext(ctx).Named("ns-tracer")
+	log.Info("start")
+
+	var (
+		wg       sync.WaitGroup
+		results  = make(chan pipelineResult)
+		pipes    = make(map[string]*pipeline)
+		finished = make(chan struct{})
+	)
+	defer func() {
+		for _, p := range pipes {
+			p.cancel()
+		}
+		close(finished)
+		wg.Wait()
+		for _, p := range pipes {
+			p.close()
+		}
+		log.Info("stop")
+		close(t.stopped)
+	}()
+
+	start := func(ns netns.NetNS) error {
+		ctx1, cancel := context.WithCancel(
+			logger.ToContext(ctx, log.WithField("netns", ns.String())),
+		)
+		p, e := newPipeline(ctx1, t.Deps, ns, t.conf)
+		if e != nil {
+			cancel()
+			return e
+		}
+		p.cancel = cancel
+		pipes[ns.Id] = p
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e := p.run(ctx1, func(tr trace.TraceModel) { t.que.Put(tr) })
+			select {
+			case results <- pipelineResult{p, e}:
+			case <-finished:
+			}
+		}()
+		log.Infof("tracing in network namespace '%s' has started", ns)
+		return nil
+	}
+	stop := func(p *pipeline) {
+		p.cancel()
+		delete(pipes, p.ns.Id)
+		go p.close()
+	}
+	rescan := func() error {
+		nss, e := t.scan()
+		if e != nil {
+			return e
+		}
+		alive := make(map[string]struct{}, len(nss))
+		for _, ns := range nss {
+			alive[ns.Id] = struct{}{}
+			if _, ok := pipes[ns.Id]; ok {
+				continue
+			}
+			if e = start(ns); e != nil {
+				if ns.IsHost() {
+					return e
+				}
+				log.Warnf("unable to trace network namespace '%s': %v", ns, e)
+			}
+		}
+		for id, p := range pipes {
+			if _, ok := alive[id]; !ok {
+				log.Infof("network namespace '%s' has gone", p.ns)
+				stop(p)
+			}
+		}
+		return nil
+	}
+
+	if err = rescan(); err != nil {
+		return ErrNsTracer{Err: err}
+	}
+
+	var tick <-chan time.Time
+	if t.scanner != nil {
+		tc := time.NewTicker(t.rescanInterval)
+		defer tc.Stop()
+		tick = tc.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("will exit cause ctx canceled")
+			return ctx.Err()
+		case <-t.stop:
+			log.Info("will exit cause it has closed")
+			return nil
+		case <-tick:
+			if err = rescan(); err != nil {
+				log.Warnf("failed to rescan network namespaces: %v", err)
+			}
+		case done := <-t.drainReq:
+			tick = nil // namespaces are not traced anymore
+			ps := make([]*pipeline, 0, len(pipes))
+			for _, p := range pipes {
+				ps = append(ps, p)
+			}
+			go func() {
+				done <- t.drain(ctx, ps, log)
+			}()
+		case req := <-t.tblSync:
+			// pipelines started later on take the new interval
+			t.conf.tableSyncInterval = req.syncInterval
+			ps := make([]*pipeline, 0, len(pipes))
+			for _, p := range pipes {
+				ps = append(ps, p)
+			}
+			go func() {
+				var errs []error
+				for _, p := range ps {
+					if e := p.restartTables(req.syncInterval); e != nil {
+						errs = append(errs, errors.WithMessagef(e, "network namespace '%s'", p.ns))
+					}
+				}
+				req.done <- multierr.Combine(errs...)
+			}()
+		case r := <-results:
+			if pipes[r.p.ns.Id] != r.p {
+				continue //pipeline has been stopped already
+			}
+			if r.p.ns.IsHost() {
+				log.Info("will exit cause tracing in host network namespace has finished")
+				if err = r.err; err == nil {
+					err = errors.New("tracing in host network namespace has finished")
+				}
+				return ErrNsTracer{Err: err}
+			}
+			log.Warnf("tracing in network namespace '%s' has finished: %v", r.p.ns, r.err)
+			stop(r.p)
+		}
+	}
+}
+
+// Reader returns merged traces of all network namespaces
+func (t *tracerImpl) Reader() <-chan trace.TraceModel {
+	return t.que.Reader()
+}
+
+// drain - pipelines concurrently and seal the queue of traces when they all are drained
+func (t *tracerImpl) drain(ctx context.Context, ps []*pipeline, log logger.TypeOfLogger) error {
+	log.Infof("drain traces of %d network namespace(s)", len(ps))
+	errs := make([]error, len(ps))
+	var wg sync.WaitGroup
+	for i := range ps {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if e := ps[i].drain(ctx); e != nil {
+				errs[i] = errors.WithMessagef(e, "network namespace '%s'", ps[i].ns)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if err := multierr.Combine(errs...); err != nil {
+		return err
+	}
+	t.que.Seal()
+	log.Info("traces have been drained")
+	return nil
+}
+
+// Drain -
+func (t *tracerImpl) Drain(ctx context.Context) error {
+	done := make(chan error, 1)
+	select {
+	case t.drainReq <- done:
+	case <-t.stop:
+		return ErrNsTracer{Err: errors.New("it has closed")}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			return ErrNsTracer{Err: err}
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RestartTableSync -
+func (t *tracerImpl) RestartTableSync(ctx context.Context, syncInterval time.Duration) error {
+	if syncInterval < time.Second {
+		return ErrNsTracer{Err: errors.Errorf("table sync interval (%v) is less than 1s", syncInterval)}
+	}
+	req := tableSyncReq{syncInterval: syncInterval, done: make(chan error, 1)}
+	select {
+	case t.tblSync <- req:
+	case <-t.stop:
+		return ErrNsTracer{Err: errors.New("it has closed")}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-req.done:
+		if err != nil {
+			return ErrNsTracer{Err: err}
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close tracer
+func (t *tracerImpl) Close() error {
+	t.onceClose.Do(func() {
+		close(t.stop)
+		t.onceRun.Do(func() {})
+		if t.stopped != nil {
+			<-t.stopped
+		}
+		nftrace.NotifyAbandoned(t.AgentSubject, nftrace.AbandonTracer, len(t.que.Discard()))
+	})
+	return nil
+}
+
+func (t *tracerImpl) scan() ([]netns.NetNS, error) {
+	if t.scanner == nil {
+		return []netns.NetNS{{}}, nil
+	}
+	return t.scanner.Scan()
+}
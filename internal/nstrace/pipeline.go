@@ -0,0 +1,434 @@
+package nstrace
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/bqueue"
+	"github.com/wildberries-tech/pkt-tracer/internal/conntrack"
+	"github.com/wildberries-tech/pkt-tracer/internal/health"
+	iftrace "github.com/wildberries-tech/pkt-tracer/internal/iface"
+	"github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
+	"github.com/wildberries-tech/pkt-tracer/internal/nflog"
+	"github.com/wildberries-tech/pkt-tracer/internal/nfrule"
+	"github.com/wildberries-tech/pkt-tracer/internal/nftmonitor"
+	"github.com/wildberries-tech/pkt-tracer/internal/nftrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nl"
+	procowner "github.com/wildberries-tech/pkt-tracer/internal/providers/proc-owner"
+	sgnw "github.com/wildberries-tech/pkt-tracer/internal/providers/sg-network"
+
+	"github.com/H-BF/corlib/pkg/parallel"
+	"github.com/H-BF/corlib/pkg/patterns/observer"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"golang.org/x/sys/unix"
+)
+
+type (
+	// pipeline - set of the trace components working in the one network namespace
+	pipeline struct {
+		ns         netns.NetNS
+		subject    observer.Subject
+		nsFile     *os.File
+		nlWatcher  nl.NetlinkWatcher
+		ifTracer   iftrace.Iface
+		nfruler    nfrule.RuleTracer
+		tblMu      sync.Mutex
+		tblWatcher nftmonitor.TableWatcher
+		newTables  func(ctx context.Context, syncInterval time.Duration) (nftmonitor.TableWatcher, error)
+		tblRestart chan tableSyncReq
+		sources    []traceSource
+		procs      procowner.ProcessResolver
+		ctMirror   conntrack.Mirror
+		cancel     context.CancelFunc
+		done       <-chan struct{}
+		draining   atomic.Bool
+		forwarders sync.WaitGroup // forwarders of merged traces
+	}
+
+	// tableSyncReq - request to restart sync of tables with the new stream and interval
+	tableSyncReq struct {
+		syncInterval time.Duration
+		done         chan error
+	}
+
+	// traceSource - collector of the traces and merger of its own,
+	// so ids of traces of different sources never meet in the one merge buffer
+	traceSource struct {
+		name      string
+		collector nftrace.TraceCollector
+		merger    nftrace.TraceMerger
+	}
+
+	// pipelineConf - settings shared by pipelines of all network namespaces
+	pipelineConf struct {
+		tableSyncInterval time.Duration
+		procs             *procsConf
+		conntrack         bool
+		rawHeaders        bool
+		nftrace           bool
+		nftraceFilter     nftrace.FilterSpec
+		nflog             *nflogConf
+		queue             queueConf
+		sgRuleScope       sgnw.RuleScope
+	}
+
+	queueConf struct {
+		capacity int
+		policy   bqueue.Policy
+		metrics  func(stage string) bqueue.Metrics
+	}
+
+	nflogConf struct {
+		groups    []uint16
+		copyRange uint32
+	}
+
+	procsConf struct {
+		cacheSize int
+		procDir   string
+	}
+)
+
+// stallTimeout - instance of the component is unhealthy when it has not polled netlink for the time,
+// netlink is polled every second at least while the instance keeps up with the data
+const stallTimeout = 30 * time.Second
+
+// stages of the pipeline connected by queues
+const (
+	StageNftrace = "nftrace-collector"
+	StageNflog   = "nflog-collector"
+	StageMerger  = "merger"
+	StageTracer  = "tracer"
+)
+
+// settings - settings of the queue feeding the next stage after the stage
+func (c queueConf) settings(stage string) bqueue.Settings {
+	s := bqueue.Settings{
+		Capacity: c.capacity,
+		Policy:   c.policy,
+	}
+	if c.metrics != nil {
+		s.Metrics = c.metrics(stage)
+	}
+	return s
+}
+
+func newPipeline(ctx context.Context, d Deps, ns netns.NetNS, conf pipelineConf) (p *pipeline, err error) {
+	p = &pipeline{
+		ns:         ns,
+		subject:    d.AgentSubject,
+		tblRestart: make(chan tableSyncReq),
+		done:       ctx.Done(),
+	}
+	defer func() {
+		if err != nil {
+			p.close()
+		}
+	}()
+
+	fd := -1
+	if !ns.IsHost() {
+		// file keeps namespace referenced until pipeline is closed
+		if p.nsFile, err = os.Open(ns.Path); err != nil {
+			return nil, errors.WithMessagef(err, "failed to open network namespace '%s'", ns)
+		}
+		fd = int(p.nsFile.Fd())
+	}
+
+	p.ifTracer = iftrace.NewIface(d.AgentSubject, iftrace.IfaceWithNetNS(fd))
+
+	if p.nlWatcher, err = nl.NewNetlinkWatcher(2, unix.NETLINK_NETFILTER,
+		nl.SkWithBufLen(nl.SockBufLen16MB),
+		nl.SkWithNlMs(unix.NFNLGRP_NFTABLES),
+		nl.NlWithNetNS(fd),
+	); err != nil {
+		return nil, err
+	}
+
+	p.nfruler = nfrule.NewRuleTrace(nfrule.Deps{
+		AgentSubject: d.AgentSubject,
+		NlWatcher:    p.nlWatcher.Reader(0),
+	}, nfrule.RuleTraceWithNetNS(fd))
+
+	tblReader := p.nlWatcher.Reader(1)
+	p.newTables = func(ctx context.Context, syncInterval time.Duration) (nftmonitor.TableWatcher, error) {
+		tblCli, e := d.TablesStream(ctx)
+		if e != nil {
+			return nil, e
+		}
+		return nftmonitor.NewTableWatcher(nftmonitor.Deps{
+			Client:       tblCli,
+			AgentSubject: d.AgentSubject,
+			NlWatcher:    tblReader,
+		},
+			syncInterval,
+			nftmonitor.WatchWithNetNS(ns.Id, fd),
+		), nil
+	}
+	if p.tblWatcher, err = p.newTables(ctx, conf.tableSyncInterval); err != nil {
+		return nil, err
+	}
+
+	mergeOpts := []nftrace.TraceMergeOpt{
+		nftrace.MergeWithNetNS(ns),
+		nftrace.MergeWithQueue(conf.queue.settings(StageMerger)),
+	}
+	if d.SgRuleProvider != nil {
+		mergeOpts = append(mergeOpts,
+			nftrace.MergeWithSgRules(d.SgRuleProvider),
+			nftrace.MergeWithSgRuleScope(conf.sgRuleScope),
+		)
+	}
+	if d.ContainerProvider != nil {
+		mergeOpts = append(mergeOpts, nftrace.MergeWithContainers(d.ContainerProvider))
+	}
+	if conf.procs != nil {
+		if p.procs, err = procowner.NewProcessResolver(
+			procowner.ResolveWithNetNS(fd),
+			procowner.ResolveWithProcDir(conf.procs.procDir),
+			procowner.ResolveWithCacheSize(conf.procs.cacheSize),
+		); err != nil {
+			return nil, err
+		}
+		mergeOpts = append(mergeOpts, nftrace.MergeWithProcesses(p.procs))
+	}
+	if conf.conntrack {
+		if p.ctMirror, err = conntrack.NewMirror(d.AgentSubject, conntrack.MirrorWithNetNS(fd)); err != nil {
+			return nil, err
+		}
+		mergeOpts = append(mergeOpts, nftrace.MergeWithConntrack(p.ctMirror))
+	}
+
+	if conf.rawHeaders {
+		mergeOpts = append(mergeOpts, nftrace.MergeWithRawHeaders())
+	}
+
+	if conf.nftrace {
+		c, e := nftrace.NewCollector(d.AgentSubject,
+			nftrace.CollectWithNetNS(fd),
+			nftrace.CollectWithFilter(conf.nftraceFilter),
+			nftrace.CollectWithQueue(conf.queue.settings(StageNftrace)),
+		)
+		if e != nil {
+			return nil, e
+		}
+		p.sources = append(p.sources, traceSource{name: "nftrace", collector: c})
+	}
+	if conf.nflog != nil {
+		c, e := nflog.NewCollector(d.AgentSubject, conf.nflog.groups,
+			nflog.CollectWithNetNS(fd),
+			nflog.CollectWithCopyRange(conf.nflog.copyRange),
+			nflog.CollectWithQueue(conf.queue.settings(StageNflog)),
+		)
+		if e != nil {
+			return nil, e
+		}
+		p.sources = append(p.sources, traceSource{name: "nflog", collector: c})
+	}
+	mergeOpts = append(mergeOpts, nftrace.MergeWithAgentSubject(d.AgentSubject))
+	for i := range p.sources {
+		p.sources[i].merger = nftrace.NewTraceMerge(p.sources[i].collector,
+			p.ifTracer, p.nfruler, d.SgNetProvider, mergeOpts...)
+	}
+	p.forwarders.Add(len(p.sources))
+
+	return p, nil
+}
+
+// run runs components of the pipeline and forwards merged traces into 'out'
+func (p *pipeline) run(ctx context.Context, out func(trace.TraceModel)) error {
+	ctx1, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ff := []func() error{
+		func() error {
+			return p.ifTracer.Run(ctx1)
+		},
+		func() error {
+			return p.nfruler.Run(ctx1)
+		},
+		p.watchHealth(health.TableWatcher, p.ns.String(), func() time.Time {
+			return nl.LastPollOf(p.nlWatcher)
+		}, func() error {
+			return p.runTables(ctx1)
+		}),
+	}
+	for _, src := range p.sources {
+		src := src
+		instance := p.ns.String() + "/" + src.name
+		ff = append(ff,
+			p.watchHealth(health.Collector, instance, src.collector.LastPoll, func() error {
+				return src.collector.Run(ctx1)
+			}),
+			p.watchHealth(health.Merger, instance, nil, func() error {
+				return src.merger.Run(ctx1)
+			}),
+			func() error {
+				defer p.forwarders.Done()
+				que := src.merger.Reader()
+				for {
+					select {
+					case <-ctx1.Done():
+						return nil
+					case tr, ok := <-que:
+						if !ok {
+							return nil
+						}
+						out(tr)
+					}
+				}
+			},
+		)
+	}
+	if p.procs != nil {
+		ff = append(ff, func() error {
+			return p.procs.Run(ctx1)
+		})
+	}
+	if p.ctMirror != nil {
+		ff = append(ff, func() error {
+			return p.ctMirror.Run(ctx1)
+		})
+	}
+	errs := make([]error, len(ff))
+	_ = parallel.ExecAbstract(len(ff), int32(len(ff))-1, func(i int) error {
+		errs[i] = ff[i]()
+		// collectors, mergers and forwarders finish one by one on drain,
+		// the rest of components keep working for them until the pipeline is stopped
+		if errs[i] != nil || !p.draining.Load() {
+			cancel()
+		}
+		return nil
+	})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	return multierr.Combine(errs...)
+}
+
+// drain - stop collectors and let mergers flush traces, it returns when all traces
+// of the pipeline have been forwarded
+func (p *pipeline) drain(ctx context.Context) error {
+	p.draining.Store(true)
+	for _, src := range p.sources {
+		// merger is told first, it flushes traces when the queue of the drained collector is closed
+		_ = src.merger.Drain()
+		_ = src.collector.Drain()
+	}
+	forwarded := make(chan struct{})
+	go func() {
+		p.forwarders.Wait()
+		close(forwarded)
+	}()
+	select {
+	case <-forwarded:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runTables runs the table watcher, it is replaced by the new one on request to restart sync of tables
+func (p *pipeline) runTables(ctx context.Context) error {
+	for {
+		w := p.tableWatcher()
+		errc := make(chan error, 1)
+		go func() {
+			errc <- w.Run(ctx)
+		}()
+		for replaced := false; !replaced; {
+			select {
+			case err := <-errc:
+				return err
+			case req := <-p.tblRestart:
+				// the new stream is opened first, so the current watcher keeps working if it fails
+				next, err := p.newTables(ctx, req.syncInterval)
+				req.done <- err
+				if err != nil {
+					continue
+				}
+				_ = w.Close()
+				<-errc
+				p.tblMu.Lock()
+				p.tblWatcher = next
+				p.tblMu.Unlock()
+				replaced = true
+			}
+		}
+	}
+}
+
+// restartTables - restart sync of tables of the pipeline with the new stream and interval
+func (p *pipeline) restartTables(syncInterval time.Duration) error {
+	req := tableSyncReq{syncInterval: syncInterval, done: make(chan error, 1)}
+	select {
+	case p.tblRestart <- req:
+	case <-p.done:
+		return nil
+	}
+	return <-req.done
+}
+
+func (p *pipeline) tableWatcher() nftmonitor.TableWatcher {
+	p.tblMu.Lock()
+	defer p.tblMu.Unlock()
+	return p.tblWatcher
+}
+
+// watchHealth - report health of the component instance running by f,
+// it is healthy since start until it fails or stalls: lastPoll, if any, is not updated for stallTimeout
+func (p *pipeline) watchHealth(component, instance string, lastPoll func() time.Time, f func() error) func() error {
+	return func() error {
+		health.Report(p.subject, component, instance, nil)
+		var stalls sync.WaitGroup
+		ctx, stop := context.WithCancel(context.Background())
+		if lastPoll != nil {
+			stalls.Add(1)
+			go func() {
+				defer stalls.Done()
+				health.WatchStall(ctx, p.subject, component, instance, lastPoll, stallTimeout)
+			}()
+		}
+		err := f()
+		stop()
+		stalls.Wait()
+		health.ReportExit(p.subject, component, instance, err)
+		return err
+	}
+}
+
+func (p *pipeline) close() {
+	if p.ifTracer != nil {
+		_ = p.ifTracer.Close()
+	}
+	if p.nlWatcher != nil {
+		_ = p.nlWatcher.Close()
+	}
+	if p.nfruler != nil {
+		_ = p.nfruler.Close()
+	}
+	if w := p.tableWatcher(); w != nil {
+		_ = w.Close()
+	}
+	for _, src := range p.sources {
+		_ = src.collector.Close()
+		if src.merger != nil {
+			_ = src.merger.Close()
+		}
+	}
+	if p.procs != nil {
+		_ = p.procs.Close()
+	}
+	if p.ctMirror != nil {
+		_ = p.ctMirror.Close()
+	}
+	if p.nsFile != nil {
+		_ = p.nsFile.Close()
+	}
+}
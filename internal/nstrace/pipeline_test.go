@@ -0,0 +1,93 @@
+package nstrace
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/bqueue"
+	model "github.com/wildberries-tech/pkt-tracer/internal/models/nltrace"
+	"github.com/wildberries-tech/pkt-tracer/internal/nfrule"
+	"github.com/wildberries-tech/pkt-tracer/internal/nftrace"
+	sgnw "github.com/wildberries-tech/pkt-tracer/internal/providers/sg-network"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+type (
+	fakeCollector struct {
+		que *bqueue.FIFO[[]model.NetlinkTrace]
+	}
+
+	fakeDeps struct{}
+)
+
+func (f fakeCollector) Run(context.Context) error { return nil }
+
+func (f fakeCollector) Reader() <-chan []model.NetlinkTrace { return f.que.Reader() }
+
+// Drain seals the queue and, like the collector waiting for its netlink reader, returns a bit later
+func (f fakeCollector) Drain() error {
+	f.que.Seal()
+	time.Sleep(50 * time.Millisecond)
+	return nil
+}
+
+func (f fakeCollector) Close() error { return f.que.Close() }
+
+func (f fakeCollector) LastPoll() time.Time { return time.Time{} }
+
+func (fakeDeps) GetIface(int) (string, error) { return "eth0", nil }
+
+func (fakeDeps) GetRuleForTrace(*model.NetlinkTrace) (nfrule.RuleEntry, error) {
+	return nfrule.RuleEntry{}, nfrule.ErrNotFoundRule
+}
+
+func (fakeDeps) GetSGByIP(net.IP) (sgnw.SgNet, error) { return sgnw.SgNet{}, sgnw.ErrSgMiss }
+
+func Test_PipelineDrainEmptyQueue(t *testing.T) {
+	col := fakeCollector{que: bqueue.NewFIFO[[]model.NetlinkTrace]()}
+	defer col.Close()
+	m := nftrace.NewTraceMerge(col, fakeDeps{}, fakeDeps{}, fakeDeps{})
+	defer m.Close()
+	p := &pipeline{sources: []traceSource{{name: "nftrace", collector: col, merger: m}}}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- m.Run(context.Background())
+	}()
+	// incomplete trace is left in the merge buffer while the queue is empty
+	verdict := nftrace.NFT_JUMP
+	tr := model.NetlinkTrace{
+		Id:         1,
+		Type:       unix.NFT_TRACETYPE_RULE,
+		Table:      "filter",
+		Chain:      "input",
+		RuleHandle: 9,
+		Verdict:    uint32(verdict),
+		Family:     unix.NFPROTO_IPV4,
+		Iif:        2,
+		Flags: 1<<nftrace.NFTNL_TRACE_IIF | 1<<nftrace.NFTNL_TRACE_NETWORK_HEADER |
+			1<<nftrace.NFTNL_TRACE_RULE_HANDLE | 1<<nftrace.NFTNL_TRACE_VERDICT,
+		Source: model.SourceNftrace,
+	}
+	tr.Nh.SAddr = net.IPv4(10, 0, 0, 1)
+	tr.Nh.DAddr = net.IPv4(10, 0, 0, 2)
+	col.que.Put([]model.NetlinkTrace{tr})
+	require.Eventually(t, func() bool { return col.que.Len() == 0 }, time.Second, time.Millisecond)
+
+	require.NoError(t, p.drain(context.Background()))
+	select {
+	case err := <-errc:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		require.FailNow(t, "merger is not stopped by drain")
+	}
+	var ids []uint32
+	for msg := range m.Reader() {
+		ids = append(ids, msg.TrId)
+	}
+	require.Equal(t, []uint32{1}, ids)
+}
@@ -0,0 +1,186 @@
+package pcapng
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Packet - packet read from the pcapng file
+type Packet struct {
+	Interface
+	Timestamp time.Time
+	Data      []byte
+	// OrigLen - length of the packet on the wire
+	OrigLen uint32
+	Comment string
+}
+
+// Reader reads packets of the pcapng file, blocks other than interface descriptions and
+// enhanced packets are skipped
+type Reader struct {
+	r      io.Reader
+	order  binary.ByteOrder
+	ifaces []readerIface
+}
+
+type readerIface struct {
+	Interface
+	// units of the timestamps per second
+	tsUnits uint64
+}
+
+// NewReader reads section header of the pcapng file
+func NewReader(r io.Reader) (*Reader, error) {
+	ret := &Reader{r: r}
+	typ, _, err := ret.readBlock()
+	if err != nil {
+		return nil, err
+	}
+	if typ != blockSHB {
+		return nil, errors.New("pcapng file does not start with section header block")
+	}
+	return ret, nil
+}
+
+// Next reads the next packet, io.EOF is returned at the end of the file
+func (r *Reader) Next() (Packet, error) {
+	for {
+		typ, body, err := r.readBlock()
+		if err != nil {
+			return Packet{}, err
+		}
+		switch typ {
+		case blockSHB:
+			r.ifaces = r.ifaces[:0]
+		case blockIDB:
+			if err = r.readInterface(body); err != nil {
+				return Packet{}, err
+			}
+		case blockEPB:
+			return r.readPacket(body)
+		}
+	}
+}
+
+func (r *Reader) readInterface(body []byte) error {
+	if len(body) < 8 {
+		return errors.New("pcapng interface description block is truncated")
+	}
+	iface := readerIface{
+		Interface: Interface{LinkType: r.order.Uint16(body[0:])},
+		tsUnits:   1e6,
+	}
+	err := r.readOptions(body[8:], func(code uint16, val []byte) {
+		switch code {
+		case optIfName:
+			iface.Name = string(val)
+		case optIfTsResol:
+			if len(val) == 0 {
+				break
+			}
+			if exp := uint64(val[0] & 0x7f); val[0]&0x80 == 0 {
+				iface.tsUnits = uint64(math.Pow10(int(exp)))
+			} else {
+				iface.tsUnits = 1 << exp
+			}
+		}
+	})
+	if err == nil {
+		r.ifaces = append(r.ifaces, iface)
+	}
+	return err
+}
+
+func (r *Reader) readPacket(body []byte) (ret Packet, err error) {
+	if len(body) < 20 {
+		return ret, errors.New("pcapng enhanced packet block is truncated")
+	}
+	id := r.order.Uint32(body[0:])
+	if int(id) >= len(r.ifaces) {
+		return ret, errors.Errorf("pcapng packet refers to unknown interface %d", id)
+	}
+	iface := r.ifaces[id]
+	ts := uint64(r.order.Uint32(body[4:]))<<32 | uint64(r.order.Uint32(body[8:]))
+	capLen := int(r.order.Uint32(body[12:]))
+	if 20+capLen > len(body) {
+		return ret, errors.New("pcapng packet data is truncated")
+	}
+	ret = Packet{
+		Interface: iface.Interface,
+		Timestamp: time.Unix(int64(ts/iface.tsUnits), //nolint:gosec
+			int64(ts%iface.tsUnits*uint64(time.Second)/iface.tsUnits)).UTC(), //nolint:gosec
+		Data:    body[20 : 20+capLen],
+		OrigLen: r.order.Uint32(body[16:]),
+	}
+	err = r.readOptions(body[20+(capLen+3)/4*4:], func(code uint16, val []byte) {
+		if code == optComment {
+			ret.Comment = string(val)
+		}
+	})
+	return ret, err
+}
+
+func (r *Reader) readOptions(b []byte, fn func(code uint16, val []byte)) error {
+	for len(b) >= 4 {
+		code, l := r.order.Uint16(b), int(r.order.Uint16(b[2:]))
+		if code == optEnd {
+			return nil
+		}
+		if 4+l > len(b) {
+			return errors.New("pcapng block option is truncated")
+		}
+		fn(code, b[4:4+l])
+		b = b[min(4+(l+3)/4*4, len(b)):]
+	}
+	return nil
+}
+
+// readBlock reads type and body of the block, byte order is taken from the section header
+func (r *Reader) readBlock() (typ uint32, body []byte, err error) {
+	var hdr [8]byte
+	if _, err = io.ReadFull(r.r, hdr[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			err = errors.New("pcapng block header is truncated")
+		}
+		return 0, nil, err
+	}
+	if binary.LittleEndian.Uint32(hdr[:]) == blockSHB {
+		var magic [4]byte
+		if _, err = io.ReadFull(r.r, magic[:]); err != nil {
+			return 0, nil, errors.WithMessage(err, "failed to read pcapng section header")
+		}
+		switch {
+		case binary.LittleEndian.Uint32(magic[:]) == byteOrderMagic:
+			r.order = binary.LittleEndian
+		case binary.BigEndian.Uint32(magic[:]) == byteOrderMagic:
+			r.order = binary.BigEndian
+		default:
+			return 0, nil, errors.New("bad byte order magic of pcapng section header")
+		}
+		body, err = r.readBody(r.order.Uint32(hdr[4:]), magic[:])
+		return blockSHB, body, err
+	}
+	if r.order == nil {
+		return 0, nil, errors.New("pcapng block is out of section")
+	}
+	body, err = r.readBody(r.order.Uint32(hdr[4:]), nil)
+	return r.order.Uint32(hdr[:]), body, err
+}
+
+// readBody reads the rest of the block of the total length, 'head' is the part of the body read yet
+func (r *Reader) readBody(total uint32, head []byte) ([]byte, error) {
+	if total < 12 || total%4 != 0 {
+		return nil, errors.Errorf("bad length of pcapng block %d", total)
+	}
+	buf := make([]byte, total-8)
+	n := copy(buf, head)
+	if _, err := io.ReadFull(r.r, buf[n:]); err != nil {
+		return nil, errors.WithMessage(err, "pcapng block is truncated")
+	}
+	// trailing total length is not a part of the body
+	return buf[:len(buf)-4], nil
+}
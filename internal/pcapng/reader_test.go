@@ -0,0 +1,54 @@
+package pcapng
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type readerTestSuite struct {
+	suite.Suite
+}
+
+func Test_Reader(t *testing.T) {
+	suite.Run(t, new(readerTestSuite))
+}
+
+func (sui *readerTestSuite) Test_ReadWritten() {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "test")
+	sui.Require().NoError(err)
+	eth := Interface{Name: "agent1/eth0", LinkType: LinkTypeEthernet}
+	raw := Interface{Name: "agent2/any", LinkType: LinkTypeRaw}
+	ts := time.Date(2024, 12, 2, 12, 0, 0, 123456000, time.UTC)
+	sui.Require().NoError(w.WritePacket(eth, ts, []byte{1, 2, 3, 4, 5}, 60, "table=filter"))
+	sui.Require().NoError(w.WritePacket(raw, ts.Add(time.Second), []byte{6, 7, 8, 9}, 4, ""))
+
+	r, err := NewReader(&buf)
+	sui.Require().NoError(err)
+	p, err := r.Next()
+	sui.Require().NoError(err)
+	sui.Require().Equal(Packet{
+		Interface: eth,
+		Timestamp: ts,
+		Data:      []byte{1, 2, 3, 4, 5},
+		OrigLen:   60,
+		Comment:   "table=filter",
+	}, p)
+	p, err = r.Next()
+	sui.Require().NoError(err)
+	sui.Require().Equal(raw, p.Interface)
+	sui.Require().Equal(ts.Add(time.Second), p.Timestamp)
+	sui.Require().Equal([]byte{6, 7, 8, 9}, p.Data)
+	sui.Require().Empty(p.Comment)
+	_, err = r.Next()
+	sui.Require().ErrorIs(err, io.EOF)
+}
+
+func (sui *readerTestSuite) Test_NotPcapng() {
+	_, err := NewReader(bytes.NewReader([]byte("{\"trace_id\":1}\n")))
+	sui.Require().Error(err)
+}
@@ -0,0 +1,145 @@
+package pcapng
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// link types of the interfaces
+const (
+	LinkTypeEthernet uint16 = 1
+	LinkTypeRaw      uint16 = 101
+)
+
+// types of the blocks
+const (
+	blockSHB uint32 = 0x0A0D0D0A
+	blockIDB uint32 = 0x00000001
+	blockEPB uint32 = 0x00000006
+)
+
+// options of the blocks
+const (
+	optEnd         uint16 = 0
+	optComment     uint16 = 1
+	optIfName      uint16 = 2
+	optShbUserAppl uint16 = 4
+	optIfTsResol   uint16 = 9
+)
+
+const (
+	byteOrderMagic = 0x1A2B3C4D
+	// snapLen - packets are not truncated by the writer
+	snapLen = 0
+)
+
+// Interface - description of the capture interface
+type Interface struct {
+	Name     string
+	LinkType uint16
+}
+
+// Writer writes packets in the pcapng format, interfaces are described by IDB blocks
+// written before the first packet of the interface
+type Writer struct {
+	w      io.Writer
+	ifaces map[Interface]uint32
+}
+
+// NewWriter writes section header of the pcapng file, 'app' is the name of the application
+// which has written the file
+func NewWriter(w io.Writer, app string) (*Writer, error) {
+	var opts options
+	if app != "" {
+		opts.add(optShbUserAppl, []byte(app))
+	}
+	body := make([]byte, 16)
+	binary.LittleEndian.PutUint32(body[0:], byteOrderMagic)
+	binary.LittleEndian.PutUint16(body[4:], 1) // major version
+	binary.LittleEndian.PutUint16(body[6:], 0) // minor version
+	// length of the section is not specified
+	binary.LittleEndian.PutUint64(body[8:], math.MaxUint64)
+	ret := &Writer{w: w, ifaces: make(map[Interface]uint32)}
+	return ret, ret.writeBlock(blockSHB, body, opts)
+}
+
+// WritePacket writes packet captured on the interface, 'origLen' is the length of the packet on the wire
+func (w *Writer) WritePacket(iface Interface, ts time.Time, data []byte, origLen uint32, comment string) error {
+	id, err := w.interfaceId(iface)
+	if err != nil {
+		return err
+	}
+	if l := uint32(len(data)); origLen < l { //nolint:gosec
+		origLen = l
+	}
+	// timestamps are in microseconds (default resolution)
+	us := uint64(ts.UnixMicro()) //nolint:gosec
+	body := make([]byte, 20, 20+len(data)+3)
+	binary.LittleEndian.PutUint32(body[0:], id)
+	binary.LittleEndian.PutUint32(body[4:], uint32(us>>32))
+	binary.LittleEndian.PutUint32(body[8:], uint32(us))
+	binary.LittleEndian.PutUint32(body[12:], uint32(len(data))) //nolint:gosec
+	binary.LittleEndian.PutUint32(body[16:], origLen)
+	body = pad(append(body, data...))
+	var opts options
+	if comment != "" {
+		opts.add(optComment, []byte(comment))
+	}
+	return w.writeBlock(blockEPB, body, opts)
+}
+
+func (w *Writer) interfaceId(iface Interface) (uint32, error) {
+	if id, ok := w.ifaces[iface]; ok {
+		return id, nil
+	}
+	body := make([]byte, 8)
+	binary.LittleEndian.PutUint16(body[0:], iface.LinkType)
+	binary.LittleEndian.PutUint32(body[4:], snapLen)
+	var opts options
+	if iface.Name != "" {
+		opts.add(optIfName, []byte(iface.Name))
+	}
+	opts.add(optIfTsResol, []byte{6})
+	if err := w.writeBlock(blockIDB, body, opts); err != nil {
+		return 0, err
+	}
+	id := uint32(len(w.ifaces)) //nolint:gosec
+	w.ifaces[iface] = id
+	return id, nil
+}
+
+func (w *Writer) writeBlock(typ uint32, body []byte, opts options) error {
+	if len(opts) > 0 {
+		opts.add(optEnd, nil)
+	}
+	total := 12 + len(body) + len(opts)
+	buf := make([]byte, 0, total)
+	buf = binary.LittleEndian.AppendUint32(buf, typ)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(total)) //nolint:gosec
+	buf = append(buf, body...)
+	buf = append(buf, opts...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(total)) //nolint:gosec
+	_, err := w.w.Write(buf)
+	return errors.WithMessage(err, "failed to write pcapng block")
+}
+
+// options - encoded options of the block
+type options []byte
+
+func (o *options) add(code uint16, val []byte) {
+	*o = binary.LittleEndian.AppendUint16(*o, code)
+	*o = binary.LittleEndian.AppendUint16(*o, uint16(len(val))) //nolint:gosec
+	*o = pad(append(*o, val...))
+}
+
+// pad pads data to 32 bits
+func pad(b []byte) []byte {
+	for len(b)%4 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
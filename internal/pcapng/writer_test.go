@@ -0,0 +1,80 @@
+package pcapng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type writerTestSuite struct {
+	suite.Suite
+}
+
+func Test_Writer(t *testing.T) {
+	suite.Run(t, new(writerTestSuite))
+}
+
+type block struct {
+	typ  uint32
+	body []byte
+}
+
+func (sui *writerTestSuite) readBlocks(b []byte) (ret []block) {
+	for len(b) > 0 {
+		sui.Require().GreaterOrEqual(len(b), 12)
+		typ := binary.LittleEndian.Uint32(b)
+		l := int(binary.LittleEndian.Uint32(b[4:]))
+		sui.Require().Zero(l % 4)
+		sui.Require().LessOrEqual(l, len(b))
+		sui.Require().Equal(uint32(l), binary.LittleEndian.Uint32(b[l-4:])) //nolint:gosec
+		ret = append(ret, block{typ: typ, body: b[8 : l-4]})
+		b = b[l:]
+	}
+	return ret
+}
+
+func (sui *writerTestSuite) Test_Write() {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "visor-cli")
+	sui.Require().NoError(err)
+	eth0 := Interface{Name: "agent1/eth0", LinkType: LinkTypeEthernet}
+	tun0 := Interface{Name: "agent1/tun0", LinkType: LinkTypeRaw}
+	ts := time.Unix(1700000000, 123456000)
+	pkt := []byte{0x45, 0, 0, 40, 1, 2, 3}
+	sui.Require().NoError(w.WritePacket(eth0, ts, pkt, 54, "verdict=rule::drop"))
+	sui.Require().NoError(w.WritePacket(tun0, ts, pkt, 0, ""))
+	sui.Require().NoError(w.WritePacket(eth0, ts, pkt, 54, ""))
+
+	blocks := sui.readBlocks(buf.Bytes())
+	sui.Require().Len(blocks, 6)
+	types := make([]uint32, 0, len(blocks))
+	for _, b := range blocks {
+		types = append(types, b.typ)
+	}
+	sui.Require().Equal([]uint32{blockSHB, blockIDB, blockEPB, blockIDB, blockEPB, blockEPB}, types)
+	sui.Require().Equal(uint32(byteOrderMagic), binary.LittleEndian.Uint32(blocks[0].body))
+	sui.Require().Contains(string(blocks[0].body), "visor-cli")
+
+	sui.Require().Equal(LinkTypeEthernet, binary.LittleEndian.Uint16(blocks[1].body))
+	sui.Require().Contains(string(blocks[1].body), "agent1/eth0")
+	sui.Require().Equal(LinkTypeRaw, binary.LittleEndian.Uint16(blocks[3].body))
+
+	epb := blocks[2].body
+	sui.Require().Equal(uint32(0), binary.LittleEndian.Uint32(epb))
+	us := uint64(binary.LittleEndian.Uint32(epb[4:]))<<32 | uint64(binary.LittleEndian.Uint32(epb[8:]))
+	sui.Require().Equal(uint64(ts.UnixMicro()), us)                             //nolint:gosec
+	sui.Require().Equal(uint32(len(pkt)), binary.LittleEndian.Uint32(epb[12:])) //nolint:gosec
+	sui.Require().Equal(uint32(54), binary.LittleEndian.Uint32(epb[16:]))
+	sui.Require().Equal(pkt, epb[20:20+len(pkt)])
+	opt := epb[20+(len(pkt)+3)/4*4:]
+	sui.Require().Equal(optComment, binary.LittleEndian.Uint16(opt))
+	sui.Require().Equal("verdict=rule::drop", string(opt[4:4+binary.LittleEndian.Uint16(opt[2:])]))
+
+	// original length is never less than the captured one
+	sui.Require().Equal(uint32(1), binary.LittleEndian.Uint32(blocks[4].body))
+	sui.Require().Equal(uint32(len(pkt)), binary.LittleEndian.Uint32(blocks[4].body[16:])) //nolint:gosec
+	sui.Require().Equal(uint32(0), binary.LittleEndian.Uint32(blocks[5].body))
+}
@@ -0,0 +1,105 @@
+package containermeta
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type containerMetaTestSuite struct {
+	suite.Suite
+	root string
+}
+
+func Test_ContainerMeta(t *testing.T) {
+	suite.Run(t, new(containerMetaTestSuite))
+}
+
+func (sui *containerMetaTestSuite) SetupTest() {
+	sui.root = sui.T().TempDir()
+}
+
+func (sui *containerMetaTestSuite) write(p, data string) string {
+	p = filepath.Join(sui.root, p)
+	sui.Require().NoError(os.MkdirAll(filepath.Dir(p), 0o755))
+	sui.Require().NoError(os.WriteFile(p, []byte(data), 0o600))
+	return p
+}
+
+func (sui *containerMetaTestSuite) Test_Cache() {
+	var cache Cache
+	sui.Require().Nil(cache.Find("ns1", 0, "veth1"))
+	cache.Init(
+		[]Entry{
+			{NetNS: "", Iface: "veth1", Id: "c1"},
+			{NetNS: "ns1", Id: "c2"},
+		},
+		map[uint64]Container{10: {Id: "c3"}},
+	)
+	sui.Require().Equal("c1", cache.Find("", 0, "eth0", "veth1").Id)
+	sui.Require().Nil(cache.Find("", 0, "eth0"))
+	sui.Require().Equal("c2", cache.Find("ns1", 10, "veth1").Id)
+	sui.Require().Equal("c3", cache.Find("ino:10", 10).Id)
+	cache.Clear()
+	sui.Require().Nil(cache.Find("ino:10", 10))
+}
+
+func (sui *containerMetaTestSuite) Test_LoadStatic() {
+	p := sui.write("map.json", `[{"netns":"ns1","iface":"eth0","id":"c1","name":"web",
+		"pod":"web-0","namespace":"prod","labels":{"tier":"front","app":"web"}}]`)
+	ret, err := LoadStatic(p)
+	sui.Require().NoError(err)
+	sui.Require().Len(ret, 1)
+	ct := ret[0].Container()
+	sui.Require().Equal("web-0", ct.Pod)
+	sui.Require().Equal([]string{"app=web", "tier=front"}, ct.LabelsList())
+
+	_, err = LoadStatic(sui.write("bad.json", "{"))
+	sui.Require().Error(err)
+}
+
+func (sui *containerMetaTestSuite) Test_LoadRuntime() {
+	nsPath := sui.write("proc/100/ns/net", "")
+	sui.Require().NoError(os.MkdirAll(filepath.Join(sui.root, "proc", "200", "ns"), 0o755))
+	sui.Require().NoError(os.Symlink(nsPath, filepath.Join(sui.root, "proc", "200", "ns", "net")))
+	sui.write("containers/a/config.v2.json", `{"ID":"a","Name":"/k8s_POD_web-0",
+		"Config":{"Labels":{"io.kubernetes.docker.type":"podsandbox","io.kubernetes.pod.name":"web-0"}},
+		"State":{"Running":true,"Pid":100}}`)
+	sui.write("containers/b/config.v2.json", `{"ID":"b","Name":"/k8s_web_web-0",
+		"Config":{"Labels":{"io.kubernetes.pod.name":"web-0","io.kubernetes.pod.namespace":"prod"}},
+		"State":{"Running":true,"Pid":200}}`)
+	sui.write("containers/c/config.v2.json", `{"ID":"c","State":{"Running":false}}`)
+	sui.write("containers/d/config.v2.json", `{"ID":"d","State":`)
+
+	ret, err := LoadRuntime(context.Background(), filepath.Join(sui.root, "containers"), filepath.Join(sui.root, "proc"))
+	sui.Require().NoError(err)
+	sui.Require().Len(ret, 1)
+	ino, err := netns.Inode(nsPath)
+	sui.Require().NoError(err)
+	ct := ret[ino]
+	sui.Require().Equal("b", ct.Id)
+	sui.Require().Equal("k8s_web_web-0", ct.Name)
+	sui.Require().Equal("web-0", ct.Pod)
+	sui.Require().Equal("prod", ct.Namespace)
+}
+
+func (sui *containerMetaTestSuite) Test_Collector() {
+	_, err := NewContainerCollector(time.Second)
+	sui.Require().Error(err)
+
+	p := sui.write("map.json", `[{"iface":"veth1","id":"c1"}]`)
+	c, err := NewContainerCollector(time.Second, WithStaticFile(p))
+	sui.Require().NoError(err)
+	defer c.Close()
+	ct, err := c.GetContainer(netns.NetNS{}, "veth1")
+	sui.Require().NoError(err)
+	sui.Require().Equal("c1", ct.Id)
+	_, err = c.GetContainer(netns.NetNS{Id: "ns1", Ino: 1}, "veth1")
+	sui.Require().ErrorIs(err, ErrContainerMiss)
+}
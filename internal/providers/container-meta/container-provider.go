@@ -0,0 +1,160 @@
+package containermeta
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/pkg/errors"
+)
+
+type (
+	// ContainerCollector - provides container metadata of the interface in network namespace
+	ContainerCollector interface {
+		Run(ctx context.Context) error
+		GetContainer(ns netns.NetNS, ifaces ...string) (Container, error)
+		Close() error
+	}
+
+	// CollectorOpt - option of the collector
+	CollectorOpt func(*collectorImpl)
+
+	collectorImpl struct {
+		cached         Cache
+		staticFile     string
+		runtimeDir     string
+		procDir        string
+		reloadInterval time.Duration
+		onceRun        sync.Once
+		onceClose      sync.Once
+		stop           chan struct{}
+		stopped        chan struct{}
+	}
+)
+
+// WithStaticFile - take containers from the static JSON mapping file
+func WithStaticFile(path string) CollectorOpt {
+	return func(c *collectorImpl) {
+		c.staticFile = path
+	}
+}
+
+// WithRuntimeDir - take running containers from the state directory of the container runtime,
+// network namespaces of the containers are resolved through procfs
+func WithRuntimeDir(dir, procDir string) CollectorOpt {
+	return func(c *collectorImpl) {
+		c.runtimeDir = dir
+		c.procDir = procDir
+	}
+}
+
+// NewContainerCollector creates collector and loads containers from the sources given by options.
+// Sources are reloaded every 'd'
+func NewContainerCollector(d time.Duration, opts ...CollectorOpt) (ContainerCollector, error) {
+	if d < time.Second {
+		panic(
+			fmt.Errorf("'Containers/ReloadInterval' is (%v) less than 1s", d),
+		)
+	}
+	c := &collectorImpl{
+		reloadInterval: d,
+		procDir:        netns.DefProcDir,
+		stop:           make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.staticFile == "" && c.runtimeDir == "" {
+		return nil, ErrContainerMeta{Err: errors.New("no source of containers is specified")}
+	}
+	if err := c.reload(context.Background()); err != nil {
+		return nil, ErrContainerMeta{Err: err}
+	}
+	return c, nil
+}
+
+// Run -
+func (c *collectorImpl) Run(ctx context.Context) (err error) {
+	var doRun bool
+	c.onceRun.Do(func() {
+		doRun = true
+		c.stopped = make(chan struct{})
+	})
+	if !doRun {
+		return ErrContainerMeta{Err: errors.New("it has been run or closed yet")}
+	}
+
+	log := logger.FromContext(ctx).Named("container-collector")
+	log.Info("start")
+	defer func() {
+		log.Info("stop")
+		close(c.stopped)
+	}()
+
+	tc := time.NewTicker(c.reloadInterval)
+	defer tc.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("will exit cause ctx canceled")
+			return ctx.Err()
+		case <-c.stop:
+			log.Info("will exit cause it has closed")
+			return nil
+		case <-tc.C:
+			if e := c.reload(ctx); e != nil {
+				log.Warnf("failed to reload containers: %v", e)
+			}
+		}
+	}
+}
+
+// GetContainer returns container owning one of the interfaces or the network namespace itself
+func (c *collectorImpl) GetContainer(ns netns.NetNS, ifaces ...string) (ct Container, err error) {
+	var ino uint64
+	if !ns.IsHost() {
+		ino = ns.Ino
+	}
+	item := c.cached.Find(ns.Id, ino, ifaces...)
+	if item == nil {
+		err = ErrContainerMiss
+	} else {
+		ct = *item
+	}
+	return ct, err
+}
+
+// Close -
+func (c *collectorImpl) Close() error {
+	c.onceClose.Do(func() {
+		close(c.stop)
+		c.onceRun.Do(func() {})
+		if c.stopped != nil {
+			<-c.stopped
+		}
+	})
+	return nil
+}
+
+func (c *collectorImpl) reload(ctx context.Context) (err error) {
+	var (
+		entries []Entry
+		byIno   map[uint64]Container
+	)
+	if c.staticFile != "" {
+		if entries, err = LoadStatic(c.staticFile); err != nil {
+			return err
+		}
+	}
+	if c.runtimeDir != "" {
+		if byIno, err = LoadRuntime(ctx, c.runtimeDir, c.procDir); err != nil {
+			return err
+		}
+	}
+	c.cached.Init(entries, byIno)
+	return nil
+}
@@ -0,0 +1,110 @@
+package containermeta
+
+import (
+	"sort"
+	"sync"
+)
+
+type (
+	// Container - metadata of the container owning network namespace or interface
+	Container struct {
+		Id        string
+		Name      string
+		Pod       string
+		Namespace string
+		Labels    map[string]string
+	}
+
+	// Entry - item of the static mapping file. Container is matched by interface name
+	// within network namespace, empty interface matches any interface of the namespace
+	Entry struct {
+		NetNS     string            `json:"netns"`
+		Iface     string            `json:"iface"`
+		Id        string            `json:"id"`
+		Name      string            `json:"name"`
+		Pod       string            `json:"pod"`
+		Namespace string            `json:"namespace"`
+		Labels    map[string]string `json:"labels"`
+	}
+
+	ifaceKey struct {
+		netns string
+		iface string
+	}
+
+	// Cache - containers indexed by network namespace and interface
+	Cache struct {
+		mu      sync.RWMutex
+		byIface map[ifaceKey]*Container
+		byIno   map[uint64]*Container
+	}
+)
+
+// LabelsList returns labels in the form 'key=value' sorted by key
+func (c Container) LabelsList() []string {
+	if len(c.Labels) == 0 {
+		return nil
+	}
+	ret := make([]string, 0, len(c.Labels))
+	for k, v := range c.Labels {
+		ret = append(ret, k+"="+v)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Container -
+func (e Entry) Container() Container {
+	return Container{
+		Id:        e.Id,
+		Name:      e.Name,
+		Pod:       e.Pod,
+		Namespace: e.Namespace,
+		Labels:    e.Labels,
+	}
+}
+
+// Init fills cache with static entries and containers of runtime keyed by namespace inode
+func (c *Cache) Init(entries []Entry, byIno map[uint64]Container) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byIface = make(map[ifaceKey]*Container, len(entries))
+	for i := range entries {
+		ct := entries[i].Container()
+		c.byIface[ifaceKey{entries[i].NetNS, entries[i].Iface}] = &ct
+	}
+	c.byIno = make(map[uint64]*Container, len(byIno))
+	for ino, ct := range byIno {
+		ct := ct
+		c.byIno[ino] = &ct
+	}
+}
+
+// Find looks for container by interface of the namespace at first, then by namespace itself
+func (c *Cache) Find(netns string, ino uint64, ifaces ...string) *Container {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, iface := range ifaces {
+		if iface == "" {
+			continue
+		}
+		if ct, ok := c.byIface[ifaceKey{netns, iface}]; ok {
+			return ct
+		}
+	}
+	if ct, ok := c.byIface[ifaceKey{netns: netns}]; ok {
+		return ct
+	}
+	if ino != 0 {
+		return c.byIno[ino]
+	}
+	return nil
+}
+
+// Clear -
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byIface = nil
+	c.byIno = nil
+}
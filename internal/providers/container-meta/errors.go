@@ -0,0 +1,24 @@
+package containermeta
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ErrContainerMeta -
+type ErrContainerMeta struct {
+	Err error
+}
+
+// Error -
+func (e ErrContainerMeta) Error() string {
+	return fmt.Sprintf("ContainerMeta: %v", e.Err)
+}
+
+// Cause -
+func (e ErrContainerMeta) Cause() error {
+	return e.Err
+}
+
+var ErrContainerMiss = errors.New("container cache miss")
@@ -0,0 +1,97 @@
+package containermeta
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefRuntimeDir - state directory of the docker containers
+	DefRuntimeDir = "/var/lib/docker/containers"
+
+	runtimeConfigFile = "config.v2.json"
+
+	labelPodName      = "io.kubernetes.pod.name"
+	labelPodNamespace = "io.kubernetes.pod.namespace"
+	labelContainerTyp = "io.kubernetes.docker.type"
+	podSandbox        = "podsandbox"
+)
+
+// runtimeConfig - part of the container state saved by runtime we are interested in
+type runtimeConfig struct {
+	ID     string `json:"ID"`
+	Name   string `json:"Name"`
+	Config struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	State struct {
+		Running bool `json:"Running"`
+		Pid     int  `json:"Pid"`
+	} `json:"State"`
+}
+
+// LoadStatic reads static mapping file which is JSON array of entries
+func LoadStatic(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read '%s'", path)
+	}
+	var ret []Entry
+	if err = json.Unmarshal(data, &ret); err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse '%s'", path)
+	}
+	return ret, nil
+}
+
+// LoadRuntime reads state of the running containers from runtime directory and
+// returns them keyed by inode of the network namespace of the container process,
+// containers with malformed config are skipped
+func LoadRuntime(ctx context.Context, dir, procDir string) (map[uint64]Container, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read '%s'", dir)
+	}
+	ret := make(map[uint64]Container)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		data, e1 := os.ReadFile(filepath.Join(dir, e.Name(), runtimeConfigFile))
+		if e1 != nil {
+			continue //container has been just removed or it is not a container
+		}
+		var cfg runtimeConfig
+		if e1 = json.Unmarshal(data, &cfg); e1 != nil {
+			logger.Warnf(ctx, "skip container '%s' cause its config is malformed: %v", e.Name(), e1)
+			continue
+		}
+		if !cfg.State.Running || cfg.State.Pid <= 0 {
+			continue
+		}
+		ino, e1 := netns.Inode(filepath.Join(procDir, strconv.Itoa(cfg.State.Pid), "ns", "net"))
+		if e1 != nil {
+			continue //process has gone
+		}
+		// containers of the pod share namespace, the pod sandbox is the less informative one
+		if prev, ok := ret[ino]; ok && prev.Labels[labelContainerTyp] != podSandbox {
+			continue
+		}
+		ret[ino] = Container{
+			Id:        cfg.ID,
+			Name:      strings.TrimPrefix(cfg.Name, "/"),
+			Pod:       cfg.Config.Labels[labelPodName],
+			Namespace: cfg.Config.Labels[labelPodNamespace],
+			Labels:    cfg.Config.Labels,
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,53 @@
+package procowner
+
+import (
+	"net"
+)
+
+type (
+	// Conn - connection (5-tuple) of the traced packet
+	Conn struct {
+		Proto uint8
+		SAddr net.IP
+		DAddr net.IP
+		SPort uint16
+		DPort uint16
+	}
+
+	// Process - process owning local socket of the connection
+	Process struct {
+		// Inode - inode of the socket
+		Inode uint64
+		// Pid - id of the process
+		Pid uint32
+		// Comm - command name of the process
+		Comm string
+		// Cgroup - cgroup path of the process
+		Cgroup string
+	}
+
+	connKey struct {
+		proto        uint8
+		saddr, daddr [16]byte
+		sport, dport uint16
+	}
+)
+
+// Reverse returns connection seen from the other side
+func (c Conn) Reverse() Conn {
+	return Conn{
+		Proto: c.Proto,
+		SAddr: c.DAddr,
+		DAddr: c.SAddr,
+		SPort: c.DPort,
+		DPort: c.SPort,
+	}
+}
+
+func (c Conn) key() (k connKey) {
+	k.proto = c.Proto
+	copy(k.saddr[:], c.SAddr.To16())
+	copy(k.daddr[:], c.DAddr.To16())
+	k.sport, k.dport = c.SPort, c.DPort
+	return k
+}
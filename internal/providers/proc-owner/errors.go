@@ -0,0 +1,24 @@
+package procowner
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ErrProcOwner -
+type ErrProcOwner struct {
+	Err error
+}
+
+// Error -
+func (e ErrProcOwner) Error() string {
+	return fmt.Sprintf("ProcOwner: %v", e.Err)
+}
+
+// Cause -
+func (e ErrProcOwner) Cause() error {
+	return e.Err
+}
+
+var ErrProcMiss = errors.New("process owning socket is not found")
@@ -0,0 +1,214 @@
+package procowner
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/lru"
+	"github.com/wildberries-tech/pkt-tracer/internal/nl"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/sys/unix"
+)
+
+type procOwnerTestSuite struct {
+	suite.Suite
+	procDir string
+}
+
+func Test_ProcOwner(t *testing.T) {
+	suite.Run(t, new(procOwnerTestSuite))
+}
+
+func (sui *procOwnerTestSuite) SetupTest() {
+	sui.procDir = sui.T().TempDir()
+}
+
+func (sui *procOwnerTestSuite) mkProc(pid, comm, cgroup string, sockets ...string) {
+	dir := filepath.Join(sui.procDir, pid)
+	sui.Require().NoError(os.MkdirAll(filepath.Join(dir, "fd"), 0o755))
+	sui.Require().NoError(os.WriteFile(filepath.Join(dir, "comm"), []byte(comm+"\n"), 0o600))
+	sui.Require().NoError(os.WriteFile(filepath.Join(dir, "cgroup"), []byte(cgroup), 0o600))
+	for i, s := range sockets {
+		sui.Require().NoError(os.Symlink(s, filepath.Join(dir, "fd", string(rune('3'+i)))))
+	}
+}
+
+// fakeSockDiag answers requests whose local end is known
+type fakeSockDiag struct {
+	data   chan nl.NlData
+	inodes map[uint16]uint32 //local port -> inode
+}
+
+func (f *fakeSockDiag) Reader(int) nl.NlReader { return f }
+func (f *fakeSockDiag) Read() chan nl.NlData   { return f.data }
+func (f *fakeSockDiag) Close() error           { return nil }
+func (f *fakeSockDiag) Send(b []byte) error {
+	seq := binary.NativeEndian.Uint32(b[8:12])
+	sport := binary.BigEndian.Uint16(b[unix.NLMSG_HDRLEN+8:])
+	msg := syscall.NetlinkMessage{Header: syscall.NlMsghdr{Seq: seq}}
+	if ino, ok := f.inodes[sport]; ok {
+		msg.Header.Type = unix.SOCK_DIAG_BY_FAMILY
+		msg.Data = make([]byte, sizeofInetDiagMsg)
+		binary.NativeEndian.PutUint32(msg.Data[sizeofInetDiagMsg-4:], ino)
+	} else {
+		msg.Header.Type = unix.NLMSG_ERROR
+		msg.Data = make([]byte, 4)
+		errno := -int32(unix.ENOENT)
+		binary.NativeEndian.PutUint32(msg.Data, uint32(errno)) //nolint:gosec
+	}
+	go func() { f.data <- nl.NlData{Messages: []syscall.NetlinkMessage{msg}} }()
+	return nil
+}
+
+func (sui *procOwnerTestSuite) Test_EncodeSockDiagReq() {
+	b, err := encodeSockDiagReq(7, Conn{
+		Proto: unix.IPPROTO_TCP,
+		SAddr: net.ParseIP("10.0.0.1"),
+		DAddr: net.ParseIP("10.0.0.2"),
+		SPort: 80,
+		DPort: 40000,
+	})
+	sui.Require().NoError(err)
+	sui.Require().Len(b, unix.NLMSG_HDRLEN+sizeofInetDiagReqV2)
+	msgs, err := syscall.ParseNetlinkMessage(b)
+	sui.Require().NoError(err)
+	sui.Require().Len(msgs, 1)
+	sui.Require().Equal(uint16(unix.SOCK_DIAG_BY_FAMILY), msgs[0].Header.Type)
+	sui.Require().Equal(uint32(7), msgs[0].Header.Seq)
+	d := msgs[0].Data
+	sui.Require().Equal(byte(unix.AF_INET), d[0])
+	sui.Require().Equal(byte(unix.IPPROTO_TCP), d[1])
+	sui.Require().Equal(uint16(80), binary.BigEndian.Uint16(d[8:]))
+	sui.Require().Equal(uint16(40000), binary.BigEndian.Uint16(d[10:]))
+	sui.Require().Equal([]byte{10, 0, 0, 1}, d[12:16])
+	sui.Require().Equal([]byte{10, 0, 0, 2}, d[28:32])
+
+	_, err = encodeSockDiagReq(1, Conn{Proto: unix.IPPROTO_UDP})
+	sui.Require().Error(err)
+}
+
+func (sui *procOwnerTestSuite) Test_ParseCgroup() {
+	sui.Require().Equal("/system.slice/nginx.service",
+		parseCgroup([]byte("0::/system.slice/nginx.service\n")))
+	sui.Require().Equal("/docker/3a9f",
+		parseCgroup([]byte("12:cpu,cpuacct:/docker/3a9f\n1:name=systemd:/docker/3a9f\n")))
+	sui.Require().Equal("", parseCgroup(nil))
+}
+
+func (sui *procOwnerTestSuite) Test_ProcIndex() {
+	sui.mkProc("10", "nginx", "0::/nginx\n", "socket:[100]", "pipe:[5]")
+	sui.mkProc("20", "curl", "0::/curl\n", "socket:[200]")
+	idx := newProcIndex(sui.procDir, time.Hour)
+	p, err := idx.find(100)
+	sui.Require().NoError(err)
+	sui.Require().Equal(Process{Inode: 100, Pid: 10, Comm: "nginx", Cgroup: "/nginx"}, p)
+
+	// socket created after scan is not found until rescan is allowed
+	sui.mkProc("30", "sshd", "0::/sshd\n", "socket:[300]")
+	_, err = idx.find(300)
+	sui.Require().ErrorIs(err, ErrProcMiss)
+	idx.minRescan = 0
+	p, err = idx.find(300)
+	sui.Require().NoError(err)
+	sui.Require().Equal(uint32(30), p.Pid)
+}
+
+func (sui *procOwnerTestSuite) Test_GetProcess() {
+	sui.mkProc("10", "nginx", "0::/nginx\n", "socket:[100]")
+	r := &resolverImpl{
+		nlWatcher: &fakeSockDiag{
+			data:   make(chan nl.NlData),
+			inodes: map[uint16]uint32{80: 100},
+		},
+		timeout: time.Second,
+		cache:   lru.NewCache[connKey, cacheEntry](16),
+		procs:   newProcIndex(sui.procDir, 0),
+		pending: make(map[uint32]chan sockDiagReply),
+		lookups: make(chan Conn, 16),
+		workers: 1,
+		stop:    make(chan struct{}),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = r.Run(ctx) }()
+	defer r.Close()
+
+	out := Conn{
+		Proto: unix.IPPROTO_TCP,
+		SAddr: net.ParseIP("10.0.0.1"),
+		DAddr: net.ParseIP("10.0.0.2"),
+		SPort: 80,
+		DPort: 40000,
+	}
+	// connection is looked up in background, it is missed until the lookup is done
+	_, err := r.GetProcess(out)
+	sui.Require().ErrorIs(err, ErrProcMiss)
+	var p Process
+	sui.Require().Eventually(func() bool {
+		p, err = r.GetProcess(out)
+		return err == nil
+	}, time.Second, time.Millisecond)
+	sui.Require().Equal("nginx", p.Comm)
+
+	// packet received by the socket is resolved through reversed connection
+	sui.Require().Eventually(func() bool {
+		p, err = r.GetProcess(out.Reverse())
+		return err == nil
+	}, time.Second, time.Millisecond)
+	sui.Require().Equal(uint32(10), p.Pid)
+	sui.Require().Equal(2, r.cache.Len())
+
+	out.SPort = 81
+	_, err = r.GetProcess(out)
+	sui.Require().ErrorIs(err, ErrProcMiss)
+	sui.Require().Eventually(func() bool {
+		e, ok := r.cache.Get(out.key())
+		return ok && e.missed && len(r.lookups) == 0
+	}, time.Second, time.Millisecond)
+	_, err = r.GetProcess(out)
+	sui.Require().ErrorIs(err, ErrProcMiss)
+	_, err = r.GetProcess(Conn{Proto: unix.IPPROTO_ICMP})
+	sui.Require().ErrorIs(err, ErrProcMiss)
+}
+
+func (sui *procOwnerTestSuite) Test_GetProcessQueue() {
+	r := &resolverImpl{
+		cache:   lru.NewCache[connKey, cacheEntry](16),
+		lookups: make(chan Conn),
+	}
+	c := Conn{
+		Proto: unix.IPPROTO_TCP,
+		SAddr: net.ParseIP("10.0.0.1"),
+		DAddr: net.ParseIP("10.0.0.2"),
+		SPort: 80,
+		DPort: 40000,
+	}
+	// nobody takes the lookup, connection is not left missed
+	_, err := r.GetProcess(c)
+	sui.Require().ErrorIs(err, ErrProcMiss)
+	_, ok := r.cache.Get(c.key())
+	sui.Require().False(ok)
+
+	// lookup resolved at once is not overwritten by the placeholder of the missed connection
+	resolved := make(chan struct{})
+	go func() {
+		defer close(resolved)
+		r.cache.Put((<-r.lookups).key(), cacheEntry{proc: Process{Pid: 10}})
+	}()
+	sui.Require().Eventually(func() bool {
+		_, err = r.GetProcess(c)
+		_, ok = r.cache.Get(c.key())
+		return ok
+	}, time.Second, time.Millisecond)
+	<-resolved
+	p, err := r.GetProcess(c)
+	sui.Require().NoError(err)
+	sui.Require().Equal(uint32(10), p.Pid)
+}
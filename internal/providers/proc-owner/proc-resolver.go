@@ -0,0 +1,306 @@
+package procowner
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/lru"
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
+	"github.com/wildberries-tech/pkt-tracer/internal/nl"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// DefCacheSize - default number of connections kept in cache
+	DefCacheSize = 4096
+
+	defLookupTimeout = 100 * time.Millisecond
+	defMissTTL       = 5 * time.Second
+	defMinRescan     = time.Second
+	defQueueSize     = 1024
+	defLookupWorkers = 4
+)
+
+type (
+	// ProcessResolver - resolves connection of the traced packet to the local process owning socket
+	ProcessResolver interface {
+		Run(ctx context.Context) error
+		// GetProcess never blocks: connection missed in cache is looked up in background
+		// and ErrProcMiss is returned until the lookup is done
+		GetProcess(c Conn) (Process, error)
+		Close() error
+	}
+
+	// ResolverOpt - option of the resolver
+	ResolverOpt func(*resolverImpl)
+
+	sockDiagReply struct {
+		inode uint32
+		err   error
+	}
+
+	cacheEntry struct {
+		proc    Process
+		missed  bool
+		expires time.Time
+	}
+
+	resolverImpl struct {
+		nlWatcher nl.NetlinkWatcher
+		netnsFd   int
+		procDir   string
+		cacheSize int
+		timeout   time.Duration
+		cache     *lru.Cache[connKey, cacheEntry]
+		procs     *procIndex
+		seq       atomic.Uint32
+		mu        sync.Mutex
+		pending   map[uint32]chan sockDiagReply
+		lookups   chan Conn
+		workers   int
+		onceRun   sync.Once
+		onceClose sync.Once
+		stop      chan struct{}
+		stopped   chan struct{}
+	}
+)
+
+var _ ProcessResolver = (*resolverImpl)(nil)
+
+// ResolveWithNetNS - look for sockets in the network namespace referred by file descriptor,
+// negative value means the current namespace
+func ResolveWithNetNS(fd int) ResolverOpt {
+	return func(r *resolverImpl) {
+		r.netnsFd = fd
+	}
+}
+
+// ResolveWithProcDir - procfs mount point, default is '/proc'
+func ResolveWithProcDir(dir string) ResolverOpt {
+	return func(r *resolverImpl) {
+		r.procDir = dir
+	}
+}
+
+// ResolveWithCacheSize - number of connections kept in cache, default is DefCacheSize
+func ResolveWithCacheSize(n int) ResolverOpt {
+	return func(r *resolverImpl) {
+		r.cacheSize = n
+	}
+}
+
+// NewProcessResolver creates resolver using NETLINK_SOCK_DIAG to find socket of the connection
+// and procfs to find process owning the socket
+func NewProcessResolver(opts ...ResolverOpt) (ProcessResolver, error) {
+	r := &resolverImpl{
+		netnsFd:   -1,
+		procDir:   netns.DefProcDir,
+		cacheSize: DefCacheSize,
+		timeout:   defLookupTimeout,
+		pending:   make(map[uint32]chan sockDiagReply),
+		lookups:   make(chan Conn, defQueueSize),
+		workers:   defLookupWorkers,
+		stop:      make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	if r.cacheSize <= 0 {
+		return nil, ErrProcOwner{Err: errors.Errorf("cache size (%d) must be > 0", r.cacheSize)}
+	}
+	r.cache = lru.NewCache[connKey, cacheEntry](r.cacheSize)
+	r.procs = newProcIndex(r.procDir, defMinRescan)
+
+	var err error
+	if r.nlWatcher, err = nl.NewNetlinkWatcher(1, unix.NETLINK_SOCK_DIAG,
+		nl.NlWithNetNS(r.netnsFd),
+	); err != nil {
+		return nil, ErrProcOwner{Err: err}
+	}
+	return r, nil
+}
+
+// Run looks up connections missed in cache and dispatches replies of the kernel to pending requests
+func (r *resolverImpl) Run(ctx context.Context) (err error) {
+	var doRun bool
+	r.onceRun.Do(func() {
+		doRun = true
+		r.stopped = make(chan struct{})
+	})
+	if !doRun {
+		return ErrProcOwner{Err: errors.New("it has been run or closed yet")}
+	}
+
+	log := logger.FromContext(ctx).Named("proc-resolver")
+	log.Info("start")
+	ctx1, cancel := context.WithCancel(ctx)
+	var workers sync.WaitGroup
+	defer func() {
+		cancel()
+		workers.Wait()
+		log.Info("stop")
+		close(r.stopped)
+	}()
+	for i := 0; i < r.workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			r.lookupLoop(ctx1)
+		}()
+	}
+
+	reader := r.nlWatcher.Reader(0).Read()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("will exit cause ctx canceled")
+			return ctx.Err()
+		case <-r.stop:
+			log.Info("will exit cause it has closed")
+			return nil
+		case nlData, ok := <-reader:
+			if !ok {
+				log.Info("will exit cause netlink watcher has been closed")
+				return nil
+			}
+			if err = nlData.Err; err != nil {
+				if errors.Is(err, nl.ErrNlReadInterrupted) {
+					continue
+				}
+				if errors.Is(err, nl.ErrNlMem) {
+					log.Warnf("sock_diag replies have been lost: %v", err)
+					continue
+				}
+				log.Errorf("will exit cause %v", err)
+				return ErrProcOwner{Err: err}
+			}
+			for _, msg := range nlData.Messages {
+				inode, e := decodeSockDiagReply(msg)
+				r.deliver(msg.Header.Seq, sockDiagReply{inode, e})
+			}
+		}
+	}
+}
+
+// GetProcess returns process owning local socket of the connection,
+// packet may be either sent or received by the socket
+func (r *resolverImpl) GetProcess(c Conn) (Process, error) {
+	if c.Proto != unix.IPPROTO_TCP && c.Proto != unix.IPPROTO_UDP {
+		return Process{}, ErrProcMiss
+	}
+	k := c.key()
+	if e, ok := r.cache.Get(k); ok && (!e.missed || time.Now().Before(e.expires)) {
+		if e.missed {
+			return Process{}, ErrProcMiss
+		}
+		return e.proc, nil
+	}
+	// connection is missed until the lookup is done, so it is queued once; the placeholder
+	// is put before the lookup is queued so it never overwrites the result of the lookup
+	r.cache.Put(k, cacheEntry{missed: true, expires: time.Now().Add(defMissTTL)})
+	select {
+	case r.lookups <- c:
+	default: // queue is full, connection is looked up next time
+		r.cache.Remove(k)
+	}
+	return Process{}, ErrProcMiss
+}
+
+// lookupLoop resolves queued connections into cache
+func (r *resolverImpl) lookupLoop(ctx context.Context) {
+	log := logger.FromContext(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stop:
+			return
+		case c := <-r.lookups:
+			if err := r.resolve(c); err != nil {
+				log.Debugf("failed to resolve process of the connection: %v", err)
+			}
+		}
+	}
+}
+
+// resolve looks up the connection and caches the result, it is missed for a while when socket is not found
+func (r *resolverImpl) resolve(c Conn) error {
+	p, err := r.lookup(c)
+	if errors.Is(err, ErrProcMiss) {
+		p, err = r.lookup(c.Reverse())
+	}
+	k := c.key()
+	switch {
+	case err == nil:
+		r.cache.Put(k, cacheEntry{proc: p})
+	case errors.Is(err, ErrProcMiss):
+		r.cache.Put(k, cacheEntry{missed: true, expires: time.Now().Add(defMissTTL)})
+	default:
+		return ErrProcOwner{Err: err}
+	}
+	return nil
+}
+
+// Close -
+func (r *resolverImpl) Close() error {
+	r.onceClose.Do(func() {
+		close(r.stop)
+		r.onceRun.Do(func() {})
+		if r.stopped != nil {
+			<-r.stopped
+		}
+		_ = r.nlWatcher.Close()
+	})
+	return nil
+}
+
+// lookup looks for socket whose local end is the source of connection
+func (r *resolverImpl) lookup(c Conn) (Process, error) {
+	seq := r.seq.Add(1)
+	req, err := encodeSockDiagReq(seq, c)
+	if err != nil {
+		return Process{}, err
+	}
+	ch := make(chan sockDiagReply, 1)
+	r.mu.Lock()
+	r.pending[seq] = ch
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, seq)
+		r.mu.Unlock()
+	}()
+
+	if err = r.nlWatcher.Send(req); err != nil {
+		return Process{}, err
+	}
+	var reply sockDiagReply
+	select {
+	case reply = <-ch:
+	case <-time.After(r.timeout):
+		return Process{}, errors.New("sock_diag request timed out")
+	case <-r.stop:
+		return Process{}, errors.New("resolver has been closed")
+	}
+	if reply.err != nil {
+		return Process{}, reply.err
+	}
+	return r.procs.find(uint64(reply.inode))
+}
+
+func (r *resolverImpl) deliver(seq uint32, reply sockDiagReply) {
+	r.mu.Lock()
+	ch, ok := r.pending[seq]
+	r.mu.Unlock()
+	if ok {
+		select {
+		case ch <- reply:
+		default: //reply has been delivered already
+		}
+	}
+}
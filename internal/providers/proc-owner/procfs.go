@@ -0,0 +1,113 @@
+package procowner
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// procIndex - index of the socket inodes owned by processes built by scanning of the procfs
+type procIndex struct {
+	mu         sync.Mutex
+	procDir    string
+	minRescan  time.Duration
+	lastScan   time.Time
+	inodeToPid map[uint64]uint32
+}
+
+func newProcIndex(procDir string, minRescan time.Duration) *procIndex {
+	return &procIndex{
+		procDir:   procDir,
+		minRescan: minRescan,
+	}
+}
+
+// find returns process owning socket, procfs is rescanned when socket is unknown
+// but not often than once per 'minRescan'
+func (x *procIndex) find(inode uint64) (Process, error) {
+	x.mu.Lock()
+	pid, ok := x.inodeToPid[inode]
+	if !ok && time.Since(x.lastScan) >= x.minRescan {
+		idx, err := scanSockets(x.procDir)
+		if err != nil {
+			x.mu.Unlock()
+			return Process{}, err
+		}
+		x.inodeToPid, x.lastScan = idx, time.Now()
+		pid, ok = idx[inode]
+	}
+	x.mu.Unlock()
+	if !ok {
+		return Process{}, ErrProcMiss
+	}
+	ret := Process{Inode: inode, Pid: pid}
+	dir := filepath.Join(x.procDir, strconv.FormatUint(uint64(pid), 10))
+	if data, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		ret.Comm = strings.TrimSpace(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "cgroup")); err == nil {
+		ret.Cgroup = parseCgroup(data)
+	}
+	return ret, nil
+}
+
+// scanSockets returns socket inodes of all processes
+func scanSockets(procDir string) (map[uint64]uint32, error) {
+	procs, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read '%s'", procDir)
+	}
+	ret := make(map[uint64]uint32)
+	for _, e := range procs {
+		pid, e1 := strconv.ParseUint(e.Name(), 10, 32)
+		if e1 != nil {
+			continue
+		}
+		fdDir := filepath.Join(procDir, e.Name(), "fd")
+		fds, e1 := os.ReadDir(fdDir)
+		if e1 != nil {
+			continue //process has gone or it is inaccessible
+		}
+		for _, fd := range fds {
+			link, e2 := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if e2 != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			ino, e2 := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]"), 10, 64)
+			if e2 != nil {
+				continue
+			}
+			if _, ok := ret[ino]; !ok {
+				ret[ino] = uint32(pid)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// parseCgroup returns path of the unified (v2) hierarchy or the first hierarchy of the legacy one
+func parseCgroup(data []byte) string {
+	var first string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(sc.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		if first == "" {
+			first = parts[2]
+		}
+	}
+	return first
+}
@@ -0,0 +1,115 @@
+package procowner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// sizes of structures of the 'linux/inet_diag.h'
+const (
+	sizeofInetDiagSockID = 48
+	sizeofInetDiagReqV2  = 8 + sizeofInetDiagSockID
+	sizeofInetDiagMsg    = 4 + sizeofInetDiagSockID + 20
+
+	inetDiagNoCookie = ^uint32(0)
+	allTcpStates     = ^uint32(0)
+)
+
+// inetDiagSockID - struct inet_diag_sockid, ports and addresses are in network byte order
+type inetDiagSockID struct {
+	SPort  [2]byte
+	DPort  [2]byte
+	Src    [16]byte
+	Dst    [16]byte
+	If     uint32
+	Cookie [2]uint32
+}
+
+// inetDiagReqV2 - struct inet_diag_req_v2
+type inetDiagReqV2 struct {
+	Family   uint8
+	Protocol uint8
+	Ext      uint8
+	Pad      uint8
+	States   uint32
+	ID       inetDiagSockID
+}
+
+// inetDiagMsg - struct inet_diag_msg
+type inetDiagMsg struct {
+	Family  uint8
+	State   uint8
+	Timer   uint8
+	Retrans uint8
+	ID      inetDiagSockID
+	Expires uint32
+	RQueue  uint32
+	WQueue  uint32
+	UID     uint32
+	Inode   uint32
+}
+
+// encodeSockDiagReq builds request looking for the one socket whose local end is the source of 'c'
+func encodeSockDiagReq(seq uint32, c Conn) ([]byte, error) {
+	req := inetDiagReqV2{
+		Protocol: c.Proto,
+		States:   allTcpStates,
+	}
+	if s4, d4 := c.SAddr.To4(), c.DAddr.To4(); s4 != nil && d4 != nil {
+		req.Family = unix.AF_INET
+		copy(req.ID.Src[:], s4)
+		copy(req.ID.Dst[:], d4)
+	} else if s16, d16 := c.SAddr.To16(), c.DAddr.To16(); s16 != nil && d16 != nil {
+		req.Family = unix.AF_INET6
+		copy(req.ID.Src[:], s16)
+		copy(req.ID.Dst[:], d16)
+	} else {
+		return nil, errors.Errorf("unsupported addresses '%s'->'%s'", c.SAddr, c.DAddr)
+	}
+	binary.BigEndian.PutUint16(req.ID.SPort[:], c.SPort)
+	binary.BigEndian.PutUint16(req.ID.DPort[:], c.DPort)
+	req.ID.Cookie = [2]uint32{inetDiagNoCookie, inetDiagNoCookie}
+
+	var buf bytes.Buffer
+	buf.Grow(unix.NLMSG_HDRLEN + sizeofInetDiagReqV2)
+	_ = binary.Write(&buf, binary.NativeEndian, unix.NlMsghdr{
+		Len:   unix.NLMSG_HDRLEN + sizeofInetDiagReqV2,
+		Type:  unix.SOCK_DIAG_BY_FAMILY,
+		Flags: unix.NLM_F_REQUEST,
+		Seq:   seq,
+	})
+	_ = binary.Write(&buf, binary.NativeEndian, req)
+	return buf.Bytes(), nil
+}
+
+// decodeSockDiagReply returns inode of the socket from reply, ErrProcMiss when kernel has not found it
+func decodeSockDiagReply(msg syscall.NetlinkMessage) (uint32, error) {
+	switch msg.Header.Type {
+	case unix.NLMSG_ERROR:
+		if len(msg.Data) < 4 {
+			return 0, errors.New("truncated netlink error message")
+		}
+		errno := syscall.Errno(-int32(binary.NativeEndian.Uint32(msg.Data[:4]))) //nolint:gosec
+		if errno == 0 || errno == unix.ENOENT {
+			return 0, ErrProcMiss
+		}
+		return 0, errors.WithMessage(errno, "sock_diag request failed")
+	case unix.SOCK_DIAG_BY_FAMILY:
+		if len(msg.Data) < sizeofInetDiagMsg {
+			return 0, errors.New("truncated sock_diag message")
+		}
+		var m inetDiagMsg
+		if err := binary.Read(bytes.NewReader(msg.Data[:sizeofInetDiagMsg]), binary.NativeEndian, &m); err != nil {
+			return 0, errors.WithMessage(err, "failed to decode sock_diag message")
+		}
+		if m.Inode == 0 {
+			return 0, ErrProcMiss //socket is in TIME_WAIT state or orphaned
+		}
+		return m.Inode, nil
+	}
+	return 0, errors.Errorf("unexpected netlink message type %d", msg.Header.Type)
+}
@@ -2,51 +2,92 @@ package sgnetwork
 
 import (
 	"net"
-	"sync"
+	"sync/atomic"
 )
 
+// Cache - networks looked up by the longest prefix matching the IP. Networks are kept in
+// immutable prefix tries which are swapped on Init, so lookups take no locks
 type Cache struct {
-	mu   sync.Mutex
-	fast map[string]*SgNet //by IP(as string)
-	slow []*SgNet
+	tries atomic.Pointer[sgTries]
 }
 
-func (cache *Cache) ensureInit() {
-	if cache.fast == nil {
-		cache.fast = make(map[string]*SgNet)
+type (
+	sgTries struct {
+		v4, v6 *trieNode
 	}
-}
 
+	// trieNode - node of the binary trie, the path from the root is the prefix of the network
+	trieNode struct {
+		child [2]*trieNode
+		item  *SgNet
+	}
+)
+
+// Clear -
 func (cache *Cache) Clear() {
-	cache.mu.Lock()
-	defer cache.mu.Unlock()
-	cache.slow = nil
-	cache.fast = nil
+	cache.tries.Store(nil)
 }
 
+// Find returns the most specific network containing the IP
 func (cache *Cache) Find(ip net.IP) *SgNet {
-	cache.mu.Lock()
-	defer cache.mu.Unlock()
-	cache.ensureInit()
-	k := ip.String()
-	item, ok := cache.fast[k]
-	if !ok {
-		for _, n := range cache.slow {
-			if n.Net.Contains(ip) {
-				item = n
-				cache.fast[k] = n
-				break
+	t := cache.tries.Load()
+	if t == nil {
+		return nil
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return t.v4.find(v4)
+	}
+	if len(ip) == net.IPv6len {
+		return t.v6.find(ip)
+	}
+	return nil
+}
+
+// Init replaces networks of the cache, the first one wins when networks are the same
+func (cache *Cache) Init(sgs []*SgNet) {
+	t := &sgTries{v4: new(trieNode), v6: new(trieNode)}
+	for _, n := range sgs {
+		ones, bits := n.Net.Mask.Size()
+		if bits == 0 {
+			continue //non canonical mask
+		}
+		ip, root := n.Net.IP, t.v6
+		if v4 := ip.To4(); v4 != nil && (bits == 8*net.IPv4len || ones >= 8*(net.IPv6len-net.IPv4len)) {
+			if bits == 8*net.IPv6len {
+				ones -= 8 * (net.IPv6len - net.IPv4len)
 			}
+			ip, root = v4, t.v4
+		} else if len(ip) != net.IPv6len {
+			continue
 		}
-		cache.fast[k] = item
+		root.insert(ip, ones, n)
 	}
-	return item
+	cache.tries.Store(t)
 }
 
-// Init -
-func (cache *Cache) Init(sgs []*SgNet) {
-	cache.mu.Lock()
-	defer cache.mu.Unlock()
-	cache.slow = sgs
-	cache.fast = nil
+func (nd *trieNode) insert(ip net.IP, ones int, item *SgNet) {
+	for i := 0; i < ones; i++ {
+		b := bitAt(ip, i)
+		if nd.child[b] == nil {
+			nd.child[b] = new(trieNode)
+		}
+		nd = nd.child[b]
+	}
+	if nd.item == nil {
+		nd.item = item
+	}
+}
+
+func (nd *trieNode) find(ip net.IP) *SgNet {
+	ret := nd.item
+	for i, n := 0, 8*len(ip); i < n && nd != nil; i++ {
+		if nd = nd.child[bitAt(ip, i)]; nd != nil && nd.item != nil {
+			ret = nd.item
+		}
+	}
+	return ret
+}
+
+func bitAt(ip net.IP, i int) byte {
+	return (ip[i/8] >> (7 - uint(i%8))) & 1
 }
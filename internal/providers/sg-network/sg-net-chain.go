@@ -0,0 +1,111 @@
+package sgnetwork
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+type (
+	// sgChainImpl - providers asked in order of priority, the first one knowing the address wins
+	sgChainImpl []SGCollector
+
+	// sgNoneImpl - provider knowing nothing, traces are not enriched by networks and security groups
+	sgNoneImpl struct {
+		onceClose sync.Once
+		stop      chan struct{}
+	}
+)
+
+// NewSgChain makes collector asking providers in order of priority
+func NewSgChain(providers ...SGCollector) SGCollector {
+	if len(providers) == 1 {
+		return providers[0]
+	}
+	return sgChainImpl(providers)
+}
+
+// NewSgNone makes collector which never finds networks
+func NewSgNone() SGCollector {
+	return &sgNoneImpl{stop: make(chan struct{})}
+}
+
+// Run runs every provider of the chain, all of them are closed when one of them exits
+func (c sgChainImpl) Run(ctx context.Context) error {
+	errc := make(chan error, len(c))
+	for _, p := range c {
+		go func(p SGCollector) {
+			errc <- p.Run(ctx)
+		}(p)
+	}
+	var err error
+	for i := range c {
+		err = multierr.Append(err, <-errc)
+		if i == 0 {
+			_ = c.Close()
+		}
+	}
+	return err
+}
+
+// GetSGByIP -
+func (c sgChainImpl) GetSGByIP(ip net.IP) (SgNet, error) {
+	for _, p := range c {
+		nw, err := p.GetSGByIP(ip)
+		if !errors.Is(err, ErrSgMiss) {
+			return nw, err
+		}
+	}
+	return SgNet{}, ErrSgMiss
+}
+
+// GetSgRule -
+func (c sgChainImpl) GetSgRule(p Packet, action string) (SgRule, error) {
+	for _, x := range c {
+		r, err := x.GetSgRule(p, action)
+		if !errors.Is(err, ErrSgRuleMiss) {
+			return r, err
+		}
+	}
+	return SgRule{}, ErrSgRuleMiss
+}
+
+// Close -
+func (c sgChainImpl) Close() error {
+	var err error
+	for _, p := range c {
+		err = multierr.Append(err, p.Close())
+	}
+	return err
+}
+
+// Run -
+func (n *sgNoneImpl) Run(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-n.stop:
+		return nil
+	}
+}
+
+// GetSGByIP -
+func (n *sgNoneImpl) GetSGByIP(net.IP) (SgNet, error) {
+	return SgNet{}, ErrSgMiss
+}
+
+// GetSgRule -
+func (n *sgNoneImpl) GetSgRule(Packet, string) (SgRule, error) {
+	return SgRule{}, ErrSgRuleMiss
+}
+
+// Close -
+func (n *sgNoneImpl) Close() error {
+	n.onceClose.Do(func() {
+		close(n.stop)
+	})
+	return nil
+}
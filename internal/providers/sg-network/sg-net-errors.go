@@ -22,3 +22,6 @@ func (e ErrSgNw) Cause() error {
 }
 
 var ErrSgMiss = errors.New("sg cache miss")
+
+// ErrSgRuleMiss - no rule of sgroups is applied to the packet
+var ErrSgRuleMiss = errors.New("sg rule cache miss")
@@ -0,0 +1,140 @@
+package sgnetwork
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// fqdnResolveTimeout - time to resolve FQDN of the rule
+	fqdnResolveTimeout = 2 * time.Second
+	// fqdnTTL - time the resolved addresses of FQDN are used before it is resolved again
+	fqdnTTL = 5 * time.Minute
+	// fqdnRetryInterval - time before FQDN which is failed to resolve is resolved again
+	fqdnRetryInterval = 30 * time.Second
+	// fqdnResolveWorkers - number of FQDNs resolved at once
+	fqdnResolveWorkers = 8
+)
+
+type (
+	// fqdnResolver resolves FQDNs of the rules in background, rules refer to the entries
+	// so addresses resolved after rules are fetched are seen by them at once
+	fqdnResolver struct {
+		lookup  func(ctx context.Context, name string) ([]net.IP, error)
+		sem     chan struct{}
+		stop    chan struct{}
+		once    sync.Once
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		entries map[string]*fqdnEntry
+	}
+
+	fqdnEntry struct {
+		addrs     atomic.Pointer[[]net.IP]
+		expires   time.Time // guarded by mu of the resolver
+		resolving bool      // guarded by mu of the resolver
+	}
+)
+
+func newFqdnResolver() *fqdnResolver {
+	return &fqdnResolver{
+		lookup:  lookupFQDN,
+		sem:     make(chan struct{}, fqdnResolveWorkers),
+		stop:    make(chan struct{}),
+		entries: make(map[string]*fqdnEntry),
+	}
+}
+
+// entry returns cached entry of the FQDN and starts to resolve it in background when
+// it is unknown or expired, it never waits for DNS
+func (f *fqdnResolver) entry(name string) *fqdnEntry {
+	name = strings.ToLower(name)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e := f.entries[name]
+	if e == nil {
+		e = new(fqdnEntry)
+		f.entries[name] = e
+	}
+	if !e.resolving && time.Now().After(e.expires) && !strings.Contains(name, "*") {
+		e.resolving = true
+		f.wg.Add(1)
+		go f.resolve(name, e)
+	}
+	return e
+}
+
+// retain drops entries of FQDNs which are not used by rules anymore
+func (f *fqdnResolver) retain(names map[string]struct{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for name := range f.entries {
+		if _, ok := names[name]; !ok {
+			delete(f.entries, name)
+		}
+	}
+}
+
+// wait waits until FQDNs being resolved at the moment are resolved
+func (f *fqdnResolver) wait() {
+	f.wg.Wait()
+}
+
+func (f *fqdnResolver) close() {
+	f.once.Do(func() {
+		close(f.stop)
+	})
+}
+
+func (f *fqdnResolver) resolve(name string, e *fqdnEntry) {
+	defer f.wg.Done()
+	expires := time.Now().Add(fqdnRetryInterval)
+	defer func() {
+		f.mu.Lock()
+		e.resolving = false
+		e.expires = expires
+		f.mu.Unlock()
+	}()
+	select {
+	case f.sem <- struct{}{}:
+	case <-f.stop:
+		return
+	}
+	defer func() { <-f.sem }()
+	ctx, cancel := context.WithTimeout(context.Background(), fqdnResolveTimeout)
+	defer cancel()
+	addrs, err := f.lookup(ctx, name)
+	if err != nil {
+		// addresses resolved before are kept until the name is resolved again
+		return
+	}
+	e.addrs.Store(&addrs)
+	expires = time.Now().Add(fqdnTTL)
+}
+
+// get returns addresses the FQDN is resolved to at the moment
+func (e *fqdnEntry) get() []net.IP {
+	if e == nil {
+		return nil
+	}
+	if p := e.addrs.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+func lookupFQDN(ctx context.Context, name string) ([]net.IP, error) {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]net.IP, 0, len(addrs))
+	for _, a := range addrs {
+		ret = append(ret, a.IP)
+	}
+	return ret, nil
+}
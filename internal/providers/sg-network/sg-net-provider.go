@@ -8,9 +8,11 @@ import (
 	"time"
 
 	sgAPI "github.com/wildberries-tech/pkt-tracer/internal/api/sgroups"
+	"github.com/wildberries-tech/pkt-tracer/internal/health"
 	sg "github.com/wildberries-tech/sgroups/v2/pkg/api/sgroups"
 
 	"github.com/H-BF/corlib/logger"
+	"github.com/H-BF/corlib/pkg/patterns/observer"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -27,23 +29,44 @@ type (
 	SGCollector interface {
 		Run(ctx context.Context) (err error)
 		GetSGByIP(ip net.IP) (SgNet, error)
+		// GetSgRule - rule with the action which is applied first to the packet
+		GetSgRule(p Packet, action string) (SgRule, error)
 		Close() error
 	}
 
 	sgCollectorImpl struct {
 		cached        Cache
+		rules         RuleCache
+		fqdns         *fqdnResolver
 		sstaus        *SyncStatus
 		client        SGClient
 		checkInterval time.Duration
 		usePushModel  bool
+		agentSubject  observer.Subject
 		onceRun       sync.Once
 		onceClose     sync.Once
 		stop          chan struct{}
 		stopped       chan struct{}
 	}
+
+	// SgSyncEvent - cache of security groups is in sync with sgroups service at the moment
+	SgSyncEvent struct {
+		observer.EventType
+		At time.Time
+	}
+
+	// SgCollectorOpt - option of the sg collector
+	SgCollectorOpt func(*sgCollectorImpl)
 )
 
-func NewSgCollector(ctx context.Context, c SGClient, d time.Duration, usePush bool) (SGCollector, error) {
+// SgWithAgentSubject - notify subject on sync and health of the collector
+func SgWithAgentSubject(as observer.Subject) SgCollectorOpt {
+	return func(s *sgCollectorImpl) {
+		s.agentSubject = as
+	}
+}
+
+func NewSgCollector(ctx context.Context, c SGClient, d time.Duration, usePush bool, opts ...SgCollectorOpt) (SGCollector, error) {
 	if d < time.Second {
 		panic(
 			fmt.Errorf("'SgCollector/CheckInterval' is (%v) less than 1s", d),
@@ -53,23 +76,35 @@ func NewSgCollector(ctx context.Context, c SGClient, d time.Duration, usePush bo
 		client:        c,
 		checkInterval: d,
 		usePushModel:  usePush,
+		fqdns:         newFqdnResolver(),
 		stop:          make(chan struct{}),
 	}
+	for _, o := range opts {
+		o(s)
+	}
 	st, err := s.getSyncStatus(ctx)
 	if err != nil {
 		return nil, err
 	}
 	if st != nil {
-		sg, err := s.fetchNwAndSG(ctx)
-		if err != nil {
+		if err = s.fetch(ctx); err != nil {
+			s.fqdns.close()
 			return nil, err
 		}
 		s.sstaus = st
-		s.cached.Init(sg)
 	}
+	s.synced()
 	return s, nil
 }
 
+// synced - cache has been checked or updated against sgroups service
+func (s *sgCollectorImpl) synced() {
+	if s.agentSubject != nil {
+		s.agentSubject.Notify(SgSyncEvent{At: time.Now()})
+	}
+	health.Report(s.agentSubject, health.SgCollector, "", nil)
+}
+
 // Run -
 func (s *sgCollectorImpl) Run(ctx context.Context) (err error) {
 	var doRun bool
@@ -86,6 +121,7 @@ func (s *sgCollectorImpl) Run(ctx context.Context) (err error) {
 	}
 	log.Infow("start", "mode", mode)
 	defer func() {
+		health.ReportExit(s.agentSubject, health.SgCollector, "", err)
 		close(s.stopped)
 		log.Info("stop")
 	}()
@@ -93,6 +129,8 @@ func (s *sgCollectorImpl) Run(ctx context.Context) (err error) {
 	if s.usePushModel {
 		return s.push(ctx, log)
 	}
+	// cache has been fetched on creation, e.g. when the collector replaces the previous one
+	health.Report(s.agentSubject, health.SgCollector, "", nil)
 	tc := time.NewTicker(s.checkInterval)
 	defer tc.Stop()
 	return s.pull(ctx, tc, log)
@@ -109,11 +147,23 @@ func (s *sgCollectorImpl) GetSGByIP(ip net.IP) (nw SgNet, err error) {
 	return nw, err
 }
 
+// GetSgRule -
+func (s *sgCollectorImpl) GetSgRule(p Packet, action string) (r SgRule, err error) {
+	item := s.rules.Find(p, action)
+	if item == nil {
+		err = ErrSgRuleMiss
+	} else {
+		r = *item
+	}
+	return r, err
+}
+
 // Close -
 func (s *sgCollectorImpl) Close() error {
 	stopped := s.stopped
 	s.onceClose.Do(func() {
 		close(s.stop)
+		s.fqdns.close()
 		s.onceRun.Do(func() {})
 		if stopped != nil {
 			<-stopped
@@ -146,6 +196,7 @@ func (s *sgCollectorImpl) push(ctx context.Context, log logger.TypeOfLogger) (er
 	}
 	streamCtx := stream.Context()
 	log.Debug("connected")
+	health.Report(s.agentSubject, health.SgCollector, "", nil)
 	errc = make(chan error, 1)
 	go func() {
 		defer close(errc)
@@ -159,13 +210,12 @@ func (s *sgCollectorImpl) push(ctx context.Context, log logger.TypeOfLogger) (er
 				s.sstaus = &SyncStatus{
 					UpdatedAt: resp.GetUpdatedAt().AsTime(),
 				}
-				sg, err := s.fetchNwAndSG(ctx)
-				if err != nil {
+				if err = s.fetch(ctx); err != nil {
 					errc <- err
 					return
 				}
-				s.cached.Init(sg)
 			}
+			s.synced()
 		}
 	}()
 	for {
@@ -205,13 +255,11 @@ func (s *sgCollectorImpl) pull(ctx context.Context, tc *time.Ticker, log logger.
 			}
 			if s.sstaus == nil || !s.sstaus.UpdatedAt.Equal(st.UpdatedAt) {
 				s.sstaus = st
-
-				sg, err := s.fetchNwAndSG(ctx)
-				if err != nil {
+				if err = s.fetch(ctx); err != nil {
 					return err
 				}
-				s.cached.Init(sg)
 			}
+			s.synced()
 		}
 	}
 }
@@ -228,8 +276,23 @@ func (s *sgCollectorImpl) getSyncStatus(ctx context.Context) (*SyncStatus, error
 	return ret, err
 }
 
-func (s *sgCollectorImpl) fetchNwAndSG(ctx context.Context) ([]*SgNet, error) {
-	listSG, err := s.client.ListSecurityGroups(ctx, &sg.ListSecurityGroupsReq{})
+// fetch - fetch networks and rules and replace them in caches
+func (s *sgCollectorImpl) fetch(ctx context.Context) error {
+	nws, err := fetchNwAndSG(ctx, s.client)
+	if err != nil {
+		return err
+	}
+	rules, err := fetchRules(ctx, s.client, s.fqdns)
+	if err != nil {
+		return err
+	}
+	s.cached.Init(nws)
+	s.rules.Init(rules)
+	return nil
+}
+
+func fetchNwAndSG(ctx context.Context, c sg.SecGroupServiceClient) ([]*SgNet, error) {
+	listSG, err := c.ListSecurityGroups(ctx, &sg.ListSecurityGroupsReq{})
 
 	if err != nil {
 		return nil, err
@@ -243,7 +306,7 @@ func (s *sgCollectorImpl) fetchNwAndSG(ctx context.Context) ([]*SgNet, error) {
 		}
 	}
 	//get a list of all networks, even those not associated with SG
-	listNw, err := s.client.ListNetworks(ctx, &sg.ListNetworksReq{})
+	listNw, err := c.ListNetworks(ctx, &sg.ListNetworksReq{})
 
 	if err != nil {
 		return nil, err
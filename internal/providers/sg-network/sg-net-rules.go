@@ -0,0 +1,417 @@
+package sgnetwork
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/wildberries-tech/sgroups/v2/pkg/api/common"
+	sg "github.com/wildberries-tech/sgroups/v2/pkg/api/sgroups"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// kinds of the sgroups rules
+const (
+	RuleSgSg     = "sg-sg"
+	RuleSgSgIcmp = "sg-sg-icmp"
+	RuleSgIcmp   = "sg-icmp"
+	RuleCidrSg   = "cidr-sg"
+	RuleSgCidr   = "sg-cidr"
+	RuleSgFqdn   = "sg-fqdn"
+)
+
+// actions of the sgroups rules
+const (
+	RuleActionAccept = "accept"
+	RuleActionDrop   = "drop"
+)
+
+type (
+	// SgRule - rule of sgroups
+	SgRule struct {
+		// Id - identity of the rule, e.g. 'sg-sg:web->db:tcp'
+		Id string
+		// Kind - kind of the rule: sg-sg, sg-sg-icmp, sg-icmp, cidr-sg, sg-cidr or sg-fqdn
+		Kind string
+		// Action - accept or drop
+		Action string
+		// Priority - rules with the less priority are applied first
+		Priority int32
+
+		seq       int // order of the rules with the same priority
+		sgFrom    string
+		sgTo      string
+		cidr      *net.IPNet
+		fqdn      *fqdnEntry // addresses the FQDN is resolved to
+		proto     uint8
+		ports     []portsPair
+		icmpTypes []uint8
+	}
+
+	// Packet - traced packet the sgroups rule is looked up for
+	Packet struct {
+		Proto    uint8
+		SAddr    net.IP
+		DAddr    net.IP
+		SPort    uint16
+		DPort    uint16
+		IcmpType uint8
+		SSg      string
+		DSg      string
+	}
+
+	// RuleCache - sgroups rules indexed by security groups they are applied to, the index
+	// is immutable and swapped on Init so lookups take no locks
+	RuleCache struct {
+		index atomic.Pointer[ruleIndex]
+	}
+
+	ruleIndex struct {
+		bySgPair map[[2]string][]*SgRule
+		bySrcSg  map[string][]*SgRule
+		byDstSg  map[string][]*SgRule
+	}
+
+	portRange struct {
+		from, to uint16
+	}
+
+	// portsPair - source and destination ports of the rule, empty set matches any port
+	portsPair struct {
+		s, d []portRange
+	}
+)
+
+// Init replaces rules of the cache
+func (c *RuleCache) Init(rules []*SgRule) {
+	idx := &ruleIndex{
+		bySgPair: make(map[[2]string][]*SgRule),
+		bySrcSg:  make(map[string][]*SgRule),
+		byDstSg:  make(map[string][]*SgRule),
+	}
+	for i, r := range rules {
+		r.seq = i
+		switch r.Kind {
+		case RuleSgSg, RuleSgSgIcmp:
+			k := [2]string{r.sgFrom, r.sgTo}
+			idx.bySgPair[k] = append(idx.bySgPair[k], r)
+		case RuleSgIcmp:
+			idx.bySrcSg[r.sgFrom] = append(idx.bySrcSg[r.sgFrom], r)
+			idx.byDstSg[r.sgTo] = append(idx.byDstSg[r.sgTo], r)
+		case RuleSgCidr, RuleSgFqdn:
+			idx.bySrcSg[r.sgFrom] = append(idx.bySrcSg[r.sgFrom], r)
+		case RuleCidrSg:
+			idx.byDstSg[r.sgTo] = append(idx.byDstSg[r.sgTo], r)
+		}
+	}
+	c.index.Store(idx)
+}
+
+// Find returns rule with the action which is applied first to the packet, empty action
+// matches any action
+func (c *RuleCache) Find(p Packet, action string) *SgRule {
+	idx := c.index.Load()
+	if idx == nil {
+		return nil
+	}
+	var ret *SgRule
+	look := func(rules []*SgRule) {
+		for _, r := range rules {
+			if (action == "" || r.Action == action) && (ret == nil || r.before(ret)) && r.Match(p) {
+				ret = r
+			}
+		}
+	}
+	if p.SSg != "" && p.DSg != "" {
+		look(idx.bySgPair[[2]string{p.SSg, p.DSg}])
+	}
+	if p.SSg != "" {
+		look(idx.bySrcSg[p.SSg])
+	}
+	if p.DSg != "" {
+		look(idx.byDstSg[p.DSg])
+	}
+	return ret
+}
+
+// Match checks the rule is applied to the packet
+func (r *SgRule) Match(p Packet) bool {
+	switch r.Kind {
+	case RuleSgSg:
+		return p.SSg == r.sgFrom && p.DSg == r.sgTo && r.matchL4(p)
+	case RuleSgFqdn:
+		return p.SSg == r.sgFrom && containsIP(r.fqdn.get(), p.DAddr) && r.matchL4(p)
+	case RuleSgCidr:
+		return p.SSg == r.sgFrom && r.cidr.Contains(p.DAddr) && r.matchL4(p)
+	case RuleCidrSg:
+		return p.DSg == r.sgTo && r.cidr.Contains(p.SAddr) && r.matchL4(p)
+	case RuleSgSgIcmp:
+		return p.SSg == r.sgFrom && p.DSg == r.sgTo && r.matchIcmp(p)
+	case RuleSgIcmp:
+		return (p.SSg == r.sgFrom || p.DSg == r.sgTo) && r.matchIcmp(p)
+	}
+	return false
+}
+
+func (r *SgRule) before(other *SgRule) bool {
+	if r.Priority != other.Priority {
+		return r.Priority < other.Priority
+	}
+	return r.seq < other.seq
+}
+
+func (r *SgRule) matchL4(p Packet) bool {
+	if p.Proto != r.proto {
+		return false
+	}
+	if len(r.ports) == 0 {
+		return true
+	}
+	for _, pp := range r.ports {
+		if inRanges(pp.s, p.SPort) && inRanges(pp.d, p.DPort) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *SgRule) matchIcmp(p Packet) bool {
+	if p.Proto != r.proto {
+		return false
+	}
+	if len(r.icmpTypes) == 0 {
+		return true
+	}
+	for _, t := range r.icmpTypes {
+		if t == p.IcmpType {
+			return true
+		}
+	}
+	return false
+}
+
+func inRanges(rr []portRange, port uint16) bool {
+	if len(rr) == 0 {
+		return true
+	}
+	for _, r := range rr {
+		if port >= r.from && port <= r.to {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, x := range ips {
+		if x.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePorts parses set of ports like '80 443 8000-8080', commas are accepted as separators too
+func parsePorts(s string) ([]portRange, error) {
+	var ret []portRange
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' }) {
+		from, to, isRange := strings.Cut(f, "-")
+		if !isRange {
+			to = from
+		}
+		a, err := strconv.ParseUint(from, 10, 16)
+		if err != nil {
+			return nil, errors.Errorf("bad port '%s'", f)
+		}
+		b, err := strconv.ParseUint(to, 10, 16)
+		if err != nil || b < a {
+			return nil, errors.Errorf("bad port range '%s'", f)
+		}
+		ret = append(ret, portRange{from: uint16(a), to: uint16(b)})
+	}
+	return ret, nil
+}
+
+type protoRuleL4 interface {
+	GetTransport() common.Networks_NetIP_Transport
+	GetPorts() []*sg.AccPorts
+	GetAction() sg.RuleAction
+	GetPriority() *sg.RulePriority
+}
+
+type protoRuleIcmp interface {
+	GetICMP() *common.ICMP
+	GetAction() sg.RuleAction
+	GetPriority() *sg.RulePriority
+}
+
+func (r *SgRule) fromL4(kind, from, to string, pr protoRuleL4) (err error) {
+	r.Kind, r.sgFrom, r.sgTo = kind, from, to
+	r.Action = proto2ModelAction(pr.GetAction())
+	r.Priority = pr.GetPriority().GetSome()
+	transport := "tcp"
+	r.proto = unix.IPPROTO_TCP
+	if pr.GetTransport() == common.Networks_NetIP_UDP {
+		transport, r.proto = "udp", unix.IPPROTO_UDP
+	}
+	r.Id = fmt.Sprintf("%s:%s->%s:%s", kind, from, to, transport)
+	for _, p := range pr.GetPorts() {
+		var pp portsPair
+		if pp.s, err = parsePorts(p.GetS()); err != nil {
+			return errors.WithMessagef(err, "rule '%s'", r.Id)
+		}
+		if pp.d, err = parsePorts(p.GetD()); err != nil {
+			return errors.WithMessagef(err, "rule '%s'", r.Id)
+		}
+		r.ports = append(r.ports, pp)
+	}
+	return nil
+}
+
+func (r *SgRule) fromIcmp(kind, from, to string, pr protoRuleIcmp) {
+	r.Kind, r.sgFrom, r.sgTo = kind, from, to
+	r.Action = proto2ModelAction(pr.GetAction())
+	r.Priority = pr.GetPriority().GetSome()
+	icmp := "icmp"
+	r.proto = unix.IPPROTO_ICMP
+	if pr.GetICMP().GetIPv() == common.IpAddrFamily_IPv6 {
+		icmp, r.proto = "icmp6", unix.IPPROTO_ICMPV6
+	}
+	for _, t := range pr.GetICMP().GetTypes() {
+		r.icmpTypes = append(r.icmpTypes, uint8(t)) //nolint:gosec
+	}
+	if kind == RuleSgIcmp {
+		r.Id = fmt.Sprintf("%s:%s:%s", kind, from, icmp)
+	} else {
+		r.Id = fmt.Sprintf("%s:%s->%s:%s", kind, from, to, icmp)
+	}
+}
+
+// proto2ModelAction - rules made before actions were introduced allow traffic
+func proto2ModelAction(a sg.RuleAction) string {
+	if a == sg.RuleAction_DROP {
+		return RuleActionDrop
+	}
+	return RuleActionAccept
+}
+
+// fetchRules fetches SG-SG, SG-FQDN, CIDR-SG and ICMP rules from sgroups. FQDNs are resolved
+// by the resolver in background, FQDN rules whose names are not resolved yet never match
+func fetchRules(ctx context.Context, c sg.SecGroupServiceClient, fqdns *fqdnResolver) ([]*SgRule, error) {
+	var ret []*SgRule
+	sgSg, err := c.FindRules(ctx, &sg.FindRulesReq{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range sgSg.GetRules() {
+		r := new(SgRule)
+		if err = r.fromL4(RuleSgSg, pr.GetSgFrom(), pr.GetSgTo(), pr); err != nil {
+			return nil, err
+		}
+		ret = append(ret, r)
+	}
+	sgSgIcmp, err := c.FindSgSgIcmpRules(ctx, &sg.FindSgSgIcmpRulesReq{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range sgSgIcmp.GetRules() {
+		r := new(SgRule)
+		r.fromIcmp(RuleSgSgIcmp, pr.GetSgFrom(), pr.GetSgTo(), pr)
+		ret = append(ret, r)
+	}
+	sgIcmp, err := c.FindSgIcmpRules(ctx, &sg.FindSgIcmpRulesReq{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range sgIcmp.GetRules() {
+		r := new(SgRule)
+		r.fromIcmp(RuleSgIcmp, pr.GetSg(), pr.GetSg(), pr)
+		ret = append(ret, r)
+	}
+	cidrSg, err := c.FindIECidrSgRules(ctx, &sg.FindIECidrSgRulesReq{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range cidrSg.GetRules() {
+		_, nt, e := net.ParseCIDR(pr.GetCIDR())
+		if e != nil {
+			return nil, errors.WithMessagef(e, "CIDR rule of the '%s'", pr.GetSG())
+		}
+		r := &SgRule{cidr: nt}
+		if pr.GetTraffic() == common.Traffic_Egress {
+			err = r.fromL4(RuleSgCidr, pr.GetSG(), nt.String(), pr)
+		} else {
+			err = r.fromL4(RuleCidrSg, nt.String(), pr.GetSG(), pr)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, r)
+	}
+	fqdn, err := c.FindFqdnRules(ctx, &sg.FindFqdnRulesReq{})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{})
+	for _, pr := range fqdn.GetRules() {
+		r := new(SgRule)
+		if err = r.fromL4(RuleSgFqdn, pr.GetSgFrom(), pr.GetFQDN(), pr); err != nil {
+			return nil, err
+		}
+		name := strings.ToLower(pr.GetFQDN())
+		names[name] = struct{}{}
+		r.fqdn = fqdns.entry(name)
+		ret = append(ret, r)
+	}
+	fqdns.retain(names)
+	return ret, nil
+}
+
+// RuleScope - tables and chains of nftables made by sgroups, patterns are 'table' or 'table/chain'
+// with shell wildcards. Verdicts made out of the scope are not resolved to sgroups rules,
+// empty scope contains any chain
+type RuleScope []string
+
+// ParseRuleScope parses comma separated patterns of the scope, e.g. 'main*,filter/sg-*'
+func ParseRuleScope(s string) (RuleScope, error) {
+	var ret RuleScope
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		if strings.Count(p, "/") > 1 {
+			return nil, errors.Errorf("bad scope pattern '%s': expected 'table' or 'table/chain'", p)
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, errors.WithMessagef(err, "bad scope pattern '%s'", p)
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// Contains checks the chain of the table is in the scope
+func (s RuleScope) Contains(table, chain string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, p := range s {
+		tp, cp, withChain := strings.Cut(p, "/")
+		if ok, _ := path.Match(tp, table); !ok {
+			continue
+		}
+		if !withChain {
+			return true
+		}
+		if ok, _ := path.Match(cp, chain); ok {
+			return true
+		}
+	}
+	return false
+}
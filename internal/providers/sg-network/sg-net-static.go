@@ -0,0 +1,219 @@
+package sgnetwork
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/H-BF/corlib/logger"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// StaticEntry - network of the static file
+	StaticEntry struct {
+		CIDR    string `yaml:"cidr"`
+		Network string `yaml:"network"`
+		SG      string `yaml:"sg"`
+	}
+
+	staticCollectorImpl struct {
+		cached         Cache
+		fileName       string
+		modTime        time.Time
+		size           int64
+		reloadInterval time.Duration
+		onceRun        sync.Once
+		onceClose      sync.Once
+		stop           chan struct{}
+		stopped        chan struct{}
+	}
+)
+
+// NewStaticCollector creates collector of networks and security groups from the static file,
+// the file is checked for changes every 'd' and reloaded when it has changed
+func NewStaticCollector(fileName string, d time.Duration) (SGCollector, error) {
+	if d < time.Second {
+		panic(
+			fmt.Errorf("'SgNet/Static/ReloadInterval' is (%v) less than 1s", d),
+		)
+	}
+	if fileName == "" {
+		return nil, ErrSgNw{Err: errors.New("file of the static networks is not specified")}
+	}
+	s := &staticCollectorImpl{
+		fileName:       fileName,
+		reloadInterval: d,
+		stop:           make(chan struct{}),
+	}
+	if _, err := s.reload(); err != nil {
+		return nil, ErrSgNw{Err: err}
+	}
+	return s, nil
+}
+
+// Run -
+func (s *staticCollectorImpl) Run(ctx context.Context) (err error) {
+	var doRun bool
+	s.onceRun.Do(func() {
+		doRun = true
+		s.stopped = make(chan struct{})
+	})
+	if !doRun {
+		return ErrSgNw{Err: errors.New("it has been run or closed yet")}
+	}
+
+	log := logger.FromContext(ctx).Named("sg-static")
+	log.Infow("start", "file", s.fileName)
+	defer func() {
+		log.Info("stop")
+		close(s.stopped)
+	}()
+
+	tc := time.NewTicker(s.reloadInterval)
+	defer tc.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("will exit cause ctx canceled")
+			return ctx.Err()
+		case <-s.stop:
+			log.Info("will exit cause it has closed")
+			return nil
+		case <-tc.C:
+			reloaded, e := s.reload()
+			if e != nil {
+				log.Warnf("failed to reload networks, the previous ones are kept: %v", e)
+			} else if reloaded {
+				log.Infof("networks have been reloaded from '%s'", s.fileName)
+			}
+		}
+	}
+}
+
+// GetSGByIP -
+func (s *staticCollectorImpl) GetSGByIP(ip net.IP) (nw SgNet, err error) {
+	item := s.cached.Find(ip)
+	if item == nil {
+		err = ErrSgMiss
+	} else {
+		nw = *item
+	}
+	return nw, err
+}
+
+// GetSgRule - static file has no rules
+func (s *staticCollectorImpl) GetSgRule(Packet, string) (SgRule, error) {
+	return SgRule{}, ErrSgRuleMiss
+}
+
+// Close -
+func (s *staticCollectorImpl) Close() error {
+	s.onceClose.Do(func() {
+		close(s.stop)
+		s.onceRun.Do(func() {})
+		if s.stopped != nil {
+			<-s.stopped
+		}
+	})
+	return nil
+}
+
+// reload - load networks when the file has changed since the last load
+func (s *staticCollectorImpl) reload() (bool, error) {
+	fi, err := os.Stat(s.fileName)
+	if err != nil {
+		return false, err
+	}
+	if fi.ModTime().Equal(s.modTime) && fi.Size() == s.size {
+		return false, nil
+	}
+	nws, err := LoadStatic(s.fileName)
+	if err != nil {
+		return false, err
+	}
+	s.cached.Init(nws)
+	s.modTime, s.size = fi.ModTime(), fi.Size()
+	return true, nil
+}
+
+// LoadStatic reads networks from the file, it is CSV of 'cidr,network,sg' records when the file
+// has '.csv' extension and YAML list of entries otherwise. Network name defaults to the CIDR
+func LoadStatic(path string) ([]*SgNet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read '%s'", path)
+	}
+	var entries []StaticEntry
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		entries, err = parseStaticCSV(data)
+	} else {
+		err = yaml.Unmarshal(data, &entries)
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse '%s'", path)
+	}
+	ret := make([]*SgNet, 0, len(entries))
+	for i, e := range entries {
+		nw, err := e.toModel()
+		if err != nil {
+			return nil, errors.WithMessagef(err, "'%s': bad network #%d", path, i+1)
+		}
+		ret = append(ret, nw)
+	}
+	return ret, nil
+}
+
+func parseStaticCSV(data []byte) ([]StaticEntry, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	var ret []StaticEntry
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return ret, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rec) > 3 {
+			line, _ := r.FieldPos(0)
+			return nil, errors.Errorf("line %d: 'cidr,network,sg' is expected", line)
+		}
+		rec = append(rec, "", "")
+		if len(ret) == 0 && strings.EqualFold(rec[0], "cidr") {
+			continue //header
+		}
+		ret = append(ret, StaticEntry{
+			CIDR:    strings.TrimSpace(rec[0]),
+			Network: strings.TrimSpace(rec[1]),
+			SG:      strings.TrimSpace(rec[2]),
+		})
+	}
+}
+
+func (e StaticEntry) toModel() (*SgNet, error) {
+	ip, nt, err := net.ParseCIDR(e.CIDR)
+	if err != nil {
+		return nil, err
+	}
+	if !nt.IP.Equal(ip) {
+		return nil, errors.Errorf("the '%s' seems just an IP address; the address of network is expected instead", e.CIDR)
+	}
+	name := e.Network
+	if name == "" {
+		name = nt.String()
+	}
+	return &SgNet{Network: Network{Net: *nt, Name: name}, SgName: e.SG}, nil
+}
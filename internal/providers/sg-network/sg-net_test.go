@@ -1,13 +1,20 @@
 package sgnetwork
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
 	"net"
+	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/sys/unix"
 )
 
 type sgHolderTestSuite struct {
@@ -76,24 +83,51 @@ func (sui *sgHolderTestSuite) Test_SgNwCache() {
 		}
 	}
 	cache.Init(sgs)
-	sui.Require().Equal(0, len(cache.fast))
-	sui.Require().Equal(sgNum*netNum, len(cache.slow))
 
 	for i := 0; i < sgNum; i++ {
 		for j := 0; j < netNum; j++ {
 			item := cache.Find(net.ParseIP(fmt.Sprintf("192.168.%d.%d", i, j)))
 			sui.Require().NotNil(item)
 			sui.Require().Equal(sgExp[i][j], *item)
-			sui.Require().Equal(i*netNum+j+1, len(cache.fast))
 		}
 	}
 	cache.Clear()
-	sui.Require().Equal(0, len(cache.fast))
-	sui.Require().Equal(0, len(cache.slow))
 	item = cache.Find(net.ParseIP("192.168.1.1"))
 	sui.Require().Nil(item)
 }
 
+func (sui *sgHolderTestSuite) Test_SgNwCacheLongestPrefix() {
+	var cache Cache
+	nws := make([]*SgNet, 0)
+	for _, c := range []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24", "10.1.2.3/32", "0.0.0.0/0",
+		"2001:db8::/32", "2001:db8:1::/48", "10.1.0.0/16"} {
+		_, ipnet, err := net.ParseCIDR(c)
+		sui.Require().NoError(err)
+		nws = append(nws, &SgNet{Network: Network{Net: *ipnet, Name: c}, SgName: "sg-" + strconv.Itoa(len(nws))})
+	}
+	cache.Init(nws)
+	for ip, exp := range map[string]string{
+		"10.1.2.3":        "sg-3",
+		"10.1.2.4":        "sg-2",
+		"10.1.3.1":        "sg-1", //the first of the same networks wins
+		"10.2.0.1":        "sg-0",
+		"192.168.0.1":     "sg-4",
+		"::ffff:10.1.2.3": "sg-3",
+		"2001:db8:1::1":   "sg-6",
+		"2001:db8:2::1":   "sg-5",
+		"2001:db9::1":     "",
+		"fe80::1":         "",
+	} {
+		item := cache.Find(net.ParseIP(ip))
+		if exp == "" {
+			sui.Require().Nil(item, ip)
+			continue
+		}
+		sui.Require().NotNil(item, ip)
+		sui.Require().Equal(exp, item.SgName, ip)
+	}
+}
+
 func (sui *sgHolderTestSuite) Test_SgNwCacheMThread() {
 	const (
 		sgNum  = 10
@@ -143,3 +177,238 @@ func (sui *sgHolderTestSuite) Test_SgNwCacheMThread() {
 	wg.Wait()
 
 }
+
+func (sui *sgHolderTestSuite) Test_SgNwLoadStatic() {
+	dir := sui.T().TempDir()
+	yamlFile := filepath.Join(dir, "networks.yaml")
+	sui.Require().NoError(os.WriteFile(yamlFile, []byte(
+		"- cidr: 10.0.0.0/24\n  network: nw-web\n  sg: sg-web\n- cidr: 10.1.0.0/16\n"), 0o600))
+	csvFile := filepath.Join(dir, "networks.csv")
+	sui.Require().NoError(os.WriteFile(csvFile, []byte(
+		"cidr,network,sg\n# comment\n10.0.0.0/24, nw-web, sg-web\n10.1.0.0/16\n"), 0o600))
+
+	_, web, _ := net.ParseCIDR("10.0.0.0/24")
+	_, other, _ := net.ParseCIDR("10.1.0.0/16")
+	exp := []*SgNet{
+		{Network: Network{Net: *web, Name: "nw-web"}, SgName: "sg-web"},
+		{Network: Network{Net: *other, Name: "10.1.0.0/16"}},
+	}
+	for _, f := range []string{yamlFile, csvFile} {
+		nws, err := LoadStatic(f)
+		sui.Require().NoError(err, f)
+		sui.Require().Equal(exp, nws, f)
+	}
+
+	bad := filepath.Join(dir, "bad.csv")
+	sui.Require().NoError(os.WriteFile(bad, []byte("10.0.0.1/24,nw\n"), 0o600))
+	_, err := LoadStatic(bad)
+	sui.Require().Error(err)
+}
+
+func (sui *sgHolderTestSuite) Test_SgNwStaticChain() {
+	fileName := filepath.Join(sui.T().TempDir(), "networks.csv")
+	sui.Require().NoError(os.WriteFile(fileName, []byte("10.0.0.0/24,nw-web,sg-web\n"), 0o600))
+	static, err := NewStaticCollector(fileName, time.Second)
+	sui.Require().NoError(err)
+
+	_, web, _ := net.ParseCIDR("10.0.0.0/8")
+	fallback := &sgCollectorImpl{stop: make(chan struct{})}
+	fallback.cached.Init([]*SgNet{{Network: Network{Net: *web, Name: "nw-all"}, SgName: "sg-all"}})
+	chain := NewSgChain(NewSgNone(), static, fallback)
+
+	nw, err := chain.GetSGByIP(net.ParseIP("10.0.0.1"))
+	sui.Require().NoError(err)
+	sui.Require().Equal("sg-web", nw.SgName)
+	nw, err = chain.GetSGByIP(net.ParseIP("10.2.0.1"))
+	sui.Require().NoError(err)
+	sui.Require().Equal("sg-all", nw.SgName)
+	_, err = chain.GetSGByIP(net.ParseIP("192.168.0.1"))
+	sui.Require().ErrorIs(err, ErrSgMiss)
+
+	sui.Require().NoError(static.Close())
+}
+
+func (sui *sgHolderTestSuite) Test_SgRules() {
+	ports := func(s, d string) portsPair {
+		pp := portsPair{}
+		var err error
+		pp.s, err = parsePorts(s)
+		sui.Require().NoError(err)
+		pp.d, err = parsePorts(d)
+		sui.Require().NoError(err)
+		return pp
+	}
+	_, cidr, _ := net.ParseCIDR("192.168.0.0/16")
+	dns := new(fqdnEntry)
+	dns.addrs.Store(&[]net.IP{net.ParseIP("8.8.8.8")})
+	rules := []*SgRule{
+		{Id: "web-db-accept", Kind: RuleSgSg, Action: RuleActionAccept, sgFrom: "web", sgTo: "db",
+			proto: unix.IPPROTO_TCP, ports: []portsPair{ports("", "5432 6432")}},
+		{Id: "web-db-drop", Kind: RuleSgSg, Action: RuleActionDrop, sgFrom: "web", sgTo: "db",
+			proto: unix.IPPROTO_TCP},
+		{Id: "web-db-drop-first", Kind: RuleSgSg, Action: RuleActionDrop, Priority: -10, sgFrom: "web", sgTo: "db",
+			proto: unix.IPPROTO_TCP, ports: []portsPair{ports("", "22")}},
+		{Id: "office-web", Kind: RuleCidrSg, Action: RuleActionAccept, sgTo: "web", cidr: cidr,
+			proto: unix.IPPROTO_TCP, ports: []portsPair{ports("", "80,443")}},
+		{Id: "web-ping", Kind: RuleSgIcmp, Action: RuleActionAccept, sgFrom: "web", sgTo: "web",
+			proto: unix.IPPROTO_ICMP, icmpTypes: []uint8{0, 8}},
+		{Id: "web-dns", Kind: RuleSgFqdn, Action: RuleActionAccept, sgFrom: "web",
+			proto: unix.IPPROTO_UDP, fqdn: dns, ports: []portsPair{ports("", "53")}},
+	}
+	var cache RuleCache
+	sui.Require().Nil(cache.Find(Packet{}, RuleActionAccept))
+	cache.Init(rules)
+
+	web2db := Packet{Proto: unix.IPPROTO_TCP, SSg: "web", DSg: "db", SPort: 40000, DPort: 6432}
+	cases := []struct {
+		p      Packet
+		action string
+		exp    string
+	}{
+		{web2db, RuleActionAccept, "web-db-accept"},
+		{web2db, RuleActionDrop, "web-db-drop"},
+		{Packet{Proto: unix.IPPROTO_TCP, SSg: "web", DSg: "db", DPort: 22}, RuleActionDrop, "web-db-drop-first"},
+		{web2db, "", "web-db-accept"},
+		{Packet{Proto: unix.IPPROTO_TCP, SSg: "web", DSg: "db", DPort: 22}, "", "web-db-drop-first"},
+		{Packet{Proto: unix.IPPROTO_TCP, SSg: "web", DSg: "db", DPort: 80}, "", "web-db-drop"},
+		{Packet{Proto: unix.IPPROTO_TCP, SSg: "web", DSg: "db", DPort: 80}, RuleActionAccept, ""},
+		{Packet{Proto: unix.IPPROTO_UDP, SSg: "web", DSg: "db", DPort: 5432}, RuleActionAccept, ""},
+		{Packet{Proto: unix.IPPROTO_TCP, SAddr: net.ParseIP("192.168.1.1"), DSg: "web", DPort: 443}, RuleActionAccept, "office-web"},
+		{Packet{Proto: unix.IPPROTO_TCP, SAddr: net.ParseIP("10.0.0.1"), DSg: "web", DPort: 443}, RuleActionAccept, ""},
+		{Packet{Proto: unix.IPPROTO_ICMP, SSg: "db", DSg: "web", IcmpType: 8}, RuleActionAccept, "web-ping"},
+		{Packet{Proto: unix.IPPROTO_ICMP, SSg: "db", DSg: "web", IcmpType: 3}, RuleActionAccept, ""},
+		{Packet{Proto: unix.IPPROTO_UDP, SSg: "web", DAddr: net.ParseIP("8.8.8.8"), DPort: 53}, RuleActionAccept, "web-dns"},
+	}
+	for i, c := range cases {
+		r := cache.Find(c.p, c.action)
+		if c.exp == "" {
+			sui.Require().Nil(r, "case #%d", i)
+			continue
+		}
+		sui.Require().NotNil(r, "case #%d", i)
+		sui.Require().Equal(c.exp, r.Id, "case #%d", i)
+	}
+
+	_, err := parsePorts("80-70")
+	sui.Require().Error(err)
+	_, err = parsePorts("http")
+	sui.Require().Error(err)
+}
+
+func (sui *sgHolderTestSuite) Test_FqdnResolver() {
+	var (
+		mu      sync.Mutex
+		calls   int
+		running int
+		maxRun  int
+		fail    bool
+	)
+	release := make(chan struct{})
+	f := newFqdnResolver()
+	defer f.close()
+	f.lookup = func(_ context.Context, name string) ([]net.IP, error) {
+		mu.Lock()
+		calls++
+		running++
+		if running > maxRun {
+			maxRun = running
+		}
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		running--
+		if fail {
+			return nil, errors.New("no such host")
+		}
+		return []net.IP{net.ParseIP("10.0.0.1")}, nil
+	}
+
+	var entries []*fqdnEntry
+	for i := 0; i < 2*fqdnResolveWorkers; i++ {
+		e := f.entry(fmt.Sprintf("host%d.example.com", i))
+		sui.Require().Nil(e.get(), "rules never wait for DNS")
+		entries = append(entries, e)
+	}
+	sui.Require().Nil(f.entry("*.example.com").get())
+	close(release)
+	f.wait()
+	sui.Require().LessOrEqual(maxRun, fqdnResolveWorkers)
+	sui.Require().Equal(2*fqdnResolveWorkers, calls, "wildcards are not resolved")
+	for _, e := range entries {
+		sui.Require().Equal("10.0.0.1", e.get()[0].String())
+	}
+
+	// addresses are cached by TTL
+	sui.Require().Same(entries[0], f.entry("HOST0.example.com"))
+	f.wait()
+	sui.Require().Equal(2*fqdnResolveWorkers, calls)
+
+	// addresses resolved before are kept when the name is failed to resolve
+	fail = true
+	f.mu.Lock()
+	entries[0].expires = time.Time{}
+	f.mu.Unlock()
+	f.entry("host0.example.com")
+	f.wait()
+	sui.Require().Equal(2*fqdnResolveWorkers+1, calls)
+	sui.Require().Equal("10.0.0.1", entries[0].get()[0].String())
+
+	f.retain(map[string]struct{}{"host1.example.com": {}})
+	sui.Require().Len(f.entries, 1)
+}
+
+// benchNetworks - networks of the size of the real sgroups installation: /24 and /28 IPv4 networks
+// and /64 IPv6 ones, with addresses to look up which are hits and misses
+func benchNetworks(n int) (nws []*SgNet, ips []net.IP) {
+	rnd := rand.New(rand.NewSource(1)) //nolint:gosec
+	for i := 0; i < n; i++ {
+		var cidr string
+		switch i % 4 {
+		case 0, 1:
+			cidr = fmt.Sprintf("10.%d.%d.0/24", rnd.Intn(256), rnd.Intn(256))
+		case 2:
+			cidr = fmt.Sprintf("172.%d.%d.%d/28", 16+rnd.Intn(16), rnd.Intn(256), rnd.Intn(16)*16)
+		default:
+			cidr = fmt.Sprintf("2001:db8:%x:%x::/64", rnd.Intn(1<<16), rnd.Intn(1<<16))
+		}
+		_, ipnet, _ := net.ParseCIDR(cidr)
+		nws = append(nws, &SgNet{Network: Network{Net: *ipnet, Name: cidr}, SgName: strconv.Itoa(i % 100)})
+	}
+	for i := 0; i < 4096; i++ {
+		if i%2 == 0 {
+			ip := make(net.IP, len(nws[i%n].Net.IP))
+			copy(ip, nws[i%n].Net.IP)
+			ip[len(ip)-1] |= byte(rnd.Intn(16))
+			ips = append(ips, ip)
+		} else {
+			ips = append(ips, net.IPv4(192, 168, byte(rnd.Intn(256)), byte(rnd.Intn(256))))
+		}
+	}
+	return nws, ips
+}
+
+func Benchmark_SgNwCacheFind(b *testing.B) {
+	for _, n := range []int{100, 5000, 50000} {
+		nws, ips := benchNetworks(n)
+		var cache Cache
+		cache.Init(nws)
+		b.Run(fmt.Sprintf("networks-%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					_ = cache.Find(ips[i%len(ips)])
+				}
+			})
+		})
+	}
+}
+
+func Benchmark_SgNwCacheInit(b *testing.B) {
+	nws, _ := benchNetworks(5000)
+	var cache Cache
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cache.Init(nws)
+	}
+}
@@ -0,0 +1,57 @@
+package sgnetwork
+
+import (
+	"context"
+	"net"
+
+	sg "github.com/wildberries-tech/sgroups/v2/pkg/api/sgroups"
+)
+
+// Policy - networks, security groups and rules of sgroups fetched at once
+type Policy struct {
+	nets  Cache
+	rules RuleCache
+}
+
+// FetchPolicy fetches networks, security groups and rules from sgroups, it waits for FQDNs
+// of the rules are resolved
+func FetchPolicy(ctx context.Context, c sg.SecGroupServiceClient) (*Policy, error) {
+	nws, err := fetchNwAndSG(ctx, c)
+	if err != nil {
+		return nil, ErrSgNw{Err: err}
+	}
+	fqdns := newFqdnResolver()
+	defer fqdns.close()
+	rules, err := fetchRules(ctx, c, fqdns)
+	if err != nil {
+		return nil, ErrSgNw{Err: err}
+	}
+	fqdns.wait()
+	p := new(Policy)
+	p.nets.Init(nws)
+	p.rules.Init(rules)
+	return p, nil
+}
+
+// GetSGByIP -
+func (p *Policy) GetSGByIP(ip net.IP) (nw SgNet, err error) {
+	item := p.nets.Find(ip)
+	if item == nil {
+		err = ErrSgMiss
+	} else {
+		nw = *item
+	}
+	return nw, err
+}
+
+// GetSgRule returns rule with the action which is applied first to the packet,
+// empty action matches any action so it is the rule the packet is intended to be decided by
+func (p *Policy) GetSgRule(pkt Packet, action string) (r SgRule, err error) {
+	item := p.rules.Find(pkt, action)
+	if item == nil {
+		err = ErrSgRuleMiss
+	} else {
+		r = *item
+	}
+	return r, err
+}
@@ -13,6 +13,9 @@ type (
 	//Reader db reader abstract
 	Reader interface {
 		FetchTraces(context.Context, *model.TraceScopeModel) ([]model.FetchTraceModel, error)
+		// StreamTraces passes traces of the scope in order of their time to 'fn' by batches of 'batchSize',
+		// time range of the scope is half-open
+		StreamTraces(ctx context.Context, scope *model.TraceScopeModel, batchSize int, fn func([]model.FetchTraceModel) error) error
 		FetchNftTable(context.Context, Scope) ([]model.FetchNftTableModel, error)
 		Close() error
 	}
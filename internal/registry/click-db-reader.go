@@ -48,18 +48,65 @@ func (c *clickDbReader) FetchTraces(ctx context.Context, scope *model.TraceScope
 	return res, errors.WithMessage(err, "on obtaining traces from db")
 }
 
+func (c *clickDbReader) StreamTraces(ctx context.Context, scope *model.TraceScopeModel, batchSize int, fn func([]model.FetchTraceModel) error) (err error) {
+	const table = "swarm.vu_fetch_trace"
+
+	var filter ch.TraceFilter
+	flt := *scope
+	flt.Time = nil
+	filter.InitFromModel(&flt)
+
+	q := sq.Select(new(ch.FetchTraceDB).Columns()...).
+		From(table).
+		Where(filter.Filters()).
+		OrderBy("timestamp")
+	if t := scope.Time; t != nil && t.From.Before(t.To) {
+		// bounds are bound with their time zones, literals would be read in the time zone of the server
+		q = q.Where(sq.Expr("timestamp >= ? AND timestamp < ?", t.From, t.To))
+	}
+	sql, args, err := q.ToSql()
+	if err != nil {
+		return errors.WithMessage(err, "on building query")
+	}
+
+	ok := c.reg.pool.Fetch(func(conn driver.Conn) {
+		var rows driver.Rows
+		if rows, err = conn.Query(ctx, sql, args...); err != nil {
+			return
+		}
+		defer rows.Close()
+		batch := make([]model.FetchTraceModel, 0, batchSize)
+		for rows.Next() {
+			var tr ch.FetchTraceDB
+			if err = rows.ScanStruct(&tr); err != nil {
+				return
+			}
+			if batch = append(batch, tr.ToModel()); len(batch) == batchSize {
+				if err = fn(batch); err != nil {
+					return
+				}
+				batch = batch[:0]
+			}
+		}
+		if err = rows.Err(); err == nil && len(batch) > 0 {
+			err = fn(batch)
+		}
+	})
+	if !ok {
+		err = ErrNoRegistry
+	}
+	return errors.WithMessage(err, "on streaming traces from db")
+}
+
 func (c *clickDbReader) FetchNftTable(ctx context.Context, scope Scope) (res []model.FetchNftTableModel, err error) {
-	const (
-		table = "swarm.nftables"
-		sel   = "*"
-	)
+	const table = "swarm.nftables"
 	var (
 		nftTables []ch.FetchNftTablesDB
 		filter    ch.NftTablesFilter
 	)
 	filter.InitFromScope(scope)
 
-	sql, args, err := sq.Select(sel).
+	sql, args, err := sq.Select(new(ch.FetchNftTablesDB).Columns()...).
 		From(table).
 		Where(filter.Filters()).
 		ToSql()
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -63,6 +64,56 @@ type FetchTraceDB struct {
 	SSgNet string `ch:"sgnet_s"`
 	// name of the network for dst ip
 	DSgNet string `ch:"sgnet_d"`
+	// network namespace
+	NetNS string `ch:"netns"`
+	// id of the container
+	ContainerId string `ch:"container_id"`
+	// name of the container
+	ContainerName string `ch:"container_name"`
+	// name of the pod
+	Pod string `ch:"pod"`
+	// namespace of the pod
+	PodNamespace string `ch:"pod_namespace"`
+	// labels of the container in the form key=value
+	ContainerLabels []string `ch:"container_labels"`
+	// inode of the local socket
+	SockInode uint64 `ch:"sock_inode"`
+	// id of the process owning the socket
+	Pid uint32 `ch:"pid"`
+	// command name of the process
+	Comm string `ch:"comm"`
+	// cgroup path of the process
+	Cgroup string `ch:"cgroup"`
+	// conntrack state of the connection
+	CtState string `ch:"ct_state"`
+	// direction of the packet in the connection
+	CtDirection string `ch:"ct_direction"`
+	// address translation applied to the connection
+	CtNat string `ch:"ct_nat"`
+	// conntrack mark of the connection
+	CtMark uint32 `ch:"ct_mark"`
+	// conntrack zone of the connection
+	CtZone uint32 `ch:"ct_zone"`
+	// original tuple of the connection
+	CtOrig string `ch:"ct_orig"`
+	// reply tuple of the connection
+	CtReply string `ch:"ct_reply"`
+	// subsystem the trace is collected from: nftrace/nflog
+	Source string `ch:"source"`
+	// prefix of the log rule (nflog source)
+	LogPrefix string `ch:"log_prefix"`
+	// log group of the packet (nflog source)
+	LogGroup uint32 `ch:"log_group"`
+	// netfilter hook the packet is logged at (nflog source)
+	Hook string `ch:"hook"`
+	// sgroups rule the verdict is resolved to
+	SgRule string `ch:"sg_rule"`
+	// action of the sgroups rule: accept/drop
+	SgRuleAction string `ch:"sg_rule_action"`
+	// raw link layer header of the packet
+	RawLlHeader string `ch:"raw_ll_header"`
+	// raw network and transport headers of the packet
+	RawHeaders string `ch:"raw_headers"`
 	// agent identifier
 	UserAgent string `ch:"agent_id"`
 	// time stamps
@@ -116,6 +167,50 @@ var expTraces = []model.FetchTraceModel{
 		SSgNet: "net1",
 		// name of the network for dst ip
 		DSgNet: "net2",
+		// network namespace
+		NetNS: "ns1",
+		// id of the container
+		ContainerId: "3a9f0c2b1d4e",
+		// name of the container
+		ContainerName: "web",
+		// name of the pod
+		Pod: "web-0",
+		// namespace of the pod
+		PodNamespace: "prod",
+		// labels of the container
+		ContainerLabels: []string{"app=web"},
+		// inode of the local socket
+		SockInode: 4026532,
+		// id of the process owning the socket
+		Pid: 1234,
+		// command name of the process
+		Comm: "nginx",
+		// cgroup path of the process
+		Cgroup: "/system.slice/nginx.service",
+		// conntrack state of the connection
+		CtState: "established",
+		// direction of the packet in the connection
+		CtDirection: "reply",
+		// address translation applied to the connection
+		CtNat: "dnat",
+		// conntrack mark of the connection
+		CtMark: 16,
+		// conntrack zone of the connection
+		CtZone: 3,
+		// original tuple of the connection
+		CtOrig: "tcp 10.0.0.1:40000->1.1.1.1:80",
+		// reply tuple of the connection
+		CtReply: "tcp 192.168.0.2:8080->10.0.0.1:40000",
+		// subsystem the trace is collected from: nftrace/nflog
+		Source: "nftrace",
+		// sgroups rule the verdict is resolved to
+		SgRule: "sg-sg:web->db:tcp",
+		// action of the sgroups rule: accept/drop
+		SgRuleAction: "accept",
+		// raw link layer header of the packet
+		RawLlHeader: []byte{0x1},
+		// raw network and transport headers of the packet
+		RawHeaders: []byte{0x45},
 		// agent identifier
 		UserAgent: "agent1",
 		// time stamps
@@ -132,31 +227,56 @@ func (m *MockDriver) Select(ctx context.Context, dest any, query string, args ..
 	case *[]ch.FetchTraceDB:
 		*v = []ch.FetchTraceDB{
 			{
-				TrId:       expTraces[0].TrId,
-				TableId:    expTraces[0].TableId,
-				Table:      expTraces[0].Table,
-				Chain:      expTraces[0].Chain,
-				JumpTarget: expTraces[0].JumpTarget,
-				RuleHandle: expTraces[0].RuleHandle,
-				Rule:       expTraces[0].Rule,
-				Verdict:    expTraces[0].Verdict,
-				Iifname:    expTraces[0].Iifname,
-				Oifname:    expTraces[0].Oifname,
-				Family:     expTraces[0].Family,
-				IpProto:    expTraces[0].IpProto,
-				Length:     expTraces[0].Length,
-				SMacAddr:   expTraces[0].SMacAddr,
-				DMacAddr:   expTraces[0].DMacAddr,
-				SAddr:      expTraces[0].SAddr,
-				DAddr:      expTraces[0].DAddr,
-				SPort:      expTraces[0].SPort,
-				DPort:      expTraces[0].DPort,
-				SSgName:    expTraces[0].SSgName,
-				DSgName:    expTraces[0].DSgName,
-				SSgNet:     expTraces[0].SSgNet,
-				DSgNet:     expTraces[0].DSgNet,
-				UserAgent:  expTraces[0].UserAgent,
-				Timestamp:  expTraces[0].Timestamp,
+				TrId:            expTraces[0].TrId,
+				TableId:         expTraces[0].TableId,
+				Table:           expTraces[0].Table,
+				Chain:           expTraces[0].Chain,
+				JumpTarget:      expTraces[0].JumpTarget,
+				RuleHandle:      expTraces[0].RuleHandle,
+				Rule:            expTraces[0].Rule,
+				Verdict:         expTraces[0].Verdict,
+				Iifname:         expTraces[0].Iifname,
+				Oifname:         expTraces[0].Oifname,
+				Family:          expTraces[0].Family,
+				IpProto:         expTraces[0].IpProto,
+				Length:          expTraces[0].Length,
+				SMacAddr:        expTraces[0].SMacAddr,
+				DMacAddr:        expTraces[0].DMacAddr,
+				SAddr:           expTraces[0].SAddr,
+				DAddr:           expTraces[0].DAddr,
+				SPort:           expTraces[0].SPort,
+				DPort:           expTraces[0].DPort,
+				SSgName:         expTraces[0].SSgName,
+				DSgName:         expTraces[0].DSgName,
+				SSgNet:          expTraces[0].SSgNet,
+				DSgNet:          expTraces[0].DSgNet,
+				NetNS:           expTraces[0].NetNS,
+				ContainerId:     expTraces[0].ContainerId,
+				ContainerName:   expTraces[0].ContainerName,
+				Pod:             expTraces[0].Pod,
+				PodNamespace:    expTraces[0].PodNamespace,
+				ContainerLabels: expTraces[0].ContainerLabels,
+				SockInode:       expTraces[0].SockInode,
+				Pid:             expTraces[0].Pid,
+				Comm:            expTraces[0].Comm,
+				Cgroup:          expTraces[0].Cgroup,
+				CtState:         expTraces[0].CtState,
+				CtDirection:     expTraces[0].CtDirection,
+				CtNat:           expTraces[0].CtNat,
+				CtMark:          expTraces[0].CtMark,
+				CtZone:          expTraces[0].CtZone,
+				CtOrig:          expTraces[0].CtOrig,
+				CtReply:         expTraces[0].CtReply,
+				Source:          expTraces[0].Source,
+				LogPrefix:       expTraces[0].LogPrefix,
+				LogGroup:        expTraces[0].LogGroup,
+				Hook:            expTraces[0].Hook,
+				SgRule:          expTraces[0].SgRule,
+				SgRuleAction:    expTraces[0].SgRuleAction,
+				RawLlHeader:     string(expTraces[0].RawLlHeader),
+				RawHeaders:      string(expTraces[0].RawHeaders),
+				UserAgent:       expTraces[0].UserAgent,
+				Timestamp:       expTraces[0].Timestamp,
 			},
 		}
 	}
@@ -168,7 +288,8 @@ func (m *MockDriver) Select(ctx context.Context, dest any, query string, args ..
 func (m *MockDriver) Contributors() []string                        { return nil }
 func (m *MockDriver) ServerVersion() (*driver.ServerVersion, error) { return nil, nil }
 func (m *MockDriver) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
-	return nil, nil
+	call := m.Called(ctx, query, args)
+	return call.Get(0).(driver.Rows), call.Error(1)
 }
 func (m *MockDriver) QueryRow(ctx context.Context, query string, args ...any) driver.Row { return nil }
 func (m *MockDriver) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
@@ -184,7 +305,7 @@ func (m *MockDriver) Close() error               { return nil }
 
 func Test_FetchTraces(t *testing.T) {
 	const (
-		sel      = "trace_id, table_id, table_name, chain_name, jump_target, handle, rule, verdict, ifin, ifout, family, ip_proto, len, mac_s, mac_d, ip_s, ip_d, sport, dport, sgname_s, sgname_d, sgnet_s, sgnet_d, agent_id, timestamp"
+		sel      = "trace_id, table_id, table_name, chain_name, jump_target, handle, rule, verdict, ifin, ifout, family, ip_proto, len, mac_s, mac_d, ip_s, ip_d, sport, dport, sgname_s, sgname_d, sgnet_s, sgnet_d, netns, container_id, container_name, pod, pod_namespace, container_labels, sock_inode, pid, comm, cgroup, ct_state, ct_direction, ct_nat, ct_mark, ct_zone, ct_orig, ct_reply, source, log_prefix, log_group, hook, sg_rule, sg_rule_action, raw_ll_header, raw_headers, agent_id, timestamp"
 		table    = "swarm.vu_fetch_trace"
 		timeFrom = "2024-09-28 01:11:14"
 		timeTo   = "2024-09-28 01:11:17"
@@ -248,3 +369,74 @@ func Test_FetchTraces(t *testing.T) {
 	}
 
 }
+
+// MockRows - rows of the query made of the traces
+type MockRows struct {
+	driver.Rows
+	traces []model.FetchTraceModel
+	cur    int
+}
+
+func (r *MockRows) Next() bool {
+	r.cur++
+	return r.cur <= len(r.traces)
+}
+
+func (r *MockRows) ScanStruct(dest any) error {
+	tr := r.traces[r.cur-1]
+	*dest.(*ch.FetchTraceDB) = ch.FetchTraceDB{
+		TrId:      tr.TrId,
+		Table:     tr.Table,
+		Verdict:   tr.Verdict,
+		UserAgent: tr.UserAgent,
+		Timestamp: tr.Timestamp,
+	}
+	return nil
+}
+
+func (r *MockRows) Close() error { return nil }
+func (r *MockRows) Err() error   { return nil }
+
+func Test_StreamTraces(t *testing.T) {
+	const (
+		timeFrom = "2024-09-28 01:11:14"
+		timeTo   = "2024-09-28 01:11:17"
+	)
+	from, _ := time.Parse("2006-01-02 15:04:05", timeFrom)
+	to, _ := time.Parse("2006-01-02 15:04:05", timeTo)
+	traces := make([]model.FetchTraceModel, 5)
+	for i := range traces {
+		traces[i] = model.FetchTraceModel{TrId: uint32(i), Table: "filter", Verdict: "drop", UserAgent: "agent1", Timestamp: from} //nolint:gosec
+	}
+
+	mockDriver := new(MockDriver)
+	queryMatch := mock.MatchedBy(func(query string) bool {
+		return assert.True(t, strings.HasSuffix(query,
+			" FROM swarm.vu_fetch_trace WHERE table_name IN (?) AND timestamp >= ? AND timestamp < ? ORDER BY timestamp"), query)
+	})
+	argsMatch := mock.MatchedBy(func(args []any) bool {
+		return assert.Equal(t, []any{"filter", from, to}, args)
+	})
+	mockDriver.On("Query", mock.Anything, queryMatch, argsMatch).Return(&MockRows{traces: traces}, nil)
+	r := clickDbReader{
+		reg: &clickDbRegistry{
+			db: "swarm",
+		},
+	}
+	r.reg.pool.Store(mockDriver, nil)
+
+	var batches [][]model.FetchTraceModel
+	err := r.StreamTraces(context.Background(), &model.TraceScopeModel{
+		Table: []string{"filter"},
+		Time:  &model.TimeRange{From: from, To: to},
+	}, 2, func(b []model.FetchTraceModel) error {
+		batches = append(batches, append([]model.FetchTraceModel(nil), b...))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, batches, 3)
+	require.Len(t, batches[2], 1)
+	require.Equal(t, traces[4].TrId, batches[2][0].TrId)
+	require.Equal(t, traces[4].Timestamp, batches[2][0].Timestamp)
+	mockDriver.AssertExpectations(t)
+}
@@ -131,6 +131,7 @@ func (c *clickDbBatcher) nftablePut(m *trace.NftTableModel) (count int, err erro
 			ChainName:   rlch.ChainName,
 			Rule:        rlch.Rule,
 			TableStr:    m.TableStr,
+			NetNS:       m.NetNS,
 			Timestamp:   time.Now(),
 		}
 		if err = c.ensureBatch(); err == nil {
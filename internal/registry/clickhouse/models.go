@@ -53,6 +53,56 @@ type (
 		SSgNet string `ch:"sgnet_s"`
 		// name of the network for dst ip
 		DSgNet string `ch:"sgnet_d"`
+		// network namespace
+		NetNS string `ch:"netns"`
+		// id of the container
+		ContainerId string `ch:"container_id"`
+		// name of the container
+		ContainerName string `ch:"container_name"`
+		// name of the pod
+		Pod string `ch:"pod"`
+		// namespace of the pod
+		PodNamespace string `ch:"pod_namespace"`
+		// labels of the container in the form key=value
+		ContainerLabels []string `ch:"container_labels"`
+		// inode of the local socket
+		SockInode uint64 `ch:"sock_inode"`
+		// id of the process owning the socket
+		Pid uint32 `ch:"pid"`
+		// command name of the process
+		Comm string `ch:"comm"`
+		// cgroup path of the process
+		Cgroup string `ch:"cgroup"`
+		// conntrack state of the connection: new/established/related
+		CtState string `ch:"ct_state"`
+		// direction of the packet in the connection: original/reply
+		CtDirection string `ch:"ct_direction"`
+		// address translation applied to the connection: snat/dnat
+		CtNat string `ch:"ct_nat"`
+		// conntrack mark of the connection
+		CtMark uint32 `ch:"ct_mark"`
+		// conntrack zone of the connection
+		CtZone uint32 `ch:"ct_zone"`
+		// original tuple of the connection
+		CtOrig string `ch:"ct_orig"`
+		// reply tuple of the connection
+		CtReply string `ch:"ct_reply"`
+		// subsystem the trace is collected from: nftrace/nflog
+		Source string `ch:"source"`
+		// prefix of the log rule (nflog source)
+		LogPrefix string `ch:"log_prefix"`
+		// log group of the packet (nflog source)
+		LogGroup uint32 `ch:"log_group"`
+		// netfilter hook the packet is logged at (nflog source)
+		Hook string `ch:"hook"`
+		// sgroups rule the verdict is resolved to
+		SgRule string `ch:"sg_rule"`
+		// action of the sgroups rule: accept/drop
+		SgRuleAction string `ch:"sg_rule_action"`
+		// raw link layer header of the packet
+		RawLlHeader []byte `ch:"raw_ll_header"`
+		// raw network and transport headers of the packet
+		RawHeaders []byte `ch:"raw_headers"`
 		// length packet
 		Length uint32 `ch:"len"`
 		// ip protocol (tcp/udp/icmp/...)
@@ -115,6 +165,56 @@ type (
 		SSgNet string `ch:"sgnet_s"`
 		// name of the network for dst ip
 		DSgNet string `ch:"sgnet_d"`
+		// network namespace
+		NetNS string `ch:"netns"`
+		// id of the container
+		ContainerId string `ch:"container_id"`
+		// name of the container
+		ContainerName string `ch:"container_name"`
+		// name of the pod
+		Pod string `ch:"pod"`
+		// namespace of the pod
+		PodNamespace string `ch:"pod_namespace"`
+		// labels of the container in the form key=value
+		ContainerLabels []string `ch:"container_labels"`
+		// inode of the local socket
+		SockInode uint64 `ch:"sock_inode"`
+		// id of the process owning the socket
+		Pid uint32 `ch:"pid"`
+		// command name of the process
+		Comm string `ch:"comm"`
+		// cgroup path of the process
+		Cgroup string `ch:"cgroup"`
+		// conntrack state of the connection: new/established/related
+		CtState string `ch:"ct_state"`
+		// direction of the packet in the connection: original/reply
+		CtDirection string `ch:"ct_direction"`
+		// address translation applied to the connection: snat/dnat
+		CtNat string `ch:"ct_nat"`
+		// conntrack mark of the connection
+		CtMark uint32 `ch:"ct_mark"`
+		// conntrack zone of the connection
+		CtZone uint32 `ch:"ct_zone"`
+		// original tuple of the connection
+		CtOrig string `ch:"ct_orig"`
+		// reply tuple of the connection
+		CtReply string `ch:"ct_reply"`
+		// subsystem the trace is collected from: nftrace/nflog
+		Source string `ch:"source"`
+		// prefix of the log rule (nflog source)
+		LogPrefix string `ch:"log_prefix"`
+		// log group of the packet (nflog source)
+		LogGroup uint32 `ch:"log_group"`
+		// netfilter hook the packet is logged at (nflog source)
+		Hook string `ch:"hook"`
+		// sgroups rule the verdict is resolved to
+		SgRule string `ch:"sg_rule"`
+		// action of the sgroups rule: accept/drop
+		SgRuleAction string `ch:"sg_rule_action"`
+		// raw link layer header of the packet
+		RawLlHeader string `ch:"raw_ll_header"`
+		// raw network and transport headers of the packet
+		RawHeaders string `ch:"raw_headers"`
 		// agent identifier
 		UserAgent string `ch:"agent_id"`
 		// time stamps
@@ -123,6 +223,9 @@ type (
 
 	timeFilter string
 
+	// labelsFilter - matches array column having any of the values
+	labelsFilter []string
+
 	// TraceFilter - filters for selecting trace from DB
 	TraceFilter struct {
 		// traces ids
@@ -161,6 +264,46 @@ type (
 		SSgNet []string `ch:"sgnet_s"`
 		// names of the network for dst ip
 		DSgNet []string `ch:"sgnet_d"`
+		// network namespaces
+		NetNS []string `ch:"netns"`
+		// ids of the containers
+		ContainerId []string `ch:"container_id"`
+		// names of the containers
+		ContainerName []string `ch:"container_name"`
+		// names of the pods
+		Pod []string `ch:"pod"`
+		// namespaces of the pods
+		PodNamespace []string `ch:"pod_namespace"`
+		// labels of the containers (any of them is matched)
+		ContainerLabels labelsFilter `ch:"container_labels"`
+		// ids of the processes
+		Pid []uint32 `ch:"pid"`
+		// command names of the processes
+		Comm []string `ch:"comm"`
+		// cgroup paths of the processes
+		Cgroup []string `ch:"cgroup"`
+		// conntrack states of the connections
+		CtState []string `ch:"ct_state"`
+		// directions of the packets in the connections
+		CtDirection []string `ch:"ct_direction"`
+		// address translations applied to the connections
+		CtNat []string `ch:"ct_nat"`
+		// conntrack marks of the connections
+		CtMark []uint32 `ch:"ct_mark"`
+		// conntrack zones of the connections
+		CtZone []uint32 `ch:"ct_zone"`
+		// subsystems the traces are collected from
+		Source []string `ch:"source"`
+		// prefixes of the log rules
+		LogPrefix []string `ch:"log_prefix"`
+		// log groups of the packets
+		LogGroup []uint32 `ch:"log_group"`
+		// netfilter hooks the packets are logged at
+		Hook []string `ch:"hook"`
+		// sgroups rules the verdicts are resolved to
+		SgRule []string `ch:"sg_rule"`
+		// actions of the sgroups rules
+		SgRuleAction []string `ch:"sg_rule_action"`
 		// lengths of packets
 		Length []uint32 `ch:"len"`
 		// ip protocols (tcp/udp/icmp/...)
@@ -189,6 +332,8 @@ type (
 		Rule string `ch:"rule"`
 		// nftables table represented as string
 		TableStr string `ch:"table_str"`
+		// network namespace
+		NetNS string `ch:"netns"`
 		// time stamps
 		Timestamp time.Time `ch:"timestamp"`
 	}
@@ -199,6 +344,8 @@ type (
 		TableId uint64 `ch:"table_id"`
 		// nftables table represented as string
 		TableStr string `ch:"table_str"`
+		// network namespace the table is in
+		NetNS string `ch:"netns"`
 		// time stamps
 		Timestamp time.Time `ch:"timestamp"`
 	}
@@ -227,6 +374,31 @@ func (t *TraceDB) InitFromTraceModel(msg *model.TraceModel) {
 	t.DSgName = msg.DSgName
 	t.SSgNet = msg.SSgNet
 	t.DSgNet = msg.DSgNet
+	t.NetNS = msg.NetNS
+	t.ContainerId = msg.ContainerId
+	t.ContainerName = msg.ContainerName
+	t.Pod = msg.Pod
+	t.PodNamespace = msg.PodNamespace
+	t.ContainerLabels = msg.ContainerLabels
+	t.SockInode = msg.SockInode
+	t.Pid = msg.Pid
+	t.Comm = msg.Comm
+	t.Cgroup = msg.Cgroup
+	t.CtState = msg.CtState
+	t.CtDirection = msg.CtDirection
+	t.CtNat = msg.CtNat
+	t.CtMark = msg.CtMark
+	t.CtZone = msg.CtZone
+	t.CtOrig = msg.CtOrig
+	t.CtReply = msg.CtReply
+	t.Source = msg.Source
+	t.LogPrefix = msg.LogPrefix
+	t.LogGroup = msg.LogGroup
+	t.Hook = msg.Hook
+	t.SgRule = msg.SgRule
+	t.SgRuleAction = msg.SgRuleAction
+	t.RawLlHeader = msg.RawLlHeader
+	t.RawHeaders = msg.RawHeaders
 	t.Length = msg.Length
 	t.IpProto = msg.IpProto
 	t.Verdict = msg.Verdict
@@ -237,29 +409,54 @@ func (t *TraceDB) InitFromTraceModel(msg *model.TraceModel) {
 
 func (t *TraceDB) ToTraceModel() model.TraceModel {
 	return model.TraceModel{
-		TrId:       t.TrId,
-		Table:      t.Table,
-		Chain:      t.Chain,
-		JumpTarget: t.JumpTarget,
-		RuleHandle: t.RuleHandle,
-		Family:     t.Family,
-		Iifname:    t.Iifname,
-		Oifname:    t.Oifname,
-		SMacAddr:   t.SMacAddr,
-		DMacAddr:   t.DMacAddr,
-		SAddr:      t.SAddr,
-		DAddr:      t.DAddr,
-		SPort:      t.DPort,
-		DPort:      t.DPort,
-		SSgName:    t.SSgName,
-		DSgName:    t.DSgName,
-		SSgNet:     t.SSgNet,
-		DSgNet:     t.DSgNet,
-		Length:     t.Length,
-		IpProto:    t.IpProto,
-		Verdict:    t.Verdict,
-		Rule:       t.Rule,
-		UserAgent:  t.UserAgent,
+		TrId:            t.TrId,
+		Table:           t.Table,
+		Chain:           t.Chain,
+		JumpTarget:      t.JumpTarget,
+		RuleHandle:      t.RuleHandle,
+		Family:          t.Family,
+		Iifname:         t.Iifname,
+		Oifname:         t.Oifname,
+		SMacAddr:        t.SMacAddr,
+		DMacAddr:        t.DMacAddr,
+		SAddr:           t.SAddr,
+		DAddr:           t.DAddr,
+		SPort:           t.DPort,
+		DPort:           t.DPort,
+		SSgName:         t.SSgName,
+		DSgName:         t.DSgName,
+		SSgNet:          t.SSgNet,
+		DSgNet:          t.DSgNet,
+		NetNS:           t.NetNS,
+		ContainerId:     t.ContainerId,
+		ContainerName:   t.ContainerName,
+		Pod:             t.Pod,
+		PodNamespace:    t.PodNamespace,
+		ContainerLabels: t.ContainerLabels,
+		SockInode:       t.SockInode,
+		Pid:             t.Pid,
+		Comm:            t.Comm,
+		Cgroup:          t.Cgroup,
+		CtState:         t.CtState,
+		CtDirection:     t.CtDirection,
+		CtNat:           t.CtNat,
+		CtMark:          t.CtMark,
+		CtZone:          t.CtZone,
+		CtOrig:          t.CtOrig,
+		CtReply:         t.CtReply,
+		Source:          t.Source,
+		LogPrefix:       t.LogPrefix,
+		LogGroup:        t.LogGroup,
+		Hook:            t.Hook,
+		SgRule:          t.SgRule,
+		SgRuleAction:    t.SgRuleAction,
+		RawLlHeader:     t.RawLlHeader,
+		RawHeaders:      t.RawHeaders,
+		Length:          t.Length,
+		IpProto:         t.IpProto,
+		Verdict:         t.Verdict,
+		Rule:            t.Rule,
+		UserAgent:       t.UserAgent,
 	}
 }
 
@@ -299,37 +496,87 @@ func (t *FetchTraceDB) InitFromModel(msg *model.FetchTraceModel) {
 	t.DSgName = msg.DSgName
 	t.SSgNet = msg.SSgNet
 	t.DSgNet = msg.DSgNet
+	t.NetNS = msg.NetNS
+	t.ContainerId = msg.ContainerId
+	t.ContainerName = msg.ContainerName
+	t.Pod = msg.Pod
+	t.PodNamespace = msg.PodNamespace
+	t.ContainerLabels = msg.ContainerLabels
+	t.SockInode = msg.SockInode
+	t.Pid = msg.Pid
+	t.Comm = msg.Comm
+	t.Cgroup = msg.Cgroup
+	t.CtState = msg.CtState
+	t.CtDirection = msg.CtDirection
+	t.CtNat = msg.CtNat
+	t.CtMark = msg.CtMark
+	t.CtZone = msg.CtZone
+	t.CtOrig = msg.CtOrig
+	t.CtReply = msg.CtReply
+	t.Source = msg.Source
+	t.LogPrefix = msg.LogPrefix
+	t.LogGroup = msg.LogGroup
+	t.Hook = msg.Hook
+	t.SgRule = msg.SgRule
+	t.SgRuleAction = msg.SgRuleAction
+	t.RawLlHeader = string(msg.RawLlHeader)
+	t.RawHeaders = string(msg.RawHeaders)
 	t.UserAgent = msg.UserAgent
 	t.Timestamp = msg.Timestamp
 }
 
 func (t *FetchTraceDB) ToModel() model.FetchTraceModel {
 	return model.FetchTraceModel{
-		TrId:       t.TrId,
-		TableId:    t.TableId,
-		Table:      t.Table,
-		Chain:      t.Chain,
-		JumpTarget: t.JumpTarget,
-		RuleHandle: t.RuleHandle,
-		Rule:       t.Rule,
-		Verdict:    t.Verdict,
-		Iifname:    t.Iifname,
-		Oifname:    t.Oifname,
-		Family:     t.Family,
-		IpProto:    t.IpProto,
-		Length:     t.Length,
-		SMacAddr:   t.SMacAddr,
-		DMacAddr:   t.DMacAddr,
-		SAddr:      t.SAddr,
-		DAddr:      t.DAddr,
-		SPort:      t.SPort,
-		DPort:      t.DPort,
-		SSgName:    t.SSgName,
-		DSgName:    t.DSgName,
-		SSgNet:     t.SSgNet,
-		DSgNet:     t.DSgNet,
-		UserAgent:  t.UserAgent,
-		Timestamp:  t.Timestamp,
+		TrId:            t.TrId,
+		TableId:         t.TableId,
+		Table:           t.Table,
+		Chain:           t.Chain,
+		JumpTarget:      t.JumpTarget,
+		RuleHandle:      t.RuleHandle,
+		Rule:            t.Rule,
+		Verdict:         t.Verdict,
+		Iifname:         t.Iifname,
+		Oifname:         t.Oifname,
+		Family:          t.Family,
+		IpProto:         t.IpProto,
+		Length:          t.Length,
+		SMacAddr:        t.SMacAddr,
+		DMacAddr:        t.DMacAddr,
+		SAddr:           t.SAddr,
+		DAddr:           t.DAddr,
+		SPort:           t.SPort,
+		DPort:           t.DPort,
+		SSgName:         t.SSgName,
+		DSgName:         t.DSgName,
+		SSgNet:          t.SSgNet,
+		DSgNet:          t.DSgNet,
+		NetNS:           t.NetNS,
+		ContainerId:     t.ContainerId,
+		ContainerName:   t.ContainerName,
+		Pod:             t.Pod,
+		PodNamespace:    t.PodNamespace,
+		ContainerLabels: t.ContainerLabels,
+		SockInode:       t.SockInode,
+		Pid:             t.Pid,
+		Comm:            t.Comm,
+		Cgroup:          t.Cgroup,
+		CtState:         t.CtState,
+		CtDirection:     t.CtDirection,
+		CtNat:           t.CtNat,
+		CtMark:          t.CtMark,
+		CtZone:          t.CtZone,
+		CtOrig:          t.CtOrig,
+		CtReply:         t.CtReply,
+		Source:          t.Source,
+		LogPrefix:       t.LogPrefix,
+		LogGroup:        t.LogGroup,
+		Hook:            t.Hook,
+		SgRule:          t.SgRule,
+		SgRuleAction:    t.SgRuleAction,
+		RawLlHeader:     []byte(t.RawLlHeader),
+		RawHeaders:      []byte(t.RawHeaders),
+		UserAgent:       t.UserAgent,
+		Timestamp:       t.Timestamp,
 	}
 }
 
@@ -344,10 +591,18 @@ func (t *FetchNftTablesDB) ToModel() model.FetchNftTableModel {
 	return model.FetchNftTableModel{
 		TableId:   t.TableId,
 		TableStr:  t.TableStr,
+		NetNS:     t.NetNS,
 		Timestamp: t.Timestamp,
 	}
 }
 
+func (t *FetchNftTablesDB) Columns() (cols []string) {
+	meta.IterFields(FetchNftTablesDB{}, "ch", func(_ any, tag string, _ uintptr) {
+		cols = append(cols, tag)
+	})
+	return
+}
+
 func (t *TraceFilter) InitFromModel(msg *model.TraceScopeModel) {
 	t.TrId = msg.TrId
 	t.Table = msg.Table
@@ -367,6 +622,26 @@ func (t *TraceFilter) InitFromModel(msg *model.TraceScopeModel) {
 	t.DSgName = msg.DSgName
 	t.SSgNet = msg.SSgNet
 	t.DSgNet = msg.DSgNet
+	t.NetNS = msg.NetNS
+	t.ContainerId = msg.ContainerId
+	t.ContainerName = msg.ContainerName
+	t.Pod = msg.Pod
+	t.PodNamespace = msg.PodNamespace
+	t.ContainerLabels = msg.ContainerLabels
+	t.Pid = msg.Pid
+	t.Comm = msg.Comm
+	t.Cgroup = msg.Cgroup
+	t.CtState = msg.CtState
+	t.CtDirection = msg.CtDirection
+	t.CtNat = msg.CtNat
+	t.CtMark = msg.CtMark
+	t.CtZone = msg.CtZone
+	t.Source = msg.Source
+	t.LogPrefix = msg.LogPrefix
+	t.LogGroup = msg.LogGroup
+	t.Hook = msg.Hook
+	t.SgRule = msg.SgRule
+	t.SgRuleAction = msg.SgRuleAction
 	t.Length = msg.Length
 	t.IpProto = msg.IpProto
 	t.Verdict = msg.Verdict
@@ -408,7 +683,12 @@ func (t *TraceFilter) Filters() sq.Sqlizer {
 					}
 				}
 			case reflect.Slice:
-				if v.Len() > 0 {
+				if v.Len() == 0 {
+					break
+				}
+				if v, ok := field.(labelsFilter); ok {
+					flt = append(flt, sq.Expr(fmt.Sprintf("hasAny(%s, ?)", tag), []string(v)))
+				} else {
 					flt = append(flt, sq.Eq{tag: field})
 				}
 			default:
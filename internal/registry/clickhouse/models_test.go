@@ -5,6 +5,7 @@ import (
 	"time"
 
 	model "github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+	"github.com/wildberries-tech/pkt-tracer/internal/netns"
 	"github.com/wildberries-tech/pkt-tracer/internal/registry/scopes"
 
 	"github.com/H-BF/corlib/pkg/filter"
@@ -65,9 +66,24 @@ func Test_NftablesFilters(t *testing.T) {
 	}
 }
 
+func Test_FetchNftTables(t *testing.T) {
+	at := time.Date(2024, 12, 4, 12, 0, 0, 0, time.UTC)
+	sql, _, err := sq.Select(new(FetchNftTablesDB).Columns()...).
+		From("swarm.nftables").
+		ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT table_id, table_str, netns, timestamp FROM swarm.nftables", sql)
+
+	ns := netns.NetNS{Id: "ino:4026532281", Ino: 4026532281}
+	db := FetchNftTablesDB{TableId: 1, TableStr: "table ip filter {}", NetNS: ns.String(), Timestamp: at}
+	require.Equal(t, model.FetchNftTableModel{
+		TableId: 1, TableStr: "table ip filter {}", NetNS: "ino:4026532281", Timestamp: at,
+	}, db.ToModel())
+}
+
 func Test_TraceFilters(t *testing.T) {
 	const (
-		sel      = "trace_id, table_id, table_name, chain_name, jump_target, handle, rule, verdict, ifin, ifout, family, ip_proto, len, mac_s, mac_d, ip_s, ip_d, sport, dport, sgname_s, sgname_d, sgnet_s, sgnet_d, agent_id, timestamp"
+		sel      = "trace_id, table_id, table_name, chain_name, jump_target, handle, rule, verdict, ifin, ifout, family, ip_proto, len, mac_s, mac_d, ip_s, ip_d, sport, dport, sgname_s, sgname_d, sgnet_s, sgnet_d, netns, container_id, container_name, pod, pod_namespace, container_labels, sock_inode, pid, comm, cgroup, ct_state, ct_direction, ct_nat, ct_mark, ct_zone, ct_orig, ct_reply, source, log_prefix, log_group, hook, sg_rule, sg_rule_action, raw_ll_header, raw_headers, agent_id, timestamp"
 		table    = "swarm.vu_fetch_trace"
 		timeFrom = "2024-09-28 01:11:14"
 		timeTo   = "2024-09-28 01:11:17"
@@ -126,6 +142,15 @@ func Test_TraceFilters(t *testing.T) {
 			expArgs: []interface{}(nil),
 			expSql:  "SELECT " + sel + " FROM swarm.vu_fetch_trace WHERE (" + sqlQuery + " AND timestamp BETWEEN '" + timeFrom + "' AND '" + timeTo + "')",
 		},
+		{
+			name: "Filter by containers and labels",
+			scope: &model.TraceScopeModel{
+				Pod:             []string{"web-0"},
+				ContainerLabels: []string{"app=web", "tier=front"},
+			},
+			expArgs: []interface{}{"web-0", []string{"app=web", "tier=front"}},
+			expSql:  "SELECT " + sel + " FROM swarm.vu_fetch_trace WHERE (pod IN (?) AND hasAny(container_labels, ?))",
+		},
 		{
 			name: "Not Empty Filter with query and visor agent ids list",
 			scope: &model.TraceScopeModel{
@@ -185,6 +210,31 @@ func Test_FieldTagMethod(t *testing.T) {
 	require.Equal(t, "sgname_d", obj.FieldTag(&obj.DSgName))
 	require.Equal(t, "sgnet_s", obj.FieldTag(&obj.SSgNet))
 	require.Equal(t, "sgnet_d", obj.FieldTag(&obj.DSgNet))
+	require.Equal(t, "netns", obj.FieldTag(&obj.NetNS))
+	require.Equal(t, "container_id", obj.FieldTag(&obj.ContainerId))
+	require.Equal(t, "container_name", obj.FieldTag(&obj.ContainerName))
+	require.Equal(t, "pod", obj.FieldTag(&obj.Pod))
+	require.Equal(t, "pod_namespace", obj.FieldTag(&obj.PodNamespace))
+	require.Equal(t, "container_labels", obj.FieldTag(&obj.ContainerLabels))
+	require.Equal(t, "sock_inode", obj.FieldTag(&obj.SockInode))
+	require.Equal(t, "pid", obj.FieldTag(&obj.Pid))
+	require.Equal(t, "comm", obj.FieldTag(&obj.Comm))
+	require.Equal(t, "cgroup", obj.FieldTag(&obj.Cgroup))
+	require.Equal(t, "ct_state", obj.FieldTag(&obj.CtState))
+	require.Equal(t, "ct_direction", obj.FieldTag(&obj.CtDirection))
+	require.Equal(t, "ct_nat", obj.FieldTag(&obj.CtNat))
+	require.Equal(t, "ct_mark", obj.FieldTag(&obj.CtMark))
+	require.Equal(t, "ct_zone", obj.FieldTag(&obj.CtZone))
+	require.Equal(t, "ct_orig", obj.FieldTag(&obj.CtOrig))
+	require.Equal(t, "ct_reply", obj.FieldTag(&obj.CtReply))
+	require.Equal(t, "source", obj.FieldTag(&obj.Source))
+	require.Equal(t, "log_prefix", obj.FieldTag(&obj.LogPrefix))
+	require.Equal(t, "log_group", obj.FieldTag(&obj.LogGroup))
+	require.Equal(t, "hook", obj.FieldTag(&obj.Hook))
+	require.Equal(t, "sg_rule", obj.FieldTag(&obj.SgRule))
+	require.Equal(t, "sg_rule_action", obj.FieldTag(&obj.SgRuleAction))
+	require.Equal(t, "raw_ll_header", obj.FieldTag(&obj.RawLlHeader))
+	require.Equal(t, "raw_headers", obj.FieldTag(&obj.RawHeaders))
 	require.Equal(t, "len", obj.FieldTag(&obj.Length))
 	require.Equal(t, "ip_proto", obj.FieldTag(&obj.IpProto))
 	require.Equal(t, "verdict", obj.FieldTag(&obj.Verdict))
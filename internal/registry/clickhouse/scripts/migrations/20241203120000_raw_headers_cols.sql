@@ -0,0 +1,232 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE swarm.traces
+ADD COLUMN IF NOT EXISTS raw_ll_header String DEFAULT '' AFTER sg_rule_action,
+ADD COLUMN IF NOT EXISTS raw_headers String DEFAULT '' AFTER raw_ll_header;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE swarm.trace_part
+ADD COLUMN IF NOT EXISTS raw_ll_header String DEFAULT '' AFTER sg_rule_action,
+ADD COLUMN IF NOT EXISTS raw_headers String DEFAULT '' AFTER raw_ll_header;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS swarm.tracepart_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW swarm.tracepart_mv TO swarm.trace_part AS
+SELECT trace_id,
+    sipHash64(table, family, chain, rule) as rule_id,
+    family,
+    ifin,
+    ifout,
+    mac_s,
+    mac_d,
+    ip_s,
+    ip_d,
+    sport,
+    dport,
+    sgname_s,
+    sgname_d,
+    sgnet_s,
+    sgnet_d,
+    netns,
+    container_id,
+    container_name,
+    pod,
+    pod_namespace,
+    container_labels,
+    sock_inode,
+    pid,
+    comm,
+    cgroup,
+    ct_state,
+    ct_direction,
+    ct_nat,
+    ct_mark,
+    ct_zone,
+    ct_orig,
+    ct_reply,
+    source,
+    log_prefix,
+    log_group,
+    hook,
+    sg_rule,
+    sg_rule_action,
+    raw_ll_header,
+    raw_headers,
+    len,
+    ip_proto,
+    agent_id,
+    timestamp
+FROM swarm.traces;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE OR REPLACE VIEW swarm.vu_fetch_trace AS
+SELECT trace.trace_id AS trace_id,
+    rt.table_id AS table_id,
+    rules.table AS table_name,
+    rules.chain AS chain_name,
+    rules.jump_target AS jump_target,
+    rules.handle AS handle,
+    rules.rule AS rule,
+    rules.verdict AS verdict,
+    trace.ifin AS ifin,
+    trace.ifout AS ifout,
+    trace.family AS family,
+    trace.ip_proto AS ip_proto,
+    trace.len AS len,
+    trace.mac_s as mac_s,
+    trace.mac_d as mac_d,
+    trace.ip_s AS ip_s,
+    trace.ip_d AS ip_d,
+    trace.sport AS sport,
+    trace.dport AS dport,
+    trace.sgname_s AS sgname_s,
+    trace.sgname_d AS sgname_d,
+    trace.sgnet_s AS sgnet_s,
+    trace.sgnet_d AS sgnet_d,
+    trace.netns AS netns,
+    trace.container_id AS container_id,
+    trace.container_name AS container_name,
+    trace.pod AS pod,
+    trace.pod_namespace AS pod_namespace,
+    trace.container_labels AS container_labels,
+    trace.sock_inode AS sock_inode,
+    trace.pid AS pid,
+    trace.comm AS comm,
+    trace.cgroup AS cgroup,
+    trace.ct_state AS ct_state,
+    trace.ct_direction AS ct_direction,
+    trace.ct_nat AS ct_nat,
+    trace.ct_mark AS ct_mark,
+    trace.ct_zone AS ct_zone,
+    trace.ct_orig AS ct_orig,
+    trace.ct_reply AS ct_reply,
+    trace.source AS source,
+    trace.log_prefix AS log_prefix,
+    trace.log_group AS log_group,
+    trace.hook AS hook,
+    trace.sg_rule AS sg_rule,
+    trace.sg_rule_action AS sg_rule_action,
+    trace.raw_ll_header AS raw_ll_header,
+    trace.raw_headers AS raw_headers,
+    trace.agent_id AS agent_id,
+    trace.timestamp AS timestamp
+FROM swarm.trace_part AS trace
+    JOIN swarm.trace_rules AS rules ON trace.rule_id = rules.rule_id
+    LEFT JOIN swarm.rule_to_table AS rt ON trace.rule_id = rt.rule_id;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE VIEW swarm.vu_fetch_trace AS
+SELECT trace.trace_id AS trace_id,
+    rt.table_id AS table_id,
+    rules.table AS table_name,
+    rules.chain AS chain_name,
+    rules.jump_target AS jump_target,
+    rules.handle AS handle,
+    rules.rule AS rule,
+    rules.verdict AS verdict,
+    trace.ifin AS ifin,
+    trace.ifout AS ifout,
+    trace.family AS family,
+    trace.ip_proto AS ip_proto,
+    trace.len AS len,
+    trace.mac_s as mac_s,
+    trace.mac_d as mac_d,
+    trace.ip_s AS ip_s,
+    trace.ip_d AS ip_d,
+    trace.sport AS sport,
+    trace.dport AS dport,
+    trace.sgname_s AS sgname_s,
+    trace.sgname_d AS sgname_d,
+    trace.sgnet_s AS sgnet_s,
+    trace.sgnet_d AS sgnet_d,
+    trace.netns AS netns,
+    trace.container_id AS container_id,
+    trace.container_name AS container_name,
+    trace.pod AS pod,
+    trace.pod_namespace AS pod_namespace,
+    trace.container_labels AS container_labels,
+    trace.sock_inode AS sock_inode,
+    trace.pid AS pid,
+    trace.comm AS comm,
+    trace.cgroup AS cgroup,
+    trace.ct_state AS ct_state,
+    trace.ct_direction AS ct_direction,
+    trace.ct_nat AS ct_nat,
+    trace.ct_mark AS ct_mark,
+    trace.ct_zone AS ct_zone,
+    trace.ct_orig AS ct_orig,
+    trace.ct_reply AS ct_reply,
+    trace.source AS source,
+    trace.log_prefix AS log_prefix,
+    trace.log_group AS log_group,
+    trace.hook AS hook,
+    trace.sg_rule AS sg_rule,
+    trace.sg_rule_action AS sg_rule_action,
+    trace.agent_id AS agent_id,
+    trace.timestamp AS timestamp
+FROM swarm.trace_part AS trace
+    JOIN swarm.trace_rules AS rules ON trace.rule_id = rules.rule_id
+    LEFT JOIN swarm.rule_to_table AS rt ON trace.rule_id = rt.rule_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS swarm.tracepart_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW swarm.tracepart_mv TO swarm.trace_part AS
+SELECT trace_id,
+    sipHash64(table, family, chain, rule) as rule_id,
+    family,
+    ifin,
+    ifout,
+    mac_s,
+    mac_d,
+    ip_s,
+    ip_d,
+    sport,
+    dport,
+    sgname_s,
+    sgname_d,
+    sgnet_s,
+    sgnet_d,
+    netns,
+    container_id,
+    container_name,
+    pod,
+    pod_namespace,
+    container_labels,
+    sock_inode,
+    pid,
+    comm,
+    cgroup,
+    ct_state,
+    ct_direction,
+    ct_nat,
+    ct_mark,
+    ct_zone,
+    ct_orig,
+    ct_reply,
+    source,
+    log_prefix,
+    log_group,
+    hook,
+    sg_rule,
+    sg_rule_action,
+    len,
+    ip_proto,
+    agent_id,
+    timestamp
+FROM swarm.traces;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE swarm.trace_part
+DROP COLUMN IF EXISTS raw_headers,
+DROP COLUMN IF EXISTS raw_ll_header;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE swarm.traces
+DROP COLUMN IF EXISTS raw_headers,
+DROP COLUMN IF EXISTS raw_ll_header;
+-- +goose StatementEnd
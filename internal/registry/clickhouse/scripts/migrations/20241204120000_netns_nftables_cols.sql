@@ -0,0 +1,170 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE swarm.nftables
+ADD COLUMN IF NOT EXISTS netns String DEFAULT '' AFTER table_str;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE swarm.rule_to_table
+ADD COLUMN IF NOT EXISTS netns String DEFAULT '' AFTER table_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS swarm.rule_to_table_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW swarm.rule_to_table_mv TO swarm.rule_to_table AS
+SELECT sipHash64(table_name, table_family, chain_name, rule) as rule_id,
+    if(netns = '', sipHash64(table_str), sipHash64(table_str, netns)) as table_id,
+    netns,
+    timestamp
+FROM swarm.nftables_tmp;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS swarm.nftables_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW swarm.nftables_mv TO swarm.nftables AS
+SELECT if(netns = '', sipHash64(table_str), sipHash64(table_str, netns)) as table_id,
+    table_str,
+    netns,
+    timestamp
+FROM swarm.nftables_tmp;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE OR REPLACE VIEW swarm.vu_fetch_trace AS
+SELECT trace.trace_id AS trace_id,
+    rt.table_id AS table_id,
+    rules.table AS table_name,
+    rules.chain AS chain_name,
+    rules.jump_target AS jump_target,
+    rules.handle AS handle,
+    rules.rule AS rule,
+    rules.verdict AS verdict,
+    trace.ifin AS ifin,
+    trace.ifout AS ifout,
+    trace.family AS family,
+    trace.ip_proto AS ip_proto,
+    trace.len AS len,
+    trace.mac_s as mac_s,
+    trace.mac_d as mac_d,
+    trace.ip_s AS ip_s,
+    trace.ip_d AS ip_d,
+    trace.sport AS sport,
+    trace.dport AS dport,
+    trace.sgname_s AS sgname_s,
+    trace.sgname_d AS sgname_d,
+    trace.sgnet_s AS sgnet_s,
+    trace.sgnet_d AS sgnet_d,
+    trace.netns AS netns,
+    trace.container_id AS container_id,
+    trace.container_name AS container_name,
+    trace.pod AS pod,
+    trace.pod_namespace AS pod_namespace,
+    trace.container_labels AS container_labels,
+    trace.sock_inode AS sock_inode,
+    trace.pid AS pid,
+    trace.comm AS comm,
+    trace.cgroup AS cgroup,
+    trace.ct_state AS ct_state,
+    trace.ct_direction AS ct_direction,
+    trace.ct_nat AS ct_nat,
+    trace.ct_mark AS ct_mark,
+    trace.ct_zone AS ct_zone,
+    trace.ct_orig AS ct_orig,
+    trace.ct_reply AS ct_reply,
+    trace.source AS source,
+    trace.log_prefix AS log_prefix,
+    trace.log_group AS log_group,
+    trace.hook AS hook,
+    trace.sg_rule AS sg_rule,
+    trace.sg_rule_action AS sg_rule_action,
+    trace.raw_ll_header AS raw_ll_header,
+    trace.raw_headers AS raw_headers,
+    trace.agent_id AS agent_id,
+    trace.timestamp AS timestamp
+FROM swarm.trace_part AS trace
+    JOIN swarm.trace_rules AS rules ON trace.rule_id = rules.rule_id
+    LEFT JOIN swarm.rule_to_table AS rt ON trace.rule_id = rt.rule_id AND trace.netns = rt.netns;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE VIEW swarm.vu_fetch_trace AS
+SELECT trace.trace_id AS trace_id,
+    rt.table_id AS table_id,
+    rules.table AS table_name,
+    rules.chain AS chain_name,
+    rules.jump_target AS jump_target,
+    rules.handle AS handle,
+    rules.rule AS rule,
+    rules.verdict AS verdict,
+    trace.ifin AS ifin,
+    trace.ifout AS ifout,
+    trace.family AS family,
+    trace.ip_proto AS ip_proto,
+    trace.len AS len,
+    trace.mac_s as mac_s,
+    trace.mac_d as mac_d,
+    trace.ip_s AS ip_s,
+    trace.ip_d AS ip_d,
+    trace.sport AS sport,
+    trace.dport AS dport,
+    trace.sgname_s AS sgname_s,
+    trace.sgname_d AS sgname_d,
+    trace.sgnet_s AS sgnet_s,
+    trace.sgnet_d AS sgnet_d,
+    trace.netns AS netns,
+    trace.container_id AS container_id,
+    trace.container_name AS container_name,
+    trace.pod AS pod,
+    trace.pod_namespace AS pod_namespace,
+    trace.container_labels AS container_labels,
+    trace.sock_inode AS sock_inode,
+    trace.pid AS pid,
+    trace.comm AS comm,
+    trace.cgroup AS cgroup,
+    trace.ct_state AS ct_state,
+    trace.ct_direction AS ct_direction,
+    trace.ct_nat AS ct_nat,
+    trace.ct_mark AS ct_mark,
+    trace.ct_zone AS ct_zone,
+    trace.ct_orig AS ct_orig,
+    trace.ct_reply AS ct_reply,
+    trace.source AS source,
+    trace.log_prefix AS log_prefix,
+    trace.log_group AS log_group,
+    trace.hook AS hook,
+    trace.sg_rule AS sg_rule,
+    trace.sg_rule_action AS sg_rule_action,
+    trace.raw_ll_header AS raw_ll_header,
+    trace.raw_headers AS raw_headers,
+    trace.agent_id AS agent_id,
+    trace.timestamp AS timestamp
+FROM swarm.trace_part AS trace
+    JOIN swarm.trace_rules AS rules ON trace.rule_id = rules.rule_id
+    LEFT JOIN swarm.rule_to_table AS rt ON trace.rule_id = rt.rule_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS swarm.nftables_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW swarm.nftables_mv TO swarm.nftables AS
+SELECT sipHash64(table_str) as table_id,
+    table_str,
+    timestamp
+FROM swarm.nftables_tmp;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS swarm.rule_to_table_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW swarm.rule_to_table_mv TO swarm.rule_to_table AS
+SELECT sipHash64(table_name, table_family, chain_name, rule) as rule_id,
+    sipHash64(table_str) as table_id,
+    timestamp
+FROM swarm.nftables_tmp;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE swarm.rule_to_table DROP COLUMN IF EXISTS netns;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE swarm.nftables DROP COLUMN IF EXISTS netns;
+-- +goose StatementEnd
@@ -0,0 +1,210 @@
+package trafficmetrics
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	nsTraffic = "traffic"
+
+	// OverflowValue - value of every label of the series the traces are counted to when limit of the series is reached
+	OverflowValue = "_other_"
+
+	keySep = "\x00"
+)
+
+// suffixes of the verdicts the packet traversal is finished with
+var terminalVerdicts = []string{"::accept", "::drop"}
+
+const verdictSep = "::"
+
+// labels of the traffic metrics
+const (
+	LabelAgent   = "agent"
+	LabelTable   = "table"
+	LabelChain   = "chain"
+	LabelVerdict = "verdict"
+	LabelProto   = "proto"
+	LabelSgSrc   = "sg_src"
+	LabelSgDst   = "sg_dst"
+)
+
+// AllLabels - labels the traffic metrics can be labeled by
+var AllLabels = []string{
+	LabelAgent, LabelTable, LabelChain, LabelVerdict, LabelProto, LabelSgSrc, LabelSgDst,
+}
+
+var labelGetters = map[string]func(*trace.TraceModel) string{
+	LabelAgent:   func(t *trace.TraceModel) string { return t.UserAgent },
+	LabelTable:   func(t *trace.TraceModel) string { return t.Table },
+	LabelChain:   func(t *trace.TraceModel) string { return t.Chain },
+	LabelVerdict: func(t *trace.TraceModel) string { return finalAction(t.Verdict) },
+	LabelProto:   func(t *trace.TraceModel) string { return t.IpProto },
+	LabelSgSrc:   func(t *trace.TraceModel) string { return t.SSgName },
+	LabelSgDst:   func(t *trace.TraceModel) string { return t.DSgName },
+}
+
+type (
+	// Collector - packet and byte counters of the traces labeled by their fields,
+	// number of the series is limited, traces of the new series over the limit are counted
+	// to the series with all labels set to OverflowValue
+	Collector struct {
+		mu          sync.Mutex
+		labels      []string
+		getters     []func(*trace.TraceModel) string
+		maxSeries   int
+		series      map[string]*series
+		overflow    *series
+		packetsDesc *prometheus.Desc
+		bytesDesc   *prometheus.Desc
+		seriesDesc  *prometheus.Desc
+	}
+
+	series struct {
+		values  []string
+		packets uint64
+		bytes   uint64
+	}
+)
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates collector labeled by the labels from AllLabels, maxSeries <= 0 means no limit
+func NewCollector(labels []string, maxSeries int, constLabels prometheus.Labels) (*Collector, error) {
+	c := &Collector{
+		maxSeries: maxSeries,
+		series:    make(map[string]*series),
+	}
+	seen := make(map[string]struct{}, len(labels))
+	for _, l := range labels {
+		g, ok := labelGetters[l]
+		if !ok {
+			return nil, errors.Errorf("unknown label '%s' of the traffic metrics, expected one of: %s",
+				l, strings.Join(AllLabels, ", "))
+		}
+		if _, dup := seen[l]; dup {
+			return nil, errors.Errorf("label '%s' of the traffic metrics is duplicated", l)
+		}
+		seen[l] = struct{}{}
+		c.labels = append(c.labels, l)
+		c.getters = append(c.getters, g)
+	}
+	c.packetsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(nsTraffic, "", "packets_total"),
+		"count of the traced packets",
+		c.labels, constLabels,
+	)
+	c.bytesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(nsTraffic, "", "bytes_total"),
+		"count of bytes of the traced packets",
+		c.labels, constLabels,
+	)
+	c.seriesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(nsTraffic, "", "series"),
+		"number of the series of the traffic counters",
+		nil, constLabels,
+	)
+	return c, nil
+}
+
+// ParseLabels parses comma separated labels
+func ParseLabels(s string) []string {
+	var ret []string
+	for _, l := range strings.Split(s, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			ret = append(ret, l)
+		}
+	}
+	return ret
+}
+
+// Observe accounts the traces, a packet makes trace in every rule and chain it traverses
+// so only traces with the terminal verdict are counted
+func (c *Collector) Observe(traces []*trace.TraceModel) {
+	if len(traces) == 0 {
+		return
+	}
+	values := make([]string, len(c.getters))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range traces {
+		if !isTerminal(t.Verdict) {
+			continue
+		}
+		for i, g := range c.getters {
+			values[i] = g(t)
+		}
+		s := c.seriesOf(values)
+		s.packets++
+		s.bytes += uint64(t.Length)
+	}
+}
+
+// Describe impl prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.packetsDesc
+	ch <- c.bytesDesc
+	ch <- c.seriesDesc
+}
+
+// Collect impl prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	collect := func(s *series) {
+		ch <- prometheus.MustNewConstMetric(c.packetsDesc, prometheus.CounterValue, float64(s.packets), s.values...)
+		ch <- prometheus.MustNewConstMetric(c.bytesDesc, prometheus.CounterValue, float64(s.bytes), s.values...)
+	}
+	for _, s := range c.series {
+		collect(s)
+	}
+	n := len(c.series)
+	if c.overflow != nil {
+		collect(c.overflow)
+		n++
+	}
+	ch <- prometheus.MustNewConstMetric(c.seriesDesc, prometheus.GaugeValue, float64(n))
+}
+
+func (c *Collector) seriesOf(values []string) *series {
+	key := strings.Join(values, keySep)
+	if s := c.series[key]; s != nil {
+		return s
+	}
+	if c.maxSeries > 0 && len(c.series) >= c.maxSeries {
+		if c.overflow == nil {
+			c.overflow = &series{values: make([]string, len(values))}
+			for i := range c.overflow.values {
+				c.overflow.values[i] = OverflowValue
+			}
+		}
+		return c.overflow
+	}
+	s := &series{values: append([]string(nil), values...)}
+	c.series[key] = s
+	return s
+}
+
+// finalAction gives the action the verdict chain is finished with,
+// the chain itself is not the label value as it multiplies the series
+func finalAction(verdict string) string {
+	if i := strings.LastIndex(verdict, verdictSep); i >= 0 {
+		return verdict[i+len(verdictSep):]
+	}
+	return verdict
+}
+
+func isTerminal(verdict string) bool {
+	for _, sfx := range terminalVerdicts {
+		if strings.HasSuffix(verdict, sfx) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package trafficmetrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wildberries-tech/pkt-tracer/internal/models/trace"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+type collectorTestSuite struct {
+	suite.Suite
+}
+
+func Test_Collector(t *testing.T) {
+	suite.Run(t, new(collectorTestSuite))
+}
+
+func tr(sgSrc, sgDst, verdict string, length uint32) *trace.TraceModel {
+	return &trace.TraceModel{
+		UserAgent: "agent-1", Table: "main", Chain: "FORWARD", IpProto: "tcp",
+		SSgName: sgSrc, DSgName: sgDst, Verdict: verdict, Length: length,
+	}
+}
+
+func (sui *collectorTestSuite) Test_Counters() {
+	c, err := NewCollector([]string{LabelVerdict, LabelSgSrc, LabelSgDst}, 0, prometheus.Labels{"host_name": "h"})
+	sui.Require().NoError(err)
+	c.Observe([]*trace.TraceModel{
+		tr("sg-a", "sg-b", "rule::drop", 100),
+		tr("sg-a", "sg-b", "rule::jump::rule::drop", 60),
+		tr("sg-a", "sg-b", "rule::accept", 40),
+		tr("sg-a", "sg-b", "rule::jump::policy::accept", 20),
+		tr("sg-a", "sg-b", "rule::continue", 40),
+		tr("sg-a", "sg-b", "rule::jump", 40),
+	})
+	c.Observe(nil)
+	exp := `
+# HELP traffic_bytes_total count of bytes of the traced packets
+# TYPE traffic_bytes_total counter
+traffic_bytes_total{host_name="h",sg_dst="sg-b",sg_src="sg-a",verdict="accept"} 60
+traffic_bytes_total{host_name="h",sg_dst="sg-b",sg_src="sg-a",verdict="drop"} 160
+# HELP traffic_packets_total count of the traced packets
+# TYPE traffic_packets_total counter
+traffic_packets_total{host_name="h",sg_dst="sg-b",sg_src="sg-a",verdict="accept"} 2
+traffic_packets_total{host_name="h",sg_dst="sg-b",sg_src="sg-a",verdict="drop"} 2
+# HELP traffic_series number of the series of the traffic counters
+# TYPE traffic_series gauge
+traffic_series{host_name="h"} 2
+`
+	sui.Require().NoError(testutil.CollectAndCompare(c, strings.NewReader(exp)))
+}
+
+func (sui *collectorTestSuite) Test_CardinalityGuard() {
+	c, err := NewCollector([]string{LabelSgSrc}, 2, nil)
+	sui.Require().NoError(err)
+	c.Observe([]*trace.TraceModel{
+		tr("sg-a", "", "rule::accept", 1),
+		tr("sg-b", "", "rule::accept", 1),
+		tr("sg-c", "", "rule::accept", 1),
+		tr("sg-d", "", "rule::accept", 1),
+		tr("sg-a", "", "rule::accept", 1),
+	})
+	exp := `
+# HELP traffic_packets_total count of the traced packets
+# TYPE traffic_packets_total counter
+traffic_packets_total{sg_src="_other_"} 2
+traffic_packets_total{sg_src="sg-a"} 2
+traffic_packets_total{sg_src="sg-b"} 1
+`
+	sui.Require().NoError(testutil.CollectAndCompare(c, strings.NewReader(exp), "traffic_packets_total"))
+}
+
+func (sui *collectorTestSuite) Test_Labels() {
+	sui.Require().Equal([]string{LabelAgent, LabelVerdict}, ParseLabels(" agent, verdict,,"))
+	_, err := NewCollector([]string{"unknown"}, 0, nil)
+	sui.Require().Error(err)
+	_, err = NewCollector([]string{LabelAgent, LabelAgent}, 0, nil)
+	sui.Require().Error(err)
+	_, err = NewCollector(AllLabels, 0, nil)
+	sui.Require().NoError(err)
+}
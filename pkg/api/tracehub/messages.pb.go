@@ -9,6 +9,7 @@ package tracehub
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -71,6 +72,56 @@ type Trace struct {
 	SSgNet string `protobuf:"bytes,21,opt,name=s_sg_net,json=sSgNet,proto3" json:"s_sg_net,omitempty"`
 	// name of the network for dst ip
 	DSgNet string `protobuf:"bytes,22,opt,name=d_sg_net,json=dSgNet,proto3" json:"d_sg_net,omitempty"`
+	// network namespace where the trace was caught (empty for the host namespace)
+	Netns string `protobuf:"bytes,23,opt,name=netns,proto3" json:"netns,omitempty"`
+	// id of the container owning the interface or network namespace
+	ContainerId string `protobuf:"bytes,24,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	// name of the container
+	ContainerName string `protobuf:"bytes,25,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
+	// name of the pod of the container
+	Pod string `protobuf:"bytes,26,opt,name=pod,proto3" json:"pod,omitempty"`
+	// namespace of the pod
+	PodNamespace string `protobuf:"bytes,27,opt,name=pod_namespace,json=podNamespace,proto3" json:"pod_namespace,omitempty"`
+	// labels of the container in the form key=value
+	ContainerLabels []string `protobuf:"bytes,28,rep,name=container_labels,json=containerLabels,proto3" json:"container_labels,omitempty"`
+	// inode of the local socket owning the packet
+	SockInode uint64 `protobuf:"varint,29,opt,name=sock_inode,json=sockInode,proto3" json:"sock_inode,omitempty"`
+	// id of the process owning the socket
+	Pid uint32 `protobuf:"varint,30,opt,name=pid,proto3" json:"pid,omitempty"`
+	// command name of the process
+	Comm string `protobuf:"bytes,31,opt,name=comm,proto3" json:"comm,omitempty"`
+	// cgroup path of the process
+	Cgroup string `protobuf:"bytes,32,opt,name=cgroup,proto3" json:"cgroup,omitempty"`
+	// conntrack state of the connection: new/established/related
+	CtState string `protobuf:"bytes,33,opt,name=ct_state,json=ctState,proto3" json:"ct_state,omitempty"`
+	// direction of the packet in the connection: original/reply
+	CtDirection string `protobuf:"bytes,34,opt,name=ct_direction,json=ctDirection,proto3" json:"ct_direction,omitempty"`
+	// address translation applied to the connection: snat/dnat
+	CtNat string `protobuf:"bytes,35,opt,name=ct_nat,json=ctNat,proto3" json:"ct_nat,omitempty"`
+	// conntrack mark of the connection
+	CtMark uint32 `protobuf:"varint,36,opt,name=ct_mark,json=ctMark,proto3" json:"ct_mark,omitempty"`
+	// conntrack zone of the connection
+	CtZone uint32 `protobuf:"varint,37,opt,name=ct_zone,json=ctZone,proto3" json:"ct_zone,omitempty"`
+	// original tuple of the connection
+	CtOrig string `protobuf:"bytes,38,opt,name=ct_orig,json=ctOrig,proto3" json:"ct_orig,omitempty"`
+	// reply tuple of the connection
+	CtReply string `protobuf:"bytes,39,opt,name=ct_reply,json=ctReply,proto3" json:"ct_reply,omitempty"`
+	// subsystem the trace is collected from: nftrace/nflog
+	Source string `protobuf:"bytes,40,opt,name=source,proto3" json:"source,omitempty"`
+	// prefix of the log rule (nflog source)
+	LogPrefix string `protobuf:"bytes,41,opt,name=log_prefix,json=logPrefix,proto3" json:"log_prefix,omitempty"`
+	// log group of the packet (nflog source)
+	LogGroup uint32 `protobuf:"varint,42,opt,name=log_group,json=logGroup,proto3" json:"log_group,omitempty"`
+	// netfilter hook the packet is logged at (nflog source)
+	Hook string `protobuf:"bytes,43,opt,name=hook,proto3" json:"hook,omitempty"`
+	// sgroups rule the verdict is resolved to
+	SgRule string `protobuf:"bytes,44,opt,name=sg_rule,json=sgRule,proto3" json:"sg_rule,omitempty"`
+	// action of the sgroups rule: accept/drop
+	SgRuleAction string `protobuf:"bytes,45,opt,name=sg_rule_action,json=sgRuleAction,proto3" json:"sg_rule_action,omitempty"`
+	// raw link layer header of the packet (when the agent keeps raw headers)
+	RawLlHeader []byte `protobuf:"bytes,46,opt,name=raw_ll_header,json=rawLlHeader,proto3" json:"raw_ll_header,omitempty"`
+	// raw network and transport headers of the packet (when the agent keeps raw headers)
+	RawHeaders []byte `protobuf:"bytes,47,opt,name=raw_headers,json=rawHeaders,proto3" json:"raw_headers,omitempty"`
 }
 
 func (x *Trace) Reset() {
@@ -259,6 +310,181 @@ func (x *Trace) GetDSgNet() string {
 	return ""
 }
 
+func (x *Trace) GetNetns() string {
+	if x != nil {
+		return x.Netns
+	}
+	return ""
+}
+
+func (x *Trace) GetContainerId() string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return ""
+}
+
+func (x *Trace) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *Trace) GetPod() string {
+	if x != nil {
+		return x.Pod
+	}
+	return ""
+}
+
+func (x *Trace) GetPodNamespace() string {
+	if x != nil {
+		return x.PodNamespace
+	}
+	return ""
+}
+
+func (x *Trace) GetContainerLabels() []string {
+	if x != nil {
+		return x.ContainerLabels
+	}
+	return nil
+}
+
+func (x *Trace) GetSockInode() uint64 {
+	if x != nil {
+		return x.SockInode
+	}
+	return 0
+}
+
+func (x *Trace) GetPid() uint32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Trace) GetComm() string {
+	if x != nil {
+		return x.Comm
+	}
+	return ""
+}
+
+func (x *Trace) GetCgroup() string {
+	if x != nil {
+		return x.Cgroup
+	}
+	return ""
+}
+
+func (x *Trace) GetCtState() string {
+	if x != nil {
+		return x.CtState
+	}
+	return ""
+}
+
+func (x *Trace) GetCtDirection() string {
+	if x != nil {
+		return x.CtDirection
+	}
+	return ""
+}
+
+func (x *Trace) GetCtNat() string {
+	if x != nil {
+		return x.CtNat
+	}
+	return ""
+}
+
+func (x *Trace) GetCtMark() uint32 {
+	if x != nil {
+		return x.CtMark
+	}
+	return 0
+}
+
+func (x *Trace) GetCtZone() uint32 {
+	if x != nil {
+		return x.CtZone
+	}
+	return 0
+}
+
+func (x *Trace) GetCtOrig() string {
+	if x != nil {
+		return x.CtOrig
+	}
+	return ""
+}
+
+func (x *Trace) GetCtReply() string {
+	if x != nil {
+		return x.CtReply
+	}
+	return ""
+}
+
+func (x *Trace) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Trace) GetLogPrefix() string {
+	if x != nil {
+		return x.LogPrefix
+	}
+	return ""
+}
+
+func (x *Trace) GetLogGroup() uint32 {
+	if x != nil {
+		return x.LogGroup
+	}
+	return 0
+}
+
+func (x *Trace) GetHook() string {
+	if x != nil {
+		return x.Hook
+	}
+	return ""
+}
+
+func (x *Trace) GetSgRule() string {
+	if x != nil {
+		return x.SgRule
+	}
+	return ""
+}
+
+func (x *Trace) GetSgRuleAction() string {
+	if x != nil {
+		return x.SgRuleAction
+	}
+	return ""
+}
+
+func (x *Trace) GetRawLlHeader() []byte {
+	if x != nil {
+		return x.RawLlHeader
+	}
+	return nil
+}
+
+func (x *Trace) GetRawHeaders() []byte {
+	if x != nil {
+		return x.RawHeaders
+	}
+	return nil
+}
+
 // Traces: represents subject of traces
 type Traces struct {
 	state         protoimpl.MessageState
@@ -320,6 +546,8 @@ type FetchTrace struct {
 	TableId uint64 `protobuf:"varint,2,opt,name=table_id,json=tableId,proto3" json:"table_id,omitempty"`
 	// trace creation time
 	Timestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// identifier of the agent the trace is collected by
+	AgentId string `protobuf:"bytes,4,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 }
 
 func (x *FetchTrace) Reset() {
@@ -375,6 +603,13 @@ func (x *FetchTrace) GetTimestamp() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *FetchTrace) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
 // TraceList: represents list of traces fetched from server
 type TraceList struct {
 	state         protoimpl.MessageState
@@ -539,6 +774,48 @@ type TraceScope struct {
 	Query string `protobuf:"bytes,25,opt,name=query,proto3" json:"query,omitempty"`
 	// list of visor agents identifiers
 	AgentsIds []string `protobuf:"bytes,26,rep,name=agents_ids,json=agentsIds,proto3" json:"agents_ids,omitempty"`
+	// network namespaces
+	Netns []string `protobuf:"bytes,27,rep,name=netns,proto3" json:"netns,omitempty"`
+	// ids of the containers
+	ContainerId []string `protobuf:"bytes,28,rep,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	// names of the containers
+	ContainerName []string `protobuf:"bytes,29,rep,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
+	// names of the pods
+	Pod []string `protobuf:"bytes,30,rep,name=pod,proto3" json:"pod,omitempty"`
+	// namespaces of the pods
+	PodNamespace []string `protobuf:"bytes,31,rep,name=pod_namespace,json=podNamespace,proto3" json:"pod_namespace,omitempty"`
+	// labels of the containers in the form key=value (any of them is matched)
+	ContainerLabels []string `protobuf:"bytes,32,rep,name=container_labels,json=containerLabels,proto3" json:"container_labels,omitempty"`
+	// ids of the processes owning sockets
+	Pid []uint32 `protobuf:"varint,33,rep,packed,name=pid,proto3" json:"pid,omitempty"`
+	// command names of the processes
+	Comm []string `protobuf:"bytes,34,rep,name=comm,proto3" json:"comm,omitempty"`
+	// cgroup paths of the processes
+	Cgroup []string `protobuf:"bytes,35,rep,name=cgroup,proto3" json:"cgroup,omitempty"`
+	// conntrack states of the connections
+	CtState []string `protobuf:"bytes,36,rep,name=ct_state,json=ctState,proto3" json:"ct_state,omitempty"`
+	// directions of the packets in the connections
+	CtDirection []string `protobuf:"bytes,37,rep,name=ct_direction,json=ctDirection,proto3" json:"ct_direction,omitempty"`
+	// address translations applied to the connections
+	CtNat []string `protobuf:"bytes,38,rep,name=ct_nat,json=ctNat,proto3" json:"ct_nat,omitempty"`
+	// conntrack marks of the connections
+	CtMark []uint32 `protobuf:"varint,39,rep,packed,name=ct_mark,json=ctMark,proto3" json:"ct_mark,omitempty"`
+	// conntrack zones of the connections
+	CtZone []uint32 `protobuf:"varint,40,rep,packed,name=ct_zone,json=ctZone,proto3" json:"ct_zone,omitempty"`
+	// subsystems the traces are collected from
+	Source []string `protobuf:"bytes,41,rep,name=source,proto3" json:"source,omitempty"`
+	// prefixes of the log rules
+	LogPrefix []string `protobuf:"bytes,42,rep,name=log_prefix,json=logPrefix,proto3" json:"log_prefix,omitempty"`
+	// log groups of the packets
+	LogGroup []uint32 `protobuf:"varint,43,rep,packed,name=log_group,json=logGroup,proto3" json:"log_group,omitempty"`
+	// netfilter hooks the packets are logged at
+	Hook []string `protobuf:"bytes,44,rep,name=hook,proto3" json:"hook,omitempty"`
+	// sgroups rules the verdicts are resolved to
+	SgRule []string `protobuf:"bytes,45,rep,name=sg_rule,json=sgRule,proto3" json:"sg_rule,omitempty"`
+	// actions of the sgroups rules
+	SgRuleAction []string `protobuf:"bytes,46,rep,name=sg_rule_action,json=sgRuleAction,proto3" json:"sg_rule_action,omitempty"`
+	// visor query the 'query' is converted from, it is matched by the offline visor instead of the SQL
+	QueryDsl string `protobuf:"bytes,47,opt,name=query_dsl,json=queryDsl,proto3" json:"query_dsl,omitempty"`
 }
 
 func (x *TraceScope) Reset() {
@@ -755,6 +1032,153 @@ func (x *TraceScope) GetAgentsIds() []string {
 	return nil
 }
 
+func (x *TraceScope) GetNetns() []string {
+	if x != nil {
+		return x.Netns
+	}
+	return nil
+}
+
+func (x *TraceScope) GetContainerId() []string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return nil
+}
+
+func (x *TraceScope) GetContainerName() []string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return nil
+}
+
+func (x *TraceScope) GetPod() []string {
+	if x != nil {
+		return x.Pod
+	}
+	return nil
+}
+
+func (x *TraceScope) GetPodNamespace() []string {
+	if x != nil {
+		return x.PodNamespace
+	}
+	return nil
+}
+
+func (x *TraceScope) GetContainerLabels() []string {
+	if x != nil {
+		return x.ContainerLabels
+	}
+	return nil
+}
+
+func (x *TraceScope) GetPid() []uint32 {
+	if x != nil {
+		return x.Pid
+	}
+	return nil
+}
+
+func (x *TraceScope) GetComm() []string {
+	if x != nil {
+		return x.Comm
+	}
+	return nil
+}
+
+func (x *TraceScope) GetCgroup() []string {
+	if x != nil {
+		return x.Cgroup
+	}
+	return nil
+}
+
+func (x *TraceScope) GetCtState() []string {
+	if x != nil {
+		return x.CtState
+	}
+	return nil
+}
+
+func (x *TraceScope) GetCtDirection() []string {
+	if x != nil {
+		return x.CtDirection
+	}
+	return nil
+}
+
+func (x *TraceScope) GetCtNat() []string {
+	if x != nil {
+		return x.CtNat
+	}
+	return nil
+}
+
+func (x *TraceScope) GetCtMark() []uint32 {
+	if x != nil {
+		return x.CtMark
+	}
+	return nil
+}
+
+func (x *TraceScope) GetCtZone() []uint32 {
+	if x != nil {
+		return x.CtZone
+	}
+	return nil
+}
+
+func (x *TraceScope) GetSource() []string {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *TraceScope) GetLogPrefix() []string {
+	if x != nil {
+		return x.LogPrefix
+	}
+	return nil
+}
+
+func (x *TraceScope) GetLogGroup() []uint32 {
+	if x != nil {
+		return x.LogGroup
+	}
+	return nil
+}
+
+func (x *TraceScope) GetHook() []string {
+	if x != nil {
+		return x.Hook
+	}
+	return nil
+}
+
+func (x *TraceScope) GetSgRule() []string {
+	if x != nil {
+		return x.SgRule
+	}
+	return nil
+}
+
+func (x *TraceScope) GetSgRuleAction() []string {
+	if x != nil {
+		return x.SgRuleAction
+	}
+	return nil
+}
+
+func (x *TraceScope) GetQueryDsl() string {
+	if x != nil {
+		return x.QueryDsl
+	}
+	return ""
+}
+
 // NftRuleInChain: rule to chain
 type NftRuleInChain struct {
 	state         protoimpl.MessageState
@@ -827,6 +1251,8 @@ type NftTable struct {
 	TableStr string `protobuf:"bytes,3,opt,name=table_str,json=tableStr,proto3" json:"table_str,omitempty"`
 	// nftables rules items
 	Rules []*NftRuleInChain `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+	// network namespace of the table (empty for the host namespace)
+	Netns string `protobuf:"bytes,5,opt,name=netns,proto3" json:"netns,omitempty"`
 }
 
 func (x *NftTable) Reset() {
@@ -889,6 +1315,13 @@ func (x *NftTable) GetRules() []*NftRuleInChain {
 	return nil
 }
 
+func (x *NftTable) GetNetns() string {
+	if x != nil {
+		return x.Netns
+	}
+	return ""
+}
+
 type SyncTableReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1030,6 +1463,8 @@ type NftTableResp struct {
 	TableStr string `protobuf:"bytes,2,opt,name=table_str,json=tableStr,proto3" json:"table_str,omitempty"`
 	// timestamp
 	Timestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// network namespace the table is in
+	Netns string `protobuf:"bytes,4,opt,name=netns,proto3" json:"netns,omitempty"`
 }
 
 func (x *NftTableResp) Reset() {
@@ -1085,6 +1520,13 @@ func (x *NftTableResp) GetTimestamp() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *NftTableResp) GetNetns() string {
+	if x != nil {
+		return x.Netns
+	}
+	return ""
+}
+
 type NftTableList struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1133,14 +1575,21 @@ func (x *NftTableList) GetTables() []*NftTableResp {
 	return nil
 }
 
-type FetchNftTableQry_All struct {
+// ExportTracesReq: query of the bulk export of traces, traces are streamed in order of their time
+// and time range of the scope is half-open [from, to)
+type ExportTracesReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// filter of the exported traces, follow mode is not supported
+	Scope *TraceScope `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+	// max number of traces in one message of the stream (1000 by default)
+	BatchSize uint32 `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
 }
 
-func (x *FetchNftTableQry_All) Reset() {
-	*x = FetchNftTableQry_All{}
+func (x *ExportTracesReq) Reset() {
+	*x = ExportTracesReq{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_tracehub_messages_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1148,13 +1597,13 @@ func (x *FetchNftTableQry_All) Reset() {
 	}
 }
 
-func (x *FetchNftTableQry_All) String() string {
+func (x *ExportTracesReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FetchNftTableQry_All) ProtoMessage() {}
+func (*ExportTracesReq) ProtoMessage() {}
 
-func (x *FetchNftTableQry_All) ProtoReflect() protoreflect.Message {
+func (x *ExportTracesReq) ProtoReflect() protoreflect.Message {
 	mi := &file_tracehub_messages_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1166,12 +1615,690 @@ func (x *FetchNftTableQry_All) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FetchNftTableQry_All.ProtoReflect.Descriptor instead.
-func (*FetchNftTableQry_All) Descriptor() ([]byte, []int) {
-	return file_tracehub_messages_proto_rawDescGZIP(), []int{9, 0}
+// Deprecated: Use ExportTracesReq.ProtoReflect.Descriptor instead.
+func (*ExportTracesReq) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{12}
 }
 
-type FetchNftTableQry_ByTableId struct {
+func (x *ExportTracesReq) GetScope() *TraceScope {
+	if x != nil {
+		return x.Scope
+	}
+	return nil
+}
+
+func (x *ExportTracesReq) GetBatchSize() uint32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+// ListAgentsReq: query of agents registered on server
+type ListAgentsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// list of agents identifiers (empty means all)
+	AgentsIds []string `protobuf:"bytes,1,rep,name=agents_ids,json=agentsIds,proto3" json:"agents_ids,omitempty"`
+	// fetch online agents only
+	OnlineOnly bool `protobuf:"varint,2,opt,name=online_only,json=onlineOnly,proto3" json:"online_only,omitempty"`
+}
+
+func (x *ListAgentsReq) Reset() {
+	*x = ListAgentsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracehub_messages_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAgentsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsReq) ProtoMessage() {}
+
+func (x *ListAgentsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_tracehub_messages_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsReq.ProtoReflect.Descriptor instead.
+func (*ListAgentsReq) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListAgentsReq) GetAgentsIds() []string {
+	if x != nil {
+		return x.AgentsIds
+	}
+	return nil
+}
+
+func (x *ListAgentsReq) GetOnlineOnly() bool {
+	if x != nil {
+		return x.OnlineOnly
+	}
+	return false
+}
+
+// Agent: tracer agent registered on server
+type Agent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// agent identifier
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// agent version
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// agent host name
+	Hostname string `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	// kernel release of the agent host
+	Kernel string `protobuf:"bytes,4,opt,name=kernel,proto3" json:"kernel,omitempty"`
+	// remote address of the agent connection
+	RemoteAddr string `protobuf:"bytes,5,opt,name=remote_addr,json=remoteAddr,proto3" json:"remote_addr,omitempty"`
+	// time of the last connection
+	ConnectedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	// time of the last heartbeat or trace batch
+	LastSeenAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	// time of the last received trace
+	LastTraceAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_trace_at,json=lastTraceAt,proto3" json:"last_trace_at,omitempty"`
+	// rate of the received traces (traces per second)
+	TraceRate float64 `protobuf:"fixed64,9,opt,name=trace_rate,json=traceRate,proto3" json:"trace_rate,omitempty"`
+	// total count of the received traces
+	TracesTotal uint64 `protobuf:"varint,10,opt,name=traces_total,json=tracesTotal,proto3" json:"traces_total,omitempty"`
+	// agent is connected and alive
+	Online bool `protobuf:"varint,11,opt,name=online,proto3" json:"online,omitempty"`
+}
+
+func (x *Agent) Reset() {
+	*x = Agent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracehub_messages_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Agent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Agent) ProtoMessage() {}
+
+func (x *Agent) ProtoReflect() protoreflect.Message {
+	mi := &file_tracehub_messages_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Agent.ProtoReflect.Descriptor instead.
+func (*Agent) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Agent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Agent) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Agent) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *Agent) GetKernel() string {
+	if x != nil {
+		return x.Kernel
+	}
+	return ""
+}
+
+func (x *Agent) GetRemoteAddr() string {
+	if x != nil {
+		return x.RemoteAddr
+	}
+	return ""
+}
+
+func (x *Agent) GetConnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return nil
+}
+
+func (x *Agent) GetLastSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return nil
+}
+
+func (x *Agent) GetLastTraceAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastTraceAt
+	}
+	return nil
+}
+
+func (x *Agent) GetTraceRate() float64 {
+	if x != nil {
+		return x.TraceRate
+	}
+	return 0
+}
+
+func (x *Agent) GetTracesTotal() uint64 {
+	if x != nil {
+		return x.TracesTotal
+	}
+	return 0
+}
+
+func (x *Agent) GetOnline() bool {
+	if x != nil {
+		return x.Online
+	}
+	return false
+}
+
+// AgentList: represents list of agents registered on server
+type AgentList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Agents []*Agent `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+}
+
+func (x *AgentList) Reset() {
+	*x = AgentList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracehub_messages_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AgentList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentList) ProtoMessage() {}
+
+func (x *AgentList) ProtoReflect() protoreflect.Message {
+	mi := &file_tracehub_messages_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentList.ProtoReflect.Descriptor instead.
+func (*AgentList) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AgentList) GetAgents() []*Agent {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+// AlertRule: alert fires when more than threshold traces match the query within the window
+type AlertRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// unique name of the rule
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// visor query expression traces are matched by
+	Query string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	// number of the matched traces the alert fires above
+	Threshold uint64 `protobuf:"varint,3,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// sliding window the matched traces are counted within
+	Window *durationpb.Duration `protobuf:"bytes,4,opt,name=window,proto3" json:"window,omitempty"`
+	// URL of the webhook (empty means the default one)
+	Webhook string `protobuf:"bytes,5,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	// min interval of repeated notifications of the firing alert (zero means the default one)
+	RepeatInterval *durationpb.Duration `protobuf:"bytes,6,opt,name=repeat_interval,json=repeatInterval,proto3" json:"repeat_interval,omitempty"`
+}
+
+func (x *AlertRule) Reset() {
+	*x = AlertRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracehub_messages_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlertRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRule) ProtoMessage() {}
+
+func (x *AlertRule) ProtoReflect() protoreflect.Message {
+	mi := &file_tracehub_messages_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRule.ProtoReflect.Descriptor instead.
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AlertRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AlertRule) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *AlertRule) GetThreshold() uint64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *AlertRule) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *AlertRule) GetWebhook() string {
+	if x != nil {
+		return x.Webhook
+	}
+	return ""
+}
+
+func (x *AlertRule) GetRepeatInterval() *durationpb.Duration {
+	if x != nil {
+		return x.RepeatInterval
+	}
+	return nil
+}
+
+// AlertRuleList: represents list of alert rules
+type AlertRuleList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rules []*AlertRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *AlertRuleList) Reset() {
+	*x = AlertRuleList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracehub_messages_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlertRuleList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRuleList) ProtoMessage() {}
+
+func (x *AlertRuleList) ProtoReflect() protoreflect.Message {
+	mi := &file_tracehub_messages_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRuleList.ProtoReflect.Descriptor instead.
+func (*AlertRuleList) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AlertRuleList) GetRules() []*AlertRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// DeleteAlertRulesReq: names of the alert rules to delete
+type DeleteAlertRulesReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Names []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+}
+
+func (x *DeleteAlertRulesReq) Reset() {
+	*x = DeleteAlertRulesReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracehub_messages_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteAlertRulesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlertRulesReq) ProtoMessage() {}
+
+func (x *DeleteAlertRulesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_tracehub_messages_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlertRulesReq.ProtoReflect.Descriptor instead.
+func (*DeleteAlertRulesReq) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteAlertRulesReq) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+// ListAlertsReq: query of the alerts state
+type ListAlertsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// fetch firing alerts only
+	FiringOnly bool `protobuf:"varint,1,opt,name=firing_only,json=firingOnly,proto3" json:"firing_only,omitempty"`
+}
+
+func (x *ListAlertsReq) Reset() {
+	*x = ListAlertsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracehub_messages_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAlertsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsReq) ProtoMessage() {}
+
+func (x *ListAlertsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_tracehub_messages_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsReq.ProtoReflect.Descriptor instead.
+func (*ListAlertsReq) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListAlertsReq) GetFiringOnly() bool {
+	if x != nil {
+		return x.FiringOnly
+	}
+	return false
+}
+
+// Alert: state of the alert rule
+type Alert struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// alert rule
+	Rule *AlertRule `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	// where the rule is defined: config/api
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// the alert is firing
+	Firing bool `protobuf:"varint,3,opt,name=firing,proto3" json:"firing,omitempty"`
+	// number of the traces matched within the window
+	Count uint64 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	// time the alert has fired at
+	FiringSince *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=firing_since,json=firingSince,proto3" json:"firing_since,omitempty"`
+	// time of the last notification
+	NotifiedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=notified_at,json=notifiedAt,proto3" json:"notified_at,omitempty"`
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracehub_messages_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_tracehub_messages_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Alert) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *Alert) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Alert) GetFiring() bool {
+	if x != nil {
+		return x.Firing
+	}
+	return false
+}
+
+func (x *Alert) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Alert) GetFiringSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FiringSince
+	}
+	return nil
+}
+
+func (x *Alert) GetNotifiedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotifiedAt
+	}
+	return nil
+}
+
+// AlertList: represents list of alerts
+type AlertList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Alerts []*Alert `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+}
+
+func (x *AlertList) Reset() {
+	*x = AlertList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracehub_messages_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlertList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertList) ProtoMessage() {}
+
+func (x *AlertList) ProtoReflect() protoreflect.Message {
+	mi := &file_tracehub_messages_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertList.ProtoReflect.Descriptor instead.
+func (*AlertList) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AlertList) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+type FetchNftTableQry_All struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *FetchNftTableQry_All) Reset() {
+	*x = FetchNftTableQry_All{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracehub_messages_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchNftTableQry_All) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchNftTableQry_All) ProtoMessage() {}
+
+func (x *FetchNftTableQry_All) ProtoReflect() protoreflect.Message {
+	mi := &file_tracehub_messages_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchNftTableQry_All.ProtoReflect.Descriptor instead.
+func (*FetchNftTableQry_All) Descriptor() ([]byte, []int) {
+	return file_tracehub_messages_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type FetchNftTableQry_ByTableId struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -1182,7 +2309,7 @@ type FetchNftTableQry_ByTableId struct {
 func (x *FetchNftTableQry_ByTableId) Reset() {
 	*x = FetchNftTableQry_ByTableId{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_tracehub_messages_proto_msgTypes[13]
+		mi := &file_tracehub_messages_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1195,7 +2322,7 @@ func (x *FetchNftTableQry_ByTableId) String() string {
 func (*FetchNftTableQry_ByTableId) ProtoMessage() {}
 
 func (x *FetchNftTableQry_ByTableId) ProtoReflect() protoreflect.Message {
-	mi := &file_tracehub_messages_proto_msgTypes[13]
+	mi := &file_tracehub_messages_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1222,9 +2349,11 @@ var File_tracehub_messages_proto protoreflect.FileDescriptor
 
 var file_tracehub_messages_proto_rawDesc = []byte{
 	0x0a, 0x17, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
 	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbb, 0x04, 0x0a, 0x05, 0x54,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x81, 0x0a, 0x0a, 0x05, 0x54,
 	0x72, 0x61, 0x63, 0x65, 0x12, 0x13, 0x0a, 0x05, 0x74, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x0d, 0x52, 0x04, 0x74, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x62,
 	0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x12,
@@ -1260,117 +2389,283 @@ var file_tracehub_messages_proto_rawDesc = []byte{
 	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x08, 0x73, 0x5f, 0x73, 0x67, 0x5f, 0x6e, 0x65, 0x74,
 	0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x53, 0x67, 0x4e, 0x65, 0x74, 0x12, 0x18,
 	0x0a, 0x08, 0x64, 0x5f, 0x73, 0x67, 0x5f, 0x6e, 0x65, 0x74, 0x18, 0x16, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x64, 0x53, 0x67, 0x4e, 0x65, 0x74, 0x22, 0x28, 0x0a, 0x06, 0x54, 0x72, 0x61, 0x63,
-	0x65, 0x73, 0x12, 0x1e, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x06, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x52, 0x06, 0x74, 0x72, 0x61, 0x63,
-	0x65, 0x73, 0x22, 0x7f, 0x0a, 0x0a, 0x46, 0x65, 0x74, 0x63, 0x68, 0x54, 0x72, 0x61, 0x63, 0x65,
-	0x12, 0x1c, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x06, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x12, 0x19,
-	0x0a, 0x08, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x07, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x22, 0x30, 0x0a, 0x09, 0x54, 0x72, 0x61, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74,
-	0x12, 0x23, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x0b, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x54, 0x72, 0x61, 0x63, 0x65, 0x52, 0x06, 0x74,
-	0x72, 0x61, 0x63, 0x65, 0x73, 0x22, 0x67, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e,
-	0x67, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x52, 0x06, 0x64, 0x53, 0x67, 0x4e, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x65, 0x74, 0x6e,
+	0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x65, 0x74, 0x6e, 0x73, 0x12, 0x21,
+	0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x18,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x61,
+	0x69, 0x6e, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x6f, 0x64, 0x18,
+	0x1a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x70, 0x6f, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x6f,
+	0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x1b, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x70, 0x6f, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12,
+	0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x73, 0x18, 0x1c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x61,
+	0x69, 0x6e, 0x65, 0x72, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x6f,
+	0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x73, 0x6f, 0x63, 0x6b, 0x49, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64,
+	0x18, 0x1e, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x63,
+	0x6f, 0x6d, 0x6d, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x6d, 0x6d, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x20, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x63, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x74, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x18, 0x21, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x22, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x74, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x15, 0x0a, 0x06, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x74, 0x18,
+	0x23, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x74, 0x4e, 0x61, 0x74, 0x12, 0x17, 0x0a, 0x07,
+	0x63, 0x74, 0x5f, 0x6d, 0x61, 0x72, 0x6b, 0x18, 0x24, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63,
+	0x74, 0x4d, 0x61, 0x72, 0x6b, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x74, 0x5f, 0x7a, 0x6f, 0x6e, 0x65,
+	0x18, 0x25, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x74, 0x5a, 0x6f, 0x6e, 0x65, 0x12, 0x17,
+	0x0a, 0x07, 0x63, 0x74, 0x5f, 0x6f, 0x72, 0x69, 0x67, 0x18, 0x26, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x63, 0x74, 0x4f, 0x72, 0x69, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x74, 0x5f, 0x72, 0x65,
+	0x70, 0x6c, 0x79, 0x18, 0x27, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x74, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x28, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x6f,
+	0x67, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x29, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x6c, 0x6f, 0x67, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67,
+	0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x2a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6c, 0x6f,
+	0x67, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x6f, 0x6b, 0x18, 0x2b,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x6f, 0x6b, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x67,
+	0x5f, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x2c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x67, 0x52,
+	0x75, 0x6c, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x67, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x2d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x67, 0x52,
+	0x75, 0x6c, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0d, 0x72, 0x61, 0x77,
+	0x5f, 0x6c, 0x6c, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x2e, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0b, 0x72, 0x61, 0x77, 0x4c, 0x6c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1f, 0x0a,
+	0x0b, 0x72, 0x61, 0x77, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x2f, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0a, 0x72, 0x61, 0x77, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x22, 0x28,
+	0x0a, 0x06, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x63,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65,
+	0x52, 0x06, 0x74, 0x72, 0x61, 0x63, 0x65, 0x73, 0x22, 0x9a, 0x01, 0x0a, 0x0a, 0x46, 0x65, 0x74,
+	0x63, 0x68, 0x54, 0x72, 0x61, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x52, 0x05,
+	0x74, 0x72, 0x61, 0x63, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x64,
+	0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x30, 0x0a, 0x09, 0x54, 0x72, 0x61, 0x63, 0x65, 0x4c, 0x69,
+	0x73, 0x74, 0x12, 0x23, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x54, 0x72, 0x61, 0x63, 0x65, 0x52,
+	0x06, 0x74, 0x72, 0x61, 0x63, 0x65, 0x73, 0x22, 0x67, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04,
+	0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72,
-	0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x22, 0xb6,
-	0x05, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x12, 0x13, 0x0a,
-	0x05, 0x74, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x04, 0x74, 0x72,
-	0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68, 0x61, 0x69,
-	0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x1f,
-	0x0a, 0x0b, 0x6a, 0x75, 0x6d, 0x70, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x04, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x0a, 0x6a, 0x75, 0x6d, 0x70, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12,
-	0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x05,
-	0x20, 0x03, 0x28, 0x04, 0x52, 0x0a, 0x72, 0x75, 0x6c, 0x65, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65,
-	0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x06, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x69, 0x66, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x69, 0x69, 0x66, 0x6e, 0x61,
-	0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x69, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x08, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x69, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x0a,
-	0x73, 0x5f, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x08, 0x73, 0x4d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1c, 0x0a, 0x0a, 0x64, 0x5f,
-	0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08,
-	0x64, 0x4d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x5f, 0x61, 0x64,
-	0x64, 0x72, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x73, 0x41, 0x64, 0x64, 0x72, 0x12,
-	0x15, 0x0a, 0x06, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x05, 0x64, 0x41, 0x64, 0x64, 0x72, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x5f, 0x70, 0x6f, 0x72, 0x74,
-	0x18, 0x0d, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x05, 0x73, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x15, 0x0a,
-	0x06, 0x64, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x05, 0x64,
-	0x50, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x0f,
-	0x20, 0x03, 0x28, 0x0d, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x19, 0x0a, 0x08,
-	0x69, 0x70, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x18, 0x10, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
-	0x69, 0x70, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x64, 0x69,
-	0x63, 0x74, 0x18, 0x11, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x64, 0x69, 0x63,
-	0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x12, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x04, 0x72, 0x75, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x09, 0x73, 0x5f, 0x73, 0x67, 0x5f, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x13, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x53, 0x67, 0x4e, 0x61, 0x6d,
-	0x65, 0x12, 0x1a, 0x0a, 0x09, 0x64, 0x5f, 0x73, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x14,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x53, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a,
-	0x08, 0x73, 0x5f, 0x73, 0x67, 0x5f, 0x6e, 0x65, 0x74, 0x18, 0x15, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x06, 0x73, 0x53, 0x67, 0x4e, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x08, 0x64, 0x5f, 0x73, 0x67, 0x5f,
-	0x6e, 0x65, 0x74, 0x18, 0x16, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x64, 0x53, 0x67, 0x4e, 0x65,
-	0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6d, 0x6f, 0x64, 0x65,
-	0x18, 0x17, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x4d, 0x6f,
-	0x64, 0x65, 0x12, 0x1e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0a, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x04, 0x74, 0x69,
-	0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x19, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x67, 0x65, 0x6e,
-	0x74, 0x73, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x1a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x73, 0x49, 0x64, 0x73, 0x22, 0x43, 0x0a, 0x0e, 0x4e, 0x66, 0x74, 0x52, 0x75,
-	0x6c, 0x65, 0x49, 0x6e, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61,
-	0x69, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
-	0x68, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x22, 0x90, 0x01, 0x0a,
-	0x08, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x62,
-	0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74,
-	0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61, 0x62, 0x6c,
-	0x65, 0x5f, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
-	0x74, 0x61, 0x62, 0x6c, 0x65, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x74,
-	0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x74, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x74, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x74, 0x72, 0x12, 0x25, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65,
-	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x4e, 0x66, 0x74, 0x52, 0x75, 0x6c,
-	0x65, 0x49, 0x6e, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22,
-	0x2f, 0x0a, 0x0c, 0x53, 0x79, 0x6e, 0x63, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x12,
-	0x1f, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09,
-	0x2e, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65,
-	0x22, 0xcb, 0x01, 0x0a, 0x10, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62,
-	0x6c, 0x65, 0x51, 0x72, 0x79, 0x12, 0x32, 0x0a, 0x08, 0x6e, 0x6f, 0x5f, 0x73, 0x63, 0x6f, 0x70,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4e,
-	0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x51, 0x72, 0x79, 0x2e, 0x41, 0x6c, 0x6c, 0x48, 0x00,
-	0x52, 0x07, 0x6e, 0x6f, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x12, 0x4a, 0x0a, 0x12, 0x73, 0x63, 0x6f,
-	0x70, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4e, 0x66, 0x74,
-	0x54, 0x61, 0x62, 0x6c, 0x65, 0x51, 0x72, 0x79, 0x2e, 0x42, 0x79, 0x54, 0x61, 0x62, 0x6c, 0x65,
-	0x49, 0x64, 0x48, 0x00, 0x52, 0x0f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x64, 0x42, 0x79, 0x54, 0x61,
-	0x62, 0x6c, 0x65, 0x49, 0x64, 0x1a, 0x05, 0x0a, 0x03, 0x41, 0x6c, 0x6c, 0x1a, 0x26, 0x0a, 0x09,
-	0x42, 0x79, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x61, 0x62,
-	0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x04, 0x52, 0x07, 0x74, 0x61, 0x62,
-	0x6c, 0x65, 0x49, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x64, 0x22, 0x80,
-	0x01, 0x0a, 0x0c, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x12,
-	0x19, 0x0a, 0x08, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x07, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61,
-	0x62, 0x6c, 0x65, 0x5f, 0x73, 0x74, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74,
-	0x61, 0x62, 0x6c, 0x65, 0x53, 0x74, 0x72, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x22, 0x35, 0x0a, 0x0c, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x4c, 0x69, 0x73,
-	0x74, 0x12, 0x25, 0x0a, 0x06, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x0d, 0x2e, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x52, 0x06, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x42, 0x42, 0x5a, 0x40, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x69, 0x6c, 0x64, 0x62, 0x65, 0x72, 0x72, 0x69,
-	0x65, 0x73, 0x2d, 0x74, 0x65, 0x63, 0x68, 0x2f, 0x70, 0x6b, 0x74, 0x2d, 0x74, 0x72, 0x61, 0x63,
-	0x65, 0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x74, 0x72, 0x61, 0x63, 0x65,
-	0x68, 0x75, 0x62, 0x3b, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f,
+	0x22, 0x81, 0x0a, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x12,
+	0x13, 0x0a, 0x05, 0x74, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x04,
+	0x74, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68,
+	0x61, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6a, 0x75, 0x6d, 0x70, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x6a, 0x75, 0x6d, 0x70, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x04, 0x52, 0x0a, 0x72, 0x75, 0x6c, 0x65, 0x48, 0x61, 0x6e, 0x64,
+	0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x69,
+	0x66, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x69, 0x69, 0x66,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x69, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x69, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c,
+	0x0a, 0x0a, 0x73, 0x5f, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x09, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x08, 0x73, 0x4d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1c, 0x0a, 0x0a,
+	0x64, 0x5f, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x64, 0x4d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x5f,
+	0x61, 0x64, 0x64, 0x72, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x73, 0x41, 0x64, 0x64,
+	0x72, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x0c, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x64, 0x41, 0x64, 0x64, 0x72, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x5f, 0x70, 0x6f,
+	0x72, 0x74, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x05, 0x73, 0x50, 0x6f, 0x72, 0x74, 0x12,
+	0x15, 0x0a, 0x06, 0x64, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0d, 0x52,
+	0x05, 0x64, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x18, 0x0f, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x19,
+	0x0a, 0x08, 0x69, 0x70, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x18, 0x10, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x69, 0x70, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x64, 0x69, 0x63, 0x74, 0x18, 0x11, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x64,
+	0x69, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x12, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x09, 0x73, 0x5f, 0x73, 0x67, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x13, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x53, 0x67, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x09, 0x64, 0x5f, 0x73, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x14, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x53, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x18, 0x0a, 0x08, 0x73, 0x5f, 0x73, 0x67, 0x5f, 0x6e, 0x65, 0x74, 0x18, 0x15, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x53, 0x67, 0x4e, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x08, 0x64, 0x5f, 0x73,
+	0x67, 0x5f, 0x6e, 0x65, 0x74, 0x18, 0x16, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x64, 0x53, 0x67,
+	0x4e, 0x65, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6d, 0x6f,
+	0x64, 0x65, 0x18, 0x17, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x18, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x19, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x73, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x1a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x49, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x65, 0x74,
+	0x6e, 0x73, 0x18, 0x1b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x65, 0x74, 0x6e, 0x73, 0x12,
+	0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x1c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x1d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x6f, 0x64,
+	0x18, 0x1e, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x70, 0x6f, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x70,
+	0x6f, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x1f, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0c, 0x70, 0x6f, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x18, 0x20, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x70,
+	0x69, 0x64, 0x18, 0x21, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x6f, 0x6d, 0x6d, 0x18, 0x22, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x6d,
+	0x6d, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x23, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x63, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x74, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x24, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x25, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x74, 0x44, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x15, 0x0a, 0x06, 0x63, 0x74, 0x5f, 0x6e, 0x61,
+	0x74, 0x18, 0x26, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x74, 0x4e, 0x61, 0x74, 0x12, 0x17,
+	0x0a, 0x07, 0x63, 0x74, 0x5f, 0x6d, 0x61, 0x72, 0x6b, 0x18, 0x27, 0x20, 0x03, 0x28, 0x0d, 0x52,
+	0x06, 0x63, 0x74, 0x4d, 0x61, 0x72, 0x6b, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x74, 0x5f, 0x7a, 0x6f,
+	0x6e, 0x65, 0x18, 0x28, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x74, 0x5a, 0x6f, 0x6e, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x29, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x6f, 0x67, 0x5f,
+	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x2a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x6f,
+	0x67, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x18, 0x2b, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x6f, 0x6b, 0x18, 0x2c, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x6f, 0x6b, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x67, 0x5f, 0x72,
+	0x75, 0x6c, 0x65, 0x18, 0x2d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x67, 0x52, 0x75, 0x6c,
+	0x65, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x67, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x2e, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x67, 0x52, 0x75, 0x6c,
+	0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x71, 0x75, 0x65, 0x72, 0x79,
+	0x5f, 0x64, 0x73, 0x6c, 0x18, 0x2f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x44, 0x73, 0x6c, 0x22, 0x43, 0x0a, 0x0e, 0x4e, 0x66, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49,
+	0x6e, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x69,
+	0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x22, 0xa6, 0x01, 0x0a, 0x08, 0x4e, 0x66,
+	0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x66,
+	0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x5f, 0x73, 0x74, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x53, 0x74, 0x72, 0x12, 0x25, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x4e, 0x66, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e,
+	0x43, 0x68, 0x61, 0x69, 0x6e, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x6e, 0x65, 0x74, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x65, 0x74,
+	0x6e, 0x73, 0x22, 0x2f, 0x0a, 0x0c, 0x53, 0x79, 0x6e, 0x63, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x12, 0x1f, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x09, 0x2e, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x05, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x22, 0xcb, 0x01, 0x0a, 0x10, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4e, 0x66, 0x74,
+	0x54, 0x61, 0x62, 0x6c, 0x65, 0x51, 0x72, 0x79, 0x12, 0x32, 0x0a, 0x08, 0x6e, 0x6f, 0x5f, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x46, 0x65, 0x74,
+	0x63, 0x68, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x51, 0x72, 0x79, 0x2e, 0x41, 0x6c,
+	0x6c, 0x48, 0x00, 0x52, 0x07, 0x6e, 0x6f, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x12, 0x4a, 0x0a, 0x12,
+	0x73, 0x63, 0x6f, 0x70, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68,
+	0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x51, 0x72, 0x79, 0x2e, 0x42, 0x79, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x49, 0x64, 0x48, 0x00, 0x52, 0x0f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x64, 0x42,
+	0x79, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x64, 0x1a, 0x05, 0x0a, 0x03, 0x41, 0x6c, 0x6c, 0x1a,
+	0x26, 0x0a, 0x09, 0x42, 0x79, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x04, 0x52, 0x07,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65,
+	0x64, 0x22, 0x96, 0x01, 0x0a, 0x0c, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x74, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x74, 0x72, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x65, 0x74, 0x6e, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x65, 0x74, 0x6e, 0x73, 0x22, 0x35, 0x0a, 0x0c, 0x4e, 0x66,
+	0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x06, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x4e, 0x66, 0x74,
+	0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x52, 0x06, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x73, 0x22, 0x53, 0x0a, 0x0f, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x63, 0x6f, 0x70, 0x65,
+	0x52, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x74, 0x63, 0x68,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x61, 0x74,
+	0x63, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x4f, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x73, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x73, 0x49, 0x64, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65,
+	0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x6f, 0x6e, 0x6c,
+	0x69, 0x6e, 0x65, 0x4f, 0x6e, 0x6c, 0x79, 0x22, 0x9d, 0x03, 0x0a, 0x05, 0x41, 0x67, 0x65, 0x6e,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x68,
+	0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68,
+	0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6b, 0x65, 0x72, 0x6e, 0x65,
+	0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12,
+	0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72,
+	0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x3c, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x5f, 0x61, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x41, 0x74, 0x12, 0x3e, 0x0a,
+	0x0d, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x61, 0x74, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x41, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x09, 0x74, 0x72, 0x61, 0x63, 0x65, 0x52, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x74, 0x72, 0x61, 0x63, 0x65, 0x73, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x63, 0x65, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12,
+	0x16, 0x0a, 0x06, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0x2b, 0x0a, 0x09, 0x41, 0x67, 0x65, 0x6e, 0x74,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x06, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x73, 0x22, 0xe4, 0x01, 0x0a, 0x09, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x75,
+	0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x31, 0x0a, 0x06, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x18, 0x0a,
+	0x07, 0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x12, 0x42, 0x0a, 0x0f, 0x72, 0x65, 0x70, 0x65, 0x61,
+	0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x72, 0x65, 0x70,
+	0x65, 0x61, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0x31, 0x0a, 0x0d, 0x41,
+	0x6c, 0x65, 0x72, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x05,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x41, 0x6c,
+	0x65, 0x72, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x2b,
+	0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x75, 0x6c,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x22, 0x30, 0x0a, 0x0d, 0x4c,
+	0x69, 0x73, 0x74, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x52, 0x65, 0x71, 0x12, 0x1f, 0x0a, 0x0b,
+	0x66, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x4f, 0x6e, 0x6c, 0x79, 0x22, 0xe9, 0x01,
+	0x0a, 0x05, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x12, 0x1e, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x75, 0x6c,
+	0x65, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x66, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x66, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x3d, 0x0a,
+	0x0c, 0x66, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x0b, 0x66, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x3b, 0x0a, 0x0b,
+	0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x6e,
+	0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x64, 0x41, 0x74, 0x22, 0x2b, 0x0a, 0x09, 0x41, 0x6c, 0x65,
+	0x72, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x06, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x06,
+	0x61, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x42, 0x42, 0x5a, 0x40, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x69, 0x6c, 0x64, 0x62, 0x65, 0x72, 0x72, 0x69, 0x65, 0x73,
+	0x2d, 0x74, 0x65, 0x63, 0x68, 0x2f, 0x70, 0x6b, 0x74, 0x2d, 0x74, 0x72, 0x61, 0x63, 0x65, 0x72,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75,
+	0x62, 0x3b, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -1385,7 +2680,7 @@ func file_tracehub_messages_proto_rawDescGZIP() []byte {
 	return file_tracehub_messages_proto_rawDescData
 }
 
-var file_tracehub_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_tracehub_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_tracehub_messages_proto_goTypes = []any{
 	(*Trace)(nil),                      // 0: Trace
 	(*Traces)(nil),                     // 1: Traces
@@ -1399,29 +2694,52 @@ var file_tracehub_messages_proto_goTypes = []any{
 	(*FetchNftTableQry)(nil),           // 9: FetchNftTableQry
 	(*NftTableResp)(nil),               // 10: NftTableResp
 	(*NftTableList)(nil),               // 11: NftTableList
-	(*FetchNftTableQry_All)(nil),       // 12: FetchNftTableQry.All
-	(*FetchNftTableQry_ByTableId)(nil), // 13: FetchNftTableQry.ByTableId
-	(*timestamppb.Timestamp)(nil),      // 14: google.protobuf.Timestamp
+	(*ExportTracesReq)(nil),            // 12: ExportTracesReq
+	(*ListAgentsReq)(nil),              // 13: ListAgentsReq
+	(*Agent)(nil),                      // 14: Agent
+	(*AgentList)(nil),                  // 15: AgentList
+	(*AlertRule)(nil),                  // 16: AlertRule
+	(*AlertRuleList)(nil),              // 17: AlertRuleList
+	(*DeleteAlertRulesReq)(nil),        // 18: DeleteAlertRulesReq
+	(*ListAlertsReq)(nil),              // 19: ListAlertsReq
+	(*Alert)(nil),                      // 20: Alert
+	(*AlertList)(nil),                  // 21: AlertList
+	(*FetchNftTableQry_All)(nil),       // 22: FetchNftTableQry.All
+	(*FetchNftTableQry_ByTableId)(nil), // 23: FetchNftTableQry.ByTableId
+	(*timestamppb.Timestamp)(nil),      // 24: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),        // 25: google.protobuf.Duration
 }
 var file_tracehub_messages_proto_depIdxs = []int32{
 	0,  // 0: Traces.traces:type_name -> Trace
 	0,  // 1: FetchTrace.trace:type_name -> Trace
-	14, // 2: FetchTrace.timestamp:type_name -> google.protobuf.Timestamp
+	24, // 2: FetchTrace.timestamp:type_name -> google.protobuf.Timestamp
 	2,  // 3: TraceList.traces:type_name -> FetchTrace
-	14, // 4: TimeRange.from:type_name -> google.protobuf.Timestamp
-	14, // 5: TimeRange.to:type_name -> google.protobuf.Timestamp
+	24, // 4: TimeRange.from:type_name -> google.protobuf.Timestamp
+	24, // 5: TimeRange.to:type_name -> google.protobuf.Timestamp
 	4,  // 6: TraceScope.time:type_name -> TimeRange
 	6,  // 7: NftTable.rules:type_name -> NftRuleInChain
 	7,  // 8: SyncTableReq.table:type_name -> NftTable
-	12, // 9: FetchNftTableQry.no_scope:type_name -> FetchNftTableQry.All
-	13, // 10: FetchNftTableQry.scoped_by_table_id:type_name -> FetchNftTableQry.ByTableId
-	14, // 11: NftTableResp.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 9: FetchNftTableQry.no_scope:type_name -> FetchNftTableQry.All
+	23, // 10: FetchNftTableQry.scoped_by_table_id:type_name -> FetchNftTableQry.ByTableId
+	24, // 11: NftTableResp.timestamp:type_name -> google.protobuf.Timestamp
 	10, // 12: NftTableList.tables:type_name -> NftTableResp
-	13, // [13:13] is the sub-list for method output_type
-	13, // [13:13] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	5,  // 13: ExportTracesReq.scope:type_name -> TraceScope
+	24, // 14: Agent.connected_at:type_name -> google.protobuf.Timestamp
+	24, // 15: Agent.last_seen_at:type_name -> google.protobuf.Timestamp
+	24, // 16: Agent.last_trace_at:type_name -> google.protobuf.Timestamp
+	14, // 17: AgentList.agents:type_name -> Agent
+	25, // 18: AlertRule.window:type_name -> google.protobuf.Duration
+	25, // 19: AlertRule.repeat_interval:type_name -> google.protobuf.Duration
+	16, // 20: AlertRuleList.rules:type_name -> AlertRule
+	16, // 21: Alert.rule:type_name -> AlertRule
+	24, // 22: Alert.firing_since:type_name -> google.protobuf.Timestamp
+	24, // 23: Alert.notified_at:type_name -> google.protobuf.Timestamp
+	20, // 24: AlertList.alerts:type_name -> Alert
+	25, // [25:25] is the sub-list for method output_type
+	25, // [25:25] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_tracehub_messages_proto_init() }
@@ -1575,7 +2893,7 @@ func file_tracehub_messages_proto_init() {
 			}
 		}
 		file_tracehub_messages_proto_msgTypes[12].Exporter = func(v any, i int) any {
-			switch v := v.(*FetchNftTableQry_All); i {
+			switch v := v.(*ExportTracesReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1587,6 +2905,126 @@ func file_tracehub_messages_proto_init() {
 			}
 		}
 		file_tracehub_messages_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*ListAgentsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracehub_messages_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*Agent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracehub_messages_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*AgentList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracehub_messages_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*AlertRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracehub_messages_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*AlertRuleList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracehub_messages_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteAlertRulesReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracehub_messages_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*ListAlertsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracehub_messages_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*Alert); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracehub_messages_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*AlertList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracehub_messages_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*FetchNftTableQry_All); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracehub_messages_proto_msgTypes[23].Exporter = func(v any, i int) any {
 			switch v := v.(*FetchNftTableQry_ByTableId); i {
 			case 0:
 				return &v.state
@@ -1609,7 +3047,7 @@ func file_tracehub_messages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_tracehub_messages_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
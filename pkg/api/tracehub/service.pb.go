@@ -7,6 +7,8 @@
 package tracehub
 
 import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
@@ -25,54 +27,118 @@ var File_tracehub_service_proto protoreflect.FileDescriptor
 var file_tracehub_service_proto_rawDesc = []byte{
 	0x0a, 0x16, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69,
 	0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x68, 0x62, 0x66, 0x2e, 0x76, 0x31,
-	0x2e, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62,
-	0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32,
-	0xda, 0x01, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x63, 0x65, 0x48, 0x75, 0x62, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x0b, 0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x12, 0x07, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x2e, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e,
+	0x2d, 0x6f, 0x70, 0x65, 0x6e, 0x61, 0x70, 0x69, 0x76, 0x32, 0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62, 0x2f, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xb6, 0x05,
+	0x0a, 0x0f, 0x54, 0x72, 0x61, 0x63, 0x65, 0x48, 0x75, 0x62, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x30, 0x0a, 0x0b, 0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x12, 0x07, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x28, 0x01, 0x12, 0x45, 0x0a, 0x0b, 0x46, 0x65, 0x74, 0x63, 0x68, 0x54, 0x72, 0x61, 0x63,
+	0x65, 0x73, 0x12, 0x0b, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x1a,
+	0x0a, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x1b, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x15, 0x3a, 0x01, 0x2a, 0x22, 0x10, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x72, 0x61, 0x63,
+	0x65, 0x73, 0x2f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x30, 0x01, 0x12, 0x4c, 0x0a, 0x0c, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x12, 0x10, 0x2e, 0x45, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x0a, 0x2e, 0x54,
+	0x72, 0x61, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16,
+	0x3a, 0x01, 0x2a, 0x22, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x72, 0x61, 0x63, 0x65, 0x73, 0x2f,
+	0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x30, 0x01, 0x12, 0x38, 0x0a, 0x0d, 0x53, 0x79, 0x6e, 0x63,
+	0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x0d, 0x2e, 0x53, 0x79, 0x6e, 0x63,
+	0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x28, 0x01, 0x12, 0x52, 0x0a, 0x0d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4e, 0x66, 0x74, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x12, 0x11, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4e, 0x66, 0x74, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x51, 0x72, 0x79, 0x1a, 0x0d, 0x2e, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c,
+	0x65, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x3a, 0x01, 0x2a,
+	0x22, 0x14, 0x2f, 0x76, 0x31, 0x2f, 0x6e, 0x66, 0x74, 0x2d, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73,
+	0x2f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x12, 0x44, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x0e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x1a, 0x0a, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74,
+	0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x3a, 0x01, 0x2a, 0x22, 0x0f, 0x2f, 0x76, 0x31,
+	0x2f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x2f, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x44, 0x0a, 0x0a,
+	0x4c, 0x69, 0x73, 0x74, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x12, 0x0e, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x0a, 0x2e, 0x41, 0x6c, 0x65,
+	0x72, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x3a, 0x01,
+	0x2a, 0x22, 0x0f, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x2f, 0x6c, 0x69,
+	0x73, 0x74, 0x12, 0x5d, 0x0a, 0x10, 0x55, 0x70, 0x73, 0x65, 0x72, 0x74, 0x41, 0x6c, 0x65, 0x72,
+	0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x0e, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x75,
+	0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x21,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x3a, 0x01, 0x2a, 0x22, 0x16, 0x2f, 0x76, 0x31, 0x2f, 0x61,
+	0x6c, 0x65, 0x72, 0x74, 0x2d, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x2f, 0x75, 0x70, 0x73, 0x65, 0x72,
+	0x74, 0x12, 0x63, 0x0a, 0x10, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x6c, 0x65, 0x72, 0x74,
+	0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x14, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x6c,
+	0x65, 0x72, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
 	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x28, 0x01, 0x12, 0x28, 0x0a, 0x0b, 0x46, 0x65, 0x74, 0x63, 0x68, 0x54, 0x72,
-	0x61, 0x63, 0x65, 0x73, 0x12, 0x0b, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x63, 0x6f, 0x70,
-	0x65, 0x1a, 0x0a, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x30, 0x01, 0x12,
-	0x38, 0x0a, 0x0d, 0x53, 0x79, 0x6e, 0x63, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73,
-	0x12, 0x0d, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x1a,
-	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x28, 0x01, 0x12, 0x31, 0x0a, 0x0d, 0x46, 0x65, 0x74,
-	0x63, 0x68, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x11, 0x2e, 0x46, 0x65, 0x74,
-	0x63, 0x68, 0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x51, 0x72, 0x79, 0x1a, 0x0d, 0x2e,
-	0x4e, 0x66, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x42, 0x5a, 0x40,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x69, 0x6c, 0x64, 0x62,
-	0x65, 0x72, 0x72, 0x69, 0x65, 0x73, 0x2d, 0x74, 0x65, 0x63, 0x68, 0x2f, 0x70, 0x6b, 0x74, 0x2d,
-	0x74, 0x72, 0x61, 0x63, 0x65, 0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x74,
-	0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62, 0x3b, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x74, 0x79, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x3a, 0x01, 0x2a, 0x22, 0x16,
+	0x2f, 0x76, 0x31, 0x2f, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2d, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x2f,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x88, 0x02, 0x92, 0x41, 0xc2, 0x01, 0x12, 0xbf, 0x01,
+	0x0a, 0x0f, 0x54, 0x72, 0x61, 0x63, 0x65, 0x48, 0x75, 0x62, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0xa6, 0x01, 0x48, 0x54, 0x54, 0x50, 0x2f, 0x4a, 0x53, 0x4f, 0x4e, 0x20, 0x67, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x20, 0x74, 0x6f, 0x20, 0x74, 0x72, 0x61, 0x63, 0x65, 0x2d, 0x68,
+	0x75, 0x62, 0x2e, 0x20, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x20, 0x72, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x20, 0x61, 0x72, 0x65, 0x20, 0x6e, 0x65, 0x77, 0x6c,
+	0x69, 0x6e, 0x65, 0x20, 0x64, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x64, 0x20, 0x4a, 0x53,
+	0x4f, 0x4e, 0x20, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x2c, 0x20, 0x6f, 0x72, 0x20, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2d, 0x73, 0x65, 0x6e, 0x74, 0x20, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x20, 0x77, 0x68, 0x65, 0x6e, 0x20, 0x74, 0x68, 0x65, 0x20, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x20, 0x68, 0x61, 0x73, 0x20, 0x27, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x3a, 0x20,
+	0x74, 0x65, 0x78, 0x74, 0x2f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2d, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x27, 0x20, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x2e, 0x32, 0x03, 0x31, 0x2e, 0x30, 0x5a,
+	0x40, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x69, 0x6c, 0x64,
+	0x62, 0x65, 0x72, 0x72, 0x69, 0x65, 0x73, 0x2d, 0x74, 0x65, 0x63, 0x68, 0x2f, 0x70, 0x6b, 0x74,
+	0x2d, 0x74, 0x72, 0x61, 0x63, 0x65, 0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75, 0x62, 0x3b, 0x74, 0x72, 0x61, 0x63, 0x65, 0x68, 0x75,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_tracehub_service_proto_goTypes = []any{
-	(*Traces)(nil),           // 0: Traces
-	(*TraceScope)(nil),       // 1: TraceScope
-	(*SyncTableReq)(nil),     // 2: SyncTableReq
-	(*FetchNftTableQry)(nil), // 3: FetchNftTableQry
-	(*emptypb.Empty)(nil),    // 4: google.protobuf.Empty
-	(*TraceList)(nil),        // 5: TraceList
-	(*NftTableList)(nil),     // 6: NftTableList
+	(*Traces)(nil),              // 0: Traces
+	(*TraceScope)(nil),          // 1: TraceScope
+	(*ExportTracesReq)(nil),     // 2: ExportTracesReq
+	(*SyncTableReq)(nil),        // 3: SyncTableReq
+	(*FetchNftTableQry)(nil),    // 4: FetchNftTableQry
+	(*ListAgentsReq)(nil),       // 5: ListAgentsReq
+	(*ListAlertsReq)(nil),       // 6: ListAlertsReq
+	(*AlertRuleList)(nil),       // 7: AlertRuleList
+	(*DeleteAlertRulesReq)(nil), // 8: DeleteAlertRulesReq
+	(*emptypb.Empty)(nil),       // 9: google.protobuf.Empty
+	(*TraceList)(nil),           // 10: TraceList
+	(*NftTableList)(nil),        // 11: NftTableList
+	(*AgentList)(nil),           // 12: AgentList
+	(*AlertList)(nil),           // 13: AlertList
 }
 var file_tracehub_service_proto_depIdxs = []int32{
-	0, // 0: hbf.v1.tracehub.TraceHubService.TraceStream:input_type -> Traces
-	1, // 1: hbf.v1.tracehub.TraceHubService.FetchTraces:input_type -> TraceScope
-	2, // 2: hbf.v1.tracehub.TraceHubService.SyncNftTables:input_type -> SyncTableReq
-	3, // 3: hbf.v1.tracehub.TraceHubService.FetchNftTable:input_type -> FetchNftTableQry
-	4, // 4: hbf.v1.tracehub.TraceHubService.TraceStream:output_type -> google.protobuf.Empty
-	5, // 5: hbf.v1.tracehub.TraceHubService.FetchTraces:output_type -> TraceList
-	4, // 6: hbf.v1.tracehub.TraceHubService.SyncNftTables:output_type -> google.protobuf.Empty
-	6, // 7: hbf.v1.tracehub.TraceHubService.FetchNftTable:output_type -> NftTableList
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: hbf.v1.tracehub.TraceHubService.TraceStream:input_type -> Traces
+	1,  // 1: hbf.v1.tracehub.TraceHubService.FetchTraces:input_type -> TraceScope
+	2,  // 2: hbf.v1.tracehub.TraceHubService.ExportTraces:input_type -> ExportTracesReq
+	3,  // 3: hbf.v1.tracehub.TraceHubService.SyncNftTables:input_type -> SyncTableReq
+	4,  // 4: hbf.v1.tracehub.TraceHubService.FetchNftTable:input_type -> FetchNftTableQry
+	5,  // 5: hbf.v1.tracehub.TraceHubService.ListAgents:input_type -> ListAgentsReq
+	6,  // 6: hbf.v1.tracehub.TraceHubService.ListAlerts:input_type -> ListAlertsReq
+	7,  // 7: hbf.v1.tracehub.TraceHubService.UpsertAlertRules:input_type -> AlertRuleList
+	8,  // 8: hbf.v1.tracehub.TraceHubService.DeleteAlertRules:input_type -> DeleteAlertRulesReq
+	9,  // 9: hbf.v1.tracehub.TraceHubService.TraceStream:output_type -> google.protobuf.Empty
+	10, // 10: hbf.v1.tracehub.TraceHubService.FetchTraces:output_type -> TraceList
+	10, // 11: hbf.v1.tracehub.TraceHubService.ExportTraces:output_type -> TraceList
+	9,  // 12: hbf.v1.tracehub.TraceHubService.SyncNftTables:output_type -> google.protobuf.Empty
+	11, // 13: hbf.v1.tracehub.TraceHubService.FetchNftTable:output_type -> NftTableList
+	12, // 14: hbf.v1.tracehub.TraceHubService.ListAgents:output_type -> AgentList
+	13, // 15: hbf.v1.tracehub.TraceHubService.ListAlerts:output_type -> AlertList
+	9,  // 16: hbf.v1.tracehub.TraceHubService.UpsertAlertRules:output_type -> google.protobuf.Empty
+	9,  // 17: hbf.v1.tracehub.TraceHubService.DeleteAlertRules:output_type -> google.protobuf.Empty
+	9,  // [9:18] is the sub-list for method output_type
+	0,  // [0:9] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_tracehub_service_proto_init() }
@@ -20,10 +20,15 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	TraceHubService_TraceStream_FullMethodName   = "/hbf.v1.tracehub.TraceHubService/TraceStream"
-	TraceHubService_FetchTraces_FullMethodName   = "/hbf.v1.tracehub.TraceHubService/FetchTraces"
-	TraceHubService_SyncNftTables_FullMethodName = "/hbf.v1.tracehub.TraceHubService/SyncNftTables"
-	TraceHubService_FetchNftTable_FullMethodName = "/hbf.v1.tracehub.TraceHubService/FetchNftTable"
+	TraceHubService_TraceStream_FullMethodName      = "/hbf.v1.tracehub.TraceHubService/TraceStream"
+	TraceHubService_FetchTraces_FullMethodName      = "/hbf.v1.tracehub.TraceHubService/FetchTraces"
+	TraceHubService_ExportTraces_FullMethodName     = "/hbf.v1.tracehub.TraceHubService/ExportTraces"
+	TraceHubService_SyncNftTables_FullMethodName    = "/hbf.v1.tracehub.TraceHubService/SyncNftTables"
+	TraceHubService_FetchNftTable_FullMethodName    = "/hbf.v1.tracehub.TraceHubService/FetchNftTable"
+	TraceHubService_ListAgents_FullMethodName       = "/hbf.v1.tracehub.TraceHubService/ListAgents"
+	TraceHubService_ListAlerts_FullMethodName       = "/hbf.v1.tracehub.TraceHubService/ListAlerts"
+	TraceHubService_UpsertAlertRules_FullMethodName = "/hbf.v1.tracehub.TraceHubService/UpsertAlertRules"
+	TraceHubService_DeleteAlertRules_FullMethodName = "/hbf.v1.tracehub.TraceHubService/DeleteAlertRules"
 )
 
 // TraceHubServiceClient is the client API for TraceHubService service.
@@ -32,8 +37,13 @@ const (
 type TraceHubServiceClient interface {
 	TraceStream(ctx context.Context, opts ...grpc.CallOption) (TraceHubService_TraceStreamClient, error)
 	FetchTraces(ctx context.Context, in *TraceScope, opts ...grpc.CallOption) (TraceHubService_FetchTracesClient, error)
+	ExportTraces(ctx context.Context, in *ExportTracesReq, opts ...grpc.CallOption) (TraceHubService_ExportTracesClient, error)
 	SyncNftTables(ctx context.Context, opts ...grpc.CallOption) (TraceHubService_SyncNftTablesClient, error)
 	FetchNftTable(ctx context.Context, in *FetchNftTableQry, opts ...grpc.CallOption) (*NftTableList, error)
+	ListAgents(ctx context.Context, in *ListAgentsReq, opts ...grpc.CallOption) (*AgentList, error)
+	ListAlerts(ctx context.Context, in *ListAlertsReq, opts ...grpc.CallOption) (*AlertList, error)
+	UpsertAlertRules(ctx context.Context, in *AlertRuleList, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteAlertRules(ctx context.Context, in *DeleteAlertRulesReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type traceHubServiceClient struct {
@@ -110,8 +120,40 @@ func (x *traceHubServiceFetchTracesClient) Recv() (*TraceList, error) {
 	return m, nil
 }
 
+func (c *traceHubServiceClient) ExportTraces(ctx context.Context, in *ExportTracesReq, opts ...grpc.CallOption) (TraceHubService_ExportTracesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TraceHubService_ServiceDesc.Streams[2], TraceHubService_ExportTraces_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &traceHubServiceExportTracesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TraceHubService_ExportTracesClient interface {
+	Recv() (*TraceList, error)
+	grpc.ClientStream
+}
+
+type traceHubServiceExportTracesClient struct {
+	grpc.ClientStream
+}
+
+func (x *traceHubServiceExportTracesClient) Recv() (*TraceList, error) {
+	m := new(TraceList)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *traceHubServiceClient) SyncNftTables(ctx context.Context, opts ...grpc.CallOption) (TraceHubService_SyncNftTablesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &TraceHubService_ServiceDesc.Streams[2], TraceHubService_SyncNftTables_FullMethodName, opts...)
+	stream, err := c.cc.NewStream(ctx, &TraceHubService_ServiceDesc.Streams[3], TraceHubService_SyncNftTables_FullMethodName, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -153,14 +195,55 @@ func (c *traceHubServiceClient) FetchNftTable(ctx context.Context, in *FetchNftT
 	return out, nil
 }
 
+func (c *traceHubServiceClient) ListAgents(ctx context.Context, in *ListAgentsReq, opts ...grpc.CallOption) (*AgentList, error) {
+	out := new(AgentList)
+	err := c.cc.Invoke(ctx, TraceHubService_ListAgents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *traceHubServiceClient) ListAlerts(ctx context.Context, in *ListAlertsReq, opts ...grpc.CallOption) (*AlertList, error) {
+	out := new(AlertList)
+	err := c.cc.Invoke(ctx, TraceHubService_ListAlerts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *traceHubServiceClient) UpsertAlertRules(ctx context.Context, in *AlertRuleList, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TraceHubService_UpsertAlertRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *traceHubServiceClient) DeleteAlertRules(ctx context.Context, in *DeleteAlertRulesReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TraceHubService_DeleteAlertRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TraceHubServiceServer is the server API for TraceHubService service.
 // All implementations must embed UnimplementedTraceHubServiceServer
 // for forward compatibility
 type TraceHubServiceServer interface {
 	TraceStream(TraceHubService_TraceStreamServer) error
 	FetchTraces(*TraceScope, TraceHubService_FetchTracesServer) error
+	ExportTraces(*ExportTracesReq, TraceHubService_ExportTracesServer) error
 	SyncNftTables(TraceHubService_SyncNftTablesServer) error
 	FetchNftTable(context.Context, *FetchNftTableQry) (*NftTableList, error)
+	ListAgents(context.Context, *ListAgentsReq) (*AgentList, error)
+	ListAlerts(context.Context, *ListAlertsReq) (*AlertList, error)
+	UpsertAlertRules(context.Context, *AlertRuleList) (*emptypb.Empty, error)
+	DeleteAlertRules(context.Context, *DeleteAlertRulesReq) (*emptypb.Empty, error)
 	mustEmbedUnimplementedTraceHubServiceServer()
 }
 
@@ -174,12 +257,27 @@ func (UnimplementedTraceHubServiceServer) TraceStream(TraceHubService_TraceStrea
 func (UnimplementedTraceHubServiceServer) FetchTraces(*TraceScope, TraceHubService_FetchTracesServer) error {
 	return status.Errorf(codes.Unimplemented, "method FetchTraces not implemented")
 }
+func (UnimplementedTraceHubServiceServer) ExportTraces(*ExportTracesReq, TraceHubService_ExportTracesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportTraces not implemented")
+}
 func (UnimplementedTraceHubServiceServer) SyncNftTables(TraceHubService_SyncNftTablesServer) error {
 	return status.Errorf(codes.Unimplemented, "method SyncNftTables not implemented")
 }
 func (UnimplementedTraceHubServiceServer) FetchNftTable(context.Context, *FetchNftTableQry) (*NftTableList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchNftTable not implemented")
 }
+func (UnimplementedTraceHubServiceServer) ListAgents(context.Context, *ListAgentsReq) (*AgentList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAgents not implemented")
+}
+func (UnimplementedTraceHubServiceServer) ListAlerts(context.Context, *ListAlertsReq) (*AlertList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlerts not implemented")
+}
+func (UnimplementedTraceHubServiceServer) UpsertAlertRules(context.Context, *AlertRuleList) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertAlertRules not implemented")
+}
+func (UnimplementedTraceHubServiceServer) DeleteAlertRules(context.Context, *DeleteAlertRulesReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlertRules not implemented")
+}
 func (UnimplementedTraceHubServiceServer) mustEmbedUnimplementedTraceHubServiceServer() {}
 
 // UnsafeTraceHubServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -240,6 +338,27 @@ func (x *traceHubServiceFetchTracesServer) Send(m *TraceList) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _TraceHubService_ExportTraces_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportTracesReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TraceHubServiceServer).ExportTraces(m, &traceHubServiceExportTracesServer{stream})
+}
+
+type TraceHubService_ExportTracesServer interface {
+	Send(*TraceList) error
+	grpc.ServerStream
+}
+
+type traceHubServiceExportTracesServer struct {
+	grpc.ServerStream
+}
+
+func (x *traceHubServiceExportTracesServer) Send(m *TraceList) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _TraceHubService_SyncNftTables_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(TraceHubServiceServer).SyncNftTables(&traceHubServiceSyncNftTablesServer{stream})
 }
@@ -284,6 +403,78 @@ func _TraceHubService_FetchNftTable_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TraceHubService_ListAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TraceHubServiceServer).ListAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TraceHubService_ListAgents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TraceHubServiceServer).ListAgents(ctx, req.(*ListAgentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TraceHubService_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TraceHubServiceServer).ListAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TraceHubService_ListAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TraceHubServiceServer).ListAlerts(ctx, req.(*ListAlertsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TraceHubService_UpsertAlertRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRuleList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TraceHubServiceServer).UpsertAlertRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TraceHubService_UpsertAlertRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TraceHubServiceServer).UpsertAlertRules(ctx, req.(*AlertRuleList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TraceHubService_DeleteAlertRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAlertRulesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TraceHubServiceServer).DeleteAlertRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TraceHubService_DeleteAlertRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TraceHubServiceServer).DeleteAlertRules(ctx, req.(*DeleteAlertRulesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TraceHubService_ServiceDesc is the grpc.ServiceDesc for TraceHubService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -295,6 +486,22 @@ var TraceHubService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FetchNftTable",
 			Handler:    _TraceHubService_FetchNftTable_Handler,
 		},
+		{
+			MethodName: "ListAgents",
+			Handler:    _TraceHubService_ListAgents_Handler,
+		},
+		{
+			MethodName: "ListAlerts",
+			Handler:    _TraceHubService_ListAlerts_Handler,
+		},
+		{
+			MethodName: "UpsertAlertRules",
+			Handler:    _TraceHubService_UpsertAlertRules_Handler,
+		},
+		{
+			MethodName: "DeleteAlertRules",
+			Handler:    _TraceHubService_DeleteAlertRules_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -307,6 +514,11 @@ var TraceHubService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _TraceHubService_FetchTraces_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ExportTraces",
+			Handler:       _TraceHubService_ExportTraces_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SyncNftTables",
 			Handler:       _TraceHubService_SyncNftTables_Handler,
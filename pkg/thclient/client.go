@@ -0,0 +1,72 @@
+// Package thclient is the Go client of trace-hub service.
+//
+// Traces are filtered by the Scope builder and read by the TraceIterator, which fetches
+// them page by page with Client.Traces or follows the new ones with Client.Subscribe:
+//
+//	c, err := thclient.New(ctx, "tcp://127.0.0.1:9000", thclient.WithToken(token))
+//	...
+//	defer c.Close()
+//	it := c.Subscribe(ctx, thclient.NewScope().Verdicts("rule::drop").Query(`dport == 443`), time.Time{})
+//	defer it.Close()
+//	for it.Next() {
+//		fmt.Println(it.Trace())
+//	}
+//	err = it.Err()
+package thclient
+
+import (
+	"context"
+
+	th "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	grpcClient "github.com/H-BF/corlib/client/grpc"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// Client trace-hub service client, the raw API is available through the embedded TraceHubServiceClient
+type Client struct {
+	th.TraceHubServiceClient
+	opts   options
+	closer func() error
+}
+
+// New connects to trace-hub at address 'tcp://host:port' or 'unix:///path'
+func New(ctx context.Context, addr string, opts ...Option) (*Client, error) {
+	const api = "thclient/new"
+
+	o := defOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c, err := dial(ctx, addr, o)
+	if err != nil {
+		return nil, errors.WithMessage(err, api)
+	}
+	return &Client{
+		TraceHubServiceClient: th.NewTraceHubServiceClient(c),
+		opts:                  o,
+		closer:                c.CloseConn,
+	}, nil
+}
+
+// NewFromConn makes client over the connection owned by the caller,
+// connection options (TLS, compression, auth) are not applied
+func NewFromConn(c grpc.ClientConnInterface, opts ...Option) *Client {
+	o := defOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Client{
+		TraceHubServiceClient: th.NewTraceHubServiceClient(grpcClient.WithErrorWrapper(c, "tracehub")),
+		opts:                  o,
+	}
+}
+
+// Close closes connection made by New
+func (c *Client) Close() error {
+	if c.closer == nil {
+		return nil
+	}
+	return c.closer()
+}
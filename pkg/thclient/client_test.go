@@ -0,0 +1,235 @@
+package thclient
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	th "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"github.com/H-BF/corlib/pkg/backoff"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type (
+	clientTestSuite struct {
+		suite.Suite
+		srv    *thServerMock
+		client *Client
+		stop   func()
+	}
+
+	thServerMock struct {
+		th.UnimplementedTraceHubServiceServer
+		sync.Mutex
+		export  func(call int, req *th.ExportTracesReq, stream th.TraceHubService_ExportTracesServer) error
+		fetch   func(call int, req *th.TraceScope, stream th.TraceHubService_FetchTracesServer) error
+		calls   int
+		scopes  []*th.TraceScope
+		authHdr []string
+	}
+)
+
+func Test_Client(t *testing.T) {
+	suite.Run(t, new(clientTestSuite))
+}
+
+func (sui *clientTestSuite) SetupTest() {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	sui.Require().NoError(err)
+	sui.srv = new(thServerMock)
+	s := grpc.NewServer()
+	th.RegisterTraceHubServiceServer(s, sui.srv)
+	go s.Serve(l) //nolint:errcheck
+	sui.client, err = New(context.Background(), "tcp://"+l.Addr().String(),
+		WithToken("secret"),
+		WithCompression(CompressionGzip),
+		WithPageSize(2),
+		WithReconnect(func() backoff.Backoff {
+			return backoff.NewConstantBackOff(time.Millisecond)
+		}))
+	sui.Require().NoError(err)
+	sui.stop = func() {
+		_ = sui.client.Close()
+		s.Stop()
+	}
+}
+
+func (sui *clientTestSuite) TearDownTest() {
+	sui.stop()
+}
+
+var t0 = time.Date(2024, 12, 2, 12, 0, 0, 0, time.UTC)
+
+func mkTrace(id uint32, sec int) *th.FetchTrace {
+	return &th.FetchTrace{
+		Trace:     &th.Trace{TrId: id},
+		AgentId:   "agent1",
+		Timestamp: timestamppb.New(t0.Add(time.Duration(sec) * time.Second)),
+	}
+}
+
+func (sui *clientTestSuite) collect(it *TraceIterator, n int) (ret []uint32) {
+	for len(ret) < n && it.Next() {
+		ret = append(ret, it.Trace().GetTrace().GetTrId())
+	}
+	return ret
+}
+
+func (sui *clientTestSuite) Test_Scope() {
+	scope, err := NewScope().Verdicts("rule::drop").DPorts(443).Agents("agent1").Query(`sport > 1024`).Proto()
+	sui.Require().NoError(err)
+	sui.Require().Equal([]string{"rule::drop"}, scope.GetVerdict())
+	sui.Require().Equal([]uint32{443}, scope.GetDPort())
+	sui.Require().Equal([]string{"agent1"}, scope.GetAgentsIds())
+	sui.Require().NotEmpty(scope.GetQuery())
+	sui.Require().Equal(`sport > 1024`, scope.GetQueryDsl())
+
+	_, err = NewScope().Query(`sport >`).Proto()
+	sui.Require().Error(err)
+}
+
+func (sui *clientTestSuite) Test_Traces() {
+	sui.srv.export = func(call int, req *th.ExportTracesReq, stream th.TraceHubService_ExportTracesServer) error {
+		sui.Require().Equal(uint32(2), req.GetBatchSize())
+		if call == 0 {
+			_ = stream.Send(&th.TraceList{Traces: []*th.FetchTrace{mkTrace(1, 0), mkTrace(2, 1)}})
+			return status.Error(codes.Unavailable, "db is down")
+		}
+		// resumed from the time of the last trace
+		sui.Require().Equal(t0.Add(time.Second), req.GetScope().GetTime().GetFrom().AsTime())
+		_ = stream.Send(&th.TraceList{Traces: []*th.FetchTrace{mkTrace(2, 1), mkTrace(3, 1)}})
+		return stream.Send(&th.TraceList{Traces: []*th.FetchTrace{mkTrace(4, 2)}})
+	}
+	it := sui.client.Traces(context.Background(), NewScope().TimeRange(t0, t0.Add(time.Hour)))
+	defer it.Close()
+	sui.Require().Equal([]uint32{1, 2, 3, 4}, sui.collect(it, 10))
+	sui.Require().NoError(it.Err())
+	sui.Require().Equal(2, sui.srv.calls)
+	sui.Require().Equal([]string{"Bearer secret"}, sui.srv.authHdr)
+
+	sui.srv.export = func(int, *th.ExportTracesReq, th.TraceHubService_ExportTracesServer) error {
+		return status.Error(codes.InvalidArgument, "bad scope")
+	}
+	it = sui.client.Traces(context.Background(), NewScope())
+	sui.Require().False(it.Next())
+	sui.Require().Equal(codes.InvalidArgument, status.Code(it.Err()))
+}
+
+func (sui *clientTestSuite) Test_TracesSameIdAndTime() {
+	withRule := func(tr *th.FetchTrace, chain string, handle uint64, verdict string) *th.FetchTrace {
+		tr.Trace.Table, tr.Trace.Chain, tr.Trace.RuleHandle, tr.Trace.Verdict = "filter", chain, handle, verdict
+		return tr
+	}
+	inNs := func(tr *th.FetchTrace, netns, source string) *th.FetchTrace {
+		tr.Trace.Netns, tr.Trace.Source = netns, source
+		return tr
+	}
+	// one packet passes several rules and namespaces within the same second, so traces share id and time
+	sent := []*th.FetchTrace{
+		withRule(mkTrace(1, 0), "INPUT", 1, "rule::continue"),
+		withRule(mkTrace(1, 0), "FORWARD", 2, "rule::continue"),
+		inNs(withRule(mkTrace(1, 0), "FORWARD", 2, "rule::continue"), "ino:4026532281", "nftrace"),
+		inNs(withRule(mkTrace(1, 0), "FORWARD", 2, "rule::continue"), "", "nflog"),
+		withRule(mkTrace(1, 0), "INPUT", 1, "rule::continue"), // the same row is stored twice
+	}
+	sui.srv.export = func(call int, _ *th.ExportTracesReq, stream th.TraceHubService_ExportTracesServer) error {
+		if call == 0 {
+			_ = stream.Send(&th.TraceList{Traces: sent})
+			return status.Error(codes.Unavailable, "db is down")
+		}
+		return stream.Send(&th.TraceList{Traces: append([]*th.FetchTrace{
+			withRule(mkTrace(2, -1), "INPUT", 1, "rule::accept"), // before the resume time
+		}, append(sent, withRule(mkTrace(1, 0), "FORWARD", 3, "rule::drop"))...)})
+	}
+	it := sui.client.Traces(context.Background(), NewScope().TimeRange(t0, t0.Add(time.Hour)))
+	defer it.Close()
+	var received []*th.FetchTrace
+	for it.Next() {
+		received = append(received, it.Trace())
+	}
+	sui.Require().NoError(it.Err())
+	sui.Require().Len(received, len(sent)+1)
+	for i, tr := range sent {
+		sui.Require().True(proto.Equal(tr, received[i]), i)
+	}
+	sui.Require().Equal(uint64(3), received[len(sent)].GetTrace().GetRuleHandle())
+}
+
+func (sui *clientTestSuite) Test_Subscribe() {
+	from := t0.Add(-time.Minute)
+	sui.srv.fetch = func(call int, req *th.TraceScope, stream th.TraceHubService_FetchTracesServer) error {
+		sui.Require().True(req.GetFollowMode())
+		if call == 0 {
+			sui.Require().Equal(from, req.GetTime().GetFrom().AsTime())
+			_ = stream.Send(&th.TraceList{Traces: []*th.FetchTrace{mkTrace(1, 0)}})
+			return stream.Send(&th.TraceList{}) // server is restarted
+		}
+		sui.Require().Equal(t0, req.GetTime().GetFrom().AsTime())
+		_ = stream.Send(&th.TraceList{Traces: []*th.FetchTrace{mkTrace(1, 0)}})
+		_ = stream.Send(&th.TraceList{Traces: []*th.FetchTrace{mkTrace(2, 1)}})
+		<-stream.Context().Done()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	it := sui.client.Subscribe(ctx, NewScope().Chains("INPUT"), from)
+	sui.Require().Equal([]uint32{1, 2}, sui.collect(it, 2))
+	cancel()
+	sui.Require().False(it.Next())
+	sui.Require().ErrorIs(it.Err(), context.Canceled)
+	sui.Require().Equal([]string{"INPUT"}, sui.srv.scopes[1].GetChain())
+}
+
+func (sui *clientTestSuite) Test_Rulesets() {
+	tables, err := sui.client.Rulesets(context.Background(), 7)
+	sui.Require().NoError(err)
+	sui.Require().Len(tables, 1)
+
+	tr := mkTrace(1, 0)
+	tr.TableId = 7
+	table, err := sui.client.RulesetOf(context.Background(), tr)
+	sui.Require().NoError(err)
+	sui.Require().Equal("table inet filter {}", table.GetTableStr())
+
+	tr.TableId = 8
+	_, err = sui.client.RulesetOf(context.Background(), tr)
+	sui.Require().ErrorIs(err, ErrRulesetNotFound)
+}
+
+func (m *thServerMock) call(ctx context.Context) int {
+	m.Lock()
+	defer m.Unlock()
+	md, _ := metadata.FromIncomingContext(ctx)
+	m.authHdr = md.Get("authorization")
+	m.calls++
+	return m.calls - 1
+}
+
+func (m *thServerMock) ExportTraces(req *th.ExportTracesReq, stream th.TraceHubService_ExportTracesServer) error {
+	return m.export(m.call(stream.Context()), req, stream)
+}
+
+func (m *thServerMock) FetchTraces(req *th.TraceScope, stream th.TraceHubService_FetchTracesServer) error {
+	m.Lock()
+	m.scopes = append(m.scopes, req)
+	m.Unlock()
+	return m.fetch(m.call(stream.Context()), req, stream)
+}
+
+func (m *thServerMock) FetchNftTable(_ context.Context, req *th.FetchNftTableQry) (*th.NftTableList, error) {
+	var ret th.NftTableList
+	for _, id := range req.GetScopedByTableId().GetTableId() {
+		if id == 7 {
+			ret.Tables = append(ret.Tables, &th.NftTableResp{TableId: id, TableStr: "table inet filter {}"})
+		}
+	}
+	return &ret, nil
+}
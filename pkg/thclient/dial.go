@@ -0,0 +1,71 @@
+package thclient
+
+import (
+	"context"
+	"time"
+
+	grpcClient "github.com/H-BF/corlib/client/grpc"
+	netPkg "github.com/H-BF/corlib/pkg/net"
+	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	grpcBackoff "google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// dial makes closable connection to the address 'tcp://host:port' or 'unix:///path'
+func dial(ctx context.Context, addr string, o options) (grpcClient.ClosableClientConnInterface, error) {
+	ep, err := netPkg.ParseEndpoint(addr)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "bad address (%s)", addr)
+	}
+	target := ep.FQN()
+	if !ep.IsUnixDomain() {
+		if target, err = ep.Address(); err != nil {
+			return nil, errors.WithMessagef(err, "bad address (%s)", addr)
+		}
+	}
+	creds := insecure.NewCredentials()
+	if o.tls != nil {
+		creds = credentials.NewTLS(o.tls)
+	}
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUserAgent(o.userAgent),
+	}
+	if o.perRPCCreds != nil {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(o.perRPCCreds))
+	}
+	if o.dialDuration > 0 {
+		d := max(o.dialDuration, time.Second)
+		bkCfg := grpcBackoff.DefaultConfig
+		bkCfg.BaseDelay = d / 10
+		bkCfg.Multiplier = 1.01
+		bkCfg.Jitter = 0.1
+		bkCfg.MaxDelay = d
+		dialOpts = append(dialOpts, grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           bkCfg,
+			MinConnectTimeout: d / 10,
+		}))
+	}
+	if o.maxRetries > 0 {
+		retrOpts := []grpc_retry.CallOption{
+			grpc_retry.WithMax(o.maxRetries),
+			grpc_retry.WithCodes(codes.Unavailable),
+		}
+		dialOpts = append(dialOpts,
+			grpc.WithChainStreamInterceptor(grpc_retry.StreamClientInterceptor(retrOpts...)),
+			grpc.WithChainUnaryInterceptor(grpc_retry.UnaryClientInterceptor(retrOpts...)),
+		)
+	}
+	if o.compressor != "" {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor(o.compressor)))
+	}
+	c, err := grpc.DialContext(ctx, target, dialOpts...) //nolint:staticcheck
+	if err != nil {
+		return nil, err
+	}
+	return grpcClient.MakeCloseable(grpcClient.WithErrorWrapper(c, "tracehub")), nil
+}
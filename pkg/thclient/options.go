@@ -0,0 +1,128 @@
+package thclient
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/H-BF/corlib/pkg/backoff"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+const (
+	// CompressionGzip gzip compression of the messages
+	CompressionGzip = gzip.Name
+
+	// DefPageSize default number of traces in one page fetched by Client.Traces
+	DefPageSize = 1000
+
+	defDialDuration = 10 * time.Second
+	defUserAgent    = "thclient"
+)
+
+type (
+	// Option option of the Client
+	Option func(*options)
+
+	options struct {
+		dialDuration time.Duration
+		userAgent    string
+		maxRetries   uint
+		tls          *tls.Config
+		perRPCCreds  credentials.PerRPCCredentials
+		compressor   string
+		pageSize     uint32
+		reconnect    func() backoff.Backoff
+	}
+)
+
+func defOptions() options {
+	return options{
+		dialDuration: defDialDuration,
+		userAgent:    defUserAgent,
+		pageSize:     DefPageSize,
+		reconnect: func() backoff.Backoff {
+			return backoff.ExponentialBackoffBuilder().
+				WithInitialInterval(time.Second).
+				WithMaxInterval(30 * time.Second).
+				WithMaxElapsedThreshold(0).
+				Build()
+		},
+	}
+}
+
+// WithDialDuration max duration of the connection establishment
+func WithDialDuration(d time.Duration) Option {
+	return func(o *options) {
+		o.dialDuration = d
+	}
+}
+
+// WithUserAgent user agent of the calls
+func WithUserAgent(ua string) Option {
+	return func(o *options) {
+		o.userAgent = ua
+	}
+}
+
+// WithMaxRetries retries of the calls failed with 'Unavailable' code
+func WithMaxRetries(n uint) Option {
+	return func(o *options) {
+		o.maxRetries = n
+	}
+}
+
+// WithTLS connects over TLS, connection is insecure by default
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *options) {
+		o.tls = cfg
+	}
+}
+
+// WithCompression compresses messages by the registered grpc compressor, e.g. CompressionGzip
+func WithCompression(name string) Option {
+	return func(o *options) {
+		o.compressor = name
+	}
+}
+
+// WithPerRPCCredentials attaches credentials to every call
+func WithPerRPCCredentials(creds credentials.PerRPCCredentials) Option {
+	return func(o *options) {
+		o.perRPCCreds = creds
+	}
+}
+
+// WithToken sends 'authorization: Bearer <token>' metadata with every call
+func WithToken(token string) Option {
+	return WithPerRPCCredentials(bearerToken(token))
+}
+
+// WithPageSize max number of traces in one page fetched by Client.Traces
+func WithPageSize(n uint32) Option {
+	return func(o *options) {
+		o.pageSize = n
+	}
+}
+
+// WithReconnect backoff between attempts to reopen the broken stream of traces,
+// exponential from 1s to 30s without limit by default; reconnects are disabled by the backoff returning backoff.Stop
+func WithReconnect(f func() backoff.Backoff) Option {
+	return func(o *options) {
+		o.reconnect = f
+	}
+}
+
+type bearerToken string
+
+// GetRequestMetadata impl credentials.PerRPCCredentials
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity impl credentials.PerRPCCredentials,
+// the token is sent over insecure connections too
+func (bearerToken) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,43 @@
+package thclient
+
+import (
+	"context"
+
+	th "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRulesetNotFound nftables table of the trace is not found on trace-hub
+var ErrRulesetNotFound = errors.New("ruleset is not found")
+
+// Rulesets fetches nftables tables the traces are matched in, all of them when no ids are given
+func (c *Client) Rulesets(ctx context.Context, tableIds ...uint64) ([]*th.NftTableResp, error) {
+	qry := &th.FetchNftTableQry{
+		Scoped: &th.FetchNftTableQry_NoScope{NoScope: &th.FetchNftTableQry_All{}},
+	}
+	if len(tableIds) > 0 {
+		qry.Scoped = &th.FetchNftTableQry_ScopedByTableId{
+			ScopedByTableId: &th.FetchNftTableQry_ByTableId{TableId: tableIds},
+		}
+	}
+	resp, err := c.FetchNftTable(ctx, qry)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to fetch rulesets")
+	}
+	return resp.GetTables(), nil
+}
+
+// RulesetOf fetches nftables table the trace is matched in
+func (c *Client) RulesetOf(ctx context.Context, tr *th.FetchTrace) (*th.NftTableResp, error) {
+	tables, err := c.Rulesets(ctx, tr.GetTableId())
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tables {
+		if t.GetTableId() == tr.GetTableId() {
+			return t, nil
+		}
+	}
+	return nil, errors.WithMessagef(ErrRulesetNotFound, "table id %d", tr.GetTableId())
+}
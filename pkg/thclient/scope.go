@@ -0,0 +1,183 @@
+package thclient
+
+import (
+	"time"
+
+	th "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+	"github.com/wildberries-tech/pkt-tracer/pkg/tracequery"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Scope builds filter of the traces: values of one field are matched by any of them,
+// different fields and the query are matched all together
+type Scope struct {
+	m     *th.TraceScope
+	query string
+}
+
+// NewScope makes scope matching all traces
+func NewScope() *Scope {
+	return &Scope{m: new(th.TraceScope)}
+}
+
+// TraceIds filters by nftables trace ids
+func (s *Scope) TraceIds(ids ...uint32) *Scope {
+	s.m.TrId = append(s.m.TrId, ids...)
+	return s
+}
+
+// Tables filters by nftables table names
+func (s *Scope) Tables(names ...string) *Scope {
+	s.m.Table = append(s.m.Table, names...)
+	return s
+}
+
+// Chains filters by nftables chain names
+func (s *Scope) Chains(names ...string) *Scope {
+	s.m.Chain = append(s.m.Chain, names...)
+	return s
+}
+
+// RuleHandles filters by nftables rule handles
+func (s *Scope) RuleHandles(handles ...uint64) *Scope {
+	s.m.RuleHandle = append(s.m.RuleHandle, handles...)
+	return s
+}
+
+// Verdicts filters by verdicts, e.g. "rule::drop" or "policy::accept"
+func (s *Scope) Verdicts(verdicts ...string) *Scope {
+	s.m.Verdict = append(s.m.Verdict, verdicts...)
+	return s
+}
+
+// Agents filters by identifiers of the agents
+func (s *Scope) Agents(ids ...string) *Scope {
+	s.m.AgentsIds = append(s.m.AgentsIds, ids...)
+	return s
+}
+
+// Iifnames filters by input interfaces
+func (s *Scope) Iifnames(names ...string) *Scope {
+	s.m.Iifname = append(s.m.Iifname, names...)
+	return s
+}
+
+// Oifnames filters by output interfaces
+func (s *Scope) Oifnames(names ...string) *Scope {
+	s.m.Oifname = append(s.m.Oifname, names...)
+	return s
+}
+
+// SAddrs filters by source ip addresses
+func (s *Scope) SAddrs(addrs ...string) *Scope {
+	s.m.SAddr = append(s.m.SAddr, addrs...)
+	return s
+}
+
+// DAddrs filters by destination ip addresses
+func (s *Scope) DAddrs(addrs ...string) *Scope {
+	s.m.DAddr = append(s.m.DAddr, addrs...)
+	return s
+}
+
+// SPorts filters by source ports
+func (s *Scope) SPorts(ports ...uint32) *Scope {
+	s.m.SPort = append(s.m.SPort, ports...)
+	return s
+}
+
+// DPorts filters by destination ports
+func (s *Scope) DPorts(ports ...uint32) *Scope {
+	s.m.DPort = append(s.m.DPort, ports...)
+	return s
+}
+
+// IpProtos filters by ip protocols: tcp, udp, icmp...
+func (s *Scope) IpProtos(protos ...string) *Scope {
+	s.m.IpProto = append(s.m.IpProto, protos...)
+	return s
+}
+
+// SSgNames filters by security groups of the source addresses
+func (s *Scope) SSgNames(names ...string) *Scope {
+	s.m.SSgName = append(s.m.SSgName, names...)
+	return s
+}
+
+// DSgNames filters by security groups of the destination addresses
+func (s *Scope) DSgNames(names ...string) *Scope {
+	s.m.DSgName = append(s.m.DSgName, names...)
+	return s
+}
+
+// SgRules filters by sgroups rules the verdicts are resolved to
+func (s *Scope) SgRules(rules ...string) *Scope {
+	s.m.SgRule = append(s.m.SgRule, rules...)
+	return s
+}
+
+// Pods filters by pod names
+func (s *Scope) Pods(names ...string) *Scope {
+	s.m.Pod = append(s.m.Pod, names...)
+	return s
+}
+
+// PodNamespaces filters by pod namespaces
+func (s *Scope) PodNamespaces(namespaces ...string) *Scope {
+	s.m.PodNamespace = append(s.m.PodNamespace, namespaces...)
+	return s
+}
+
+// Containers filters by container names
+func (s *Scope) Containers(names ...string) *Scope {
+	s.m.ContainerName = append(s.m.ContainerName, names...)
+	return s
+}
+
+// Comms filters by command names of the processes
+func (s *Scope) Comms(names ...string) *Scope {
+	s.m.Comm = append(s.m.Comm, names...)
+	return s
+}
+
+// CtStates filters by conntrack states of the connections
+func (s *Scope) CtStates(states ...string) *Scope {
+	s.m.CtState = append(s.m.CtState, states...)
+	return s
+}
+
+// TimeRange filters by time of the traces, Client.Subscribe ignores it
+func (s *Scope) TimeRange(from, to time.Time) *Scope {
+	s.m.Time = &th.TimeRange{From: timestamppb.New(from), To: timestamppb.New(to)}
+	return s
+}
+
+// Last filters traces of the last period till now
+func (s *Scope) Last(d time.Duration) *Scope {
+	now := time.Now()
+	return s.TimeRange(now.Add(-d), now)
+}
+
+// Query filters by the visor query, e.g. `sport >= 1024 and (verdict == "rule::drop" or dport in (80, 443))`
+func (s *Scope) Query(q string) *Scope {
+	s.query = q
+	return s
+}
+
+// Proto makes message of the scope
+func (s *Scope) Proto() (*th.TraceScope, error) {
+	ret := new(th.TraceScope)
+	if s == nil || s.m == nil {
+		return ret, nil
+	}
+	ret = proto.Clone(s.m).(*th.TraceScope)
+	ret.QueryDsl = s.query
+	var err error
+	if ret.Query, err = tracequery.ToSql(s.query); err != nil {
+		err = errors.WithMessagef(err, "bad query '%s'", s.query)
+	}
+	return ret, err
+}
@@ -0,0 +1,226 @@
+package thclient
+
+import (
+	"context"
+	"io"
+	"maps"
+	"time"
+
+	th "github.com/wildberries-tech/pkt-tracer/pkg/api/tracehub"
+
+	"github.com/H-BF/corlib/pkg/backoff"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type (
+	// TraceIterator iterates over the traces received by pages from trace-hub:
+	//
+	//	for it.Next() {
+	//		tr := it.Trace()
+	//	}
+	//	err := it.Err()
+	//
+	// Broken stream is reopened after transient errors and resumed from the time of the last trace,
+	// traces received yet are skipped: traces are ordered by time, so the resumed ones before the last
+	// time are received yet and the resumed ones of the last time are skipped as many times as the same
+	// rows (all fields of the trace, agent and table) have been received with this time before the stream is broken
+	TraceIterator struct {
+		ctx     context.Context
+		cancel  context.CancelFunc
+		follow  bool
+		open    func(ctx context.Context, resumeFrom *time.Time) (traceStream, error)
+		stream  traceStream
+		backoff backoff.Backoff
+		page    []*th.FetchTrace
+		cur     *th.FetchTrace
+		// time of the last trace and the number of times the rows are received with this time
+		last time.Time
+		seen map[string]int
+		// rows of the last time left to skip in the reopened stream, nil when the stream is not resumed
+		skip map[string]int
+		err  error
+	}
+
+	traceStream interface {
+		Recv() (*th.TraceList, error)
+	}
+)
+
+// Traces fetches traces of the scope ordered by time, the time range of the scope is half-open [from, to).
+// Traces are received by pages of WithPageSize option, follow mode is not supported
+func (c *Client) Traces(ctx context.Context, scope *Scope) *TraceIterator {
+	flt, err := scope.Proto()
+	if flt.Time == nil {
+		// resumed fetch should not pick up traces arrived after the start
+		flt.Time = &th.TimeRange{To: timestamppb.Now()}
+	}
+	it := c.newIterator(ctx, false, func(ctx context.Context, resumeFrom *time.Time) (traceStream, error) {
+		f := proto.Clone(flt).(*th.TraceScope)
+		if resumeFrom != nil {
+			f.Time.From = timestamppb.New(*resumeFrom)
+		}
+		return c.ExportTraces(ctx, &th.ExportTracesReq{
+			Scope:     f,
+			BatchSize: c.opts.pageSize,
+		})
+	})
+	it.err = err
+	return it
+}
+
+// Subscribe follows new traces of the scope from the given time, zero time means now.
+// Stream is reopened after transient errors until the context is done or the iterator is closed
+func (c *Client) Subscribe(ctx context.Context, scope *Scope, from time.Time) *TraceIterator {
+	flt, err := scope.Proto()
+	flt.FollowMode = true
+	if from.IsZero() {
+		from = time.Now()
+	}
+	it := c.newIterator(ctx, true, func(ctx context.Context, resumeFrom *time.Time) (traceStream, error) {
+		f := proto.Clone(flt).(*th.TraceScope)
+		f.Time = &th.TimeRange{From: timestamppb.New(from), To: timestamppb.Now()}
+		if resumeFrom != nil {
+			f.Time.From = timestamppb.New(*resumeFrom)
+		}
+		return c.FetchTraces(ctx, f)
+	})
+	it.err = err
+	return it
+}
+
+func (c *Client) newIterator(ctx context.Context, follow bool,
+	open func(context.Context, *time.Time) (traceStream, error)) *TraceIterator {
+	ret := &TraceIterator{
+		follow:  follow,
+		open:    open,
+		backoff: c.opts.reconnect(),
+		seen:    make(map[string]int),
+	}
+	ret.ctx, ret.cancel = context.WithCancel(ctx)
+	return ret
+}
+
+// Next receives the next trace, it returns false when traces are over or on error
+func (it *TraceIterator) Next() bool {
+	for {
+		if len(it.page) > 0 {
+			tr := it.page[0]
+			it.page = it.page[1:]
+			if it.received(tr) {
+				continue
+			}
+			it.cur = tr
+			return true
+		}
+		it.cur = nil
+		if it.err != nil {
+			return false
+		}
+		if it.stream == nil {
+			var err error
+			from := it.resumeFrom()
+			if from != nil {
+				it.skip = maps.Clone(it.seen)
+			}
+			if it.stream, err = it.open(it.ctx, from); err != nil {
+				it.retryOrFail(err)
+			}
+			continue
+		}
+		l, err := it.stream.Recv()
+		switch {
+		case err == nil:
+			// empty list marks the end of the batch of the follow mode
+			it.page = l.GetTraces()
+			it.backoff.Reset()
+		case errors.Is(err, io.EOF) && !it.follow:
+			it.err = io.EOF
+		default:
+			it.stream = nil
+			it.retryOrFail(err)
+		}
+	}
+}
+
+// Trace current trace
+func (it *TraceIterator) Trace() *th.FetchTrace {
+	return it.cur
+}
+
+// Err error the iteration is stopped by, nil when traces are over
+func (it *TraceIterator) Err() error {
+	if errors.Is(it.err, io.EOF) {
+		return nil
+	}
+	return it.err
+}
+
+// Close stops the iteration
+func (it *TraceIterator) Close() {
+	it.cancel()
+	if it.err == nil {
+		it.err = io.EOF
+	}
+}
+
+// received checks that the trace was received before the stream was reopened and remembers it otherwise
+func (it *TraceIterator) received(tr *th.FetchTrace) bool {
+	ts := tr.GetTimestamp().AsTime()
+	if ts.Before(it.last) {
+		// the reopened stream repeats traces received yet, traces of the live stream are passed as is
+		return it.skip != nil
+	}
+	// the whole row is the identity of the trace, same skb is traced by several namespaces and sources
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(tr)
+	key := string(b)
+	if ts.After(it.last) {
+		it.last = ts
+		it.skip = nil
+		clear(it.seen)
+	} else if it.skip[key] > 0 {
+		it.skip[key]--
+		return true
+	}
+	it.seen[key]++
+	return false
+}
+
+func (it *TraceIterator) resumeFrom() *time.Time {
+	if it.last.IsZero() {
+		return nil
+	}
+	ret := it.last
+	return &ret
+}
+
+// retryOrFail waits before reopening the stream broken by the transient error or stops the iteration
+func (it *TraceIterator) retryOrFail(err error) {
+	if it.ctx.Err() != nil {
+		it.err = it.ctx.Err()
+		return
+	}
+	if !(it.follow && errors.Is(err, io.EOF)) {
+		switch status.Code(errors.Cause(err)) {
+		case codes.Unavailable, codes.Aborted, codes.ResourceExhausted:
+		default:
+			it.err = err
+			return
+		}
+	}
+	d := it.backoff.NextBackOff()
+	if d == backoff.Stop {
+		it.err = err
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-it.ctx.Done():
+		it.err = it.ctx.Err()
+	case <-t.C:
+	}
+}
@@ -0,0 +1,182 @@
+package tracequery
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+)
+
+type (
+	// Matcher - predicate of the trace made of the query expression
+	Matcher[T any] func(T) bool
+
+	// Getters - values of the query parameters of the trace by the parameter names,
+	// value is string or int64
+	Getters[T any] map[string]func(T) any
+
+	// evalFunc - evaluates node of the query on the trace, value is string, int64, bool or list of them
+	evalFunc[T any] func(T) any
+)
+
+// NewMatcher makes predicate of the trace evaluating the query, it accepts the same queries as ToSql
+func NewMatcher[T any](q string, getters Getters[T]) (Matcher[T], error) {
+	if q == "" {
+		return func(T) bool { return true }, nil
+	}
+	// the query is formatted the same way as for ToSql, so '-' of the names are replaced by '_'
+	tree, err := parser.Parse(NewQueryParser(q, nil).cmd)
+	if err != nil {
+		return nil, err
+	}
+	byIdent := make(Getters[T], len(getters))
+	for k, g := range getters {
+		byIdent[strings.ReplaceAll(k, "-", "_")] = g
+	}
+	eval, err := compileNode(tree.Node, byIdent)
+	if err != nil {
+		return nil, err
+	}
+	return func(t T) bool {
+		b, _ := eval(t).(bool)
+		return b
+	}, nil
+}
+
+func compileNode[T any](node ast.Node, getters Getters[T]) (evalFunc[T], error) {
+	switch n := node.(type) {
+	case *ast.BinaryNode:
+		left, err := compileNode(n.Left, getters)
+		if err != nil {
+			return nil, err
+		}
+		right, err := compileNode(n.Right, getters)
+		if err != nil {
+			return nil, err
+		}
+		return compileBinary(n.Operator, left, right)
+	case *ast.UnaryNode:
+		val, err := compileNode(n.Node, getters)
+		if err != nil {
+			return nil, err
+		}
+		switch n.Operator {
+		case "!", "not":
+			return func(t T) any {
+				b, _ := val(t).(bool)
+				return !b
+			}, nil
+		}
+		return nil, fmt.Errorf("unsupported operator '%s'", n.Operator)
+	case *ast.IdentifierNode:
+		g, ok := getters[n.Value]
+		if !ok {
+			return nil, fmt.Errorf("%s is invalid query parameter", n.Value)
+		}
+		return evalFunc[T](g), nil
+	case *ast.StringNode:
+		v := n.Value
+		return func(T) any { return v }, nil
+	case *ast.IntegerNode:
+		v := int64(n.Value)
+		return func(T) any { return v }, nil
+	}
+	return nil, fmt.Errorf("unknown node type %T", node)
+}
+
+func compileBinary[T any](op string, left, right evalFunc[T]) (evalFunc[T], error) {
+	switch op {
+	case "&&", "and":
+		return func(t T) any {
+			l, _ := left(t).(bool)
+			if !l {
+				return false
+			}
+			r, _ := right(t).(bool)
+			return r
+		}, nil
+	case "||", "or":
+		return func(t T) any {
+			if l, _ := left(t).(bool); l {
+				return true
+			}
+			r, _ := right(t).(bool)
+			return r
+		}, nil
+	case ",":
+		// list of values of 'in' operator, alternatives otherwise
+		return func(t T) any {
+			l, r := left(t), right(t)
+			lb, lok := l.(bool)
+			rb, rok := r.(bool)
+			if lok && rok {
+				return lb || rb
+			}
+			return append(asList(l), asList(r)...)
+		}, nil
+	case "in":
+		return func(t T) any {
+			v := left(t)
+			for _, x := range asList(right(t)) {
+				if c, ok := compareValues(v, x); ok && c == 0 {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case "==", "!=", "<", "<=", ">", ">=":
+		return func(t T) any {
+			c, ok := compareValues(left(t), right(t))
+			if !ok {
+				return op == "!="
+			}
+			switch op {
+			case "==":
+				return c == 0
+			case "!=":
+				return c != 0
+			case "<":
+				return c < 0
+			case "<=":
+				return c <= 0
+			case ">":
+				return c > 0
+			}
+			return c >= 0
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported operator '%s'", op)
+}
+
+func asList(v any) []any {
+	if l, ok := v.([]any); ok {
+		return l
+	}
+	return []any{v}
+}
+
+// compareValues compares values of the same type, false is returned when types differ
+func compareValues(a, b any) (int, bool) {
+	switch x := a.(type) {
+	case int64:
+		y, ok := b.(int64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	}
+	return 0, false
+}
@@ -0,0 +1,71 @@
+// Package tracequery converts queries of the traces like
+// '(sport>=80 and sport<=443) and ip-dst=="93.184.215.14" and dport not in (80,443)'
+// to the sql condition of trace-hub and to the predicate evaluating the query in process
+package tracequery
+
+// columns - columns of the query parameters
+var columns = map[string]string{
+	"trid":           "trace_id",
+	"table":          "table",
+	"chain":          "chain",
+	"jt":             "jump_target",
+	"handle":         "handle",
+	"family":         "family",
+	"iif":            "ifin",
+	"oif":            "ifout",
+	"hw-src":         "mac_s",
+	"hw-dst":         "mac_d",
+	"ip-src":         "ip_s",
+	"ip-dst":         "ip_d",
+	"sport":          "sport",
+	"dport":          "dport",
+	"sg-src":         "sgname_s",
+	"sg-dst":         "sgname_d",
+	"net-src":        "sgnet_s",
+	"net-dst":        "sgnet_d",
+	"netns":          "netns",
+	"container-id":   "container_id",
+	"container":      "container_name",
+	"pod":            "pod",
+	"pod-ns":         "pod_namespace",
+	"pid":            "pid",
+	"comm":           "comm",
+	"cgroup":         "cgroup",
+	"ct-state":       "ct_state",
+	"ct-dir":         "ct_direction",
+	"ct-nat":         "ct_nat",
+	"ct-mark":        "ct_mark",
+	"ct-zone":        "ct_zone",
+	"source":         "source",
+	"log-prefix":     "log_prefix",
+	"log-group":      "log_group",
+	"hook":           "hook",
+	"sg-rule":        "sg_rule",
+	"sg-rule-action": "sg_rule_action",
+	"len":            "len",
+	"proto":          "ip_proto",
+	"verdict":        "verdict",
+}
+
+// Params returns names of the query parameters
+func Params() []string {
+	ret := make([]string, 0, len(columns))
+	for k := range columns {
+		ret = append(ret, k)
+	}
+	return ret
+}
+
+// Column returns column of the query parameter
+func Column(param string) (string, bool) {
+	c, ok := columns[param]
+	return c, ok
+}
+
+// ToSql converts the query to the sql condition of trace-hub, empty query is empty condition
+func ToSql(q string) (string, error) {
+	if q == "" {
+		return "", nil
+	}
+	return NewQueryParser(q, columns).ToSql()
+}
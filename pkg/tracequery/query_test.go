@@ -0,0 +1,71 @@
+package tracequery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type queryTestSuite struct {
+	suite.Suite
+}
+
+func Test_Query(t *testing.T) {
+	suite.Run(t, new(queryTestSuite))
+}
+
+type testTrace struct {
+	trid    int64
+	sg      string
+	dport   int64
+	verdict string
+}
+
+var testGetters = Getters[testTrace]{
+	"trid":    func(t testTrace) any { return t.trid },
+	"sg-src":  func(t testTrace) any { return t.sg },
+	"dport":   func(t testTrace) any { return t.dport },
+	"verdict": func(t testTrace) any { return t.verdict },
+}
+
+func (sui *queryTestSuite) Test_ToSql() {
+	testCases := []struct {
+		query string
+		exp   string
+	}{
+		{"", ""},
+		{`sg-src == "sg-a" and dport in (80, 443)`, "sgname_s = 'sg-a' AND dport IN (80,443)"},
+		{`verdict not in ("rule::drop", "policy::drop") or trid != 3`, "verdict NOT IN ('rule::drop','policy::drop') OR trace_id != 3"},
+	}
+	for _, tc := range testCases {
+		sql, err := ToSql(tc.query)
+		sui.Require().NoError(err, tc.query)
+		sui.Require().Equal(tc.exp, sql)
+	}
+	_, err := ToSql(`unknown == 1`)
+	sui.Require().Error(err)
+}
+
+func (sui *queryTestSuite) Test_Match() {
+	tr := testTrace{trid: 123, sg: "sg-a", dport: 443, verdict: "rule::drop"}
+	testCases := []struct {
+		query string
+		exp   bool
+	}{
+		{"", true},
+		{`sg-src == "sg-a" and dport in (80, 443)`, true},
+		{`sg-src == 'sg-b' || dport < 443`, false},
+		{`verdict not in ("rule::drop", "policy::drop")`, false},
+		{`!(trid == 123) or dport >= 443`, true},
+		{`dport == "443"`, false},
+	}
+	for _, tc := range testCases {
+		m, err := NewMatcher(tc.query, testGetters)
+		sui.Require().NoError(err, tc.query)
+		sui.Require().Equal(tc.exp, m(tr), tc.query)
+	}
+	for _, q := range []string{`unknown == 1`, `dport ==`, `dport + 1 == 2`} {
+		_, err := NewMatcher(q, testGetters)
+		sui.Require().Error(err, q)
+	}
+}